@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// Component names of the GraphQL-over-HTTP request and response envelopes
+const (
+	GraphQLRequestSchemaName  = "GraphQLRequest"
+	GraphQLResponseSchemaName = "GraphQLResponse"
+)
+
+// GraphQLRequestSchema returns the GraphQL-over-HTTP request envelope
+func GraphQLRequestSchema() spec.Schema {
+	freeForm := true
+	return spec.Schema{
+		Type:        "object",
+		Description: "GraphQL request",
+		Properties: map[string]spec.Schema{
+			"query":         {Type: "string", Description: "GraphQL document with the operations to execute"},
+			"operationName": {Type: "string", Description: "Operation to execute when the document has several"},
+			"variables":     {Type: "object", Description: "Values of the variables of the operation", AdditionalPropertiesAllowed: &freeForm},
+			"extensions":    {Type: "object", Description: "Implementation specific request extensions, e.g. persisted queries", AdditionalPropertiesAllowed: &freeForm},
+		},
+		Required: []string{"query"},
+	}
+}
+
+// GraphQLResponseSchema returns the GraphQL-over-HTTP response envelope
+func GraphQLResponseSchema() spec.Schema {
+	freeForm := true
+	location := spec.Schema{
+		Type: "object",
+		Properties: map[string]spec.Schema{
+			"line":   {Type: "integer"},
+			"column": {Type: "integer"},
+		},
+	}
+	graphQLError := spec.Schema{
+		Type: "object",
+		Properties: map[string]spec.Schema{
+			"message":    {Type: "string", Description: "Description of the error"},
+			"locations":  {Type: "array", Items: &location, Description: "Locations in the document the error refers to"},
+			"path":       {Type: "array", Items: &spec.Schema{OneOf: []spec.Schema{{Type: "string"}, {Type: "integer"}}}, Description: "Path of the response field the error occurred in"},
+			"extensions": {Type: "object", AdditionalPropertiesAllowed: &freeForm},
+		},
+		Required: []string{"message"},
+	}
+	return spec.Schema{
+		Type:        "object",
+		Description: "GraphQL response",
+		Properties: map[string]spec.Schema{
+			"data":       {Type: "object", Description: "Result of the executed operation, shaped by the query", AdditionalPropertiesAllowed: &freeForm, Nullable: true},
+			"errors":     {Type: "array", Items: &graphQLError, Description: "Errors raised while executing the operation"},
+			"extensions": {Type: "object", AdditionalPropertiesAllowed: &freeForm},
+		},
+	}
+}
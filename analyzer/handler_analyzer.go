@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/zainokta/openapi-gen/logger"
 	"github.com/zainokta/openapi-gen/spec"
 
 	"golang.org/x/tools/go/packages"
@@ -18,7 +19,15 @@ type HandlerAnalyzer interface {
 	ExtractTypes(handler interface{}) (requestType, responseType reflect.Type, err error)
 	AnalyzeHandler(handler interface{}) HandlerSchema
 	GetFrameworkName() string
-	SetConfig(config interface{})
+	SetConfig(config Config)
+}
+
+// Config is the configuration the generator passes to its handler analyzer
+type Config interface {
+	IsProductionMode() bool     // Handler source is not read in production
+	IsASTAnalysisEnabled() bool // Handler source is read to find request and response types
+	SourceDirs() []string       // Directories handler source is searched from, empty for the working directory
+	Logger() logger.Logger      // Logger of the generator, nil when none was set
 }
 
 // DynamicTypeRegistry manages automatic type discovery from any imported package
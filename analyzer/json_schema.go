@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// Draft is a JSON Schema dialect, identified by the URI of its meta-schema
+type Draft string
+
+const (
+	// Draft202012 is JSON Schema 2020-12, definitions live in $defs
+	Draft202012 Draft = "https://json-schema.org/draft/2020-12/schema"
+	// Draft07 is JSON Schema draft-07, definitions live in definitions
+	Draft07 Draft = "http://json-schema.org/draft-07/schema#"
+)
+
+// jsonSchemaDefs collects the named struct types of a JSON Schema document as definitions
+type jsonSchemaDefs struct {
+	root      reflect.Type
+	expanding reflect.Type            // Type whose definition is being generated, it is not replaced by a reference
+	names     map[reflect.Type]string // Definition name of each type
+	taken     map[string]bool
+	schemas   map[string]spec.Schema
+}
+
+// GenerateJSONSchema generates a standalone JSON Schema document for a Go type
+//
+// Types are analyzed as for OpenAPI, so a message or config struct validates the same way
+// its API representation is documented. Named structs nested in t become definitions in
+// $defs (definitions for draft-07), referenced from where they are used, and types referring
+// to themselves refer to their definition or, for t, to the document. OpenAPI-only keywords
+// are translated: nullable becomes a "null" type, example becomes examples.
+//
+// $id is the type name followed by ".schema.json", relative to wherever the document is
+// published; replace it in the returned document for an absolute identifier.
+func (sg *SchemaGenerator) GenerateJSONSchema(t reflect.Type, draft Draft) (map[string]interface{}, error) {
+	if t == nil {
+		return nil, fmt.Errorf("type is required")
+	}
+	if draft != Draft202012 && draft != Draft07 {
+		return nil, fmt.Errorf("unsupported JSON Schema draft %q", draft)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// A generator of its own, references must not end up in the cache of OpenAPI schemas
+	defs := &jsonSchemaDefs{
+		root:      t,
+		expanding: t,
+		names:     make(map[reflect.Type]string),
+		taken:     make(map[string]bool),
+		schemas:   make(map[string]spec.Schema),
+	}
+	generator := &SchemaGenerator{
+		typeCache:  make(map[reflect.Type]spec.Schema),
+		processing: make(map[reflect.Type]bool),
+		maxDepth:   sg.maxDepth,
+		fieldDocs:  sg.fieldDocs,
+		timeFormat: sg.timeFormat,
+		required:   sg.required,
+		unions:     sg.unions,
+		astTypes:   sg.astTypes,
+		defs:       defs,
+	}
+	root := generator.GenerateSchemaFromType(t)
+
+	defsKeyword, defsPrefix := "$defs", "#/$defs/"
+	if draft == Draft07 {
+		defsKeyword, defsPrefix = "definitions", "#/definitions/"
+	}
+	document, err := jsonSchemaValue(root, draft, defsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	document["$schema"] = string(draft)
+	if name := GoTypeSchemaName(t); name != "" {
+		document["$id"] = name + ".schema.json"
+	}
+	if len(defs.schemas) > 0 {
+		definitions := make(map[string]interface{}, len(defs.schemas))
+		for name, schema := range defs.schemas {
+			if definitions[name], err = jsonSchemaValue(schema, draft, defsPrefix); err != nil {
+				return nil, err
+			}
+		}
+		document[defsKeyword] = definitions
+	}
+	return document, nil
+}
+
+// reference returns a reference to the definition of t, false when t is not a named struct
+// or is the type being expanded
+//
+// The definition is generated the first time t is referenced. Its name is claimed first, so
+// a type nested in itself refers back to it.
+func (d *jsonSchemaDefs) reference(sg *SchemaGenerator, t reflect.Type) (spec.Schema, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == d.expanding {
+		d.expanding = nil
+		return spec.Schema{}, false
+	}
+	if t == d.root {
+		return spec.Schema{Ref: "#"}, true
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" || isFileType(t) {
+		return spec.Schema{}, false
+	}
+	if schema := sg.handleBasicType(t); schema.Type != "" {
+		return spec.Schema{}, false // Well-known types such as time.Time are inlined
+	}
+
+	name, exists := d.names[t]
+	if !exists {
+		name = d.claim(GoTypeSchemaName(t))
+		d.names[t] = name
+		d.expanding = t
+		d.schemas[name] = sg.GenerateSchemaFromType(t)
+	}
+	return spec.Schema{Ref: componentRefPrefix + name}, true
+}
+
+// union returns the oneOf of a union's variants, each variant becomes a definition
+//
+// Variants are named like their components, as the references of Union.Schema expect.
+func (d *jsonSchemaDefs) union(sg *SchemaGenerator, union Union) spec.Schema {
+	for _, variant := range union.Variants {
+		name := GoTypeSchemaName(variant.Type)
+		if d.taken[name] {
+			continue
+		}
+		d.taken[name] = true
+		variantType := variant.Type
+		for variantType.Kind() == reflect.Pointer {
+			variantType = variantType.Elem()
+		}
+		d.names[variantType] = name
+		d.expanding = variantType
+		d.schemas[name] = union.VariantSchema(sg, variant)
+	}
+	return union.Schema()
+}
+
+// claim reserves a definition name, numbered when another type took it
+func (d *jsonSchemaDefs) claim(name string) string {
+	unique := name
+	for n := 2; d.taken[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+	d.taken[unique] = true
+	return unique
+}
+
+// jsonSchemaValue converts an OpenAPI schema to a JSON Schema object
+func jsonSchemaValue(schema spec.Schema, draft Draft, defsPrefix string) (map[string]interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	return convertJSONSchema(value, draft, defsPrefix), nil
+}
+
+// convertJSONSchema translates the OpenAPI keywords of a schema and its subschemas to JSON Schema
+func convertJSONSchema(schema map[string]interface{}, draft Draft, defsPrefix string) map[string]interface{} {
+	for _, keyword := range []string{"items", "not"} {
+		if subschema, ok := schema[keyword].(map[string]interface{}); ok {
+			schema[keyword] = convertJSONSchema(subschema, draft, defsPrefix)
+		}
+	}
+	if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		schema["additionalProperties"] = convertJSONSchema(values, draft, defsPrefix)
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			if property, ok := property.(map[string]interface{}); ok {
+				properties[name] = convertJSONSchema(property, draft, defsPrefix)
+			}
+		}
+	}
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		if members, ok := schema[keyword].([]interface{}); ok {
+			for i, member := range members {
+				if member, ok := member.(map[string]interface{}); ok {
+					members[i] = convertJSONSchema(member, draft, defsPrefix)
+				}
+			}
+		}
+	}
+
+	// The discriminator is an OpenAPI annotation, the variants' enums already tell them apart
+	delete(schema, "discriminator")
+	if schema["type"] == "any" {
+		delete(schema, "type")
+	}
+	if example, exists := schema["example"]; exists {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+	for _, bound := range []string{"Maximum", "Minimum"} {
+		exclusive := "exclusive" + bound
+		limit := strings.ToLower(bound)
+		if schema[exclusive] == true {
+			schema[exclusive] = schema[limit]
+			delete(schema, limit)
+		}
+		if schema[exclusive] == nil {
+			delete(schema, exclusive)
+		}
+	}
+
+	nullable := schema["nullable"] == true
+	delete(schema, "nullable")
+
+	if ref, ok := schema["$ref"].(string); ok {
+		ref = strings.Replace(ref, componentRefPrefix, defsPrefix, 1)
+		schema["$ref"] = ref
+		if draft == Draft07 && len(schema) > 1 {
+			// Draft-07 ignores keywords next to $ref, so the reference moves into an allOf
+			delete(schema, "$ref")
+			allOf, _ := schema["allOf"].([]interface{})
+			schema["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, allOf...)
+		}
+	}
+
+	if nullable {
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{typ, "null"}
+		} else {
+			return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+		}
+	}
+	return schema
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonSchemaCustomer struct {
+	Name string `json:"name" example:"Ada"`
+}
+
+type jsonSchemaLine struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type jsonSchemaOrder struct {
+	ID       string              `json:"id" validate:"required"`
+	Customer jsonSchemaCustomer  `json:"customer" description:"Who placed the order"`
+	Lines    []jsonSchemaLine    `json:"lines"`
+	Parent   *jsonSchemaOrder    `json:"parent,omitempty"`
+	Placed   time.Time           `json:"placed"`
+	Backup   *jsonSchemaCustomer `json:"backup,omitempty"`
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	document, err := NewSchemaGenerator().GenerateJSONSchema(reflect.TypeOf(&jsonSchemaOrder{}), Draft202012)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "jsonSchemaOrder.schema.json",
+		"title": "jsonSchemaOrder",
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string"},
+			"customer": {"$ref": "#/$defs/jsonSchemaCustomer", "description": "Who placed the order"},
+			"lines": {"type": "array", "items": {"$ref": "#/$defs/jsonSchemaLine"}},
+			"parent": {"$ref": "#"},
+			"placed": {"type": "string", "format": "date-time"},
+			"backup": {"$ref": "#/$defs/jsonSchemaCustomer"}
+		},
+		"$defs": {
+			"jsonSchemaCustomer": {
+				"title": "jsonSchemaCustomer",
+				"type": "object",
+				"properties": {"name": {"type": "string", "examples": ["Ada"]}}
+			},
+			"jsonSchemaLine": {
+				"title": "jsonSchemaLine",
+				"type": "object",
+				"required": ["sku"],
+				"properties": {"sku": {"type": "string"}, "quantity": {"type": "integer", "minimum": 1}}
+			}
+		}
+	}`, string(data))
+
+	// The OpenAPI schemas of the generator are not affected
+	generator := NewSchemaGenerator()
+	_, err = generator.GenerateJSONSchema(reflect.TypeOf(jsonSchemaOrder{}), Draft202012)
+	require.NoError(t, err)
+	assert.Equal(t, "object", generator.GenerateSchemaFromType(reflect.TypeOf(jsonSchemaOrder{})).Properties["customer"].Type)
+
+	_, err = generator.GenerateJSONSchema(reflect.TypeOf(jsonSchemaOrder{}), Draft("draft-04"))
+	assert.Error(t, err)
+	_, err = generator.GenerateJSONSchema(nil, Draft202012)
+	assert.Error(t, err)
+}
+
+func TestGenerateJSONSchemaDraft07(t *testing.T) {
+	document, err := NewSchemaGenerator().GenerateJSONSchema(reflect.TypeOf(jsonSchemaOrder{}), Draft07)
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", document["$schema"])
+	assert.Contains(t, document, "definitions")
+	assert.NotContains(t, document, "$defs")
+
+	// Keywords next to $ref are ignored by draft-07, so the reference moves into an allOf
+	properties := document["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"allOf":       []interface{}{map[string]interface{}{"$ref": "#/definitions/jsonSchemaCustomer"}},
+		"description": "Who placed the order",
+	}, properties["customer"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/jsonSchemaCustomer"}, properties["backup"])
+}
+
+func TestGenerateJSONSchemaUnion(t *testing.T) {
+	generator := NewSchemaGenerator()
+	require.NoError(t, generator.Unions().Register(reflect.TypeOf((*unionShape)(nil)).Elem(), "kind", []UnionVariant{
+		{Type: reflect.TypeOf(unionCircle{})},
+		{Value: "square", Type: reflect.TypeOf(&unionSquare{})},
+	}))
+
+	document, err := generator.GenerateJSONSchema(reflect.TypeOf(unionDrawing{}), Draft202012)
+	require.NoError(t, err)
+
+	items := document["properties"].(map[string]interface{})["shapes"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$ref": "#/$defs/unionCircle"},
+		map[string]interface{}{"$ref": "#/$defs/unionSquare"},
+	}, items["oneOf"])
+	assert.NotContains(t, items, "discriminator")
+
+	defs := document["$defs"].(map[string]interface{})
+	square := defs["unionSquare"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"square"}, square["kind"].(map[string]interface{})["enum"])
+	assert.Contains(t, defs, "unionCircle")
+}
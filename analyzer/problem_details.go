@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// ProblemDetailsSchemaName is the component name of the RFC 7807 problem details schema
+const ProblemDetailsSchemaName = "ProblemDetails"
+
+// ProblemDetailsSchema returns the RFC 7807 problem details schema
+func ProblemDetailsSchema() spec.Schema {
+	return spec.Schema{
+		Type:        "object",
+		Description: "RFC 7807 problem details",
+		Properties: map[string]spec.Schema{
+			"type":     {Type: "string", Format: "uri-reference", Description: "URI identifying the problem type", Default: "about:blank"},
+			"title":    {Type: "string", Description: "Short, human-readable summary of the problem type"},
+			"status":   {Type: "integer", Description: "HTTP status code of this occurrence"},
+			"detail":   {Type: "string", Description: "Explanation specific to this occurrence"},
+			"instance": {Type: "string", Format: "uri-reference", Description: "URI identifying this occurrence"},
+		},
+	}
+}
+
+// IsProblemDetails reports whether a schema has the shape of RFC 7807 problem details
+//
+// Structs such as problem.Problem are recognized by a title and an integer status next
+// to a type or detail property, extension members are allowed.
+func IsProblemDetails(schema spec.Schema) bool {
+	if schema.Type != "object" {
+		return false
+	}
+	title, hasTitle := schema.Properties["title"]
+	status, hasStatus := schema.Properties["status"]
+	if !hasTitle || !hasStatus || title.Type != "string" || status.Type != "integer" {
+		return false
+	}
+	_, hasType := schema.Properties["type"]
+	_, hasDetail := schema.Properties["detail"]
+	return hasType || hasDetail
+}
@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type problemLike struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail"`
+	Extras map[string]any `json:"extras"`
+}
+
+type plainError struct {
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+func TestIsProblemDetails(t *testing.T) {
+	generator := NewSchemaGenerator()
+
+	assert.True(t, IsProblemDetails(ProblemDetailsSchema()))
+	assert.True(t, IsProblemDetails(generator.GenerateSchemaFromType(reflect.TypeOf(problemLike{}))))
+	assert.False(t, IsProblemDetails(generator.GenerateSchemaFromType(reflect.TypeOf(plainError{}))), "status must be an integer")
+	assert.False(t, IsProblemDetails(generator.GenerateSchemaFromType(reflect.TypeOf(namingLoginRequest{}))))
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// MarkReadOnlyProperties marks properties with these names read-only in every route schema
+//
+// Read-only properties, e.g. "id" or "created_at", are left out of request components.
+// Fields can also be marked individually with an openapi:"readOnly" struct tag.
+func (sr *SchemaRegistry) MarkReadOnlyProperties(names ...string) {
+	for _, name := range names {
+		sr.readOnlyProperties[name] = true
+	}
+}
+
+// MarkWriteOnlyProperties marks properties with these names write-only in every route schema
+//
+// Write-only properties, e.g. "password", are left out of response components.
+// Fields can also be marked individually with an openapi:"writeOnly" struct tag.
+func (sr *SchemaRegistry) MarkWriteOnlyProperties(names ...string) {
+	for _, name := range names {
+		sr.writeOnlyProperties[name] = true
+	}
+}
+
+// directionalSchema returns the variant of a route schema for the direction it is used in
+//
+// Request schemas lose their read-only properties and response schemas their write-only
+// ones. variant is true when properties were removed, so the component differs from the
+// schema of its Go type.
+func (sr *SchemaRegistry) directionalSchema(schema spec.Schema, request bool) (spec.Schema, bool) {
+	schema = sr.markPropertyAccess(schema)
+	if request {
+		return withoutProperties(schema, func(property spec.Schema) bool { return property.ReadOnly })
+	}
+	return withoutProperties(schema, func(property spec.Schema) bool { return property.WriteOnly })
+}
+
+// markPropertyAccess applies MarkReadOnlyProperties and MarkWriteOnlyProperties to a schema and its nested schemas
+func (sr *SchemaRegistry) markPropertyAccess(schema spec.Schema) spec.Schema {
+	if len(sr.readOnlyProperties) == 0 && len(sr.writeOnlyProperties) == 0 {
+		return schema
+	}
+
+	schema = mapNestedSchemas(schema, sr.markPropertyAccess)
+	if schema.Properties == nil {
+		return schema
+	}
+
+	marked := make(map[string]spec.Schema, len(schema.Properties))
+	for name, property := range schema.Properties {
+		property = sr.markPropertyAccess(property)
+		if sr.readOnlyProperties[name] {
+			property.ReadOnly = true
+		}
+		if sr.writeOnlyProperties[name] {
+			property.WriteOnly = true
+		}
+		marked[name] = property
+	}
+	schema.Properties = marked
+	return schema
+}
+
+// withoutProperties removes the properties matching drop from a schema and its nested schemas
+//
+// The schema is copied, schemas returned by the generator share their maps with its cache.
+func withoutProperties(schema spec.Schema, drop func(spec.Schema) bool) (spec.Schema, bool) {
+	// Most schemas have nothing to drop, spare them the copy
+	if !hasProperty(schema, drop) {
+		return schema, false
+	}
+
+	removed := false
+	var strip func(spec.Schema) spec.Schema
+	strip = func(current spec.Schema) spec.Schema {
+		current = mapNestedSchemas(current, strip)
+		if current.Properties == nil {
+			return current
+		}
+
+		kept := make(map[string]spec.Schema, len(current.Properties))
+		for name, property := range current.Properties {
+			if drop(property) {
+				removed = true
+				continue
+			}
+			kept[name] = strip(property)
+		}
+		required := make([]string, 0, len(current.Required))
+		for _, name := range current.Required {
+			if _, exists := kept[name]; exists {
+				required = append(required, name)
+			}
+		}
+		current.Properties = kept
+		current.Required = required
+		return current
+	}
+
+	stripped := strip(schema)
+	if !removed {
+		return schema, false
+	}
+	return stripped, true
+}
+
+// hasProperty reports whether a schema or one of its nested schemas has a property matching match
+func hasProperty(schema spec.Schema, match func(spec.Schema) bool) bool {
+	for _, property := range schema.Properties {
+		if match(property) || hasProperty(property, match) {
+			return true
+		}
+	}
+	if schema.Items != nil && hasProperty(*schema.Items, match) {
+		return true
+	}
+	if schema.AdditionalProperties != nil && hasProperty(*schema.AdditionalProperties, match) {
+		return true
+	}
+	for _, list := range [][]spec.Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, nested := range list {
+			if hasProperty(nested, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mapNestedSchemas applies fn to the item, additional property and composed schemas of a schema
+func mapNestedSchemas(schema spec.Schema, fn func(spec.Schema) spec.Schema) spec.Schema {
+	if schema.Items != nil {
+		items := fn(*schema.Items)
+		schema.Items = &items
+	}
+	if schema.AdditionalProperties != nil {
+		values := fn(*schema.AdditionalProperties)
+		schema.AdditionalProperties = &values
+	}
+	for _, list := range []*[]spec.Schema{&schema.AllOf, &schema.OneOf, &schema.AnyOf} {
+		if *list == nil {
+			continue
+		}
+		mapped := make([]spec.Schema, len(*list))
+		for i, nested := range *list {
+			mapped[i] = fn(nested)
+		}
+		*list = mapped
+	}
+	return schema
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type accessAccount struct {
+	ID       int    `json:"id" openapi:"readOnly"`
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type accessTeam struct {
+	Members []accessAccount `json:"members"`
+}
+
+func TestSchemaRegistry_DirectionalSchemas(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.MarkWriteOnlyProperties("password")
+	registry.RegisterHandlerTypes("POST", "/accounts", reflect.TypeOf(accessAccount{}), reflect.TypeOf(accessAccount{}))
+
+	schemas := registry.GetAllSchemas()
+	request := schemas[registry.generateSchemaName("POST /accounts", "request")]
+	response := schemas[registry.generateSchemaName("POST /accounts", "response")]
+
+	assert.NotContains(t, request.Properties, "id")
+	assert.Contains(t, request.Properties, "password")
+	assert.True(t, request.Properties["password"].WriteOnly)
+	assert.ElementsMatch(t, []string{"email", "password"}, request.Required)
+
+	assert.Contains(t, response.Properties, "id")
+	assert.NotContains(t, response.Properties, "password")
+	assert.Equal(t, []string{"email"}, response.Required)
+
+	names := registry.ResolveComponentNames(nil)
+	assert.Equal(t, "accessAccountRequest", names["POST_accountsrequest"])
+	assert.Equal(t, "accessAccountResponse", names["POST_accountsresponse"])
+
+	// The generator's cached schema is not modified
+	full := registry.schemaGen.GenerateSchemaFromType(reflect.TypeOf(accessAccount{}))
+	assert.Len(t, full.Properties, 3)
+}
+
+func TestSchemaRegistry_DirectionalSchemasNested(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterHandlerTypes("GET", "/teams", nil, reflect.TypeOf(accessTeam{}))
+
+	schemas := registry.GetAllSchemas()
+	response := schemas[registry.generateSchemaName("GET /teams", "response")]
+	assert.Contains(t, response.Properties["members"].Items.Properties, "id")
+
+	registry.MarkWriteOnlyProperties("password")
+	schemas = registry.GetAllSchemas()
+	response = schemas[registry.generateSchemaName("GET /teams", "response")]
+	assert.NotContains(t, response.Properties["members"].Items.Properties, "password")
+
+	// A schema without access markers is unchanged and keeps its type name
+	_, variant := registry.directionalSchema(spec.Schema{Type: "object", Properties: map[string]spec.Schema{
+		"name": {Type: "string"},
+	}}, true)
+	assert.False(t, variant)
+}
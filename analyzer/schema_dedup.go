@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// componentRefPrefix is the JSON pointer prefix of component schema references
+const componentRefPrefix = "#/components/schemas/"
+
+// Descriptions of the placeholder schemas used when handler analysis is unavailable
+const (
+	FallbackRequestDescription  = "Generic request schema - AST analysis not available"
+	FallbackResponseDescription = "Generic response schema - AST analysis not available"
+)
+
+// isFallbackSchema reports whether a schema is a per-route placeholder rather than a real DTO
+func isFallbackSchema(schema spec.Schema) bool {
+	return schema.Description == FallbackRequestDescription || schema.Description == FallbackResponseDescription
+}
+
+// SchemaHash returns a structural hash of a schema, identical schemas hash to the same value
+//
+// encoding/json sorts map keys, so the hash does not depend on map iteration order.
+func SchemaHash(schema spec.Schema) string {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DeduplicateSchemas collapses structurally identical component schemas into a single component
+//
+// typeNamed maps Go type names to the schema generated from that type, and goTypes maps
+// component names to the Go type they were generated from. Schemas of different Go types are
+// never merged, even when identical: only components of the same type, and route schemas
+// without a known type, are. An untyped schema joins the group of a typed one when exactly
+// one Go type shares its structure. A group keeps the name of a preferred member (one that was
+// already named deliberately), else it is named after its Go type, or for untyped groups a Go
+// type with the same structure, when the name is free, otherwise after the first member in
+// sorted order. Fallback placeholders are never merged, each route keeps its own slot. The
+// returned renames map every component name that no longer exists to the component that
+// replaced it.
+func DeduplicateSchemas(schemas map[string]spec.Schema, typeNamed map[string]spec.Schema, preferred map[string]bool, goTypes map[string]reflect.Type) (map[string]spec.Schema, map[string]string) {
+	structures := make(map[string]string, len(schemas))
+	memberTypes := make(map[string]string, len(schemas))
+	typesByHash := make(map[string]map[string]reflect.Type)
+	for name, schema := range schemas {
+		hash := SchemaHash(schema)
+		if hash == "" || isFallbackSchema(schema) {
+			// Unhashable schemas and placeholders are never merged
+			hash = "name:" + name
+		}
+		structures[name] = hash
+		if identity, goType := typeIdentity(goTypes[name]); identity != "" {
+			memberTypes[name] = identity
+			if typesByHash[hash] == nil {
+				typesByHash[hash] = make(map[string]reflect.Type)
+			}
+			typesByHash[hash][identity] = goType
+		}
+	}
+
+	// A group is a structure plus the Go type it was generated from
+	groups := make(map[string][]string)
+	hashes := make(map[string]string, len(schemas))
+	groupTypes := make(map[string]reflect.Type)
+	for name, hash := range structures {
+		identity := memberTypes[name]
+		if identity == "" && len(typesByHash[hash]) == 1 {
+			for only := range typesByHash[hash] {
+				identity = only
+			}
+		}
+		group := hash
+		if identity != "" {
+			group += " " + identity
+			groupTypes[group] = typesByHash[hash][identity]
+		}
+		hashes[name] = group
+		groups[group] = append(groups[group], name)
+	}
+
+	typeNamesByHash := make(map[string][]string)
+	for typeName, schema := range typeNamed {
+		if typeName == "" {
+			continue
+		}
+		hash := SchemaHash(schema)
+		typeNamesByHash[hash] = append(typeNamesByHash[hash], typeName)
+	}
+
+	// Process groups in a stable order so naming is deterministic
+	orderedGroups := make([]string, 0, len(groups))
+	for group, members := range groups {
+		sort.Strings(members)
+		orderedGroups = append(orderedGroups, group)
+	}
+	sort.Slice(orderedGroups, func(i, j int) bool {
+		return groups[orderedGroups[i]][0] < groups[orderedGroups[j]][0]
+	})
+
+	deduplicated := make(map[string]spec.Schema, len(groups))
+	renames := make(map[string]string)
+	for _, group := range orderedGroups {
+		members := groups[group]
+		name := members[0]
+
+		var candidates []string
+		for _, member := range members {
+			if preferred[member] {
+				candidates = append(candidates, member)
+			}
+		}
+		if len(candidates) == 0 {
+			if goType, typed := groupTypes[group]; typed {
+				candidates = []string{GoTypeSchemaName(goType)}
+			} else {
+				candidates = typeNamesByHash[structures[members[0]]]
+				sort.Strings(candidates)
+			}
+		}
+		for _, candidate := range candidates {
+			_, taken := deduplicated[candidate]
+			// A component with this name but a different structure or type keeps its name
+			if owner, exists := hashes[candidate]; taken || (exists && owner != group) {
+				continue
+			}
+			name = candidate
+			break
+		}
+
+		deduplicated[name] = schemas[members[0]]
+		for _, member := range members {
+			if member != name {
+				renames[member] = name
+			}
+		}
+	}
+
+	// Components can reference each other, keep those references pointing at live names
+	if len(renames) > 0 {
+		for name, schema := range deduplicated {
+			RewriteSchemaRefs(&schema, renames)
+			deduplicated[name] = schema
+		}
+	}
+
+	return deduplicated, renames
+}
+
+// typeIdentity returns the package-qualified name of a named Go type, empty for unnamed types
+func typeIdentity(t reflect.Type) (string, reflect.Type) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return "", nil
+	}
+	return t.PkgPath() + "." + t.Name(), t
+}
+
+// RewriteSchemaRefs points component references in a schema, and every nested schema, at their new names
+//
+// Nested schemas, maps and slices can be shared with the registry's stored schemas, they are
+// replaced by rewritten copies so the stored schemas keep their references.
+func RewriteSchemaRefs(schema *spec.Schema, renames map[string]string) {
+	if schema == nil {
+		return
+	}
+	if strings.HasPrefix(schema.Ref, componentRefPrefix) {
+		if renamed, exists := renames[strings.TrimPrefix(schema.Ref, componentRefPrefix)]; exists {
+			schema.Ref = componentRefPrefix + renamed
+		}
+	}
+
+	if schema.Discriminator != nil {
+		discriminator := *schema.Discriminator
+		if discriminator.Mapping != nil {
+			discriminator.Mapping = make(map[string]string, len(schema.Discriminator.Mapping))
+			for value, ref := range schema.Discriminator.Mapping {
+				if renamed, exists := renames[strings.TrimPrefix(ref, componentRefPrefix)]; exists && strings.HasPrefix(ref, componentRefPrefix) {
+					ref = componentRefPrefix + renamed
+				}
+				discriminator.Mapping[value] = ref
+			}
+		}
+		schema.Discriminator = &discriminator
+	}
+
+	schema.AllOf = rewriteSchemaListRefs(schema.AllOf, renames)
+	schema.OneOf = rewriteSchemaListRefs(schema.OneOf, renames)
+	schema.AnyOf = rewriteSchemaListRefs(schema.AnyOf, renames)
+	schema.Not = rewriteNestedSchemaRefs(schema.Not, renames)
+	schema.Items = rewriteNestedSchemaRefs(schema.Items, renames)
+	schema.AdditionalProperties = rewriteNestedSchemaRefs(schema.AdditionalProperties, renames)
+	if schema.Properties != nil {
+		properties := make(map[string]spec.Schema, len(schema.Properties))
+		for key, property := range schema.Properties {
+			RewriteSchemaRefs(&property, renames)
+			properties[key] = property
+		}
+		schema.Properties = properties
+	}
+}
+
+// rewriteNestedSchemaRefs returns a rewritten copy of a nested schema, nil stays nil
+func rewriteNestedSchemaRefs(schema *spec.Schema, renames map[string]string) *spec.Schema {
+	if schema == nil {
+		return nil
+	}
+	rewritten := *schema
+	RewriteSchemaRefs(&rewritten, renames)
+	return &rewritten
+}
+
+// rewriteSchemaListRefs returns a rewritten copy of a list of schemas, nil stays nil
+func rewriteSchemaListRefs(list []spec.Schema, renames map[string]string) []spec.Schema {
+	if list == nil {
+		return nil
+	}
+	rewritten := make([]spec.Schema, len(list))
+	copy(rewritten, list)
+	for i := range rewritten {
+		RewriteSchemaRefs(&rewritten[i], renames)
+	}
+	return rewritten
+}
@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateSchemas(t *testing.T) {
+	user := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}}
+	other := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"id": {Type: "integer"}}}
+	list := spec.Schema{Type: "array", Items: &spec.Schema{Ref: "#/components/schemas/POST_usersrequest"}}
+
+	schemas, renames := DeduplicateSchemas(map[string]spec.Schema{
+		"POST_usersrequest":      user,
+		"PUT_users_idrequest":    user,
+		"GET_usersresponse":      list,
+		"GET_users_idresponse":   other,
+		"DELETE_users_idrequest": other,
+	}, map[string]spec.Schema{"User": user}, nil, nil)
+
+	assert.Len(t, schemas, 3)
+	assert.Contains(t, schemas, "User", "Groups are named after the matching Go type")
+	assert.Contains(t, schemas, "DELETE_users_idrequest", "Groups without a Go type keep the first name")
+	assert.Equal(t, "User", renames["POST_usersrequest"])
+	assert.Equal(t, "User", renames["PUT_users_idrequest"])
+	assert.Equal(t, "DELETE_users_idrequest", renames["GET_users_idresponse"])
+	assert.Equal(t, "#/components/schemas/User", schemas["GET_usersresponse"].Items.Ref, "References between components are rewritten")
+}
+
+func TestDeduplicateSchemasKeepsFallbacksAndNameConflicts(t *testing.T) {
+	fallback := spec.Schema{Type: "object", Description: FallbackResponseDescription}
+	user := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}}
+	account := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"email": {Type: "string"}}}
+
+	schemas, renames := DeduplicateSchemas(map[string]spec.Schema{
+		"GET_aresponse":  fallback,
+		"GET_bresponse":  fallback,
+		"User":           account,
+		"GET_meresponse": user,
+	}, map[string]spec.Schema{"User": user}, nil, nil)
+
+	assert.Len(t, schemas, 4)
+	assert.Empty(t, renames)
+	assert.Equal(t, account, schemas["User"], "An existing component with a different structure keeps its name")
+}
+
+func TestDeduplicateSchemasPreferredNames(t *testing.T) {
+	user := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}}
+
+	schemas, renames := DeduplicateSchemas(map[string]spec.Schema{
+		"ApiUser":             user,
+		"PUT_users_idrequest": user,
+	}, map[string]spec.Schema{"User": user}, map[string]bool{"ApiUser": true}, nil)
+
+	assert.Len(t, schemas, 1)
+	assert.Contains(t, schemas, "ApiUser", "Deliberately named components win over Go type names")
+	assert.Equal(t, "ApiUser", renames["PUT_users_idrequest"])
+}
+
+type dedupAccount struct {
+	Name string `json:"name"`
+}
+
+type dedupProfile struct {
+	Name string `json:"name"`
+}
+
+type dedupInvoice struct {
+	Total int `json:"total"`
+}
+
+func TestDeduplicateSchemasKeepsGoTypesApart(t *testing.T) {
+	name := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}}
+	total := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"total": {Type: "integer"}}}
+
+	schemas, renames := DeduplicateSchemas(map[string]spec.Schema{
+		"dedupAccount":          name,
+		"dedupProfile":          name,
+		"POST_accountsrequest":  name,
+		"GET_meresponse":        name,
+		"GET_invoicesresponse":  total,
+		"POST_invoicesresponse": total,
+	}, map[string]spec.Schema{"dedupAccount": name, "dedupProfile": name}, nil, map[string]reflect.Type{
+		"dedupAccount":         reflect.TypeOf(dedupAccount{}),
+		"dedupProfile":         reflect.TypeOf(dedupProfile{}),
+		"POST_accountsrequest": reflect.TypeOf(&dedupAccount{}),
+		"GET_invoicesresponse": reflect.TypeOf(dedupInvoice{}),
+	})
+
+	assert.Contains(t, schemas, "dedupAccount")
+	assert.Contains(t, schemas, "dedupProfile", "Identical schemas of different Go types are not merged")
+	assert.Equal(t, "dedupAccount", renames["POST_accountsrequest"], "Schemas of the same Go type are merged")
+	assert.Contains(t, schemas, "GET_meresponse", "An untyped schema matching several Go types stays on its own")
+	assert.Equal(t, "dedupInvoice", renames["GET_invoicesresponse"])
+	assert.Equal(t, "dedupInvoice", renames["POST_invoicesresponse"], "An untyped schema joins the only Go type with its structure")
+	assert.Len(t, schemas, 4)
+}
+
+func TestRewriteSchemaRefsLeavesSharedSchemasUntouched(t *testing.T) {
+	original := spec.Schema{
+		OneOf: []spec.Schema{{Ref: "#/components/schemas/dogRequest"}},
+		Discriminator: &spec.Discriminator{
+			PropertyName: "type",
+			Mapping:      map[string]string{"dog": "#/components/schemas/dogRequest"},
+		},
+		Items:      &spec.Schema{Ref: "#/components/schemas/dogRequest"},
+		Properties: map[string]spec.Schema{"pet": {Ref: "#/components/schemas/dogRequest"}},
+	}
+
+	rewritten := original
+	RewriteSchemaRefs(&rewritten, map[string]string{"dogRequest": "Dog"})
+
+	assert.Equal(t, "#/components/schemas/Dog", rewritten.OneOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/Dog", rewritten.Discriminator.Mapping["dog"])
+	assert.Equal(t, "#/components/schemas/Dog", rewritten.Items.Ref)
+	assert.Equal(t, "#/components/schemas/Dog", rewritten.Properties["pet"].Ref)
+
+	assert.Equal(t, "#/components/schemas/dogRequest", original.OneOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/dogRequest", original.Discriminator.Mapping["dog"])
+	assert.Equal(t, "#/components/schemas/dogRequest", original.Items.Ref)
+	assert.Equal(t, "#/components/schemas/dogRequest", original.Properties["pet"].Ref)
+}
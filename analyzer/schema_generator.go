@@ -1,34 +1,112 @@
 package analyzer
 
 import (
+	"encoding"
 	"fmt"
 	"go/ast"
+	"maps"
+	"mime/multipart"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/zainokta/openapi-gen/schemagen"
 	"github.com/zainokta/openapi-gen/spec"
 )
 
+// textMarshalerType is implemented by types that encode as JSON strings
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// timeType is documented in the generator's time format, see SetTimeFormat
+var timeType = reflect.TypeOf(time.Time{})
+
+// fileHeaderType is the type of multipart file fields
+var fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+
 // SchemaGenerator generates OpenAPI schemas from Go types using reflection
 type SchemaGenerator struct {
 	typeCache    map[reflect.Type]spec.Schema
 	processing   map[reflect.Type]bool // Prevent infinite recursion
 	maxDepth     int
 	currentDepth int
+	fieldDocs    bool                    // Describe properties with field doc comments in AST analysis
+	timeFormat   string                  // How time.Time values are serialized, see schemagen.TimeSchema
+	required     schemagen.RequiredRules // Which fields are required properties
+	unions       *UnionRegistry          // Interfaces documented as a oneOf of their variants
+	astTypes     ASTTypeResolver         // Finds the structs embedded in AST structs, nil when not set
+	defs         *jsonSchemaDefs         // Named structs become JSON Schema definitions, nil for OpenAPI schemas
 }
 
+// ASTTypeResolver finds the struct declared as typeName in the package pkgPath, for the
+// embedded fields of structs generated from source
+//
+// pkgPath is an import path, or empty for the package of the struct the generation started
+// from. The imports of the file declaring the struct are returned with it, found is false
+// when the type is unknown or not a struct.
+type ASTTypeResolver func(pkgPath, typeName string) (structType *ast.StructType, packageImports map[string]string, found bool)
+
 // NewSchemaGenerator creates a new schema generator
 func NewSchemaGenerator() *SchemaGenerator {
 	return &SchemaGenerator{
 		typeCache:  make(map[reflect.Type]spec.Schema),
 		processing: make(map[reflect.Type]bool),
 		maxDepth:   10, // Prevent deep recursion
+		fieldDocs:  true,
+		unions:     NewUnionRegistry(),
 	}
 }
 
+// Unions returns the unions this generator documents interfaces with
+func (sg *SchemaGenerator) Unions() *UnionRegistry {
+	return sg.unions
+}
+
+// SetUnions shares a union registry with this generator, e.g. the one of the schema registry
+//
+// Cached schemas may predate the unions and are dropped.
+func (sg *SchemaGenerator) SetUnions(unions *UnionRegistry) {
+	sg.unions = unions
+	sg.ClearCache()
+}
+
+// SetFieldDocs controls whether AST analysis describes properties with their field doc comments
+//
+// Enabled by default. A description tag always takes precedence over the comment.
+func (sg *SchemaGenerator) SetFieldDocs(enabled bool) {
+	sg.fieldDocs = enabled
+}
+
+// SetTimeFormat sets how time.Time values are documented when their field has no time_format
+// tag, see schemagen.TimeSchema; RFC 3339 strings by default
+//
+// Cached schemas may use the previous format and are dropped.
+func (sg *SchemaGenerator) SetTimeFormat(format string) {
+	sg.timeFormat = format
+	sg.ClearCache()
+}
+
+// SetRequiredRules sets which struct fields are required properties, see schemagen.RequiredRules
+//
+// Cached schemas may follow the previous rules and are dropped.
+func (sg *SchemaGenerator) SetRequiredRules(rules schemagen.RequiredRules) {
+	sg.required = rules
+	sg.ClearCache()
+}
+
+// SetASTTypeResolver sets how embedded types are found when generating schemas from source
+func (sg *SchemaGenerator) SetASTTypeResolver(resolver ASTTypeResolver) {
+	sg.astTypes = resolver
+}
+
 // GenerateSchemaFromType generates OpenAPI schema from Go type
 func (sg *SchemaGenerator) GenerateSchemaFromType(t reflect.Type) spec.Schema {
+	if sg.defs != nil {
+		if ref, isDef := sg.defs.reference(sg, t); isDef {
+			return ref
+		}
+	}
+
 	// Check cache first
 	if schema, exists := sg.typeCache[t]; exists {
 		return schema
@@ -87,35 +165,31 @@ func (sg *SchemaGenerator) generateSchema(t reflect.Type) spec.Schema {
 
 // handleBasicType handles Go basic types to OpenAPI types
 func (sg *SchemaGenerator) handleBasicType(t reflect.Type) spec.Schema {
-	switch t.Kind() {
-	case reflect.String:
-		return spec.Schema{Type: "string"}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return spec.Schema{Type: "integer"}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return spec.Schema{Type: "integer", Minimum: float64Ptr(0)}
-	case reflect.Float32, reflect.Float64:
-		return spec.Schema{Type: "number"}
-	case reflect.Bool:
-		return spec.Schema{Type: "boolean"}
-	}
-
-	// Handle special known types
-	if t == reflect.TypeOf(time.Time{}) {
-		return spec.Schema{
-			Type:   "string",
-			Format: "date-time",
+	if t == timeType {
+		return schemagen.TimeSchema(sg.timeFormat)
+	}
+
+	// Handle special known types such as time.Duration and multipart.FileHeader
+	if t.PkgPath() != "" {
+		if schema, known := schemagen.KnownType(t.PkgPath(), t.Name()); known {
+			return schema
 		}
 	}
 
+	// Kind names match the predeclared type names, e.g. "uint8"
+	if schema, basic := schemagen.BasicType(t.Kind().String()); basic {
+		return schema
+	}
+
 	return spec.Schema{} // Empty schema for unknown types
 }
 
-// handleStruct converts Go struct to OpenAPI object schema
+// handleStruct converts Go struct to OpenAPI object schema, titled with the name of a named struct
 func (sg *SchemaGenerator) handleStruct(t reflect.Type) spec.Schema {
 	schema := spec.Schema{
 		Type:       "object",
-		Properties: make(map[string]spec.Schema),
+		Title:      GoTypeSchemaName(t),
+		Properties: make(map[string]spec.Schema, t.NumField()),
 		Required:   []string{},
 	}
 
@@ -126,24 +200,37 @@ func (sg *SchemaGenerator) handleStruct(t reflect.Type) spec.Schema {
 		if !field.IsExported() {
 			continue
 		}
+		if field.Name == "XMLName" {
+			schema.XML = schemagen.XMLName(field.Tag) // The element encoding/xml renders the struct as
+		}
 
 		// Get field name from json tag or field name
-		fieldName := sg.getFieldName(field)
-		if fieldName == "-" {
+		fieldName := schemagen.FieldName(field.Name, field.Tag)
+		if isFileType(field.Type) {
+			// File fields are bound from multipart forms, so the form tag names them
+			if formName, _, _ := strings.Cut(field.Tag.Get("form"), ","); formName != "" && formName != "-" {
+				fieldName = formName
+			}
+		}
+		if fieldName == "" {
 			continue // Skip fields marked as ignored
 		}
 
 		// Generate schema for field type
 		fieldSchema := sg.GenerateSchemaFromType(field.Type)
+		if format := field.Tag.Get(schemagen.TimeFormatTag); format != "" && isTimeType(field.Type) {
+			fieldSchema = schemagen.TimeSchema(format)
+		}
 
 		// Extract field metadata from tags
-		sg.applyFieldTags(field, &fieldSchema)
+		schemagen.ApplyFieldTags(&fieldSchema, field.Tag)
+		fieldSchema.BindName = schemagen.BindName(fieldName, field.Name, field.Tag)
 
 		// Add to properties
 		schema.Properties[fieldName] = fieldSchema
 
 		// Check if field is required
-		if sg.isFieldRequired(field) {
+		if sg.required.IsRequired(field.Tag, field.Type.Kind() == reflect.Pointer) {
 			schema.Required = append(schema.Required, fieldName)
 		}
 	}
@@ -151,15 +238,76 @@ func (sg *SchemaGenerator) handleStruct(t reflect.Type) spec.Schema {
 	return schema
 }
 
+// isFileType reports whether t is a multipart file or a slice of them
+func isFileType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t == fileHeaderType
+}
+
+// isTimeType reports whether t is time.Time or a pointer to it
+func isTimeType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == timeType
+}
+
+// isTimeASTType reports whether a field type expression is time.Time or a pointer to it
+func isTimeASTType(expr ast.Expr, packageImports map[string]string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return isTimeASTType(star.X, packageImports)
+	}
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Time" {
+		return false
+	}
+	packageName, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	importPath := packageName.Name
+	if path, exists := packageImports[packageName.Name]; exists {
+		importPath = path
+	}
+	return importPath == "time"
+}
+
+// ContainsBinary reports whether a schema has a binary (file) property at any depth
+func ContainsBinary(schema spec.Schema) bool {
+	if schema.Type == "string" && schema.Format == "binary" {
+		return true
+	}
+	if schema.Items != nil && ContainsBinary(*schema.Items) {
+		return true
+	}
+	for _, property := range schema.Properties {
+		if ContainsBinary(property) {
+			return true
+		}
+	}
+	return false
+}
+
 // handleArray converts Go slice/array to OpenAPI array schema
 func (sg *SchemaGenerator) handleArray(t reflect.Type) spec.Schema {
 	itemType := t.Elem()
-	itemSchema := sg.GenerateSchemaFromType(itemType)
 
-	return spec.Schema{
-		Type:  "array",
-		Items: &itemSchema,
+	// Byte slices are sent as base64 strings
+	if t.Kind() == reflect.Slice && itemType.Kind() == reflect.Uint8 {
+		return schemagen.Bytes()
+	}
+
+	if t.Kind() == reflect.Array {
+		// Arrays that marshal as text, e.g. UUIDs, are strings on the wire
+		if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+			return spec.Schema{Type: "string"}
+		}
+		return schemagen.ArrayOfLength(sg.GenerateSchemaFromType(itemType), t.Len())
 	}
+
+	return schemagen.ArrayOf(sg.GenerateSchemaFromType(itemType))
 }
 
 // handleMap converts Go map to OpenAPI object schema
@@ -167,182 +315,219 @@ func (sg *SchemaGenerator) handleMap(t reflect.Type) spec.Schema {
 	valueType := t.Elem()
 	valueSchema := sg.GenerateSchemaFromType(valueType)
 
-	return spec.Schema{
-		Type:                 "object",
-		AdditionalProperties: &valueSchema,
+	// Unnamed key types are documented by kind, named ones (e.g. type Currency string) by name
+	keyType := t.Key()
+	if keyType.PkgPath() == "" {
+		return schemagen.MapWithKeys(keyType.Kind().String(), valueSchema)
 	}
+	return schemagen.MapWithKeys(GoTypeSchemaName(keyType), valueSchema)
 }
 
 // handleInterface handles interface types
 func (sg *SchemaGenerator) handleInterface(t reflect.Type) spec.Schema {
+	// Interfaces declared in the union registry become a oneOf of their variants
+	if union, exists := sg.unions.Lookup(t); exists {
+		if sg.defs != nil {
+			return sg.defs.union(sg, union)
+		}
+		return union.Schema()
+	}
 	return spec.Schema{
 		Type:        "object",
 		Description: fmt.Sprintf("Interface type: %s", t.String()),
 	}
 }
 
-// getFieldName extracts field name from json tag or uses struct field name
-func (sg *SchemaGenerator) getFieldName(field reflect.StructField) string {
-	tag := field.Tag.Get("json")
-	if tag == "" {
-		return sg.toSnakeCase(field.Name)
-	}
-
-	// Parse json tag (e.g., "field_name,omitempty")
-	parts := strings.Split(tag, ",")
-	if len(parts) > 0 && parts[0] != "" {
-		return parts[0]
-	}
-
-	return sg.toSnakeCase(field.Name)
+// GenerateSchemaFromStructAST generates OpenAPI schema directly from AST struct type
+//
+// Embedded structs are flattened like encoding/json does, their fields are promoted into the
+// schema unless the embedded field is named by a tag. Embedded types are found through the
+// resolver set with SetASTTypeResolver, those it cannot find are left out.
+func (sg *SchemaGenerator) GenerateSchemaFromStructAST(structType *ast.StructType, packageImports map[string]string) spec.Schema {
+	schema, _ := sg.generateSchemaFromStructAST(structType, packageImports, "", make(map[string]bool))
+	return schema
 }
 
-// applyFieldTags applies struct tag information to schema
-func (sg *SchemaGenerator) applyFieldTags(field reflect.StructField, schema *spec.Schema) {
-	// Apply validation tags
-	if validateTag := field.Tag.Get("validate"); validateTag != "" {
-		sg.applyValidationTags(validateTag, schema)
-	}
+// promotedField is a property promoted from an embedded struct
+type promotedField struct {
+	schema    spec.Schema
+	required  bool
+	depth     int  // Number of embeddings the field is promoted through
+	ambiguous bool // Promoted at the same depth by several embedded structs
+}
 
-	// Apply example from tag
-	if example := field.Tag.Get("example"); example != "" {
-		schema.Example = example
+// generateSchemaFromStructAST walks the fields of a struct declared in the package pkgPath,
+// empty for the package of the struct GenerateSchemaFromStructAST started from
+//
+// The depth of every property is returned with the schema, 0 for the struct's own fields.
+// embedding holds the embedded types being promoted, which stops embedding cycles.
+func (sg *SchemaGenerator) generateSchemaFromStructAST(structType *ast.StructType, packageImports map[string]string, pkgPath string, embedding map[string]bool) (spec.Schema, map[string]int) {
+	schema := spec.Schema{
+		Type:       "object",
+		Properties: make(map[string]spec.Schema),
+		Required:   []string{},
 	}
+	depths := make(map[string]int)
 
-	// Apply description from tag
-	if desc := field.Tag.Get("description"); desc != "" {
-		schema.Description = desc
+	if structType.Fields == nil {
+		return schema, depths
 	}
-}
 
-// applyValidationTags applies validation rules to schema
-func (sg *SchemaGenerator) applyValidationTags(validateTag string, schema *spec.Schema) {
-	rules := strings.Split(validateTag, ",")
-	for _, rule := range rules {
-		rule = strings.TrimSpace(rule)
+	// Fields promoted from embedded structs, in declaration order
+	promoted := make(map[string]*promotedField)
+	var promotedOrder []string
 
-		if rule == "required" {
-			// Required is handled at struct level
-			continue
-		}
+	for _, field := range structType.Fields.List {
+		tag := schemagen.FieldTag(field)
 
-		if strings.HasPrefix(rule, "min=") {
-			// Handle min length/value
-			if val := strings.TrimPrefix(rule, "min="); val != "" {
-				switch schema.Type {
-				case "string":
-					if minLen := parseInt(val); minLen >= 0 {
-						schema.MinLength = &minLen
-					}
-				case "integer", "number":
-					if minVal := parseFloat(val); minVal != nil {
-						schema.Minimum = minVal
+		if len(field.Names) == 0 {
+			// An embedded field named by its tag is a regular property
+			if fieldName := schemagen.FieldName("", tag); fieldName != "" {
+				sg.addASTField(&schema, field, fieldName, embeddedTypeName(field.Type), tag, packageImports)
+				continue
+			}
+			if tag.Get("json") == "-" {
+				continue
+			}
+			embedded, embeddedDepths, found := sg.embeddedStructAST(field.Type, packageImports, pkgPath, embedding)
+			if !found {
+				continue
+			}
+			for _, fieldName := range slices.Sorted(maps.Keys(embedded.Properties)) {
+				depth := embeddedDepths[fieldName] + 1
+				switch existing := promoted[fieldName]; {
+				case existing == nil:
+					promotedOrder = append(promotedOrder, fieldName)
+					fallthrough
+				case depth < existing.depth:
+					promoted[fieldName] = &promotedField{
+						schema:   embedded.Properties[fieldName],
+						required: slices.Contains(embedded.Required, fieldName),
+						depth:    depth,
 					}
+				case depth == existing.depth:
+					existing.ambiguous = true
 				}
 			}
+			continue
 		}
 
-		if strings.HasPrefix(rule, "max=") {
-			// Handle max length/value
-			if val := strings.TrimPrefix(rule, "max="); val != "" {
-				switch schema.Type {
-				case "string":
-					if maxLen := parseInt(val); maxLen >= 0 {
-						schema.MaxLength = &maxLen
-					}
-				case "integer", "number":
-					if maxVal := parseFloat(val); maxVal != nil {
-						schema.Maximum = maxVal
-					}
-				}
+		for _, name := range field.Names {
+			// Skip unexported fields (those starting with lowercase)
+			if !name.IsExported() {
+				continue
+			}
+			if name.Name == "XMLName" {
+				schema.XML = schemagen.XMLName(tag) // The element encoding/xml renders the struct as
 			}
-		}
 
-		if rule == "email" && schema.Type == "string" {
-			schema.Format = "email"
+			// Get field name from json tag or field name
+			fieldName := schemagen.FieldName(name.Name, tag)
+			if fieldName == "" {
+				continue // Skip fields marked as ignored
+			}
+			sg.addASTField(&schema, field, fieldName, name.Name, tag, packageImports)
 		}
 	}
-}
-
-// isFieldRequired checks if field is required based on validate tag
-func (sg *SchemaGenerator) isFieldRequired(field reflect.StructField) bool {
-	validateTag := field.Tag.Get("validate")
-	return strings.Contains(validateTag, "required")
-}
 
-// toSnakeCase converts PascalCase to snake_case
-func (sg *SchemaGenerator) toSnakeCase(s string) string {
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && ('A' <= r && r <= 'Z') {
-			result.WriteRune('_')
+	// The struct's own fields win over promoted ones, ambiguous fields are dropped
+	for _, fieldName := range promotedOrder {
+		field := promoted[fieldName]
+		if _, exists := schema.Properties[fieldName]; exists || field.ambiguous {
+			continue
+		}
+		schema.Properties[fieldName] = field.schema
+		depths[fieldName] = field.depth
+		if field.required {
+			schema.Required = append(schema.Required, fieldName)
 		}
-		result.WriteRune(r)
 	}
-	return strings.ToLower(result.String())
+
+	return schema, depths
 }
 
-// Helper functions
+// addASTField adds the property of a struct field to schema
+func (sg *SchemaGenerator) addASTField(schema *spec.Schema, field *ast.Field, fieldName, goName string, tag reflect.StructTag, packageImports map[string]string) {
+	// Generate schema for field type using AST
+	fieldSchema := sg.generateSchemaFromASTType(field.Type, packageImports)
+	if format := tag.Get(schemagen.TimeFormatTag); format != "" && isTimeASTType(field.Type, packageImports) {
+		fieldSchema = schemagen.TimeSchema(format)
+	}
+	if doc := schemagen.FieldDoc(field); sg.fieldDocs && doc != "" {
+		fieldSchema.Description = doc
+	}
 
-func float64Ptr(v float64) *float64 {
-	return &v
-}
+	// Extract field metadata from tags
+	schemagen.ApplyFieldTags(&fieldSchema, tag)
+	fieldSchema.BindName = schemagen.BindName(fieldName, goName, tag)
 
-func parseInt(s string) int {
-	var result int
-	fmt.Sscanf(s, "%d", &result)
-	return result
-}
+	// Add to properties
+	schema.Properties[fieldName] = fieldSchema
 
-func parseFloat(s string) *float64 {
-	var result float64
-	if n, err := fmt.Sscanf(s, "%f", &result); n > 0 && err == nil {
-		return &result
+	// Check if field is required
+	if _, pointer := field.Type.(*ast.StarExpr); sg.required.IsRequired(tag, pointer) {
+		schema.Required = append(schema.Required, fieldName)
 	}
-	return nil
 }
 
-// GenerateSchemaFromStructAST generates OpenAPI schema directly from AST struct type
-func (sg *SchemaGenerator) GenerateSchemaFromStructAST(structType *ast.StructType, packageImports map[string]string) spec.Schema {
-	schema := spec.Schema{
-		Type:       "object",
-		Properties: make(map[string]spec.Schema),
-		Required:   []string{},
+// embeddedStructAST generates the schema of an embedded struct, Base or *dto.Base, with the
+// depths of its properties
+func (sg *SchemaGenerator) embeddedStructAST(typeExpr ast.Expr, packageImports map[string]string, pkgPath string, embedding map[string]bool) (spec.Schema, map[string]int, bool) {
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
 	}
 
-	if structType.Fields == nil {
-		return schema
+	typeName := ""
+	switch t := typeExpr.(type) {
+	case *ast.Ident:
+		typeName = t.Name
+	case *ast.SelectorExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			return spec.Schema{}, nil, false
+		}
+		typeName = t.Sel.Name
+		pkgPath = ident.Name
+		if path, exists := packageImports[ident.Name]; exists {
+			pkgPath = path
+		}
+	default:
+		return spec.Schema{}, nil, false
 	}
 
-	for _, field := range structType.Fields.List {
-		// Skip unexported fields (those starting with lowercase)
-		for _, name := range field.Names {
-			if !name.IsExported() {
-				continue
-			}
-
-			// Get field name from json tag or field name
-			fieldName := sg.getFieldNameFromAST(field)
-			if fieldName == "-" {
-				continue // Skip fields marked as ignored
-			}
-
-			// Generate schema for field type using AST
-			fieldSchema := sg.generateSchemaFromASTType(field.Type, packageImports)
+	key := pkgPath + "." + typeName
+	if sg.astTypes == nil || embedding[key] {
+		return spec.Schema{}, nil, false
+	}
+	structType, imports, found := sg.astTypes(pkgPath, typeName)
+	if !found {
+		return spec.Schema{}, nil, false
+	}
 
-			// Extract field metadata from tags
-			sg.applyFieldTagsFromAST(field, &fieldSchema)
+	embedding[key] = true
+	defer delete(embedding, key)
+	schema, depths := sg.generateSchemaFromStructAST(structType, imports, pkgPath, embedding)
+	return schema, depths, true
+}
 
-			// Add to properties
-			schema.Properties[fieldName] = fieldSchema
+// embeddedTypeName returns the Go name of an embedded field, "Base" for *dto.Base
+func embeddedTypeName(typeExpr ast.Expr) string {
+	name := astTypeName(typeExpr)
+	return name[strings.LastIndex(name, ".")+1:]
+}
 
-			// Check if field is required
-			if sg.isFieldRequiredFromAST(field) {
-				schema.Required = append(schema.Required, fieldName)
-			}
-		}
+// GenerateSchemaFromTypeSpecAST generates OpenAPI schema from an AST type declaration, described by its doc comment
+//
+// decl is the declaration holding typeSpec, it carries the doc comment of ungrouped declarations.
+func (sg *SchemaGenerator) GenerateSchemaFromTypeSpecAST(decl *ast.GenDecl, typeSpec *ast.TypeSpec, packageImports map[string]string) spec.Schema {
+	var schema spec.Schema
+	if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+		schema = sg.GenerateSchemaFromStructAST(structType, packageImports)
+	} else {
+		schema = sg.generateSchemaFromASTType(typeSpec.Type, packageImports)
 	}
 
+	schemagen.ApplyTypeDoc(&schema, typeSpec.Name.Name, schemagen.TypeDoc(decl, typeSpec))
 	return schema
 }
 
@@ -360,22 +545,23 @@ func (sg *SchemaGenerator) generateSchemaFromASTType(typeExpr ast.Expr, packageI
 			return sg.handlePackageTypeFromAST(packageName, typeName, packageImports)
 		}
 	case *ast.ArrayType:
-		// Handle []Type
-		itemSchema := sg.generateSchemaFromASTType(t.Elt, packageImports)
-		return spec.Schema{
-			Type:  "array",
-			Items: &itemSchema,
+		// Handle []Type and [N]Type
+		if length, fixed := schemagen.ArrayLength(t); fixed {
+			return schemagen.ArrayOfLength(sg.generateSchemaFromASTType(t.Elt, packageImports), length)
+		}
+		if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && schemagen.IsByteType(ident.Name) {
+			return schemagen.Bytes()
 		}
+		return schemagen.ArrayOf(sg.generateSchemaFromASTType(t.Elt, packageImports))
 	case *ast.StarExpr:
 		// Handle *Type (pointer types)
 		return sg.generateSchemaFromASTType(t.X, packageImports)
 	case *ast.MapType:
-		// Handle map[string]Type
-		valueSchema := sg.generateSchemaFromASTType(t.Value, packageImports)
-		return spec.Schema{
-			Type:                 "object",
-			AdditionalProperties: &valueSchema,
-		}
+		// Handle map[K]Type
+		return schemagen.MapWithKeys(astTypeName(t.Key), sg.generateSchemaFromASTType(t.Value, packageImports))
+	case *ast.StructType:
+		// Handle anonymous inline structs, e.g. Address struct { City string }
+		return sg.GenerateSchemaFromStructAST(t, packageImports)
 	}
 
 	// Fallback for unknown types
@@ -385,32 +571,41 @@ func (sg *SchemaGenerator) generateSchemaFromASTType(typeExpr ast.Expr, packageI
 	}
 }
 
+// astTypeName returns the name of a type expression as written, e.g. "int" or "dto.Currency", empty when unnamed
+func astTypeName(typeExpr ast.Expr) string {
+	switch t := typeExpr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return ident.Name + "." + t.Sel.Name
+		}
+	case *ast.StarExpr:
+		return astTypeName(t.X)
+	}
+	return ""
+}
+
 // handleBasicASTType handles built-in Go types from AST
 func (sg *SchemaGenerator) handleBasicASTType(typeName string) spec.Schema {
-	switch typeName {
-	case "string":
-		return spec.Schema{Type: "string"}
-	case "int", "int8", "int16", "int32", "int64":
-		return spec.Schema{Type: "integer"}
-	case "uint", "uint8", "uint16", "uint32", "uint64":
-		return spec.Schema{Type: "integer", Minimum: float64Ptr(0)}
-	case "float32", "float64":
-		return spec.Schema{Type: "number"}
-	case "bool":
-		return spec.Schema{Type: "boolean"}
-	default:
-		return spec.Schema{Type: "object", Description: "Unknown basic type: " + typeName}
+	if schema, basic := schemagen.BasicType(typeName); basic {
+		return schema
 	}
+	return spec.Schema{Type: "object", Description: "Unknown basic type: " + typeName}
 }
 
 // handlePackageTypeFromAST handles package.Type expressions from AST
 func (sg *SchemaGenerator) handlePackageTypeFromAST(packageName, typeName string, packageImports map[string]string) spec.Schema {
-	// Handle known special types
-	if packageName == "time" && typeName == "Time" {
-		return spec.Schema{
-			Type:   "string",
-			Format: "date-time",
-		}
+	// Handle known special types, resolving the import alias when there is one
+	importPath := packageName
+	if path, exists := packageImports[packageName]; exists {
+		importPath = path
+	}
+	if importPath == "time" && typeName == "Time" {
+		return schemagen.TimeSchema(sg.timeFormat)
+	}
+	if schema, known := schemagen.KnownType(importPath, typeName); known {
+		return schema
 	}
 
 	// For other package types, we would need to recursively parse them
@@ -421,88 +616,16 @@ func (sg *SchemaGenerator) handlePackageTypeFromAST(packageName, typeName string
 	}
 }
 
-// getFieldNameFromAST extracts field name from json tag or uses struct field name
-func (sg *SchemaGenerator) getFieldNameFromAST(field *ast.Field) string {
-	if field.Tag != nil {
-		tagValue := strings.Trim(field.Tag.Value, "`")
-
-		// Parse struct tags to find json tag
-		tags := parseStructTag(tagValue)
-		if jsonTag, exists := tags["json"]; exists {
-			parts := strings.Split(jsonTag, ",")
-			if len(parts) > 0 && parts[0] != "" {
-				return parts[0]
-			}
+// WarmUp generates and caches the schemas of types ahead of spec generation
+//
+// Call it at init with the request and response types of large services, so the first
+// GenerateSpec does not pay for walking every struct.
+func (sg *SchemaGenerator) WarmUp(types []reflect.Type) {
+	for _, t := range types {
+		if t != nil {
+			sg.GenerateSchemaFromType(t)
 		}
 	}
-
-	// Use the field name if no json tag
-	if len(field.Names) > 0 {
-		return sg.toSnakeCase(field.Names[0].Name)
-	}
-
-	return ""
-}
-
-// applyFieldTagsFromAST applies struct tag information to schema from AST
-func (sg *SchemaGenerator) applyFieldTagsFromAST(field *ast.Field, schema *spec.Schema) {
-	if field.Tag == nil {
-		return
-	}
-
-	tagValue := strings.Trim(field.Tag.Value, "`")
-	tags := parseStructTag(tagValue)
-
-	// Apply validation tags
-	if validateTag, exists := tags["validate"]; exists {
-		sg.applyValidationTags(validateTag, schema)
-	}
-
-	// Apply example from tag
-	if example, exists := tags["example"]; exists {
-		schema.Example = example
-	}
-
-	// Apply description from tag
-	if desc, exists := tags["description"]; exists {
-		schema.Description = desc
-	}
-}
-
-// isFieldRequiredFromAST checks if field is required based on validate tag from AST
-func (sg *SchemaGenerator) isFieldRequiredFromAST(field *ast.Field) bool {
-	if field.Tag == nil {
-		return false
-	}
-
-	tagValue := strings.Trim(field.Tag.Value, "`")
-	tags := parseStructTag(tagValue)
-
-	if validateTag, exists := tags["validate"]; exists {
-		return strings.Contains(validateTag, "required")
-	}
-
-	return false
-}
-
-// parseStructTag parses struct tag string into a map
-func parseStructTag(tag string) map[string]string {
-	result := make(map[string]string)
-
-	// Simple tag parser - splits on spaces and key:value pairs
-	parts := strings.Fields(tag)
-	for _, part := range parts {
-		if strings.Contains(part, ":") {
-			keyValue := strings.SplitN(part, ":", 2)
-			if len(keyValue) == 2 {
-				key := keyValue[0]
-				value := strings.Trim(keyValue[1], "\"")
-				result[key] = value
-			}
-		}
-	}
-
-	return result
 }
 
 // ClearCache clears the type cache (useful for testing)
@@ -0,0 +1,450 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"mime/multipart"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zainokta/openapi-gen/schemagen"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestSchemaGenerator_FileHeader(t *testing.T) {
+	type uploadRequest struct {
+		Title       string                  `json:"title" form:"title"`
+		Avatar      *multipart.FileHeader   `form:"avatar" json:"-" validate:"required"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+	}
+
+	schema := NewSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(uploadRequest{}))
+
+	if assert.Contains(t, schema.Properties, "avatar") {
+		assert.Equal(t, "string", schema.Properties["avatar"].Type)
+		assert.Equal(t, "binary", schema.Properties["avatar"].Format)
+	}
+	if assert.Contains(t, schema.Properties, "attachments") {
+		assert.Equal(t, "array", schema.Properties["attachments"].Type)
+		assert.Equal(t, "binary", schema.Properties["attachments"].Items.Format)
+	}
+	assert.Contains(t, schema.Required, "avatar")
+	assert.True(t, ContainsBinary(schema))
+	assert.False(t, ContainsBinary(schema.Properties["title"]))
+}
+
+// untitled drops the title of the schema of a named struct, struct expressions have no name
+func untitled(schema spec.Schema) spec.Schema {
+	schema.Title = ""
+	return schema
+}
+
+func TestSchemaGenerator_ASTMatchesReflection(t *testing.T) {
+	type profileRequest struct {
+		Name     string    `json:"name" validate:"required,min=2"`
+		Email    string    `json:"email" validate:"required,email"`
+		Age      uint8     `json:"age,omitempty" validate:"max=150"`
+		Tags     []string  `json:"tags"`
+		Birthday time.Time `json:"birthday"`
+		Page     int       `form:"page"`
+		Secret   string    `json:"-"`
+	}
+	source := "struct {\n" +
+		"Name string `json:\"name\" validate:\"required,min=2\"`\n" +
+		"Email string `json:\"email\" validate:\"required,email\"`\n" +
+		"Age uint8 `json:\"age,omitempty\" validate:\"max=150\"`\n" +
+		"Tags []string `json:\"tags\"`\n" +
+		"Birthday time.Time `json:\"birthday\"`\n" +
+		"Page int `form:\"page\"`\n" +
+		"Secret string `json:\"-\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	fromReflection := generator.GenerateSchemaFromType(reflect.TypeOf(profileRequest{}))
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), map[string]string{"time": "time"})
+
+	assert.Equal(t, "profileRequest", fromReflection.Title)
+	assert.Equal(t, untitled(fromReflection), fromAST)
+	assert.Equal(t, []string{"name", "email"}, fromAST.Required)
+	assert.NotContains(t, fromAST.Properties, "secret")
+}
+
+func TestSchemaGenerator_TimeFormat(t *testing.T) {
+	type event struct {
+		At        time.Time  `json:"at"`
+		CreatedAt *time.Time `json:"created_at" time_format:"unixmilli"`
+		Day       time.Time  `json:"day" time_format:"2006-01-02"`
+		Name      string     `json:"name" time_format:"unix"`
+	}
+	source := "struct {\n" +
+		"At t.Time `json:\"at\"`\n" +
+		"CreatedAt *t.Time `json:\"created_at\" time_format:\"unixmilli\"`\n" +
+		"Day t.Time `json:\"day\" time_format:\"2006-01-02\"`\n" +
+		"Name string `json:\"name\" time_format:\"unix\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	generator.SetTimeFormat("unix")
+	fromReflection := generator.GenerateSchemaFromType(reflect.TypeOf(event{}))
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), map[string]string{"t": "time"})
+	assert.Equal(t, untitled(fromReflection), fromAST)
+
+	// The generator's format applies to times without a tag, the tag wins for its field
+	assert.Equal(t, "integer", fromAST.Properties["at"].Type)
+	assert.Equal(t, "Unix time in seconds", fromAST.Properties["at"].Description)
+	assert.Equal(t, "Unix time in milliseconds", fromAST.Properties["created_at"].Description)
+	assert.Equal(t, spec.Schema{Type: "string", Format: "date"}, fromAST.Properties["day"])
+	assert.Equal(t, spec.Schema{Type: "string"}, fromAST.Properties["name"], "only time fields follow the tag")
+
+	generator.SetTimeFormat("")
+	assert.Equal(t, "date-time", generator.GenerateSchemaFromType(reflect.TypeOf(event{})).Properties["at"].Format)
+}
+
+func TestSchemaGenerator_JSONStringOption(t *testing.T) {
+	type account struct {
+		ID      int64   `json:"id,string"`
+		Balance float64 `json:"balance,string,omitempty"`
+		Active  bool    `json:"active,string"`
+		Scores  []int   `json:"scores,string"`
+	}
+	source := "struct {\n" +
+		"ID int64 `json:\"id,string\"`\n" +
+		"Balance float64 `json:\"balance,string,omitempty\"`\n" +
+		"Active bool `json:\"active,string\"`\n" +
+		"Scores []int `json:\"scores,string\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	fromReflection := generator.GenerateSchemaFromType(reflect.TypeOf(account{}))
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+	assert.Equal(t, untitled(fromReflection), fromAST)
+
+	assert.Equal(t, "string", fromAST.Properties["id"].Type)
+	assert.Equal(t, "string", fromAST.Properties["balance"].Type)
+	assert.Equal(t, []string{"true", "false"}, fromAST.Properties["active"].Enum)
+	assert.Equal(t, "array", fromAST.Properties["scores"].Type, "encoding/json ignores the option for slices")
+}
+
+func TestSchemaGenerator_XMLTags(t *testing.T) {
+	type invoice struct {
+		XMLName xml.Name `json:"-" xml:"invoice"`
+		ID      string   `json:"id" xml:"id,attr"`
+		Lines   []string `json:"lines" xml:"lines>line"`
+	}
+	source := "struct {\n" +
+		"XMLName xml.Name `json:\"-\" xml:\"invoice\"`\n" +
+		"ID string `json:\"id\" xml:\"id,attr\"`\n" +
+		"Lines []string `json:\"lines\" xml:\"lines>line\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	fromReflection := generator.GenerateSchemaFromType(reflect.TypeOf(invoice{}))
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), map[string]string{"xml": "encoding/xml"})
+	assert.Equal(t, untitled(fromReflection), fromAST)
+
+	assert.Equal(t, &spec.XML{Name: "invoice"}, fromAST.XML)
+	assert.Equal(t, &spec.XML{Name: "id", Attribute: true}, fromAST.Properties["id"].XML)
+	assert.Equal(t, &spec.XML{Name: "lines", Wrapped: true}, fromAST.Properties["lines"].XML)
+	assert.Equal(t, &spec.XML{Name: "line"}, fromAST.Properties["lines"].Items.XML)
+	assert.NotContains(t, fromAST.Properties, "xml_name")
+}
+
+func TestSchemaGenerator_RequiredRules(t *testing.T) {
+	type order struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name,omitempty" validate:"required"`
+		Coupon *string `json:"coupon" binding:"required"`
+		Note   string  `json:"note" openapi:"required"`
+		Tags   []int   `json:"tags" validate:"dive,required"`
+	}
+	source := "struct {\n" +
+		"ID string `json:\"id\"`\n" +
+		"Name string `json:\"name,omitempty\" validate:\"required\"`\n" +
+		"Coupon *string `json:\"coupon\" binding:\"required\"`\n" +
+		"Note string `json:\"note\" openapi:\"required\"`\n" +
+		"Tags []int `json:\"tags\" validate:\"dive,required\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	fromReflection := generator.GenerateSchemaFromType(reflect.TypeOf(order{}))
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+	assert.Equal(t, untitled(fromReflection), fromAST)
+	assert.Equal(t, []string{"name", "coupon", "note"}, fromAST.Required)
+
+	generator.SetRequiredRules(schemagen.RequiredRules{Tags: []string{"validate"}, PointersOptional: true})
+	fromReflection = generator.GenerateSchemaFromType(reflect.TypeOf(order{}))
+	fromAST = generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+	assert.Equal(t, untitled(fromReflection), fromAST)
+	assert.Equal(t, []string{"name", "note"}, fromAST.Required)
+}
+
+func TestSchemaGenerator_ASTFieldDocs(t *testing.T) {
+	source := "package dto\n\ntype LoginRequest struct {\n" +
+		"\t// Email is the user's login email\n\tEmail string `json:\"email\"`\n" +
+		"\t// Ignored in favour of the tag\n\tPassword string `json:\"password\" description:\"Account password\"`\n}\n"
+	file, err := parser.ParseFile(token.NewFileSet(), "dto.go", source, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+	structType := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType)
+
+	generator := NewSchemaGenerator()
+	schema := generator.GenerateSchemaFromStructAST(structType, nil)
+	assert.Equal(t, "Email is the user's login email", schema.Properties["email"].Description)
+	assert.Equal(t, "Account password", schema.Properties["password"].Description)
+
+	generator.SetFieldDocs(false)
+	schema = generator.GenerateSchemaFromStructAST(structType, nil)
+	assert.Empty(t, schema.Properties["email"].Description)
+	assert.Equal(t, "Account password", schema.Properties["password"].Description)
+}
+
+func TestSchemaGenerator_ASTTypeDocs(t *testing.T) {
+	source := "package dto\n\n// LoginRequest is the payload for POST /login.\ntype LoginRequest struct {\n\tEmail string `json:\"email\"`\n}\n"
+	file, err := parser.ParseFile(token.NewFileSet(), "dto.go", source, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+	decl := file.Decls[0].(*ast.GenDecl)
+
+	schema := NewSchemaGenerator().GenerateSchemaFromTypeSpecAST(decl, decl.Specs[0].(*ast.TypeSpec), nil)
+	assert.Equal(t, "object", schema.Type)
+	assert.Contains(t, schema.Properties, "email")
+	assert.Equal(t, "LoginRequest", schema.Title)
+	assert.Equal(t, "LoginRequest is the payload for POST /login.", schema.Description)
+}
+
+type testCurrency string
+
+func TestSchemaGenerator_MapKeys(t *testing.T) {
+	type balances struct {
+		ByCurrency map[testCurrency]float64 `json:"by_currency"`
+		ByYear     map[int]string           `json:"by_year"`
+		Labels     map[string]string        `json:"labels"`
+	}
+
+	schema := NewSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(balances{}))
+	assert.Equal(t, "testCurrency", schema.Properties["by_currency"].XKeyType)
+	assert.Equal(t, "number", schema.Properties["by_currency"].AdditionalProperties.Type)
+	assert.Equal(t, "integer", schema.Properties["by_year"].XKeyType)
+	assert.Empty(t, schema.Properties["labels"].XKeyType)
+
+	expr, err := parser.ParseExpr("struct {\nByCurrency map[money.Currency]float64 `json:\"by_currency\"`\nByYear map[int]string `json:\"by_year\"`\n}")
+	if !assert.NoError(t, err) {
+		return
+	}
+	fromAST := NewSchemaGenerator().GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+	assert.Equal(t, "Currency", fromAST.Properties["by_currency"].XKeyType)
+	assert.Equal(t, "integer", fromAST.Properties["by_year"].XKeyType)
+}
+
+type testUUID [16]byte
+
+func (u testUUID) MarshalText() ([]byte, error) {
+	return []byte("00000000-0000-0000-0000-000000000000"), nil
+}
+
+func TestSchemaGenerator_Arrays(t *testing.T) {
+	type payload struct {
+		Point    [2]float64 `json:"point"`
+		Checksum []byte     `json:"checksum"`
+		Digest   [4]byte    `json:"digest"`
+		ID       testUUID   `json:"id"`
+		Names    []string   `json:"names"`
+	}
+
+	schema := NewSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(payload{}))
+
+	point := schema.Properties["point"]
+	assert.Equal(t, "array", point.Type)
+	assert.Equal(t, 2, *point.MinItems)
+	assert.Equal(t, 2, *point.MaxItems)
+	assert.Equal(t, "number", point.Items.Type)
+
+	assert.Equal(t, spec.Schema{Type: "string", Format: "byte"}, schema.Properties["checksum"])
+	assert.Equal(t, 4, *schema.Properties["digest"].MaxItems)
+	assert.Equal(t, "string", schema.Properties["id"].Type)
+	assert.Nil(t, schema.Properties["names"].MaxItems)
+
+	expr, err := parser.ParseExpr("struct {\nPoint [2]float64 `json:\"point\"`\nChecksum []byte `json:\"checksum\"`\n}")
+	if !assert.NoError(t, err) {
+		return
+	}
+	fromAST := NewSchemaGenerator().GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+	assert.Equal(t, point, fromAST.Properties["point"])
+	assert.Equal(t, schema.Properties["checksum"], fromAST.Properties["checksum"])
+}
+
+func TestSchemaGenerator_ASTAnonymousStructs(t *testing.T) {
+	type shipment struct {
+		Address struct {
+			City string `json:"city" validate:"required"`
+			Geo  struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"geo"`
+		} `json:"address"`
+		Parcels []struct {
+			Weight int `json:"weight"`
+		} `json:"parcels"`
+	}
+	source := "struct {\n" +
+		"Address struct {\nCity string `json:\"city\" validate:\"required\"`\nGeo struct {\nLat float64 `json:\"lat\"`\nLng float64 `json:\"lng\"`\n} `json:\"geo\"`\n} `json:\"address\"`\n" +
+		"Parcels []struct {\nWeight int `json:\"weight\"`\n} `json:\"parcels\"`\n" +
+		"}"
+	expr, err := parser.ParseExpr(source)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	generator := NewSchemaGenerator()
+	fromAST := generator.GenerateSchemaFromStructAST(expr.(*ast.StructType), nil)
+
+	address := fromAST.Properties["address"]
+	assert.Equal(t, "object", address.Type)
+	assert.Equal(t, []string{"city"}, address.Required)
+	assert.Equal(t, "number", address.Properties["geo"].Properties["lat"].Type)
+	assert.Equal(t, "integer", fromAST.Properties["parcels"].Items.Properties["weight"].Type)
+	assert.Equal(t, untitled(generator.GenerateSchemaFromType(reflect.TypeOf(shipment{}))), fromAST)
+}
+
+func TestSchemaGenerator_ASTEmbeddedFields(t *testing.T) {
+	sources := map[string]string{
+		".request": "package api\n\nimport \"github.com/acme/shop/dto\"\n\ntype request struct {\n" +
+			"\tsecret string\n\tName string `json:\"name\"`\n\t*dto.Audit\n\tbase\n\tdto.Meta `json:\"meta\"`\n\tdto.Hidden `json:\"-\"`\n\tUnknown\n}\n",
+		".base":                            "package api\n\ntype base struct {\n\tID int64 `json:\"id\" validate:\"required\"`\n\tName int `json:\"name\"`\n\tRegion string `json:\"region\"`\n\t*request\n}\n",
+		"github.com/acme/shop/dto.Audit":   "package dto\n\ntype Audit struct {\n\tCreatedBy string `json:\"created_by\"`\n\tRegion string `json:\"region\"`\n\tVersion\n}\n",
+		"github.com/acme/shop/dto.Version": "package dto\n\ntype Version struct {\n\tRevision int `json:\"revision\"`\n\tID string `json:\"id\"`\n}\n",
+		"github.com/acme/shop/dto.Meta":    "package dto\n\ntype Meta struct {\n\tTrace string `json:\"trace\"`\n}\n",
+	}
+	parse := func(source string) (*ast.StructType, map[string]string) {
+		file, err := parser.ParseFile(token.NewFileSet(), "source.go", source, 0)
+		if !assert.NoError(t, err) {
+			return nil, nil
+		}
+		imports := make(map[string]string)
+		for _, spec := range file.Imports {
+			path := strings.Trim(spec.Path.Value, `"`)
+			imports[path[strings.LastIndex(path, "/")+1:]] = path
+		}
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+				return genDecl.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType), imports
+			}
+		}
+		return nil, nil
+	}
+
+	generator := NewSchemaGenerator()
+	generator.SetASTTypeResolver(func(pkgPath, typeName string) (*ast.StructType, map[string]string, bool) {
+		source, exists := sources[pkgPath+"."+typeName]
+		if !exists {
+			return nil, nil, false
+		}
+		structType, imports := parse(source)
+		return structType, imports, structType != nil
+	})
+
+	structType, imports := parse(sources[".request"])
+	schema := generator.GenerateSchemaFromStructAST(structType, imports)
+
+	assert.NotContains(t, schema.Properties, "secret", "Unexported fields are skipped")
+	assert.Equal(t, "string", schema.Properties["name"].Type, "The struct's own fields win over promoted ones")
+	assert.Equal(t, "string", schema.Properties["created_by"].Type)
+	assert.Equal(t, "integer", schema.Properties["revision"].Type, "Fields are promoted through nested embeddings")
+	assert.Equal(t, "integer", schema.Properties["id"].Type, "Shallower embeddings win")
+	assert.Equal(t, []string{"id"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "region", "Fields promoted at the same depth are ambiguous")
+	assert.Contains(t, schema.Properties, "meta", "Tagged embedded fields are regular properties")
+	assert.NotContains(t, schema.Properties, "trace")
+	assert.NotContains(t, schema.Properties, "hidden")
+	assert.NotContains(t, schema.Properties, "unknown", "Embedded types that cannot be resolved are left out")
+	assert.Len(t, schema.Properties, 5)
+
+	assert.Equal(t, []string{"meta", "name"}, slices.Sorted(maps.Keys(NewSchemaGenerator().GenerateSchemaFromStructAST(structType, imports).Properties)),
+		"Without a resolver embedded structs cannot be promoted")
+}
+
+type benchmarkAddress struct {
+	Street  string `json:"street" validate:"required,min=1,max=200"`
+	City    string `json:"city" validate:"required"`
+	Country string `json:"country" validate:"required,min=2,max=2" example:"ID"`
+}
+
+type benchmarkOrder struct {
+	ID        int64              `json:"id" openapi:"readOnly"`
+	Customer  string             `json:"customer" validate:"required,email" description:"Customer email"`
+	Items     []benchmarkAddress `json:"items"`
+	Shipping  benchmarkAddress   `json:"shipping"`
+	Totals    map[string]float64 `json:"totals"`
+	Notes     *string            `json:"notes,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	Page      int                `form:"page" validate:"min=1"`
+}
+
+func BenchmarkSchemaGenerator_GenerateSchemaFromType(b *testing.B) {
+	generator := NewSchemaGenerator()
+	t := reflect.TypeOf(benchmarkOrder{})
+	b.ReportAllocs()
+	for b.Loop() {
+		generator.ClearCache()
+		generator.GenerateSchemaFromType(t)
+	}
+}
+
+func BenchmarkSchemaGenerator_GenerateSchemaFromStructAST(b *testing.B) {
+	expr, err := parser.ParseExpr("struct {\n" +
+		"ID int64 `json:\"id\" openapi:\"readOnly\"`\n" +
+		"Customer string `json:\"customer\" validate:\"required,email\" description:\"Customer email\"`\n" +
+		"Tags []string `json:\"tags\"`\n" +
+		"Totals map[string]float64 `json:\"totals\"`\n" +
+		"CreatedAt time.Time `json:\"created_at\"`\n" +
+		"Page int `form:\"page\" validate:\"min=1\"`\n" +
+		"}")
+	if err != nil {
+		b.Fatal(err)
+	}
+	structType := expr.(*ast.StructType)
+	imports := map[string]string{"time": "time"}
+
+	generator := NewSchemaGenerator()
+	b.ReportAllocs()
+	for b.Loop() {
+		generator.GenerateSchemaFromStructAST(structType, imports)
+	}
+}
+
+func TestSchemaGenerator_WarmUp(t *testing.T) {
+	generator := NewSchemaGenerator()
+	generator.WarmUp([]reflect.Type{reflect.TypeOf(benchmarkOrder{}), nil})
+
+	assert.Contains(t, generator.typeCache, reflect.TypeOf(benchmarkOrder{}))
+	assert.Contains(t, generator.typeCache, reflect.TypeOf(benchmarkAddress{}), "Nested types are cached too")
+}
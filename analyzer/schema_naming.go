@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// SchemaNamer names the component of a route schema
+//
+// routeKey is "METHOD /path", kind is "request", "response" or "response<status>" for
+// responses documented under another status code, followed by "Variant<n>" for the variants
+// of routes dispatching on a content type or header, and goType is the Go type the schema
+// was generated from, nil when unknown. Returning an empty string keeps the default name.
+type SchemaNamer func(routeKey, kind string, goType reflect.Type) string
+
+// ResponseSchemaKind returns the schema kind of a response documented under a status code
+func ResponseSchemaKind(status int) string {
+	if status == http.StatusOK {
+		return "response"
+	}
+	return "response" + strconv.Itoa(status)
+}
+
+// VariantSchemaKind returns the schema kind of the nth variant, counted from 1, of a request
+// (status 0) or of the response documented under a status code
+func VariantSchemaKind(status, n int) string {
+	kind := "request"
+	if status != 0 {
+		kind = ResponseSchemaKind(status)
+	}
+	return kind + "Variant" + strconv.Itoa(n)
+}
+
+// GoTypeSchemaName returns a component name for a Go type, e.g. "LoginRequest" or "PageUser" for Page[User]
+func GoTypeSchemaName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+
+	name := t.Name()
+	base, args, generic := strings.Cut(name, "[")
+	if !generic {
+		return name
+	}
+
+	// Instantiated generics carry fully qualified type arguments, keep only their names
+	var builder strings.Builder
+	builder.WriteString(base)
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		arg = arg[strings.LastIndexAny(arg, "./*]")+1:]
+		if arg == "" {
+			continue
+		}
+		runes := []rune(arg)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+// ResolveComponentNames maps the internal, route-derived component names to their public names
+//
+// Without a namer, or when it returns an empty string, route schemas are named after the Go
+// type they were generated from. A schema that lost read-only or write-only properties is a
+// variant of its type and gets a "Request" or "Response" suffix, e.g. "UserRequest". Only
+// names that change are returned.
+func (sr *SchemaRegistry) ResolveComponentNames(namer SchemaNamer) map[string]string {
+	names := make(map[string]string)
+
+	resolve := func(routeKey, kind string, schema spec.Schema) {
+		internal := sr.generateSchemaName(routeKey, kind)
+		goType, overridden := sr.overrideTypes[internal]
+		if !overridden {
+			goType = sr.schemaTypes[internal]
+		}
+
+		var name string
+		if namer != nil {
+			name = namer(routeKey, kind, goType)
+		}
+		if name == "" && goType != nil {
+			name = GoTypeSchemaName(goType)
+			request := strings.HasPrefix(kind, "request")
+			if _, variant := sr.directionalSchema(schema, request); variant {
+				suffix := "Response"
+				if request {
+					suffix = "Request"
+				}
+				if !strings.HasSuffix(name, suffix) {
+					name += suffix
+				}
+			}
+		}
+		if name != "" && name != internal {
+			names[internal] = name
+		}
+	}
+
+	for key, schema := range sr.requestSchemas {
+		resolve(key, "request", schema)
+	}
+	for key, schema := range sr.responseSchemas {
+		resolve(key, "response", schema)
+	}
+	for key, statuses := range sr.responseOverrides {
+		for status, schema := range statuses {
+			if status != http.StatusOK && !reflect.DeepEqual(schema, spec.Schema{}) {
+				resolve(key, ResponseSchemaKind(status), schema)
+			}
+		}
+	}
+	for key, variants := range sr.variants {
+		for _, variant := range variants {
+			resolve(key, variant.Kind, variant.Schema)
+		}
+	}
+
+	return names
+}
+
+// ApplySchemaNames renames components, a rename is skipped when its target already names a different schema
+//
+// The returned map holds the renames that were applied.
+func ApplySchemaNames(schemas map[string]spec.Schema, names map[string]string) (map[string]spec.Schema, map[string]string) {
+	internals := make([]string, 0, len(names))
+	for internal := range names {
+		internals = append(internals, internal)
+	}
+	sort.Strings(internals)
+
+	renamed := make(map[string]spec.Schema, len(schemas))
+	for name, schema := range schemas {
+		if _, moving := names[name]; !moving {
+			renamed[name] = schema
+		}
+	}
+
+	// Many routes usually share a target, hash each schema once
+	hashes := make(map[string]string)
+	hash := func(name string, schema spec.Schema) string {
+		if _, exists := hashes[name]; !exists {
+			hashes[name] = SchemaHash(schema)
+		}
+		return hashes[name]
+	}
+
+	applied := make(map[string]string)
+	for _, internal := range internals {
+		schema, exists := schemas[internal]
+		if !exists {
+			continue
+		}
+		target := names[internal]
+		if existing, taken := renamed[target]; taken && hash(target, existing) != hash(internal, schema) {
+			// Two different types share a name (e.g. from different packages), keep the route name
+			renamed[internal] = schema
+			continue
+		}
+		renamed[target] = schema
+		applied[internal] = target
+	}
+
+	return renamed, applied
+}
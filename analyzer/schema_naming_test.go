@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namingLoginRequest struct {
+	Email string `json:"email"`
+}
+
+type namingPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestGoTypeSchemaName(t *testing.T) {
+	assert.Equal(t, "namingLoginRequest", GoTypeSchemaName(reflect.TypeOf(&namingLoginRequest{})))
+	assert.Equal(t, "namingPageNamingLoginRequest", GoTypeSchemaName(reflect.TypeOf(namingPage[namingLoginRequest]{})))
+	assert.Equal(t, "", GoTypeSchemaName(reflect.TypeOf(struct{}{})))
+	assert.Equal(t, "", GoTypeSchemaName(nil))
+}
+
+func TestSchemaRegistry_ResolveComponentNames(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterHandlerTypes("POST", "/auth/login", reflect.TypeOf(namingLoginRequest{}), nil)
+	registry.RegisterResponseSchema("POST", "/auth/login", spec.Schema{Type: "object"})
+
+	names := registry.ResolveComponentNames(nil)
+	assert.Equal(t, map[string]string{"POST_auth_loginrequest": "namingLoginRequest"}, names)
+
+	names = registry.ResolveComponentNames(func(routeKey, kind string, goType reflect.Type) string {
+		if kind == "response" {
+			return "LoginResult"
+		}
+		return ""
+	})
+	assert.Equal(t, "namingLoginRequest", names["POST_auth_loginrequest"], "An empty name falls back to the default")
+	assert.Equal(t, "LoginResult", names["POST_auth_loginresponse"])
+
+	// A different schema registered for the route no longer matches the Go type
+	registry.RegisterRequestSchema("POST", "/auth/login", spec.Schema{Type: "string"})
+	assert.Empty(t, registry.ResolveComponentNames(nil))
+}
+
+func TestApplySchemaNamesConflict(t *testing.T) {
+	first := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"a": {Type: "string"}}}
+	second := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"b": {Type: "string"}}}
+
+	schemas, applied := ApplySchemaNames(map[string]spec.Schema{
+		"GET_aresponse": first,
+		"GET_bresponse": second,
+	}, map[string]string{
+		"GET_aresponse": "User",
+		"GET_bresponse": "User",
+	})
+
+	assert.Equal(t, map[string]string{"GET_aresponse": "User"}, applied)
+	assert.Equal(t, first, schemas["User"])
+	assert.Equal(t, second, schemas["GET_bresponse"], "The conflicting schema keeps its route name")
+}
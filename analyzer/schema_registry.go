@@ -3,9 +3,14 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
-	"path/filepath"
+	"path"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/zainokta/openapi-gen/spec"
@@ -13,41 +18,115 @@ import (
 
 // SchemaRegistry manages manual schema registration and overrides
 type SchemaRegistry struct {
-	requestSchemas  map[string]spec.Schema // key: "METHOD /path"
-	responseSchemas map[string]spec.Schema
-	typeSchemas     map[reflect.Type]spec.Schema // Direct type mapping
-	routeMetadata   map[string]spec.RouteInfo    // key: "METHOD /path"
-	handlerSchemas  map[string]HandlerSchema     // key: handler name
-	schemaGen       *SchemaGenerator
+	requestSchemas      map[string]spec.Schema // key: "METHOD /path"
+	responseSchemas     map[string]spec.Schema
+	typeSchemas         map[reflect.Type]spec.Schema   // Direct type mapping
+	routeMetadata       map[string]spec.RouteInfo      // key: "METHOD /path"
+	handlerSchemas      map[string]HandlerSchema       // key: handler name, or namespace/handler name for nested schema files
+	handlerNamespaces   map[string][]string            // key: handler name, directories of the schema files declaring it
+	duplicateHandlers   map[string][]string            // key: handler name, schema files declaring it more than once
+	streamSchemas       map[string]StreamSchema        // key: "METHOD /path"
+	fileResponses       map[string]string              // key: "METHOD /path", value: content type
+	requestOverrides    map[string]spec.Schema         // key: "METHOD /path"
+	responseOverrides   map[string]map[int]spec.Schema // key: "METHOD /path", then status code
+	namedTypes          map[string]spec.Schema         // key: Go type name, used to name deduplicated components
+	schemaTypes         map[string]reflect.Type        // key: route component name, Go type the schema was generated from
+	overrideTypes       map[string]reflect.Type        // key: route component name, Go type of the override
+	requestRequired     map[string]bool                // key: "METHOD /path", whether the request body is required
+	variants            map[string][]SchemaVariant     // key: "METHOD /path", in registration order
+	readOnlyProperties  map[string]bool                // Property names left out of request components
+	writeOnlyProperties map[string]bool                // Property names left out of response components
+	schemaGen           *SchemaGenerator
 }
 
 // HandlerSchema represents request and response schemas for a handler
 type HandlerSchema struct {
-	RequestSchema  spec.Schema
-	ResponseSchema spec.Schema
+	RequestSchema   spec.Schema
+	ResponseSchema  spec.Schema
+	Stream          StreamKind // Set when the handler upgrades to WebSocket, streams SSE or writes in chunks
+	FileResponse    bool       // Set when the handler responds with a file download
+	GraphQL         bool       // Set when the handler serves a GraphQL endpoint, e.g. with gqlgen
+	Metrics         bool       // Set when the handler serves Prometheus metrics with promhttp
+	ResponseFormats []string   // Media types the handler renders responses in, e.g. application/xml, nil when unknown
+	NoRequestBody   bool       // Set when the handler source was analyzed and never reads a request body
+	Directives      []string   // //openapi:<directive> comments on the handler, e.g. "ignore"
+	// Responses of further status codes, e.g. 201 or 404, from schema files; an empty schema
+	// documents a response without a body
+	Responses map[int]spec.Schema
+	// Example payloads of responses by status code, e.g. a sample 422 validation error, from
+	// schema files
+	Examples map[int]any
+}
+
+// Handler comments understood by the generator
+const (
+	DirectiveIgnore   = "ignore"   // //openapi:ignore leaves the route out of the spec
+	DirectiveInternal = "internal" // //openapi:internal marks the operation x-internal
+)
+
+// HasDirective reports whether the handler carries the //openapi:<name> comment
+func (s HandlerSchema) HasDirective(name string) bool {
+	return slices.Contains(s.Directives, name)
+}
+
+// StreamKind identifies handlers that keep the connection open instead of returning JSON
+type StreamKind string
+
+const (
+	StreamNone      StreamKind = ""
+	StreamWebSocket StreamKind = "websocket"
+	StreamSSE       StreamKind = "sse"
+	StreamChunked   StreamKind = "chunked" // Written in chunks, e.g. with c.Stream or io.Copy
+)
+
+// StreamSchema describes the messages exchanged over a WebSocket or SSE endpoint
+type StreamSchema struct {
+	Kind     StreamKind
+	Inbound  map[string]spec.Schema // Messages sent by the client, keyed by message name
+	Outbound map[string]spec.Schema // Messages sent by the server, keyed by message or SSE event name
 }
 
 // NewSchemaRegistry creates a new schema registry
 func NewSchemaRegistry() *SchemaRegistry {
 	return &SchemaRegistry{
-		requestSchemas:  make(map[string]spec.Schema),
-		responseSchemas: make(map[string]spec.Schema),
-		typeSchemas:     make(map[reflect.Type]spec.Schema),
-		routeMetadata:   make(map[string]spec.RouteInfo),
-		handlerSchemas:  make(map[string]HandlerSchema),
-		schemaGen:       NewSchemaGenerator(),
+		requestSchemas:      make(map[string]spec.Schema),
+		responseSchemas:     make(map[string]spec.Schema),
+		typeSchemas:         make(map[reflect.Type]spec.Schema),
+		routeMetadata:       make(map[string]spec.RouteInfo),
+		handlerSchemas:      make(map[string]HandlerSchema),
+		handlerNamespaces:   make(map[string][]string),
+		duplicateHandlers:   make(map[string][]string),
+		streamSchemas:       make(map[string]StreamSchema),
+		fileResponses:       make(map[string]string),
+		requestOverrides:    make(map[string]spec.Schema),
+		responseOverrides:   make(map[string]map[int]spec.Schema),
+		namedTypes:          make(map[string]spec.Schema),
+		schemaTypes:         make(map[string]reflect.Type),
+		overrideTypes:       make(map[string]reflect.Type),
+		requestRequired:     make(map[string]bool),
+		variants:            make(map[string][]SchemaVariant),
+		readOnlyProperties:  make(map[string]bool),
+		writeOnlyProperties: make(map[string]bool),
+		schemaGen:           NewSchemaGenerator(),
 	}
 }
 
 // RegisterRequestSchema registers a request schema for a specific endpoint
 func (sr *SchemaRegistry) RegisterRequestSchema(method, path string, schema spec.Schema) {
 	key := sr.createRouteKey(method, path)
+	if existing, exists := sr.requestSchemas[key]; exists && !reflect.DeepEqual(existing, schema) {
+		// The schema no longer describes the Go type it was generated from
+		delete(sr.schemaTypes, sr.generateSchemaName(key, "request"))
+	}
 	sr.requestSchemas[key] = schema
 }
 
 // RegisterResponseSchema registers a response schema for a specific endpoint
 func (sr *SchemaRegistry) RegisterResponseSchema(method, path string, schema spec.Schema) {
 	key := sr.createRouteKey(method, path)
+	if existing, exists := sr.responseSchemas[key]; exists && !reflect.DeepEqual(existing, schema) {
+		delete(sr.schemaTypes, sr.generateSchemaName(key, "response"))
+	}
 	sr.responseSchemas[key] = schema
 }
 
@@ -62,11 +141,15 @@ func (sr *SchemaRegistry) RegisterHandlerTypes(method, path string, reqType, res
 	if reqType != nil {
 		reqSchema := sr.schemaGen.GenerateSchemaFromType(reqType)
 		sr.RegisterRequestSchema(method, path, reqSchema)
+		sr.recordTypeName(reqType, reqSchema)
+		sr.schemaTypes[sr.generateSchemaName(sr.createRouteKey(method, path), "request")] = reqType
 	}
 
 	if respType != nil {
 		respSchema := sr.schemaGen.GenerateSchemaFromType(respType)
 		sr.RegisterResponseSchema(method, path, respSchema)
+		sr.recordTypeName(respType, respSchema)
+		sr.schemaTypes[sr.generateSchemaName(sr.createRouteKey(method, path), "response")] = respType
 	}
 }
 
@@ -141,6 +224,149 @@ func (sr *SchemaRegistry) GetHandlerSchemas(method, path string) HandlerSchema {
 	}
 }
 
+// RegisterStreamMessage registers a message exchanged over a WebSocket or SSE endpoint
+//
+// Inbound messages are sent by the client (WebSocket only); outbound messages are
+// sent by the server. For SSE endpoints the name is the event name.
+func (sr *SchemaRegistry) RegisterStreamMessage(method, path string, kind StreamKind, inbound bool, name string, schema spec.Schema) {
+	key := sr.createRouteKey(method, path)
+	stream, exists := sr.streamSchemas[key]
+	if !exists {
+		stream = StreamSchema{
+			Inbound:  make(map[string]spec.Schema),
+			Outbound: make(map[string]spec.Schema),
+		}
+	}
+	stream.Kind = kind
+
+	if inbound {
+		stream.Inbound[name] = schema
+	} else {
+		stream.Outbound[name] = schema
+	}
+	sr.streamSchemas[key] = stream
+}
+
+// GetStreamSchema retrieves the stream messages registered for an endpoint
+func (sr *SchemaRegistry) GetStreamSchema(method, path string) (StreamSchema, bool) {
+	key := sr.createRouteKey(method, path)
+	stream, exists := sr.streamSchemas[key]
+	return stream, exists
+}
+
+// RegisterFileResponse marks an endpoint as returning a file with the given content type
+func (sr *SchemaRegistry) RegisterFileResponse(method, path, contentType string) {
+	key := sr.createRouteKey(method, path)
+	sr.fileResponses[key] = contentType
+}
+
+// GetFileResponse retrieves the file content type registered for an endpoint
+func (sr *SchemaRegistry) GetFileResponse(method, path string) (string, bool) {
+	key := sr.createRouteKey(method, path)
+	contentType, exists := sr.fileResponses[key]
+	return contentType, exists
+}
+
+// RegisterRequestOverride registers a request schema that takes precedence over handler analysis
+func (sr *SchemaRegistry) RegisterRequestOverride(method, path string, schema spec.Schema) {
+	key := sr.createRouteKey(method, path)
+	sr.requestOverrides[key] = schema
+}
+
+// RegisterRequestOverrideType registers a request override generated from a Go type
+func (sr *SchemaRegistry) RegisterRequestOverrideType(method, path string, t reflect.Type) {
+	sr.RegisterRequestOverride(method, path, sr.GenerateSchemaFromType(t))
+	sr.overrideTypes[sr.generateSchemaName(sr.createRouteKey(method, path), "request")] = t
+}
+
+// GetRequestOverride retrieves the request override for an endpoint
+func (sr *SchemaRegistry) GetRequestOverride(method, path string) (spec.Schema, bool) {
+	key := sr.createRouteKey(method, path)
+	schema, exists := sr.requestOverrides[key]
+	return schema, exists
+}
+
+// RegisterRequestRequired sets whether the request body of an endpoint is required, it is by default
+func (sr *SchemaRegistry) RegisterRequestRequired(method, path string, required bool) {
+	key := sr.createRouteKey(method, path)
+	sr.requestRequired[key] = required
+}
+
+// GetRequestRequired retrieves whether the request body of an endpoint was registered as required
+func (sr *SchemaRegistry) GetRequestRequired(method, path string) (bool, bool) {
+	key := sr.createRouteKey(method, path)
+	required, exists := sr.requestRequired[key]
+	return required, exists
+}
+
+// RegisterResponseOverride registers a response schema for a status code that takes precedence over handler analysis
+//
+// A zero schema documents the status without a response body.
+func (sr *SchemaRegistry) RegisterResponseOverride(method, path string, status int, schema spec.Schema) {
+	key := sr.createRouteKey(method, path)
+	if sr.responseOverrides[key] == nil {
+		sr.responseOverrides[key] = make(map[int]spec.Schema)
+	}
+	sr.responseOverrides[key][status] = schema
+}
+
+// RegisterResponseOverrideType registers a response override for a status code generated from a Go type
+func (sr *SchemaRegistry) RegisterResponseOverrideType(method, path string, status int, t reflect.Type) {
+	sr.RegisterResponseOverride(method, path, status, sr.GenerateSchemaFromType(t))
+	sr.overrideTypes[sr.generateSchemaName(sr.createRouteKey(method, path), ResponseSchemaKind(status))] = t
+}
+
+// GetResponseOverrides retrieves the response overrides for an endpoint, keyed by status code
+func (sr *SchemaRegistry) GetResponseOverrides(method, path string) map[int]spec.Schema {
+	key := sr.createRouteKey(method, path)
+	return sr.responseOverrides[key]
+}
+
+// SchemaVariant is one of several request or response shapes of an endpoint, chosen by the
+// client through a content type or a request header value
+type SchemaVariant struct {
+	Status      int    // Response status code, 0 for a request variant
+	ContentType string // Media type of the variant, empty for application/json
+	Header      string // Request header the endpoint dispatches on, e.g. Accept-Version
+	Value       string // Value of Header selecting the variant
+	Kind        string // Schema kind naming the component, see VariantSchemaKind
+	Schema      spec.Schema
+}
+
+// RegisterVariantType registers a variant of an endpoint generated from a Go type
+//
+// A variant with the same status, content type and header value replaces the earlier one.
+func (sr *SchemaRegistry) RegisterVariantType(method, path string, variant SchemaVariant, t reflect.Type) {
+	key := sr.createRouteKey(method, path)
+	variant.Schema = sr.GenerateSchemaFromType(t)
+
+	variants := sr.variants[key]
+	index := slices.IndexFunc(variants, func(existing SchemaVariant) bool {
+		return existing.Status == variant.Status && existing.ContentType == variant.ContentType &&
+			existing.Header == variant.Header && existing.Value == variant.Value
+	})
+	if index >= 0 {
+		variant.Kind = variants[index].Kind
+		variants[index] = variant
+	} else {
+		ordinal := 1
+		for _, existing := range variants {
+			if existing.Status == variant.Status {
+				ordinal++
+			}
+		}
+		variant.Kind = VariantSchemaKind(variant.Status, ordinal)
+		sr.variants[key] = append(variants, variant)
+	}
+	sr.overrideTypes[sr.generateSchemaName(key, variant.Kind)] = t
+}
+
+// GetVariants retrieves the variants of an endpoint in registration order
+func (sr *SchemaRegistry) GetVariants(method, path string) []SchemaVariant {
+	key := sr.createRouteKey(method, path)
+	return sr.variants[key]
+}
+
 // GetTypeSchema retrieves schema for a specific Go type
 func (sr *SchemaRegistry) GetTypeSchema(t reflect.Type) (spec.Schema, bool) {
 	schema, exists := sr.typeSchemas[t]
@@ -169,25 +395,103 @@ func (sr *SchemaRegistry) GenerateSchemaFromType(t reflect.Type) spec.Schema {
 	}
 
 	// Generate using schema generator
-	return sr.schemaGen.GenerateSchemaFromType(t)
+	schema := sr.schemaGen.GenerateSchemaFromType(t)
+	sr.recordTypeName(t, schema)
+	return schema
+}
+
+// recordTypeName remembers the schema generated for a named Go type
+func (sr *SchemaRegistry) recordTypeName(t reflect.Type, schema spec.Schema) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return
+	}
+	sr.namedTypes[t.Name()] = schema
+}
+
+// GetTypeNamedSchemas returns the schemas generated from named Go types, keyed by type name
+func (sr *SchemaRegistry) GetTypeNamedSchemas() map[string]spec.Schema {
+	named := make(map[string]spec.Schema, len(sr.namedTypes)+len(sr.typeSchemas))
+	for name, schema := range sr.namedTypes {
+		named[name] = schema
+	}
+	for t, schema := range sr.typeSchemas {
+		if name := t.Name(); name != "" {
+			named[name] = schema
+		}
+	}
+	return named
+}
+
+// GetComponentTypes returns the Go type each component was generated from, keyed by component name
+//
+// Route schemas are keyed by their internal, route-derived names. Schemas found by source
+// analysis have no Go type and are left out.
+func (sr *SchemaRegistry) GetComponentTypes() map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(sr.schemaTypes)+len(sr.overrideTypes)+len(sr.typeSchemas))
+	for name, t := range sr.schemaTypes {
+		types[name] = t
+	}
+	for name, t := range sr.overrideTypes {
+		types[name] = t
+	}
+	for t := range sr.typeSchemas {
+		if name := t.Name(); name != "" {
+			types[name] = t
+		}
+	}
+	for _, union := range sr.schemaGen.Unions().All() {
+		for _, variant := range union.Variants {
+			types[GoTypeSchemaName(variant.Type)] = variant.Type
+		}
+	}
+	return types
 }
 
 // GetAllSchemas returns all registered schemas as a single map
 func (sr *SchemaRegistry) GetAllSchemas() map[string]spec.Schema {
 	allSchemas := make(map[string]spec.Schema)
 
-	// Add request schemas
+	// Add request schemas, without their read-only properties
 	for key, schema := range sr.requestSchemas {
 		// Create a unique name for the schema
 		name := sr.generateSchemaName(key, "request")
-		allSchemas[name] = schema
+		allSchemas[name], _ = sr.directionalSchema(schema, true)
 	}
 
-	// Add response schemas
+	// Add response schemas, without their write-only properties
 	for key, schema := range sr.responseSchemas {
 		// Create a unique name for the schema
 		name := sr.generateSchemaName(key, "response")
-		allSchemas[name] = schema
+		allSchemas[name], _ = sr.directionalSchema(schema, false)
+	}
+
+	// Add response overrides for other status codes, 200 overrides are stored as response schemas
+	for key, statuses := range sr.responseOverrides {
+		for status, schema := range statuses {
+			if status == 200 || reflect.DeepEqual(schema, spec.Schema{}) {
+				continue
+			}
+			name := sr.generateSchemaName(key, ResponseSchemaKind(status))
+			allSchemas[name], _ = sr.directionalSchema(schema, false)
+		}
+	}
+
+	// Add request and response variants of endpoints that dispatch on a content type or header
+	for key, variants := range sr.variants {
+		for _, variant := range variants {
+			name := sr.generateSchemaName(key, variant.Kind)
+			allSchemas[name], _ = sr.directionalSchema(variant.Schema, variant.Status == 0)
+		}
+	}
+
+	// Add union variants, oneOf schemas reference them by type name
+	for _, union := range sr.schemaGen.Unions().All() {
+		for _, variant := range union.Variants {
+			allSchemas[GoTypeSchemaName(variant.Type)] = union.VariantSchema(sr.schemaGen, variant)
+		}
 	}
 
 	// Add type schemas
@@ -216,6 +520,35 @@ func (sr *SchemaRegistry) generateSchemaName(routeKey, schemaType string) string
 	return cleanKey + schemaType
 }
 
+// UnregisterRoute forgets the request, response and override schemas of an endpoint
+//
+// GetAllSchemas no longer emits its components, e.g. after the route was removed from
+// the router. Schemas of Go types and handlers are kept, other routes may share them.
+func (sr *SchemaRegistry) UnregisterRoute(method, path string) {
+	key := sr.createRouteKey(method, path)
+	for status := range sr.responseOverrides[key] {
+		delete(sr.overrideTypes, sr.generateSchemaName(key, ResponseSchemaKind(status)))
+	}
+	for _, kind := range []string{"request", "response"} {
+		name := sr.generateSchemaName(key, kind)
+		delete(sr.schemaTypes, name)
+		delete(sr.overrideTypes, name)
+	}
+	for _, variant := range sr.variants[key] {
+		delete(sr.overrideTypes, sr.generateSchemaName(key, variant.Kind))
+	}
+
+	delete(sr.requestSchemas, key)
+	delete(sr.responseSchemas, key)
+	delete(sr.routeMetadata, key)
+	delete(sr.streamSchemas, key)
+	delete(sr.fileResponses, key)
+	delete(sr.requestOverrides, key)
+	delete(sr.responseOverrides, key)
+	delete(sr.requestRequired, key)
+	delete(sr.variants, key)
+}
+
 // ClearAll clears all registered schemas
 func (sr *SchemaRegistry) ClearAll() {
 	sr.requestSchemas = make(map[string]spec.Schema)
@@ -223,6 +556,17 @@ func (sr *SchemaRegistry) ClearAll() {
 	sr.typeSchemas = make(map[reflect.Type]spec.Schema)
 	sr.routeMetadata = make(map[string]spec.RouteInfo)
 	sr.handlerSchemas = make(map[string]HandlerSchema)
+	sr.handlerNamespaces = make(map[string][]string)
+	sr.duplicateHandlers = make(map[string][]string)
+	sr.streamSchemas = make(map[string]StreamSchema)
+	sr.fileResponses = make(map[string]string)
+	sr.requestOverrides = make(map[string]spec.Schema)
+	sr.responseOverrides = make(map[string]map[int]spec.Schema)
+	sr.namedTypes = make(map[string]spec.Schema)
+	sr.schemaTypes = make(map[string]reflect.Type)
+	sr.overrideTypes = make(map[string]reflect.Type)
+	sr.requestRequired = make(map[string]bool)
+	sr.variants = make(map[string][]SchemaVariant)
 	sr.schemaGen.ClearCache()
 }
 
@@ -312,15 +656,26 @@ func (sr *SchemaRegistry) HasHandlerSchema(handlerName string) bool {
 	return exists
 }
 
-// GetAllHandlerNames returns all registered handler names
+// GetAllHandlerNames returns all registered handler names in sorted order
 func (sr *SchemaRegistry) GetAllHandlerNames() []string {
 	names := make([]string, 0, len(sr.handlerSchemas))
 	for handlerName := range sr.handlerSchemas {
 		names = append(names, handlerName)
 	}
+	sort.Strings(names)
 	return names
 }
 
+// SchemaBundleFileName is the file the CLI packs all generated schema files into
+const SchemaBundleFileName = "openapi-schemas.bundle.json"
+
+// SchemaBundle is a single file containing every generated handler schema
+type SchemaBundle struct {
+	Version int               `json:"version"`
+	Schemas []json.RawMessage `json:"schemas"`
+	Paths   []string          `json:"paths,omitempty"` // Path of each schema file relative to the schema directory, e.g. users/Create.json
+}
+
 // LoadStaticSchemas loads schema files from a directory
 func (sr *SchemaRegistry) LoadStaticSchemas(schemaDir string) error {
 	if _, err := os.Stat(schemaDir); os.IsNotExist(err) {
@@ -328,105 +683,358 @@ func (sr *SchemaRegistry) LoadStaticSchemas(schemaDir string) error {
 		return nil
 	}
 
-	// Read all JSON files in the schema directory
-	files, err := filepath.Glob(filepath.Join(schemaDir, "*.json"))
-	if err != nil {
-		return fmt.Errorf("failed to read schema files: %w", err)
+	return sr.LoadStaticSchemasFS(os.DirFS(schemaDir), ".")
+}
+
+// LoadStaticSchemasFS loads schema files from a directory inside a file system such as an embed.FS
+//
+// Unlike LoadStaticSchemas, a missing directory is an error: an embedded directory
+// is fixed at build time, so its absence means the embed pattern is wrong.
+//
+// Subdirectories are loaded too, hidden ones excepted. A schema file in a subdirectory is
+// also registered under its namespace, the directory relative to dir: users/Create for
+// the Create handler in users/. Handlers of that name in a package whose import path ends
+// in the namespace get it, see GetNamespacedHandlerSchema. The plain handler name goes to
+// the first file declaring it in lexical order, the others are listed by DuplicateHandlerSchemas.
+func (sr *SchemaRegistry) LoadStaticSchemasFS(fsys fs.FS, dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+	if info, err := fs.Stat(fsys, dir); err != nil {
+		return fmt.Errorf("schema directory %q not found: %w", dir, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("schema directory %q is not a directory", dir)
 	}
 
-	for _, file := range files {
-		if err := sr.loadSchemaFile(file); err != nil {
+	// Read all JSON files under the schema directory
+	files := make(map[string][]string) // key: handler name, in lexical order
+	err := fs.WalkDir(fsys, dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != dir && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// The bundle holds copies of the individual files
+		if path.Ext(file) != ".json" || entry.Name() == SchemaBundleFileName {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		var handlerName string
+		var handlerSchema HandlerSchema
+		if err == nil {
+			handlerName, handlerSchema, err = sr.parseSchemaFileData(data)
+		}
+		if err != nil {
 			// Log error but continue loading other files
 			fmt.Printf("Warning: failed to load schema file %s: %v\n", file, err)
-			continue
+			return nil
 		}
+
+		namespace := "."
+		if parent := path.Dir(file); parent != dir {
+			namespace = strings.TrimPrefix(parent, dir+"/")
+		}
+		sr.registerSchemaFile(file, namespace, handlerName, handlerSchema, files)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read schema files: %w", err)
 	}
 
+	sr.recordDuplicateHandlers(files)
 	return nil
 }
 
-// loadSchemaFile loads a single schema file and registers it
-func (sr *SchemaRegistry) loadSchemaFile(filePath string) error {
-	data, err := os.ReadFile(filePath)
+// registerSchemaFile registers a parsed schema file under its handler name, and under its
+// namespace when it is not "."; files lists the files declaring each handler name so far
+func (sr *SchemaRegistry) registerSchemaFile(file, namespace, handlerName string, handlerSchema HandlerSchema, files map[string][]string) {
+	if namespace != "." {
+		sr.handlerSchemas[namespace+"/"+handlerName] = handlerSchema
+		sr.handlerNamespaces[handlerName] = append(sr.handlerNamespaces[handlerName], namespace)
+	}
+	if len(files[handlerName]) == 0 {
+		sr.RegisterHandlerSchema(handlerName, handlerSchema)
+	}
+	files[handlerName] = append(files[handlerName], file)
+}
+
+// recordDuplicateHandlers records the handler names declared by several of the files loaded
+func (sr *SchemaRegistry) recordDuplicateHandlers(files map[string][]string) {
+	for handlerName, declaring := range files {
+		if len(declaring) > 1 {
+			sr.duplicateHandlers[handlerName] = declaring
+		}
+	}
+}
+
+// GetNamespacedHandlerSchema retrieves the schema of a handler loaded from a subdirectory of
+// the schema directory, whose path is the longest suffix of the handler's package import path
+func (sr *SchemaRegistry) GetNamespacedHandlerSchema(packagePath, handlerName string) (HandlerSchema, bool) {
+	best := ""
+	for _, namespace := range sr.handlerNamespaces[handlerName] {
+		if (packagePath == namespace || strings.HasSuffix(packagePath, "/"+namespace)) && len(namespace) > len(best) {
+			best = namespace
+		}
+	}
+	if best == "" {
+		return HandlerSchema{}, false
+	}
+	schema, exists := sr.handlerSchemas[best+"/"+handlerName]
+	return schema, exists
+}
+
+// DuplicateHandlerSchemas returns the handler names declared by several schema files, with the files
+func (sr *SchemaRegistry) DuplicateHandlerSchemas() map[string][]string {
+	return maps.Clone(sr.duplicateHandlers)
+}
+
+// LoadSchemaBundle loads a schema bundle from a file system such as an embed.FS
+//
+// The bundle is looked up by SchemaBundleFileName anywhere in the file system, so
+// embedding "schemas/openapi-schemas.bundle.json" works without fs.Sub.
+func (sr *SchemaRegistry) LoadSchemaBundle(fsys fs.FS) error {
+	bundlePath := ""
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == SchemaBundleFileName {
+			bundlePath = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search schema bundle: %w", err)
+	}
+	if bundlePath == "" {
+		return fmt.Errorf("schema bundle %s not found, generate it with openapi-gen -bundle", SchemaBundleFileName)
+	}
+
+	data, err := fs.ReadFile(fsys, bundlePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return fmt.Errorf("failed to read schema bundle: %w", err)
+	}
+
+	return sr.LoadSchemaBundleData(data)
+}
+
+// LoadSchemaBundleData loads handler schemas from the contents of a schema bundle
+func (sr *SchemaRegistry) LoadSchemaBundleData(data []byte) error {
+	var bundle SchemaBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to parse schema bundle: %w", err)
+	}
+	if bundle.Version != 1 {
+		return fmt.Errorf("unsupported schema bundle version %d", bundle.Version)
 	}
 
+	// Schema files in subdirectories are namespaced as by LoadStaticSchemasFS
+	files := make(map[string][]string)
+	for i, schemaData := range bundle.Schemas {
+		handlerName, handlerSchema, err := sr.parseSchemaFileData(schemaData)
+		if err != nil {
+			return fmt.Errorf("invalid schema %d in bundle: %w", i, err)
+		}
+		file := fmt.Sprintf("schema %d", i)
+		if i < len(bundle.Paths) {
+			file = bundle.Paths[i]
+		}
+		sr.registerSchemaFile(file, path.Dir(file), handlerName, handlerSchema, files)
+	}
+
+	sr.recordDuplicateHandlers(files)
+	return nil
+}
+
+// parseSchemaFileData parses the contents of a generated schema file
+func (sr *SchemaRegistry) parseSchemaFileData(data []byte) (string, HandlerSchema, error) {
 	// Parse the schema file
 	var schemaFile struct {
 		HandlerName    string                 `json:"handlerName"`
 		RequestSchema  map[string]interface{} `json:"requestSchema,omitempty"`
 		ResponseSchema map[string]interface{} `json:"responseSchema,omitempty"`
+		// Responses of further status codes, by status code
+		Responses map[string]map[string]interface{} `json:"responses,omitempty"`
+		// Example payloads of responses, by status code
+		Examples map[string]interface{} `json:"examples,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &schemaFile); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+		return "", HandlerSchema{}, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	if schemaFile.HandlerName == "" {
-		return fmt.Errorf("schema file missing handlerName")
+		return "", HandlerSchema{}, fmt.Errorf("schema file missing handlerName")
 	}
 
 	// Convert map[string]interface{} to spec.Schema
 	handlerSchema := HandlerSchema{}
-	
+
 	if schemaFile.RequestSchema != nil {
-		handlerSchema.RequestSchema = sr.convertToSpecSchema(schemaFile.RequestSchema)
+		schema, err := sr.convertToSpecSchema(schemaFile.RequestSchema)
+		if err != nil {
+			return "", HandlerSchema{}, fmt.Errorf("invalid requestSchema for %s: %w", schemaFile.HandlerName, err)
+		}
+		handlerSchema.RequestSchema = schema
 	}
-	
+
 	if schemaFile.ResponseSchema != nil {
-		handlerSchema.ResponseSchema = sr.convertToSpecSchema(schemaFile.ResponseSchema)
+		schema, err := sr.convertToSpecSchema(schemaFile.ResponseSchema)
+		if err != nil {
+			return "", HandlerSchema{}, fmt.Errorf("invalid responseSchema for %s: %w", schemaFile.HandlerName, err)
+		}
+		handlerSchema.ResponseSchema = schema
+	}
+
+	for code, responseSchema := range schemaFile.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return "", HandlerSchema{}, fmt.Errorf("invalid response status %q for %s", code, schemaFile.HandlerName)
+		}
+		schema, err := sr.convertToSpecSchema(responseSchema)
+		if err != nil {
+			return "", HandlerSchema{}, fmt.Errorf("invalid %s response schema for %s: %w", code, schemaFile.HandlerName, err)
+		}
+		if handlerSchema.Responses == nil {
+			handlerSchema.Responses = make(map[int]spec.Schema, len(schemaFile.Responses))
+		}
+		handlerSchema.Responses[status] = schema
 	}
 
-	// Register the handler schema
-	sr.RegisterHandlerSchema(schemaFile.HandlerName, handlerSchema)
+	for code, example := range schemaFile.Examples {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return "", HandlerSchema{}, fmt.Errorf("invalid example status %q for %s", code, schemaFile.HandlerName)
+		}
+		if handlerSchema.Examples == nil {
+			handlerSchema.Examples = make(map[int]any, len(schemaFile.Examples))
+		}
+		handlerSchema.Examples[status] = example
+	}
 
-	return nil
+	return schemaFile.HandlerName, handlerSchema, nil
 }
 
 // convertToSpecSchema converts a map[string]interface{} to spec.Schema
-func (sr *SchemaRegistry) convertToSpecSchema(schemaMap map[string]interface{}) spec.Schema {
-	schema := spec.Schema{}
-	
-	if typ, ok := schemaMap["type"].(string); ok {
-		schema.Type = typ
-	}
-	
-	if desc, ok := schemaMap["description"].(string); ok {
-		schema.Description = desc
-	}
-	
-	if props, ok := schemaMap["properties"].(map[string]interface{}); ok {
-		schema.Properties = make(map[string]spec.Schema)
-		for key, value := range props {
-			if propMap, ok := value.(map[string]interface{}); ok {
-				schema.Properties[key] = sr.convertToSpecSchema(propMap)
+//
+// The map is normalized to the OpenAPI 3.0 subset spec.Schema models and then decoded
+// straight into it, so every supported keyword (enum, example, pattern, bounds, ...)
+// survives the round trip.
+func (sr *SchemaRegistry) convertToSpecSchema(schemaMap map[string]interface{}) (spec.Schema, error) {
+	var schema spec.Schema
+
+	data, err := json.Marshal(normalizeSchemaMap(schemaMap))
+	if err != nil {
+		return schema, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return schema, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	return schema, nil
+}
+
+// normalizeSchemaMap rewrites JSON Schema constructs that spec.Schema cannot hold into their OpenAPI 3.0 form
+func normalizeSchemaMap(schemaMap map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(schemaMap))
+	for key, value := range schemaMap {
+		normalized[key] = value
+	}
+
+	// "type": ["string", "null"] becomes a nullable string
+	if types, ok := normalized["type"].([]interface{}); ok {
+		delete(normalized, "type")
+		for _, typ := range types {
+			if name, ok := typ.(string); ok {
+				if name == "null" {
+					normalized["nullable"] = true
+				} else if _, set := normalized["type"]; !set {
+					normalized["type"] = name
+				}
+			}
+		}
+	}
+
+	// const is a single-value enum
+	if constant, ok := normalized["const"]; ok {
+		delete(normalized, "const")
+		if _, hasEnum := normalized["enum"]; !hasEnum {
+			normalized["enum"] = []interface{}{constant}
+		}
+	}
+
+	// spec.Schema enums are strings, numeric and boolean values keep their textual form
+	if values, ok := normalized["enum"].([]interface{}); ok {
+		enum := make([]string, 0, len(values))
+		for _, value := range values {
+			switch v := value.(type) {
+			case nil:
+				normalized["nullable"] = true
+			case string:
+				enum = append(enum, v)
+			default:
+				enum = append(enum, fmt.Sprint(v))
+			}
+		}
+		normalized["enum"] = enum
+	}
+
+	// JSON Schema 2019+ writes exclusive bounds as numbers
+	for _, bound := range []struct{ exclusive, inclusive string }{
+		{"exclusiveMinimum", "minimum"},
+		{"exclusiveMaximum", "maximum"},
+	} {
+		if limit, ok := normalized[bound.exclusive].(float64); ok {
+			normalized[bound.inclusive] = limit
+			normalized[bound.exclusive] = true
+		}
+	}
+
+	if required, ok := normalized["required"].([]interface{}); ok {
+		names := make([]string, 0, len(required))
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
 			}
 		}
+		normalized["required"] = names
 	}
-	
-	if required, ok := schemaMap["required"].([]interface{}); ok {
-		schema.Required = make([]string, len(required))
-		for i, req := range required {
-			if reqStr, ok := req.(string); ok {
-				schema.Required[i] = reqStr
+
+	// Nested schemas
+	if props, ok := normalized["properties"].(map[string]interface{}); ok {
+		properties := make(map[string]interface{}, len(props))
+		for name, value := range props {
+			if propMap, ok := value.(map[string]interface{}); ok {
+				properties[name] = normalizeSchemaMap(propMap)
 			}
 		}
+		normalized["properties"] = properties
 	}
-	
-	if format, ok := schemaMap["format"].(string); ok {
-		schema.Format = format
+	for _, key := range []string{"items", "not"} {
+		if nested, ok := normalized[key].(map[string]interface{}); ok {
+			normalized[key] = normalizeSchemaMap(nested)
+		}
 	}
-	
-	if items, ok := schemaMap["items"].(map[string]interface{}); ok {
-		itemSchema := sr.convertToSpecSchema(items)
-		schema.Items = &itemSchema
+	// A boolean additionalProperties is kept, spec.Schema reads it into AdditionalPropertiesAllowed
+	if additional, ok := normalized["additionalProperties"].(map[string]interface{}); ok {
+		normalized["additionalProperties"] = normalizeSchemaMap(additional)
 	}
-	
-	if additionalProps, ok := schemaMap["additionalProperties"].(map[string]interface{}); ok {
-		additionalSchema := sr.convertToSpecSchema(additionalProps)
-		schema.AdditionalProperties = &additionalSchema
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if list, ok := normalized[key].([]interface{}); ok {
+			schemas := make([]interface{}, 0, len(list))
+			for _, value := range list {
+				if nested, ok := value.(map[string]interface{}); ok {
+					schemas = append(schemas, normalizeSchemaMap(nested))
+				}
+			}
+			normalized[key] = schemas
+		}
 	}
 
-	return schema
+	return normalized
 }
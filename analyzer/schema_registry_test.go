@@ -0,0 +1,235 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchemaBundle = `{
+  "version": 1,
+  "schemas": [
+    {"handlerName": "Login", "requestSchema": {"type": "object", "properties": {"email": {"type": "string", "format": "email"}}}},
+    {"handlerName": "GetUser", "responseSchema": {"type": "object", "properties": {"id": {"type": "string"}}}}
+  ]
+}`
+
+func TestSchemaRegistry_LoadSchemaBundle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/" + SchemaBundleFileName: {Data: []byte(testSchemaBundle)},
+	}
+
+	sr := NewSchemaRegistry()
+	assert.NoError(t, sr.LoadSchemaBundle(fsys))
+	assert.Equal(t, []string{"GetUser", "Login"}, sr.GetAllHandlerNames())
+
+	login, exists := sr.GetHandlerSchema("Login")
+	if assert.True(t, exists) {
+		assert.Equal(t, "email", login.RequestSchema.Properties["email"].Format)
+	}
+}
+
+func TestSchemaRegistry_LoadSchemaBundlePaths(t *testing.T) {
+	sr := NewSchemaRegistry()
+	assert.NoError(t, sr.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [
+		{"handlerName": "Create", "requestSchema": {"type": "object", "description": "invoice"}},
+		{"handlerName": "Create", "requestSchema": {"type": "object", "description": "user"}}
+	], "paths": ["billing/Create.json", "internal/users/Create.json"]}`)))
+
+	schema, exists := sr.GetNamespacedHandlerSchema("github.com/acme/shop/internal/users", "Create")
+	assert.True(t, exists)
+	assert.Equal(t, "user", schema.RequestSchema.Description)
+	schema, exists = sr.GetHandlerSchema("Create")
+	assert.True(t, exists)
+	assert.Equal(t, "invoice", schema.RequestSchema.Description)
+	assert.Equal(t, map[string][]string{"Create": {"billing/Create.json", "internal/users/Create.json"}}, sr.DuplicateHandlerSchemas())
+}
+
+func TestSchemaRegistry_LoadSchemaBundleErrors(t *testing.T) {
+	sr := NewSchemaRegistry()
+
+	assert.ErrorContains(t, sr.LoadSchemaBundle(fstest.MapFS{}), "not found")
+	assert.ErrorContains(t, sr.LoadSchemaBundleData([]byte(`{"version": 2}`)), "unsupported schema bundle version")
+	assert.ErrorContains(t, sr.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [{}]}`)), "missing handlerName")
+}
+
+func TestSchemaRegistry_LoadStaticSchemasFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/Login.json":              {Data: []byte(`{"handlerName": "Login", "requestSchema": {"type": "object"}}`)},
+		"schemas/GetUser.json":            {Data: []byte(`{"handlerName": "GetUser", "responseSchema": {"type": "object"}}`)},
+		"schemas/broken.json":             {Data: []byte(`{`)},
+		"schemas/" + SchemaBundleFileName: {Data: []byte(testSchemaBundle)},
+		"schemas/README.md":               {Data: []byte("not a schema")},
+	}
+
+	sr := NewSchemaRegistry()
+	assert.NoError(t, sr.LoadStaticSchemasFS(fsys, "schemas"))
+	assert.Equal(t, []string{"GetUser", "Login"}, sr.GetAllHandlerNames())
+
+	assert.Error(t, NewSchemaRegistry().LoadStaticSchemasFS(fsys, "missing"))
+	assert.Error(t, NewSchemaRegistry().LoadStaticSchemasFS(fsys, "schemas/Login.json"))
+}
+
+func TestSchemaRegistry_LoadStaticSchemasFSNested(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/Login.json":                    {Data: []byte(`{"handlerName": "Login", "requestSchema": {"type": "object"}}`)},
+		"schemas/billing/Create.json":           {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "description": "invoice"}}`)},
+		"schemas/users/Create.json":             {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "description": "user"}}`)},
+		"schemas/api/users/Create.json":         {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "description": "api user"}}`)},
+		"schemas/.cache/Create.json":            {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "description": "stale"}}`)},
+		"schemas/users/" + SchemaBundleFileName: {Data: []byte(testSchemaBundle)},
+	}
+
+	sr := NewSchemaRegistry()
+	assert.NoError(t, sr.LoadStaticSchemasFS(fsys, "schemas"))
+	assert.Equal(t, []string{"Create", "Login", "api/users/Create", "billing/Create", "users/Create"}, sr.GetAllHandlerNames())
+
+	// The plain name goes to the first file in lexical order
+	schema, exists := sr.GetHandlerSchema("Create")
+	assert.True(t, exists)
+	assert.Equal(t, "api user", schema.RequestSchema.Description)
+	assert.Equal(t, map[string][]string{
+		"Create": {"schemas/api/users/Create.json", "schemas/billing/Create.json", "schemas/users/Create.json"},
+	}, sr.DuplicateHandlerSchemas())
+
+	// The longest namespace matching the end of the package path wins
+	schema, exists = sr.GetNamespacedHandlerSchema("github.com/acme/shop/billing", "Create")
+	assert.True(t, exists)
+	assert.Equal(t, "invoice", schema.RequestSchema.Description)
+	schema, exists = sr.GetNamespacedHandlerSchema("github.com/acme/shop/internal/users", "Create")
+	assert.True(t, exists)
+	assert.Equal(t, "user", schema.RequestSchema.Description)
+	schema, exists = sr.GetNamespacedHandlerSchema("github.com/acme/shop/api/users", "Create")
+	assert.True(t, exists)
+	assert.Equal(t, "api user", schema.RequestSchema.Description)
+
+	_, exists = sr.GetNamespacedHandlerSchema("github.com/acme/shop/superusers", "Create")
+	assert.False(t, exists)
+	_, exists = sr.GetNamespacedHandlerSchema("github.com/acme/shop/users", "Login")
+	assert.False(t, exists)
+}
+
+func TestSchemaRegistry_ResponseOverrides(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterResponseOverride("post", "/login", 200, spec.Schema{Type: "object"})
+	registry.RegisterResponseOverride("POST", "/login", 401, spec.Schema{Type: "object"})
+	registry.RegisterResponseOverride("POST", "/login", 204, spec.Schema{})
+
+	overrides := registry.GetResponseOverrides("POST", "/login")
+	assert.Len(t, overrides, 3)
+
+	schemas := registry.GetAllSchemas()
+	assert.Contains(t, schemas, "POST_loginresponse401")
+	assert.NotContains(t, schemas, "POST_loginresponse204", "Empty overrides document a status without a body")
+	assert.NotContains(t, schemas, "POST_loginresponse200", "200 overrides are registered as response schemas by the generator")
+}
+
+func TestSchemaRegistry_ConvertToSpecSchema(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+	integer := func(v int) *int { return &v }
+	boolean := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		name     string
+		input    string
+		expected spec.Schema
+	}{
+		{"type and description", `{"type": "string", "description": "Name", "title": "UserName"}`,
+			spec.Schema{Type: "string", Description: "Name", Title: "UserName"}},
+		{"format", `{"type": "string", "format": "email"}`, spec.Schema{Type: "string", Format: "email"}},
+		{"enum", `{"type": "string", "enum": ["active", "banned"]}`, spec.Schema{Type: "string", Enum: []string{"active", "banned"}}},
+		{"numeric enum", `{"type": "integer", "enum": [1, 2]}`, spec.Schema{Type: "integer", Enum: []string{"1", "2"}}},
+		{"const", `{"type": "string", "const": "fixed"}`, spec.Schema{Type: "string", Enum: []string{"fixed"}}},
+		{"example and default", `{"type": "string", "example": "jane", "default": "john"}`,
+			spec.Schema{Type: "string", Example: "jane", Default: "john"}},
+		{"pattern", `{"type": "string", "pattern": "^[a-z]+$"}`, spec.Schema{Type: "string", Pattern: "^[a-z]+$"}},
+		{"string lengths", `{"type": "string", "minLength": 3, "maxLength": 20}`,
+			spec.Schema{Type: "string", MinLength: integer(3), MaxLength: integer(20)}},
+		{"number bounds", `{"type": "number", "minimum": 0, "maximum": 100, "multipleOf": 0.5}`,
+			spec.Schema{Type: "number", Minimum: float(0), Maximum: float(100), MultipleOf: float(0.5)}},
+		{"openapi exclusive bounds", `{"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 10, "exclusiveMaximum": true}`,
+			spec.Schema{Type: "number", Minimum: float(0), ExclusiveMinimum: true, Maximum: float(10), ExclusiveMaximum: true}},
+		{"json schema exclusive bounds", `{"type": "number", "exclusiveMinimum": 1, "exclusiveMaximum": 5}`,
+			spec.Schema{Type: "number", Minimum: float(1), ExclusiveMinimum: true, Maximum: float(5), ExclusiveMaximum: true}},
+		{"array constraints", `{"type": "array", "items": {"type": "string", "enum": ["a"]}, "minItems": 1, "maxItems": 5, "uniqueItems": true}`,
+			spec.Schema{Type: "array", Items: &spec.Schema{Type: "string", Enum: []string{"a"}}, MinItems: integer(1), MaxItems: integer(5), UniqueItems: true}},
+		{"object constraints", `{"type": "object", "minProperties": 1, "maxProperties": 3, "required": ["id"], "properties": {"id": {"type": "string", "readOnly": true}}}`,
+			spec.Schema{Type: "object", MinProperties: integer(1), MaxProperties: integer(3), Required: []string{"id"},
+				Properties: map[string]spec.Schema{"id": {Type: "string", ReadOnly: true}}}},
+		{"additionalProperties schema", `{"type": "object", "additionalProperties": {"type": "integer", "minimum": 1}}`,
+			spec.Schema{Type: "object", AdditionalProperties: &spec.Schema{Type: "integer", Minimum: float(1)}}},
+		{"additionalProperties true", `{"type": "object", "additionalProperties": true}`,
+			spec.Schema{Type: "object", AdditionalPropertiesAllowed: boolean(true)}},
+		{"additionalProperties false", `{"type": "object", "additionalProperties": false}`,
+			spec.Schema{Type: "object", AdditionalPropertiesAllowed: boolean(false)}},
+		{"flags", `{"type": "string", "nullable": true, "writeOnly": true, "deprecated": true}`,
+			spec.Schema{Type: "string", Nullable: true, WriteOnly: true, Deprecated: true}},
+		{"nullable type list", `{"type": ["string", "null"]}`, spec.Schema{Type: "string", Nullable: true}},
+		{"composition", `{"oneOf": [{"$ref": "#/components/schemas/Dog"}, {"type": "string", "minLength": 1}], "discriminator": {"propertyName": "type"}}`,
+			spec.Schema{OneOf: []spec.Schema{{Ref: "#/components/schemas/Dog"}, {Type: "string", MinLength: integer(1)}},
+				Discriminator: &spec.Discriminator{PropertyName: "type"}}},
+		{"allOf anyOf not", `{"allOf": [{"type": "object"}], "anyOf": [{"type": "string"}], "not": {"type": "integer"}}`,
+			spec.Schema{AllOf: []spec.Schema{{Type: "object"}}, AnyOf: []spec.Schema{{Type: "string"}}, Not: &spec.Schema{Type: "integer"}}},
+	}
+
+	registry := NewSchemaRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.input), &input))
+
+			schema, err := registry.convertToSpecSchema(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, schema)
+		})
+	}
+}
+
+func TestSchemaRegistry_StaticSchemaKeepsConstraints(t *testing.T) {
+	registry := NewSchemaRegistry()
+	err := registry.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [{"handlerName": "Register", "requestSchema": {
+		"type": "object",
+		"required": ["email"],
+		"properties": {
+			"email": {"type": "string", "format": "email", "example": "jane@example.com"},
+			"age": {"type": "integer", "minimum": 18, "maximum": 130},
+			"role": {"type": "string", "enum": ["admin", "user"]}
+		}
+	}}]}`))
+	assert.NoError(t, err)
+
+	schema, exists := registry.GetHandlerSchema("Register")
+	if assert.True(t, exists) {
+		properties := schema.RequestSchema.Properties
+		assert.Equal(t, "jane@example.com", properties["email"].Example)
+		assert.Equal(t, 18.0, *properties["age"].Minimum)
+		assert.Equal(t, 130.0, *properties["age"].Maximum)
+		assert.Equal(t, []string{"admin", "user"}, properties["role"].Enum)
+	}
+}
+
+func TestSchemaRegistry_StaticSchemaResponses(t *testing.T) {
+	registry := NewSchemaRegistry()
+	assert.NoError(t, registry.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [{"handlerName": "CreateUser",
+		"responses": {"201": {"type": "object", "properties": {"id": {"type": "string"}}}, "204": {}},
+		"examples": {"422": {"error": "email is invalid", "code": 422}}
+	}]}`)))
+
+	schema, exists := registry.GetHandlerSchema("CreateUser")
+	if assert.True(t, exists) {
+		assert.Contains(t, schema.Responses[201].Properties, "id")
+		assert.Equal(t, spec.Schema{}, schema.Responses[204])
+		assert.Empty(t, schema.ResponseSchema.Type)
+		assert.Equal(t, map[string]any{"error": "email is invalid", "code": 422.0}, schema.Examples[422])
+	}
+
+	err := registry.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [{"handlerName": "Broken", "responses": {"2xx": {}}}]}`))
+	assert.ErrorContains(t, err, `invalid response status "2xx" for Broken`)
+
+	err = registry.LoadSchemaBundleData([]byte(`{"version": 1, "schemas": [{"handlerName": "Broken", "examples": {"error": {}}}]}`))
+	assert.ErrorContains(t, err, `invalid example status "error" for Broken`)
+}
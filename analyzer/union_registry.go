@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// DefaultDiscriminatorProperty is the property that tells union variants apart when none is given
+const DefaultDiscriminatorProperty = "type"
+
+// Union lists the concrete types an interface can hold
+type Union struct {
+	Interface    reflect.Type
+	PropertyName string // Discriminator property present on every variant
+	Variants     []UnionVariant
+}
+
+// UnionVariant is one concrete type of a union and its discriminator value
+type UnionVariant struct {
+	Value string
+	Type  reflect.Type
+}
+
+// UnionRegistry holds the unions known to a SchemaGenerator
+//
+// Generators documenting the same service share one registry, see SchemaGenerator.SetUnions.
+type UnionRegistry struct {
+	mu          sync.RWMutex
+	byInterface map[reflect.Type]Union
+}
+
+// NewUnionRegistry creates an empty union registry
+func NewUnionRegistry() *UnionRegistry {
+	return &UnionRegistry{byInterface: make(map[reflect.Type]Union)}
+}
+
+// Register declares the concrete types an interface can hold
+//
+// Fields of the interface type are documented as a oneOf of the variants with a
+// discriminator on propertyName (DefaultDiscriminatorProperty when empty). Every
+// variant must implement the interface, either by value or by pointer.
+func (r *UnionRegistry) Register(iface reflect.Type, propertyName string, variants []UnionVariant) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("union type must be an interface, got %v", iface)
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("union %s needs at least one variant", iface)
+	}
+	if propertyName == "" {
+		propertyName = DefaultDiscriminatorProperty
+	}
+
+	seen := make(map[string]bool, len(variants))
+	union := Union{Interface: iface, PropertyName: propertyName}
+	for _, variant := range variants {
+		if variant.Type == nil {
+			return fmt.Errorf("union %s has a nil variant", iface)
+		}
+		for variant.Type.Kind() == reflect.Pointer {
+			variant.Type = variant.Type.Elem()
+		}
+		if !variant.Type.Implements(iface) && !reflect.PointerTo(variant.Type).Implements(iface) {
+			return fmt.Errorf("%s does not implement %s", variant.Type, iface)
+		}
+		if variant.Value == "" {
+			variant.Value = GoTypeSchemaName(variant.Type)
+		}
+		if seen[variant.Value] {
+			return fmt.Errorf("union %s has duplicate discriminator value %q", iface, variant.Value)
+		}
+		seen[variant.Value] = true
+		union.Variants = append(union.Variants, variant)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byInterface[iface] = union
+	return nil
+}
+
+// Lookup returns the union registered for an interface type
+func (r *UnionRegistry) Lookup(iface reflect.Type) (Union, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	union, exists := r.byInterface[iface]
+	return union, exists
+}
+
+// All returns every registered union, ordered by interface name
+func (r *UnionRegistry) All() []Union {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered := make([]Union, 0, len(r.byInterface))
+	for _, union := range r.byInterface {
+		registered = append(registered, union)
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Interface.String() < registered[j].Interface.String()
+	})
+	return registered
+}
+
+// Schema returns the oneOf schema referencing each variant component
+func (u Union) Schema() spec.Schema {
+	schema := spec.Schema{
+		Discriminator: &spec.Discriminator{
+			PropertyName: u.PropertyName,
+			Mapping:      make(map[string]string, len(u.Variants)),
+		},
+	}
+	for _, variant := range u.Variants {
+		ref := componentRefPrefix + GoTypeSchemaName(variant.Type)
+		schema.OneOf = append(schema.OneOf, spec.Schema{Ref: ref})
+		schema.Discriminator.Mapping[variant.Value] = ref
+	}
+	return schema
+}
+
+// VariantSchema generates the component schema of a variant, with the discriminator property pinned to its value
+func (u Union) VariantSchema(generator *SchemaGenerator, variant UnionVariant) spec.Schema {
+	schema := generator.GenerateSchemaFromType(variant.Type)
+
+	// Copy before changing, the generator caches schemas and shares their maps
+	properties := make(map[string]spec.Schema, len(schema.Properties)+1)
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+	discriminator, exists := properties[u.PropertyName]
+	if !exists {
+		discriminator = spec.Schema{Type: "string"}
+	}
+	discriminator.Enum = []string{variant.Value}
+	properties[u.PropertyName] = discriminator
+	schema.Properties = properties
+
+	required := append([]string{}, schema.Required...)
+	found := false
+	for _, name := range required {
+		if name == u.PropertyName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		required = append(required, u.PropertyName)
+	}
+	schema.Required = required
+	return schema
+}
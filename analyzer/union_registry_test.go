@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unionShape interface{ Area() float64 }
+
+type unionCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c unionCircle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type unionSquare struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (s *unionSquare) Area() float64 { return s.Side * s.Side }
+
+type unionDrawing struct {
+	Shapes []unionShape `json:"shapes"`
+}
+
+func TestUnionRegistry(t *testing.T) {
+	shape := reflect.TypeOf((*unionShape)(nil)).Elem()
+	registry := NewSchemaRegistry()
+	err := registry.GetSchemaGenerator().Unions().Register(shape, "kind", []UnionVariant{
+		{Type: reflect.TypeOf(unionCircle{})},
+		{Value: "square", Type: reflect.TypeOf(&unionSquare{})},
+	})
+	assert.NoError(t, err)
+
+	schema := registry.GetSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(unionDrawing{}))
+	items := schema.Properties["shapes"].Items
+	if assert.NotNil(t, items) && assert.NotNil(t, items.Discriminator) {
+		assert.Equal(t, "kind", items.Discriminator.PropertyName)
+		assert.Equal(t, map[string]string{
+			"unionCircle": "#/components/schemas/unionCircle",
+			"square":      "#/components/schemas/unionSquare",
+		}, items.Discriminator.Mapping)
+		assert.Equal(t, []spec.Schema{
+			{Ref: "#/components/schemas/unionCircle"},
+			{Ref: "#/components/schemas/unionSquare"},
+		}, items.OneOf)
+	}
+
+	// Variant components pin the discriminator property to their value
+	schemas := registry.GetAllSchemas()
+	circle := schemas["unionCircle"]
+	assert.Equal(t, []string{"unionCircle"}, circle.Properties["kind"].Enum)
+	assert.Contains(t, circle.Required, "kind")
+	square := schemas["unionSquare"]
+	assert.Equal(t, []string{"square"}, square.Properties["kind"].Enum)
+	assert.Contains(t, square.Properties, "side")
+
+	// Unions belong to their generator, others document the interface as a plain object
+	other := NewSchemaRegistry()
+	assert.Nil(t, other.GetSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(unionDrawing{})).Properties["shapes"].Items.Discriminator)
+	assert.NotContains(t, other.GetAllSchemas(), "unionCircle")
+}
+
+func TestUnionRegistryErrors(t *testing.T) {
+	shape := reflect.TypeOf((*unionShape)(nil)).Elem()
+	unions := NewUnionRegistry()
+
+	assert.Error(t, unions.Register(reflect.TypeOf(unionCircle{}), "", []UnionVariant{{Type: reflect.TypeOf(unionCircle{})}}), "Union type must be an interface")
+	assert.Error(t, unions.Register(shape, "", nil), "Unions need variants")
+	assert.Error(t, unions.Register(shape, "", []UnionVariant{{Type: reflect.TypeOf(unionDrawing{})}}), "Variants must implement the interface")
+	assert.Error(t, unions.Register(shape, "", []UnionVariant{
+		{Value: "x", Type: reflect.TypeOf(unionCircle{})},
+		{Value: "x", Type: reflect.TypeOf(unionSquare{})},
+	}), "Discriminator values must be unique")
+}
@@ -0,0 +1,80 @@
+package asyncapi
+
+import "github.com/zainokta/openapi-gen/spec"
+
+// Version is the AsyncAPI specification version emitted by this package
+const Version = "2.6.0"
+
+// Document represents an AsyncAPI 2.6 document
+type Document struct {
+	AsyncAPI           string                 `json:"asyncapi"`
+	ID                 string                 `json:"id,omitempty"`
+	Info               Info                   `json:"info"`
+	Servers            map[string]Server      `json:"servers,omitempty"`
+	DefaultContentType string                 `json:"defaultContentType,omitempty"`
+	Channels           map[string]ChannelItem `json:"channels"`
+	Components         Components             `json:"components,omitempty"`
+	Tags               []spec.Tag             `json:"tags,omitempty"`
+}
+
+// Info provides metadata about the event-driven API
+type Info struct {
+	Title       string       `json:"title"`
+	Version     string       `json:"version"`
+	Description string       `json:"description,omitempty"`
+	Contact     spec.Contact `json:"contact,omitempty"`
+}
+
+// Server describes a message broker the application connects to
+type Server struct {
+	URL             string                         `json:"url"`
+	Protocol        string                         `json:"protocol"` // kafka, nats, ws, amqp, mqtt, ...
+	ProtocolVersion string                         `json:"protocolVersion,omitempty"`
+	Description     string                         `json:"description,omitempty"`
+	Variables       map[string]spec.ServerVariable `json:"variables,omitempty"`
+	Security        []spec.SecurityRequirement     `json:"security,omitempty"`
+}
+
+// ChannelItem describes the operations available on a single channel
+type ChannelItem struct {
+	Description string                 `json:"description,omitempty"`
+	Subscribe   *Operation             `json:"subscribe,omitempty"`
+	Publish     *Operation             `json:"publish,omitempty"`
+	Parameters  map[string]Parameter   `json:"parameters,omitempty"`
+	Bindings    map[string]interface{} `json:"bindings,omitempty"`
+}
+
+// Operation describes a publish or subscribe operation on a channel
+type Operation struct {
+	OperationID string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []spec.Tag             `json:"tags,omitempty"`
+	Message     Message                `json:"message"`
+	Bindings    map[string]interface{} `json:"bindings,omitempty"`
+}
+
+// Parameter describes a parameter included in a channel name
+type Parameter struct {
+	Description string      `json:"description,omitempty"`
+	Schema      spec.Schema `json:"schema,omitempty"`
+	Location    string      `json:"location,omitempty"`
+}
+
+// Message describes a message sent or received on a channel
+type Message struct {
+	Ref         string       `json:"$ref,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	Title       string       `json:"title,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	ContentType string       `json:"contentType,omitempty"`
+	Headers     *spec.Schema `json:"headers,omitempty"`
+	Payload     *spec.Schema `json:"payload,omitempty"`
+}
+
+// Components holds reusable messages and schemas
+type Components struct {
+	Messages map[string]Message     `json:"messages,omitempty"`
+	Schemas  map[string]spec.Schema `json:"schemas,omitempty"`
+}
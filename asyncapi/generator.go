@@ -0,0 +1,242 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// Action identifies which side of a channel the application operates on
+type Action string
+
+const (
+	// ActionSubscribe means the application sends messages to the channel and clients consume them
+	ActionSubscribe Action = "subscribe"
+	// ActionPublish means clients send messages to the channel and the application consumes them
+	ActionPublish Action = "publish"
+)
+
+// channelRegistration holds everything needed to render a channel
+type channelRegistration struct {
+	name        string
+	payload     reflect.Type
+	action      Action
+	description string
+	summary     string
+	operationID string
+	contentType string
+	tags        []string
+	bindings    map[string]interface{}
+}
+
+// ChannelOption configures a registered channel
+type ChannelOption func(*channelRegistration)
+
+// WithAction sets whether the channel is published or subscribed (default: subscribe)
+func WithAction(action Action) ChannelOption {
+	return func(c *channelRegistration) {
+		c.action = action
+	}
+}
+
+// WithDescription sets the channel description
+func WithDescription(description string) ChannelOption {
+	return func(c *channelRegistration) {
+		c.description = description
+	}
+}
+
+// WithSummary sets the operation summary
+func WithSummary(summary string) ChannelOption {
+	return func(c *channelRegistration) {
+		c.summary = summary
+	}
+}
+
+// WithOperationID sets the operation ID
+func WithOperationID(operationID string) ChannelOption {
+	return func(c *channelRegistration) {
+		c.operationID = operationID
+	}
+}
+
+// WithContentType sets the message content type, overriding the document default
+func WithContentType(contentType string) ChannelOption {
+	return func(c *channelRegistration) {
+		c.contentType = contentType
+	}
+}
+
+// WithTags sets the operation tags
+func WithTags(tags ...string) ChannelOption {
+	return func(c *channelRegistration) {
+		c.tags = tags
+	}
+}
+
+// WithBindings sets protocol-specific channel bindings (e.g. "kafka", "nats", "ws")
+func WithBindings(bindings map[string]interface{}) ChannelOption {
+	return func(c *channelRegistration) {
+		c.bindings = bindings
+	}
+}
+
+// Generator builds AsyncAPI documents from registered channels
+type Generator struct {
+	info            Info
+	servers         map[string]Server
+	channels        map[string]*channelRegistration
+	schemaGenerator *analyzer.SchemaGenerator
+}
+
+// NewGenerator creates a new AsyncAPI generator
+func NewGenerator(info Info) *Generator {
+	return &Generator{
+		info:            info,
+		servers:         make(map[string]Server),
+		channels:        make(map[string]*channelRegistration),
+		schemaGenerator: analyzer.NewSchemaGenerator(),
+	}
+}
+
+// AddServer registers a message broker under the given name
+func (g *Generator) AddServer(name string, server Server) {
+	g.servers[name] = server
+}
+
+// RegisterChannel registers a channel and the Go type of the messages it carries
+func (g *Generator) RegisterChannel(name string, payload reflect.Type, opts ...ChannelOption) error {
+	if name == "" {
+		return fmt.Errorf("channel name is required")
+	}
+	if payload == nil {
+		return fmt.Errorf("payload type is required for channel %s", name)
+	}
+	if _, exists := g.channels[name]; exists {
+		return fmt.Errorf("channel %s is already registered", name)
+	}
+
+	registration := &channelRegistration{
+		name:    name,
+		payload: payload,
+		action:  ActionSubscribe,
+	}
+	for _, opt := range opts {
+		opt(registration)
+	}
+
+	if registration.action != ActionSubscribe && registration.action != ActionPublish {
+		return fmt.Errorf("invalid action %q for channel %s", registration.action, name)
+	}
+
+	g.channels[name] = registration
+	return nil
+}
+
+// Generate builds the AsyncAPI document
+func (g *Generator) Generate() *Document {
+	document := &Document{
+		AsyncAPI:           Version,
+		Info:               g.info,
+		DefaultContentType: "application/json",
+		Channels:           make(map[string]ChannelItem),
+		Components: Components{
+			Messages: make(map[string]Message),
+			Schemas:  make(map[string]spec.Schema),
+		},
+	}
+
+	if len(g.servers) > 0 {
+		document.Servers = g.servers
+	}
+
+	// Iterate in sorted order so shared message names resolve the same way every run
+	names := make([]string, 0, len(g.channels))
+	for name := range g.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Go types of the component schemas, two types sharing a name must not overwrite each other
+	schemaTypes := make(map[string]reflect.Type)
+
+	for _, name := range names {
+		registration := g.channels[name]
+		messageName := g.registerMessage(document, schemaTypes, registration)
+
+		operation := &Operation{
+			OperationID: registration.operationID,
+			Summary:     registration.summary,
+			Message:     Message{Ref: "#/components/messages/" + messageName},
+		}
+		for _, tag := range registration.tags {
+			operation.Tags = append(operation.Tags, spec.Tag{Name: tag})
+		}
+
+		item := ChannelItem{
+			Description: registration.description,
+			Bindings:    registration.bindings,
+		}
+		if registration.action == ActionPublish {
+			item.Publish = operation
+		} else {
+			item.Subscribe = operation
+		}
+
+		document.Channels[name] = item
+	}
+
+	return document
+}
+
+// registerMessage adds the payload schema and message to the document components
+//
+// Payloads are named after their Go type. Anonymous types, and a type whose name is already
+// taken by a type from another package, are named after the channel instead.
+func (g *Generator) registerMessage(document *Document, schemaTypes map[string]reflect.Type, registration *channelRegistration) string {
+	payloadType := registration.payload
+	for payloadType.Kind() == reflect.Ptr {
+		payloadType = payloadType.Elem()
+	}
+
+	messageName := analyzer.GoTypeSchemaName(payloadType)
+	if existing, taken := schemaTypes[messageName]; messageName == "" || taken && existing != payloadType {
+		messageName = registration.name
+	}
+
+	if _, exists := document.Components.Schemas[messageName]; !exists {
+		document.Components.Schemas[messageName] = g.schemaGenerator.GenerateSchemaFromType(payloadType)
+		schemaTypes[messageName] = payloadType
+	}
+
+	message := Message{
+		Name:        messageName,
+		Title:       messageName,
+		ContentType: registration.contentType,
+		Payload:     &spec.Schema{Ref: "#/components/schemas/" + messageName},
+	}
+
+	// Same payload with a different content type needs its own message entry
+	if existing, exists := document.Components.Messages[messageName]; exists && existing.ContentType != message.ContentType {
+		messageName = messageName + "." + registration.name
+		message.Name = messageName
+	}
+	document.Components.Messages[messageName] = message
+
+	return messageName
+}
+
+// WriteJSON writes the AsyncAPI document as indented JSON
+func (g *Generator) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g.Generate()); err != nil {
+		return fmt.Errorf("failed to write AsyncAPI document: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package asyncapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type UserCreated struct {
+	ID    string `json:"id" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+type OrderPlaced struct {
+	OrderID string  `json:"order_id"`
+	Total   float64 `json:"total"`
+}
+
+func TestGenerator_RegisterChannel(t *testing.T) {
+	g := NewGenerator(Info{Title: "Events", Version: "1.0.0"})
+
+	assert.NoError(t, g.RegisterChannel("user.created", reflect.TypeOf(UserCreated{})))
+	assert.Error(t, g.RegisterChannel("user.created", reflect.TypeOf(UserCreated{})), "duplicate channel should be rejected")
+	assert.Error(t, g.RegisterChannel("", reflect.TypeOf(UserCreated{})))
+	assert.Error(t, g.RegisterChannel("order.placed", nil))
+	assert.Error(t, g.RegisterChannel("order.placed", reflect.TypeOf(OrderPlaced{}), WithAction("listen")))
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := NewGenerator(Info{Title: "Events", Version: "1.0.0"})
+	g.AddServer("production", Server{URL: "kafka.example.com:9092", Protocol: "kafka"})
+
+	assert.NoError(t, g.RegisterChannel("user.created", reflect.TypeOf(UserCreated{}),
+		WithDescription("Emitted after a user signs up"),
+		WithOperationID("onUserCreated"),
+		WithTags("users"),
+	))
+	assert.NoError(t, g.RegisterChannel("orders.place", reflect.TypeOf(&OrderPlaced{}), WithAction(ActionPublish)))
+
+	document := g.Generate()
+
+	assert.Equal(t, "2.6.0", document.AsyncAPI)
+	assert.Equal(t, "kafka", document.Servers["production"].Protocol)
+
+	userChannel := document.Channels["user.created"]
+	assert.Equal(t, "Emitted after a user signs up", userChannel.Description)
+	assert.Nil(t, userChannel.Publish)
+	if assert.NotNil(t, userChannel.Subscribe) {
+		assert.Equal(t, "onUserCreated", userChannel.Subscribe.OperationID)
+		assert.Equal(t, "#/components/messages/UserCreated", userChannel.Subscribe.Message.Ref)
+		assert.Equal(t, "users", userChannel.Subscribe.Tags[0].Name)
+	}
+
+	orderChannel := document.Channels["orders.place"]
+	assert.Nil(t, orderChannel.Subscribe)
+	if assert.NotNil(t, orderChannel.Publish) {
+		assert.Equal(t, "#/components/messages/OrderPlaced", orderChannel.Publish.Message.Ref)
+	}
+
+	userSchema := document.Components.Schemas["UserCreated"]
+	assert.Equal(t, "object", userSchema.Type)
+	assert.Contains(t, userSchema.Properties, "email")
+	assert.ElementsMatch(t, []string{"id", "email"}, userSchema.Required)
+	assert.Equal(t, "#/components/schemas/UserCreated", document.Components.Messages["UserCreated"].Payload.Ref)
+}
+
+func TestGenerator_GenerateTypeNameCollision(t *testing.T) {
+	orderPlaced := reflect.TypeOf(OrderPlaced{})
+
+	// Shares its name with the package-level OrderPlaced, as a type from another package would
+	type OrderPlaced struct {
+		Reference string `json:"reference"`
+	}
+
+	g := NewGenerator(Info{Title: "Events", Version: "1.0.0"})
+	assert.NoError(t, g.RegisterChannel("orders.placed", orderPlaced))
+	assert.NoError(t, g.RegisterChannel("billing.placed", reflect.TypeOf(OrderPlaced{})))
+	assert.NoError(t, g.RegisterChannel("shipping.placed", reflect.TypeOf(&OrderPlaced{})))
+
+	document := g.Generate()
+
+	// billing.placed sorts first and claims the type name
+	assert.Equal(t, "#/components/messages/OrderPlaced", document.Channels["billing.placed"].Subscribe.Message.Ref)
+	assert.Equal(t, "#/components/messages/orders.placed", document.Channels["orders.placed"].Subscribe.Message.Ref)
+	assert.Equal(t, "#/components/messages/OrderPlaced", document.Channels["shipping.placed"].Subscribe.Message.Ref)
+
+	assert.Contains(t, document.Components.Schemas["OrderPlaced"].Properties, "reference")
+	assert.Contains(t, document.Components.Schemas["orders.placed"].Properties, "order_id")
+	assert.Equal(t, "#/components/schemas/orders.placed", document.Components.Messages["orders.placed"].Payload.Ref)
+}
+
+func TestGenerator_WriteJSON(t *testing.T) {
+	g := NewGenerator(Info{Title: "Events", Version: "1.0.0"})
+	assert.NoError(t, g.RegisterChannel("user.created", reflect.TypeOf(UserCreated{})))
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.WriteJSON(&buf))
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "2.6.0", decoded["asyncapi"])
+	assert.Contains(t, decoded["channels"], "user.created")
+}
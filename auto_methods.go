@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// isAutoMethod reports whether a route is a HEAD or OPTIONS variant of another route on the same path
+//
+// Frameworks and CORS setups register those variants next to the real route, documenting
+// them only adds operations without a meaning of their own. A HEAD or OPTIONS route alone
+// on its path is kept. Config.KeepAutoMethods turns the filtering off.
+func (g *Generator) isAutoMethod(route spec.RouteInfo) bool {
+	if g.config.KeepAutoMethods {
+		return false
+	}
+
+	switch strings.ToUpper(route.Method) {
+	case "HEAD":
+		_, exists := g.routes[routeKey("GET", route.Path)]
+		return exists
+	case "OPTIONS":
+		for _, analyzed := range g.routes {
+			if analyzed.route.Path == route.Path && !strings.EqualFold(analyzed.route.Method, "OPTIONS") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addCORSPreflights documents the CORS preflight of every path served behind a CORS middleware
+//
+// The preflight is answered by the middleware, so a single OPTIONS operation per path is
+// added, listing the methods of the path item. Paths with a documented OPTIONS operation
+// are left alone. Only runs with Config.DocumentCORS.
+func (g *Generator) addCORSPreflights() {
+	if !g.config.DocumentCORS {
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(g.routes)) {
+		analyzed := g.routes[key]
+		if analyzed.hidden || !analyzed.route.CORS {
+			continue
+		}
+		path := g.specPath(analyzed.route.Path)
+		pathItem, exists := g.spec.Paths[path]
+		if !exists || pathItem.Options != nil {
+			continue
+		}
+		preflight := corsPreflightOperation(g.generateOperationID("OPTIONS", analyzed.route.Path), pathItem)
+		preflight.Parameters = append(pathParameters(analyzed.route.Path), preflight.Parameters...)
+		pathItem.Options = preflight
+		g.spec.Paths[path] = pathItem
+	}
+}
+
+// corsPreflightOperation builds the OPTIONS operation answering the CORS preflight of a path item
+func corsPreflightOperation(operationID string, pathItem spec.PathItem) *spec.Operation {
+	var methods []string
+	for _, method := range []struct {
+		name      string
+		operation *spec.Operation
+	}{
+		{"GET", pathItem.Get}, {"POST", pathItem.Post}, {"PUT", pathItem.Put}, {"PATCH", pathItem.Patch},
+		{"DELETE", pathItem.Delete}, {"HEAD", pathItem.Head}, {"TRACE", pathItem.Trace},
+	} {
+		if method.operation != nil {
+			methods = append(methods, method.name)
+		}
+	}
+
+	stringHeader := func(description string) spec.Header {
+		return spec.Header{Description: description, Schema: spec.Schema{Type: "string"}}
+	}
+	return &spec.Operation{
+		Summary:     "CORS preflight",
+		Description: "Answered by the CORS middleware, browsers send it before cross-origin requests.",
+		OperationID: operationID,
+		Parameters: []spec.Parameter{
+			{Name: "Origin", In: "header", Required: true, Schema: spec.Schema{Type: "string"}},
+			{Name: "Access-Control-Request-Method", In: "header", Required: true, Schema: spec.Schema{Type: "string", Enum: methods}},
+			{Name: "Access-Control-Request-Headers", In: "header", Schema: spec.Schema{Type: "string"}},
+		},
+		Responses: map[string]spec.Response{
+			"204": {
+				Description: "Cross-origin request allowed",
+				Headers: map[string]spec.Header{
+					"Access-Control-Allow-Origin":  stringHeader("Origin allowed to make the request"),
+					"Access-Control-Allow-Methods": stringHeader("Methods allowed: " + strings.Join(methods, ", ")),
+					"Access-Control-Allow-Headers": stringHeader("Request headers allowed"),
+					"Access-Control-Max-Age":       {Description: "Seconds the preflight result can be cached", Schema: spec.Schema{Type: "integer"}},
+				},
+			},
+		},
+		Security: []spec.SecurityRequirement{},
+	}
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestAutoMethodsAreLeftOut(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "HEAD", Path: "/users"},
+		{Method: "OPTIONS", Path: "/users"},
+		{Method: "HEAD", Path: "/ping"},
+		{Method: "OPTIONS", Path: "/capabilities"},
+	}
+
+	openAPISpec, err := newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotNil(t, openAPISpec.Paths["/users"].Get)
+	assert.Nil(t, openAPISpec.Paths["/users"].Head)
+	assert.Nil(t, openAPISpec.Paths["/users"].Options)
+
+	// Alone on their path, HEAD and OPTIONS routes are real endpoints
+	assert.NotNil(t, openAPISpec.Paths["/ping"].Head)
+	assert.NotNil(t, openAPISpec.Paths["/capabilities"].Options)
+
+	cfg := NewConfig(WithKeepAutoMethods(true))
+	cfg.SchemaDir = ""
+	openAPISpec, err = newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotNil(t, openAPISpec.Paths["/users"].Head)
+	assert.NotNil(t, openAPISpec.Paths["/users"].Options)
+}
+
+func TestDocumentCORS(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", CORS: true},
+		{Method: "PUT", Path: "/users/:id", CORS: true},
+		{Method: "GET", Path: "/health"},
+	}
+
+	openAPISpec, err := newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Nil(t, openAPISpec.Paths["/users/{id}"].Options)
+
+	cfg := NewConfig(WithDocumentCORS(true))
+	cfg.SchemaDir = ""
+	openAPISpec, err = newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Nil(t, openAPISpec.Paths["/health"].Options)
+
+	preflight := openAPISpec.Paths["/users/{id}"].Options
+	if !assert.NotNil(t, preflight) {
+		return
+	}
+	assert.Equal(t, "id", preflight.Parameters[0].Name)
+	assert.Equal(t, "path", preflight.Parameters[0].In)
+	if assert.Contains(t, preflight.Responses, "204") {
+		assert.Contains(t, preflight.Responses["204"].Headers, "Access-Control-Allow-Origin")
+		assert.Equal(t, "Methods allowed: GET, PUT", preflight.Responses["204"].Headers["Access-Control-Allow-Methods"].Description)
+	}
+}
@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// EnumCasing is the casing of enum member names, see WithEnumVarNames
+type EnumCasing string
+
+const (
+	// EnumCasingPascal names "in_progress" InProgress, as C# and TypeScript enum members
+	EnumCasingPascal EnumCasing = "pascal"
+	// EnumCasingCamel names "in_progress" inProgress
+	EnumCasingCamel EnumCasing = "camel"
+	// EnumCasingUpperSnake names "in_progress" IN_PROGRESS, as Java and Kotlin constants
+	EnumCasingUpperSnake EnumCasing = "upper_snake"
+)
+
+// NullableStyle is how nullable schemas are written, see WithNullableStyle
+type NullableStyle string
+
+const (
+	// NullableKeyword writes nullable: true, next to $ref for nullable references
+	NullableKeyword NullableStyle = "nullable"
+	// NullableAllOf writes nullable: true and wraps nullable references in an allOf, OpenAPI
+	// 3.0 ignores keywords next to $ref and so do NSwag and openapi-generator
+	NullableAllOf NullableStyle = "allOf"
+	// NullableExtension writes x-nullable: true instead of nullable, for generators that
+	// started with Swagger 2.0 such as AutoRest
+	NullableExtension NullableStyle = "x-nullable"
+)
+
+// WithEnumVarNames names the members of every enum for client generators
+//
+// Enums get x-enum-varnames, which openapi-generator and NSwag use as member names instead
+// of deriving them from the values on their own. Names are built from the words of each
+// value in the given casing, e.g. "in_progress" or "inProgress" is InProgress for
+// EnumCasingPascal; names starting with a digit are prefixed with Value and names taken
+// twice are numbered. Enums that already have names keep them.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithEnumVarNames(openapi.EnumCasingPascal),
+//	)
+func WithEnumVarNames(casing EnumCasing) Option {
+	return func(opts *Options) {
+		switch casing {
+		case EnumCasingPascal, EnumCasingCamel, EnumCasingUpperSnake:
+			opts.enumCasing = casing
+		default:
+			opts.conflicts = append(opts.conflicts, fmt.Errorf("unknown enum casing %q", casing))
+		}
+	}
+}
+
+// WithNullableStyle chooses how nullable schemas are written, NullableKeyword by default
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithNullableStyle(openapi.NullableAllOf),
+//	)
+func WithNullableStyle(style NullableStyle) Option {
+	return func(opts *Options) {
+		switch style {
+		case NullableKeyword, NullableAllOf, NullableExtension:
+			opts.nullableStyle = style
+		default:
+			opts.conflicts = append(opts.conflicts, fmt.Errorf("unknown nullable style %q", style))
+		}
+	}
+}
+
+// applyClientNaming names enum members and rewrites nullable schemas in the built spec, see
+// WithEnumVarNames and WithNullableStyle
+func (g *Generator) applyClientNaming(openAPISpec *spec.OpenAPISpec) {
+	if g.enumCasing == "" && (g.nullableStyle == "" || g.nullableStyle == NullableKeyword) {
+		return
+	}
+	rewrite := func(schema spec.Schema) spec.Schema {
+		return rewriteSchemas(schema, func(schema spec.Schema) spec.Schema {
+			if g.enumCasing != "" && len(schema.Enum) > 0 && len(schema.XEnumVarNames) != len(schema.Enum) {
+				schema.XEnumVarNames = enumVarNames(schema.Enum, g.enumCasing)
+			}
+			return nullableSchema(schema, g.nullableStyle)
+		})
+	}
+	rewriteSpecSchemas(openAPISpec, rewrite)
+}
+
+// nullableSchema writes a nullable schema in a style
+func nullableSchema(schema spec.Schema, style NullableStyle) spec.Schema {
+	if !schema.Nullable {
+		return schema
+	}
+	switch style {
+	case NullableAllOf:
+		if schema.Ref != "" {
+			ref := spec.Schema{Ref: schema.Ref}
+			schema.Ref = ""
+			schema.AllOf = append([]spec.Schema{ref}, schema.AllOf...)
+		}
+	case NullableExtension:
+		schema.Nullable, schema.XNullable = false, true
+	}
+	return schema
+}
+
+// rewriteSpecSchemas replaces every schema of a spec, nested ones included, by its rewrite
+//
+// Maps and slices are replaced rather than changed, the built spec shares some of them
+// between operations.
+func rewriteSpecSchemas(openAPISpec *spec.OpenAPISpec, rewrite func(spec.Schema) spec.Schema) {
+	components := &openAPISpec.Components
+	if components.Schemas != nil {
+		schemas := make(map[string]spec.Schema, len(components.Schemas))
+		for name, schema := range components.Schemas {
+			schemas[name] = rewrite(schema)
+		}
+		components.Schemas = schemas
+	}
+	if components.Parameters != nil {
+		parameters := make(map[string]spec.Parameter, len(components.Parameters))
+		for name, parameter := range components.Parameters {
+			parameters[name] = rewriteParameterSchemas([]spec.Parameter{parameter}, rewrite)[0]
+		}
+		components.Parameters = parameters
+	}
+	if components.RequestBodies != nil {
+		requestBodies := make(map[string]spec.RequestBody, len(components.RequestBodies))
+		for name, requestBody := range components.RequestBodies {
+			requestBody.Content = rewriteContentSchemas(requestBody.Content, rewrite)
+			requestBodies[name] = requestBody
+		}
+		components.RequestBodies = requestBodies
+	}
+	components.Responses = rewriteResponseSchemas(components.Responses, rewrite)
+	components.Headers = rewriteHeaderSchemas(components.Headers, rewrite)
+
+	for path, pathItem := range openAPISpec.Paths {
+		pathItem.Parameters = rewriteParameterSchemas(pathItem.Parameters, rewrite)
+		for _, operation := range []**spec.Operation{
+			&pathItem.Get, &pathItem.Put, &pathItem.Post, &pathItem.Delete,
+			&pathItem.Options, &pathItem.Head, &pathItem.Patch, &pathItem.Trace,
+		} {
+			if *operation == nil {
+				continue
+			}
+			rewritten := **operation
+			rewritten.Parameters = rewriteParameterSchemas(rewritten.Parameters, rewrite)
+			if rewritten.RequestBody != nil {
+				requestBody := *rewritten.RequestBody
+				requestBody.Content = rewriteContentSchemas(requestBody.Content, rewrite)
+				rewritten.RequestBody = &requestBody
+			}
+			rewritten.Responses = rewriteResponseSchemas(rewritten.Responses, rewrite)
+			*operation = &rewritten
+		}
+		openAPISpec.Paths[path] = pathItem
+	}
+}
+
+// rewriteParameterSchemas returns a copy of parameters with their schemas rewritten
+func rewriteParameterSchemas(parameters []spec.Parameter, rewrite func(spec.Schema) spec.Schema) []spec.Parameter {
+	if parameters == nil {
+		return nil
+	}
+	rewritten := make([]spec.Parameter, len(parameters))
+	for i, parameter := range parameters {
+		if parameter.Ref == "" {
+			parameter.Schema = rewrite(parameter.Schema)
+			parameter.Content = rewriteContentSchemas(parameter.Content, rewrite)
+		}
+		rewritten[i] = parameter
+	}
+	return rewritten
+}
+
+// rewriteResponseSchemas returns a copy of responses with the schemas of their content and headers rewritten
+func rewriteResponseSchemas(responses map[string]spec.Response, rewrite func(spec.Schema) spec.Schema) map[string]spec.Response {
+	if responses == nil {
+		return nil
+	}
+	rewritten := make(map[string]spec.Response, len(responses))
+	for code, response := range responses {
+		response.Content = rewriteContentSchemas(response.Content, rewrite)
+		response.Headers = rewriteHeaderSchemas(response.Headers, rewrite)
+		rewritten[code] = response
+	}
+	return rewritten
+}
+
+// rewriteHeaderSchemas returns a copy of headers with their schemas rewritten
+func rewriteHeaderSchemas(headers map[string]spec.Header, rewrite func(spec.Schema) spec.Schema) map[string]spec.Header {
+	if headers == nil {
+		return nil
+	}
+	rewritten := make(map[string]spec.Header, len(headers))
+	for name, header := range headers {
+		if header.Ref == "" {
+			header.Schema = rewrite(header.Schema)
+		}
+		rewritten[name] = header
+	}
+	return rewritten
+}
+
+// rewriteContentSchemas returns a copy of content with the schemas of its media types rewritten
+func rewriteContentSchemas(content map[string]spec.MediaType, rewrite func(spec.Schema) spec.Schema) map[string]spec.MediaType {
+	if content == nil {
+		return nil
+	}
+	rewritten := make(map[string]spec.MediaType, len(content))
+	for contentType, mediaType := range content {
+		mediaType.Schema = rewrite(mediaType.Schema)
+		rewritten[contentType] = mediaType
+	}
+	return rewritten
+}
+
+// rewriteSchemas rewrites the subschemas of a schema, then the schema itself, into copies
+func rewriteSchemas(schema spec.Schema, rewrite func(spec.Schema) spec.Schema) spec.Schema {
+	if schema.Properties != nil {
+		properties := make(map[string]spec.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = rewriteSchemas(property, rewrite)
+		}
+		schema.Properties = properties
+	}
+	for _, subschema := range []**spec.Schema{&schema.Items, &schema.AdditionalProperties, &schema.Not} {
+		if *subschema != nil {
+			rewritten := rewriteSchemas(**subschema, rewrite)
+			*subschema = &rewritten
+		}
+	}
+	for _, list := range []*[]spec.Schema{&schema.AllOf, &schema.OneOf, &schema.AnyOf} {
+		if *list == nil {
+			continue
+		}
+		rewritten := make([]spec.Schema, len(*list))
+		for i, member := range *list {
+			rewritten[i] = rewriteSchemas(member, rewrite)
+		}
+		*list = rewritten
+	}
+	return rewrite(schema)
+}
+
+// enumVarNames names the members of an enum in a casing, each name is unique
+func enumVarNames(values []string, casing EnumCasing) []string {
+	names := make([]string, len(values))
+	taken := make(map[string]bool, len(values))
+	for i, value := range values {
+		words := enumWords(value)
+		if len(words) == 0 {
+			words = []string{"empty"}
+		}
+		if strings.HasPrefix(strings.TrimSpace(value), "-") {
+			words = append([]string{"minus"}, words...) // -1 and 1 are both members
+		}
+		if unicode.IsDigit([]rune(words[0])[0]) {
+			words = append([]string{"value"}, words...)
+		}
+
+		var name string
+		switch casing {
+		case EnumCasingUpperSnake:
+			name = strings.ToUpper(strings.Join(words, "_"))
+		default:
+			var b strings.Builder
+			for j, word := range words {
+				if j == 0 && casing == EnumCasingCamel {
+					b.WriteString(word)
+					continue
+				}
+				letters := []rune(word)
+				letters[0] = unicode.ToUpper(letters[0])
+				b.WriteString(string(letters))
+			}
+			name = b.String()
+		}
+
+		unique := name
+		for n := 2; taken[unique]; n++ {
+			separator := ""
+			if casing == EnumCasingUpperSnake {
+				separator = "_"
+			}
+			unique = name + separator + strconv.Itoa(n)
+		}
+		taken[unique] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// enumWords splits an enum value into lower case words at separators and case changes,
+// e.g. "HTTPServer-error" is http, server and error
+func enumWords(value string) []string {
+	runes := []rune(value)
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			previous := word[len(word)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// A new word starts at "S" in "userStatus" and in "HTTPServer"
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestEnumVarNames(t *testing.T) {
+	values := []string{"in_progress", "inProgress", "HTTPServer-error", "1", "-1", "", "v2"}
+
+	assert.Equal(t, []string{"InProgress", "InProgress2", "HttpServerError", "Value1", "Minus1", "Empty", "V2"},
+		enumVarNames(values, EnumCasingPascal))
+	assert.Equal(t, []string{"inProgress", "inProgress2", "httpServerError", "value1", "minus1", "empty", "v2"},
+		enumVarNames(values, EnumCasingCamel))
+	assert.Equal(t, []string{"IN_PROGRESS", "IN_PROGRESS_2", "HTTP_SERVER_ERROR", "VALUE_1", "MINUS_1", "EMPTY", "V2"},
+		enumVarNames(values, EnumCasingUpperSnake))
+}
+
+func clientNamingSpec() *spec.OpenAPISpec {
+	status := spec.Schema{Type: "string", Enum: []string{"active", "on_hold"}}
+	return &spec.OpenAPISpec{
+		Paths: map[string]spec.PathItem{
+			"/orders": {Get: &spec.Operation{
+				Parameters: []spec.Parameter{{Name: "status", In: "query", Schema: status}},
+				Responses: map[string]spec.Response{"200": {
+					Description: "OK",
+					Content:     map[string]spec.MediaType{"application/json": {Schema: spec.Schema{Ref: "#/components/schemas/Order"}}},
+				}},
+			}},
+		},
+		Components: spec.Components{Schemas: map[string]spec.Schema{
+			"Order": {Type: "object", Properties: map[string]spec.Schema{
+				"status":   status,
+				"note":     {Type: "string", Nullable: true},
+				"customer": {Ref: "#/components/schemas/Customer", Nullable: true, Description: "Buyer"},
+				"priority": {Type: "integer", Enum: []string{"1", "2"}, XEnumVarNames: []string{"Low", "High"}},
+			}},
+			"Customer": {Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}},
+		}},
+	}
+}
+
+func TestWithEnumVarNames(t *testing.T) {
+	generator := newTestGenerator(t, nil, WithEnumVarNames(EnumCasingPascal))
+	require.NoError(t, generator.MergeSpec(clientNamingSpec()))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	order := openAPISpec.Components.Schemas["Order"]
+	assert.Equal(t, []string{"Active", "OnHold"}, order.Properties["status"].XEnumVarNames)
+	assert.Equal(t, []string{"Low", "High"}, order.Properties["priority"].XEnumVarNames, "names given in the spec are kept")
+	assert.Equal(t, []string{"Active", "OnHold"}, openAPISpec.Paths["/orders"].Get.Parameters[0].Schema.XEnumVarNames)
+
+	data, err := json.Marshal(order.Properties["status"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string","enum":["active","on_hold"],"x-enum-varnames":["Active","OnHold"]}`, string(data))
+
+	// Without the option enums are not named
+	generator = newTestGenerator(t, nil)
+	require.NoError(t, generator.MergeSpec(clientNamingSpec()))
+	openAPISpec, err = generator.GenerateSpec()
+	require.NoError(t, err)
+	assert.Empty(t, openAPISpec.Components.Schemas["Order"].Properties["status"].XEnumVarNames)
+}
+
+func TestWithNullableStyle(t *testing.T) {
+	generate := func(style NullableStyle) spec.Schema {
+		generator := newTestGenerator(t, nil, WithNullableStyle(style))
+		require.NoError(t, generator.MergeSpec(clientNamingSpec()))
+		openAPISpec, err := generator.GenerateSpec()
+		require.NoError(t, err)
+		return openAPISpec.Components.Schemas["Order"]
+	}
+
+	order := generate(NullableKeyword)
+	assert.True(t, order.Properties["note"].Nullable)
+	assert.Equal(t, "#/components/schemas/Customer", order.Properties["customer"].Ref)
+
+	order = generate(NullableAllOf)
+	assert.True(t, order.Properties["note"].Nullable)
+	customer := order.Properties["customer"]
+	assert.Empty(t, customer.Ref)
+	assert.Equal(t, []spec.Schema{{Ref: "#/components/schemas/Customer"}}, customer.AllOf)
+	assert.True(t, customer.Nullable)
+	assert.Equal(t, "Buyer", customer.Description)
+
+	order = generate(NullableExtension)
+	note := order.Properties["note"]
+	assert.False(t, note.Nullable)
+	assert.True(t, note.XNullable)
+	data, err := json.Marshal(note)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string","x-nullable":true}`, string(data))
+}
+
+func TestClientNamingOptionsRejectUnknownValues(t *testing.T) {
+	_, err := NewGenerator(nil, nil, processOptions(
+		WithRouteDiscoverer(&staticDiscoverer{}),
+		WithEnumVarNames("kebab"),
+		WithNullableStyle("oneOf"),
+	))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown enum casing "kebab"`)
+	assert.Contains(t, err.Error(), `unknown nullable style "oneOf"`)
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// untaggedDocsPage is the page of operations without tags
+const untaggedDocsPage = "default"
+
+// docsSite is the reference documentation of a spec, rendered as one page per tag
+type docsSite struct {
+	Title       string
+	Version     string
+	Description string
+	Extension   string // File extension of the pages, e.g. ".md"
+	Tags        []*docsTag
+	Schemas     []docsSchema
+}
+
+// docsTag is the page of a tag, listing its operations
+type docsTag struct {
+	Name        string
+	Description string
+	File        string
+	Operations  []docsOperation
+}
+
+// docsOperation documents an operation of a tag page
+type docsOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Parameters  []docsField
+	RequestBody *docsBody
+	Responses   []docsResponse
+}
+
+// docsBody is a request body, its properties are listed when its schema is not a component
+type docsBody struct {
+	Description string
+	ContentType string
+	Required    bool
+	Type        docsType
+	Properties  []docsField
+}
+
+// docsResponse is a row of the responses table of an operation
+type docsResponse struct {
+	Status      string
+	Description string
+	ContentType string
+	Type        docsType
+	HasBody     bool
+}
+
+// docsField is a row of a parameter or property table
+type docsField struct {
+	Name        string
+	In          string // Location of a parameter, empty for properties
+	Type        docsType
+	Required    bool
+	Description string
+	Notes       []string // Constraints, e.g. "Values: a, b" or "Minimum: 1"
+}
+
+// docsSchema is a component schema, shown as a table of its properties
+type docsSchema struct {
+	Name        string
+	Description string
+	Type        docsType
+	Notes       []string
+	Properties  []docsField
+}
+
+// docsType describes the type of a value, Name links to its schema when it is a component,
+// e.g. Prefix "array of " and Name "User"
+type docsType struct {
+	Prefix string
+	Name   string
+	Schema bool
+}
+
+// runDocs implements the docs command: openapi-gen docs [flags] openapi.json
+func runDocs(args []string) {
+	flags := flag.NewFlagSet("docs", flag.ExitOnError)
+	format := flags.String("format", "markdown", "Output format: markdown or html")
+	outputDir := flags.String("output", "./docs", "Output directory for the documentation pages")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: openapi-gen docs [flags] <openapi.json|openapi.yaml>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	var extension string
+	switch *format {
+	case "markdown", "md":
+		extension = ".md"
+	case "html":
+		extension = ".html"
+	default:
+		log.Fatalf("Unsupported format %q, use markdown or html", *format)
+	}
+
+	openAPISpec, err := loadSpecFile(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	site := buildDocsSite(openAPISpec, extension)
+	count, err := writeDocs(site, *outputDir)
+	if err != nil {
+		log.Fatalf("Failed to write documentation: %v", err)
+	}
+	log.Printf("Generated %d documentation pages in %s", count, *outputDir)
+}
+
+// loadSpecFile reads an OpenAPI document, as YAML when named .yaml or .yml and as JSON otherwise
+func loadSpecFile(path string) (*spec.OpenAPISpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if extension := strings.ToLower(filepath.Ext(path)); extension == ".yaml" || extension == ".yml" {
+		var document any
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		// The spec types decode from JSON, YAML keys may be numbers such as unquoted response codes
+		if data, err = json.Marshal(stringKeys(document)); err != nil {
+			return nil, err
+		}
+	}
+	var openAPISpec spec.OpenAPISpec
+	if err := json.Unmarshal(data, &openAPISpec); err != nil {
+		return nil, err
+	}
+	return &openAPISpec, nil
+}
+
+// stringKeys converts the map keys of a decoded YAML value to strings
+func stringKeys(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringKeys(item)
+		}
+		return v
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case []any:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+	}
+	return value
+}
+
+// buildDocsSite groups the operations of a spec by tag
+//
+// Tags are ordered as declared in the spec, then by name; operations tagged with several
+// tags appear on each of their pages and untagged operations on the "default" page.
+func buildDocsSite(openAPISpec *spec.OpenAPISpec, extension string) docsSite {
+	site := docsSite{
+		Title:       openAPISpec.Info.Title,
+		Version:     openAPISpec.Info.Version,
+		Description: openAPISpec.Info.Description,
+		Extension:   extension,
+	}
+
+	tags := make(map[string]*docsTag)
+	tagPage := func(name string) *docsTag {
+		if tag, exists := tags[name]; exists {
+			return tag
+		}
+		tag := &docsTag{Name: name}
+		tags[name] = tag
+		return tag
+	}
+	for _, tag := range openAPISpec.Tags {
+		tagPage(tag.Name).Description = tag.Description
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(openAPISpec.Paths)) {
+		pathItem := openAPISpec.Paths[path]
+		for _, method := range docsMethods {
+			operation := method.operation(&pathItem)
+			if operation == nil {
+				continue
+			}
+			documented := buildDocsOperation(openAPISpec, method.name, path, pathItem.Parameters, operation)
+			operationTags := operation.Tags
+			if len(operationTags) == 0 {
+				operationTags = []string{untaggedDocsPage}
+			}
+			for _, name := range operationTags {
+				tag := tagPage(name)
+				tag.Operations = append(tag.Operations, documented)
+			}
+		}
+	}
+
+	// Pages come in the order tags are declared, undeclared tags follow by name
+	for _, tag := range openAPISpec.Tags {
+		if tag, exists := tags[tag.Name]; exists && len(tag.Operations) > 0 {
+			site.Tags = append(site.Tags, tag)
+			delete(tags, tag.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(tags)) {
+		if len(tags[name].Operations) > 0 {
+			site.Tags = append(site.Tags, tags[name])
+		}
+	}
+
+	files := map[string]bool{"index": true, "schemas": true}
+	for _, tag := range site.Tags {
+		file := docsAnchor(tag.Name)
+		if file == "" {
+			file = "tag"
+		}
+		unique := file
+		for n := 2; files[unique]; n++ {
+			unique = file + "-" + strconv.Itoa(n)
+		}
+		files[unique] = true
+		tag.File = unique + extension
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(openAPISpec.Components.Schemas)) {
+		schema := openAPISpec.Components.Schemas[name]
+		site.Schemas = append(site.Schemas, docsSchema{
+			Name:        name,
+			Description: schema.Description,
+			Type:        docsTypeOf(schema),
+			Notes:       docsNotes(schema),
+			Properties:  docsProperties(schema, openAPISpec.Components.Schemas),
+		})
+	}
+	return site
+}
+
+// docsMethods are the operations of a path item, in the order they are documented
+var docsMethods = []struct {
+	name      string
+	operation func(*spec.PathItem) *spec.Operation
+}{
+	{"GET", func(p *spec.PathItem) *spec.Operation { return p.Get }},
+	{"POST", func(p *spec.PathItem) *spec.Operation { return p.Post }},
+	{"PUT", func(p *spec.PathItem) *spec.Operation { return p.Put }},
+	{"PATCH", func(p *spec.PathItem) *spec.Operation { return p.Patch }},
+	{"DELETE", func(p *spec.PathItem) *spec.Operation { return p.Delete }},
+	{"HEAD", func(p *spec.PathItem) *spec.Operation { return p.Head }},
+	{"OPTIONS", func(p *spec.PathItem) *spec.Operation { return p.Options }},
+	{"TRACE", func(p *spec.PathItem) *spec.Operation { return p.Trace }},
+}
+
+// buildDocsOperation documents an operation, resolving the component parameters, request
+// bodies and responses it refers to
+func buildDocsOperation(openAPISpec *spec.OpenAPISpec, method, path string, pathParameters []spec.Parameter, operation *spec.Operation) docsOperation {
+	components := openAPISpec.Components
+	documented := docsOperation{
+		Method:      method,
+		Path:        path,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Deprecated:  operation.Deprecated,
+	}
+
+	// Operation parameters override path item parameters of the same name and location
+	var parameters []spec.Parameter
+	for _, parameter := range append(slices.Clone(pathParameters), operation.Parameters...) {
+		if component, exists := components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]; parameter.Ref != "" && exists {
+			parameter = component
+		}
+		index := slices.IndexFunc(parameters, func(p spec.Parameter) bool { return p.Name == parameter.Name && p.In == parameter.In })
+		if index >= 0 {
+			parameters[index] = parameter
+		} else {
+			parameters = append(parameters, parameter)
+		}
+	}
+	for _, parameter := range parameters {
+		schema := parameter.Schema
+		if _, mediaType, found := docsContent(parameter.Content); found {
+			schema = mediaType.Schema
+		}
+		documented.Parameters = append(documented.Parameters, docsField{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Type:        docsTypeOf(schema),
+			Required:    parameter.Required,
+			Description: parameter.Description,
+			Notes:       docsNotes(schema),
+		})
+	}
+
+	if requestBody := operation.RequestBody; requestBody != nil {
+		if component, exists := components.RequestBodies[strings.TrimPrefix(requestBody.Ref, "#/components/requestBodies/")]; requestBody.Ref != "" && exists {
+			requestBody = &component
+		}
+		if contentType, mediaType, found := docsContent(requestBody.Content); found {
+			body := &docsBody{
+				Description: requestBody.Description,
+				ContentType: contentType,
+				Required:    requestBody.Required,
+				Type:        docsTypeOf(mediaType.Schema),
+			}
+			if mediaType.Schema.Ref == "" {
+				body.Properties = docsProperties(mediaType.Schema, components.Schemas)
+			}
+			documented.RequestBody = body
+		}
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(operation.Responses)) {
+		response := operation.Responses[status]
+		if component, exists := components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]; response.Ref != "" && exists {
+			response = component
+		}
+		row := docsResponse{Status: status, Description: response.Description}
+		if contentType, mediaType, found := docsContent(response.Content); found {
+			row.ContentType, row.Type, row.HasBody = contentType, docsTypeOf(mediaType.Schema), true
+		}
+		documented.Responses = append(documented.Responses, row)
+	}
+	return documented
+}
+
+// docsContent picks the media type to document, JSON when there is one
+func docsContent(content map[string]spec.MediaType) (string, spec.MediaType, bool) {
+	if mediaType, exists := content["application/json"]; exists {
+		return "application/json", mediaType, true
+	}
+	contentTypes := slices.Sorted(maps.Keys(content))
+	if len(contentTypes) == 0 {
+		return "", spec.MediaType{}, false
+	}
+	return contentTypes[0], content[contentTypes[0]], true
+}
+
+// docsProperties lists the properties of an object schema, including those of its allOf members
+func docsProperties(schema spec.Schema, schemas map[string]spec.Schema) []docsField {
+	properties := make(map[string]spec.Schema)
+	var required []string
+	var collect func(schema spec.Schema, depth int)
+	collect = func(schema spec.Schema, depth int) {
+		if schema.Ref != "" {
+			component, exists := schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+			if !exists || depth > 8 {
+				return
+			}
+			schema = component
+		}
+		for _, member := range schema.AllOf {
+			collect(member, depth+1)
+		}
+		maps.Copy(properties, schema.Properties)
+		required = append(required, schema.Required...)
+	}
+	collect(schema, 0)
+
+	fields := make([]docsField, 0, len(properties))
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		property := properties[name]
+		fields = append(fields, docsField{
+			Name:        name,
+			Type:        docsTypeOf(property),
+			Required:    slices.Contains(required, name),
+			Description: property.Description,
+			Notes:       docsNotes(property),
+		})
+	}
+	return fields
+}
+
+// docsTypeOf describes the type of a schema, e.g. "string (email)", "array of User"
+func docsTypeOf(schema spec.Schema) docsType {
+	var described docsType
+	switch {
+	case schema.Ref != "":
+		described = docsType{Name: schema.Ref[strings.LastIndex(schema.Ref, "/")+1:], Schema: true}
+	case schema.Type == "array" && schema.Items != nil:
+		described = docsTypeOf(*schema.Items)
+		described.Prefix = "array of " + described.Prefix
+	case schema.AdditionalProperties != nil && len(schema.Properties) == 0:
+		described = docsTypeOf(*schema.AdditionalProperties)
+		described.Prefix = "map of " + described.Prefix
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		var variants []string
+		for _, variant := range append(slices.Clone(schema.OneOf), schema.AnyOf...) {
+			variant := docsTypeOf(variant)
+			variants = append(variants, variant.Prefix+variant.Name)
+		}
+		described = docsType{Name: "one of " + strings.Join(variants, ", ")}
+	case len(schema.AllOf) == 1 && len(schema.Properties) == 0:
+		described = docsTypeOf(schema.AllOf[0])
+	case schema.Type == "" || schema.Type == "any":
+		described = docsType{Name: "any"}
+		if len(schema.Properties) > 0 || len(schema.AllOf) > 0 {
+			described.Name = "object"
+		}
+	default:
+		described = docsType{Name: schema.Type}
+		if schema.Format != "" {
+			described.Name += " (" + schema.Format + ")"
+		}
+	}
+	if schema.Nullable {
+		described.Prefix = "nullable " + described.Prefix
+	}
+	return described
+}
+
+// docsNotes lists the constraints of a schema that its type does not show
+func docsNotes(schema spec.Schema) []string {
+	var notes []string
+	if schema.Deprecated {
+		notes = append(notes, "Deprecated")
+	}
+	if schema.ReadOnly {
+		notes = append(notes, "Read-only")
+	}
+	if schema.WriteOnly {
+		notes = append(notes, "Write-only")
+	}
+	if len(schema.Enum) > 0 {
+		notes = append(notes, "Values: "+strings.Join(schema.Enum, ", "))
+	}
+	if schema.Default != nil {
+		notes = append(notes, fmt.Sprintf("Default: %v", schema.Default))
+	}
+	if schema.Minimum != nil {
+		bound := "Minimum: "
+		if schema.ExclusiveMinimum {
+			bound = "Greater than: "
+		}
+		notes = append(notes, bound+strconv.FormatFloat(*schema.Minimum, 'g', -1, 64))
+	}
+	if schema.Maximum != nil {
+		bound := "Maximum: "
+		if schema.ExclusiveMaximum {
+			bound = "Less than: "
+		}
+		notes = append(notes, bound+strconv.FormatFloat(*schema.Maximum, 'g', -1, 64))
+	}
+	if schema.MinLength != nil {
+		notes = append(notes, "Min length: "+strconv.Itoa(*schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		notes = append(notes, "Max length: "+strconv.Itoa(*schema.MaxLength))
+	}
+	if schema.MinItems != nil {
+		notes = append(notes, "Min items: "+strconv.Itoa(*schema.MinItems))
+	}
+	if schema.MaxItems != nil {
+		notes = append(notes, "Max items: "+strconv.Itoa(*schema.MaxItems))
+	}
+	if schema.Pattern != "" {
+		notes = append(notes, "Pattern: "+schema.Pattern)
+	}
+	return notes
+}
+
+// docsAnchor turns a name into a lower case identifier for file names and heading anchors,
+// the way GitHub derives heading anchors
+func docsAnchor(name string) string {
+	var anchor strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			anchor.WriteRune(r)
+		case r == ' ':
+			anchor.WriteRune('-')
+		}
+	}
+	return anchor.String()
+}
+
+// docsDetails joins a description and the notes on a value, one per line
+func docsDetails(description string, notes []string) string {
+	return strings.Join(slices.DeleteFunc(append([]string{description}, notes...), func(line string) bool { return line == "" }), "\n")
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "<br>")
+}
+
+// docsTemplate renders the pages of one format
+type docsTemplate interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// writeDocs renders the index, the tag pages and the schemas page into outputDir
+func writeDocs(site docsSite, outputDir string) (int, error) {
+	funcs := map[string]any{"anchor": docsAnchor, "cell": markdownCell, "details": docsDetails}
+	var templates docsTemplate
+	if site.Extension == ".html" {
+		templates = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(htmlDocsTemplates))
+	} else {
+		templates = template.Must(template.New("markdown").Funcs(funcs).Parse(markdownDocsTemplates))
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return 0, err
+	}
+
+	type page struct {
+		file, template string
+		data           any
+	}
+	pages := []page{{"index" + site.Extension, "index", site}}
+	for _, tag := range site.Tags {
+		pages = append(pages, page{tag.File, "tag", struct {
+			Site docsSite
+			Tag  *docsTag
+		}{site, tag}})
+	}
+	if len(site.Schemas) > 0 {
+		pages = append(pages, page{"schemas" + site.Extension, "schemas", site})
+	}
+
+	for _, page := range pages {
+		var rendered bytes.Buffer
+		if err := templates.ExecuteTemplate(&rendered, page.template, page.data); err != nil {
+			return 0, fmt.Errorf("failed to render %s: %w", page.file, err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, page.file), rendered.Bytes(), 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(pages), nil
+}
@@ -0,0 +1,161 @@
+package main
+
+// markdownDocsTemplates render the documentation pages as Markdown, tables use the GitHub syntax
+const markdownDocsTemplates = `
+{{- define "type"}}{{.Prefix}}{{if .Schema}}[{{.Name}}](schemas.md#{{anchor .Name}}){{else}}{{.Name}}{{end}}{{end}}
+
+{{- define "properties"}}| Property | Type | Required | Description |
+| --- | --- | --- | --- |
+{{range .}}| ` + "`{{.Name}}`" + ` | {{template "type" .Type}} | {{if .Required}}Yes{{else}}No{{end}} | {{cell (details .Description .Notes)}} |
+{{end}}{{end}}
+
+{{- define "index"}}# {{.Title}}
+{{with .Version}}
+Version {{.}}
+{{end}}{{with .Description}}
+{{.}}
+{{end}}
+## Operations
+
+| Tag | Description | Operations |
+| --- | --- | --- |
+{{range .Tags}}| [{{cell .Name}}]({{.File}}) | {{cell .Description}} | {{len .Operations}} |
+{{end}}{{if .Schemas}}
+## Schemas
+
+{{range .Schemas}}- [{{.Name}}](schemas.md#{{anchor .Name}})
+{{end}}{{end}}{{end}}
+
+{{- define "tag"}}[{{.Site.Title}}](index.md)
+
+# {{.Tag.Name}}
+{{with .Tag.Description}}
+{{.}}
+{{end}}{{range .Tag.Operations}}
+## {{.Method}} {{.Path}}
+{{if .Deprecated}}
+**Deprecated**
+{{end}}{{with .Summary}}
+{{.}}
+{{end}}{{with .Description}}
+{{.}}
+{{end}}{{with .Parameters}}
+### Parameters
+
+| Name | In | Type | Required | Description |
+| --- | --- | --- | --- | --- |
+{{range .}}| ` + "`{{.Name}}`" + ` | {{.In}} | {{template "type" .Type}} | {{if .Required}}Yes{{else}}No{{end}} | {{cell (details .Description .Notes)}} |
+{{end}}{{end}}{{with .RequestBody}}
+### Request Body
+
+` + "`{{.ContentType}}`" + ` {{template "type" .Type}}{{if .Required}}, required{{end}}
+{{with .Description}}
+{{.}}
+{{end}}{{with .Properties}}
+{{template "properties" .}}{{end}}{{end}}{{with .Responses}}
+### Responses
+
+| Status | Description | Content Type | Type |
+| --- | --- | --- | --- |
+{{range .}}| {{.Status}} | {{cell .Description}} | {{if .HasBody}}` + "`{{.ContentType}}`" + `{{end}} | {{if .HasBody}}{{template "type" .Type}}{{end}} |
+{{end}}{{end}}{{end}}{{end}}
+
+{{- define "schemas"}}[{{.Title}}](index.md)
+
+# Schemas
+{{range .Schemas}}
+## {{.Name}}
+{{with .Description}}
+{{.}}
+{{end}}
+Type: {{template "type" .Type}}
+{{with .Notes}}
+{{range .}}- {{.}}
+{{end}}{{end}}{{with .Properties}}
+{{template "properties" .}}{{end}}{{end}}{{end}}
+`
+
+// htmlDocsTemplates render the documentation pages as standalone HTML pages
+const htmlDocsTemplates = `
+{{- define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 0 auto; padding: 1rem 2rem; color: #1f2328; line-height: 1.5; }
+table { border-collapse: collapse; width: 100%; margin: 1rem 0; }
+th, td { border: 1px solid #d0d7de; padding: 0.4rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.details, p { white-space: pre-line; }
+code { background: #f6f8fa; padding: 0.1rem 0.3rem; border-radius: 4px; }
+.method { font-family: monospace; font-weight: bold; margin-right: 0.5rem; }
+.deprecated { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{- define "foot"}}</body>
+</html>
+{{end}}
+
+{{- define "type"}}{{.Prefix}}{{if .Schema}}<a href="schemas.html#{{anchor .Name}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{end}}
+
+{{- define "properties"}}<table>
+<tr><th>Property</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{template "type" .Type}}</td><td>{{if .Required}}Yes{{else}}No{{end}}</td><td class="details">{{details .Description .Notes}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{- define "index"}}{{template "head" .Title}}<h1>{{.Title}}</h1>
+{{with .Version}}<p>Version {{.}}</p>
+{{end}}{{with .Description}}<p>{{.}}</p>
+{{end}}<h2>Operations</h2>
+<table>
+<tr><th>Tag</th><th>Description</th><th>Operations</th></tr>
+{{range .Tags}}<tr><td><a href="{{.File}}">{{.Name}}</a></td><td class="details">{{.Description}}</td><td>{{len .Operations}}</td></tr>
+{{end}}</table>
+{{if .Schemas}}<h2>Schemas</h2>
+<ul>
+{{range .Schemas}}<li><a href="schemas.html#{{anchor .Name}}">{{.Name}}</a></li>
+{{end}}</ul>
+{{end}}{{template "foot"}}{{end}}
+
+{{- define "tag"}}{{template "head" (printf "%s - %s" .Tag.Name .Site.Title)}}<p><a href="index.html">{{.Site.Title}}</a></p>
+<h1>{{.Tag.Name}}</h1>
+{{with .Tag.Description}}<p>{{.}}</p>
+{{end}}{{range .Tag.Operations}}<section>
+<h2><span class="method">{{.Method}}</span>{{.Path}}</h2>
+{{if .Deprecated}}<p class="deprecated">Deprecated</p>
+{{end}}{{with .Summary}}<p>{{.}}</p>
+{{end}}{{with .Description}}<p>{{.}}</p>
+{{end}}{{with .Parameters}}<h3>Parameters</h3>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{template "type" .Type}}</td><td>{{if .Required}}Yes{{else}}No{{end}}</td><td class="details">{{details .Description .Notes}}</td></tr>
+{{end}}</table>
+{{end}}{{with .RequestBody}}<h3>Request Body</h3>
+<p><code>{{.ContentType}}</code> {{template "type" .Type}}{{if .Required}}, required{{end}}</p>
+{{with .Description}}<p>{{.}}</p>
+{{end}}{{with .Properties}}{{template "properties" .}}{{end}}{{end}}{{with .Responses}}<h3>Responses</h3>
+<table>
+<tr><th>Status</th><th>Description</th><th>Content Type</th><th>Type</th></tr>
+{{range .}}<tr><td>{{.Status}}</td><td class="details">{{.Description}}</td><td>{{if .HasBody}}<code>{{.ContentType}}</code>{{end}}</td><td>{{if .HasBody}}{{template "type" .Type}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}</section>
+{{end}}{{template "foot"}}{{end}}
+
+{{- define "schemas"}}{{template "head" (printf "Schemas - %s" .Title)}}<p><a href="index.html">{{.Title}}</a></p>
+<h1>Schemas</h1>
+{{range .Schemas}}<section id="{{anchor .Name}}">
+<h2>{{.Name}}</h2>
+{{with .Description}}<p>{{.}}</p>
+{{end}}<p>Type: {{template "type" .Type}}</p>
+{{with .Notes}}<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{with .Properties}}{{template "properties" .}}{{end}}</section>
+{{end}}{{template "foot"}}{{end}}
+`
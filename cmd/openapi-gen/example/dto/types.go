@@ -2,8 +2,9 @@ package dto
 
 // LoginRequest represents the login request payload
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	// Email is the user's login email
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"` // Plain text, only sent over TLS
 }
 
 // AuthResponse represents the authentication response
@@ -16,10 +17,10 @@ type AuthResponse struct {
 
 // CreateUserRequest represents the user creation request
 type CreateUserRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Age      int    `json:"age,omitempty"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Age      int    `json:"age,omitempty" validate:"min=0,max=150"`
 }
 
 // UserResponse represents the user response
@@ -1,8 +1,12 @@
 module github.com/zainokta/openapi-gen/cmd/openapi-gen
 
-go 1.24.0
+go 1.25.1
 
-require github.com/cloudwego/hertz v0.10.2
+require (
+	github.com/cloudwego/hertz v0.10.2
+	github.com/zainokta/openapi-gen v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/bytedance/go-tagexpr/v2 v2.9.11 // indirect
@@ -27,3 +31,5 @@ require (
 	golang.org/x/text v0.28.0 // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
 )
+
+replace github.com/zainokta/openapi-gen => ../..
@@ -1,18 +1,31 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
+	"go/printer"
 	"go/token"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/zainokta/openapi-gen/schemagen"
+	"github.com/zainokta/openapi-gen/spec"
 )
 
 // SchemaAnnotation represents a go:generate annotation for schema generation
@@ -20,15 +33,47 @@ type SchemaAnnotation struct {
 	HandlerName  string `json:"handlerName"`
 	RequestType  string `json:"requestType,omitempty"`
 	ResponseType string `json:"responseType,omitempty"`
+	Status       int    `json:"status,omitempty"` // Status code of the response, 200 when not set
 	FilePath     string `json:"filePath"`
 	LineNumber   int    `json:"lineNumber"`
 }
 
 // SchemaFile represents the generated schema file structure
 type SchemaFile struct {
-	HandlerName    string                 `json:"handlerName"`
-	RequestSchema  map[string]interface{} `json:"requestSchema,omitempty"`
-	ResponseSchema map[string]interface{} `json:"responseSchema,omitempty"`
+	HandlerName    string       `json:"handlerName"`
+	RequestSchema  *spec.Schema `json:"requestSchema,omitempty"`
+	ResponseSchema *spec.Schema `json:"responseSchema,omitempty"`
+	// Responses of further status codes, e.g. 201 or 404, by status code
+	Responses map[string]*spec.Schema `json:"responses,omitempty"`
+	// Examples of responses by status code, written by hand and kept when the file is regenerated
+	Examples map[string]json.RawMessage `json:"examples,omitempty"`
+	// SourceHash is the hash of the struct declarations the schemas were generated from,
+	// verify reports the file as stale when it no longer matches
+	SourceHash string `json:"sourceHash,omitempty"`
+}
+
+// errStaleSchema is reported by verify for schema files that no longer match their sources
+var errStaleSchema = errors.New("stale schema file")
+
+// schemaBundleFileName must match analyzer.SchemaBundleFileName in the library
+const schemaBundleFileName = "openapi-schemas.bundle.json"
+
+// SchemaBundle packs every generated schema file into a single embeddable document
+type SchemaBundle struct {
+	Version int               `json:"version"`
+	Schemas []json.RawMessage `json:"schemas"`
+	Paths   []string          `json:"paths,omitempty"` // Path of each schema file relative to the output directory
+}
+
+// StructDefinition is a struct type declaration found in the source tree
+type StructDefinition struct {
+	// Name is the name of the type, the title of its schema
+	Name string
+	Type *ast.StructType
+	// Doc is the doc comment of the type declaration
+	Doc string
+	// Imports are the imports of the file declaring the struct
+	Imports importTable
 }
 
 // PackageContext tracks the current package directory for resolving nested struct references
@@ -41,20 +86,122 @@ type PackageContext struct {
 	CurrentPackageName string
 	// VisitedTypes tracks types to prevent infinite recursion
 	VisitedTypes map[string]bool
+	// FieldDocs describes properties with their field doc comments
+	FieldDocs bool
+	// TimeFormat is how time.Time values are serialized, see schemagen.TimeSchema
+	TimeFormat string
+	// Required decides which fields are required properties
+	Required schemagen.RequiredRules
+	// Search controls which directories are searched for other packages
+	Search SearchOptions
+	// Imports are the imports of the file declaring the type being analyzed, package
+	// qualifiers such as dto in dto.User are resolved through them
+	Imports importTable
+	// Modules are the module roots import paths are resolved in
+	Modules []moduleRoot
+	// Sources receives the declarations of the structs the schema is generated from, nil
+	// when they are not needed
+	Sources io.Writer
+}
+
+// SearchOptions controls which directories are searched for package sources
+type SearchOptions struct {
+	// IncludeVendor searches vendor/ too, for projects using go mod vendor. Packages
+	// outside vendor/ are preferred when both define the same package.
+	IncludeVendor bool
+	// Build is the build context source files must match, like go build files guarded by
+	// //go:build lines or _GOOS/_GOARCH suffixes are skipped. Nil matches every file.
+	Build *build.Context
+}
+
+// matchFile reports whether a Go source file is part of the build, test files never are
+func (s SearchOptions) matchFile(path string) bool {
+	if strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+	if s.Build == nil {
+		return true
+	}
+	match, err := s.Build.MatchFile(filepath.Dir(path), filepath.Base(path))
+	return err == nil && match
+}
+
+// packageFiles lists the Go files of a package directory that are part of the build
+func (s SearchOptions) packageFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(files, func(file string) bool { return !s.matchFile(file) }), nil
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "docs" {
+		runDocs(os.Args[2:])
+		return
+	}
+	// verify takes the flags of generation and checks the schema files instead of writing them
+	arguments := os.Args[1:]
+	verify := len(arguments) > 0 && arguments[0] == "verify"
+	if verify {
+		arguments = arguments[1:]
+	}
+
 	var (
 		outputDir    = flag.String("output", "./schemas", "Output directory for schema files")
 		verbose      = flag.Bool("verbose", false, "Verbose output")
 		requestType  = flag.String("request", "", "Request type in format package.TypeName")
 		responseType = flag.String("response", "", "Response type in format package.TypeName")
 		handlerName  = flag.String("handler", "", "Handler name (auto-detected if not provided)")
+		status       = flag.Int("status", 0, "Status code of the response type (default 200)")
+		bundle       = flag.Bool("bundle", false, "Pack all schema files in the output directory into "+schemaBundleFileName)
+		fieldDocs    = flag.Bool("field-docs", true, "Use field doc comments as property descriptions")
+		vendor       = flag.Bool("vendor", false, "Also search vendor/ for package sources")
+		tags         = flag.String("tags", "", "Comma-separated build tags source files are matched against, as with go build -tags")
+		timeFormat   = flag.String("time-format", "", "How time.Time values are serialized: unix, unixmilli, unixmicro, unixnano or a Go time layout (default RFC 3339 strings)")
+		requiredTags = flag.String("required-tags", strings.Join(schemagen.DefaultRequiredTags, ","), "Comma-separated tags whose required rule makes a field required")
+		pointersOpt  = flag.Bool("pointers-optional", false, "Keep pointer fields optional even when validated as required")
+		module       = flag.String("module", "", "Directory of the module schemas are generated for, by default the module holding the working directory")
+		layout       = flag.String("layout", layoutPackage, "Schema file layout: package writes each file into the subdirectory of its package, flat writes every file into the output directory")
+		strict       = flag.Bool("strict", false, "Exit with status 1 when an annotation fails or is skipped")
+		quiet        = flag.Bool("quiet", false, "Only log errors ending the run")
+		jsonOutput   = flag.Bool("json", false, "Print a JSON report of the generated, failed and skipped annotations to stdout")
+		workers      = flag.Int("workers", runtime.GOMAXPROCS(0), "Number of files and schema files processed in parallel")
 	)
-	flag.Parse()
+	flag.CommandLine.Parse(arguments)
+	moduleDir = *module
 
-	if len(flag.Args()) == 0 {
-		log.Fatal("Please specify at least one Go file to process")
+	if *layout != layoutPackage && *layout != layoutFlat {
+		fatalf("Invalid -layout %q, use package or flat", *layout)
+	}
+
+	if *status != 0 && !validStatus(*status) {
+		fatalf("Invalid -status %d, use an HTTP status code", *status)
+	}
+
+	if !schemagen.ValidTimeFormat(*timeFormat) {
+		fatalf("Invalid -time-format %q, use unix, unixmilli, unixmicro, unixnano or a Go time layout", *timeFormat)
+	}
+	required := schemagen.RequiredRules{PointersOptional: *pointersOpt}
+	for tag := range strings.SplitSeq(*requiredTags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			required.Tags = append(required.Tags, tag)
+		}
+	}
+
+	// GOOS and GOARCH come from the environment, like for the go command
+	buildContext := build.Default
+	if *tags != "" {
+		buildContext.BuildTags = strings.Split(*tags, ",")
+	}
+	search := SearchOptions{IncludeVendor: *vendor, Build: &buildContext}
+
+	if *quiet {
+		log.SetOutput(io.Discard)
+	}
+
+	if len(flag.Args()) == 0 && (!*bundle || verify) {
+		fatalf("Please specify at least one Go file to process")
 	}
 
 	// Expand . to the actual file path if needed
@@ -64,13 +211,21 @@ func main() {
 			// Get current directory and find the first .go file
 			currentDir, err := os.Getwd()
 			if err != nil {
-				log.Fatal("Failed to get current directory")
+				fatalf("Failed to get current directory")
+			}
+
+			// go generate names the file of the annotation it runs
+			if goFile := os.Getenv("GOFILE"); goFile != "" {
+				if _, err := os.Stat(filepath.Join(currentDir, goFile)); err == nil {
+					args[i] = filepath.Join(currentDir, goFile)
+					continue
+				}
 			}
 
 			// Find Go files in current directory
 			files, err := filepath.Glob(filepath.Join(currentDir, "*.go"))
 			if err != nil || len(files) == 0 {
-				log.Fatal("No Go files found in current directory")
+				fatalf("No Go files found in current directory")
 			}
 			args[i] = files[0] // Use the first Go file found
 		} else {
@@ -81,17 +236,44 @@ func main() {
 	// Find package root and create output directory there
 	packageRoot, err := findPackageRoot()
 	if err != nil {
-		log.Fatalf("Failed to find package root: %v", err)
+		fatalf("Failed to find package root: %v", err)
 	}
 
 	// Create output directory in package root
 	outputPath := filepath.Join(packageRoot, *outputDir)
-	if err := os.MkdirAll(outputPath, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+	if !verify {
+		if err := os.MkdirAll(outputPath, 0755); err != nil {
+			fatalf("Failed to create output directory: %v", err)
+		}
+	}
+
+	// Report the outcome last, after the bundle has been written
+	report := &RunReport{OutputDir: outputPath, Verify: verify}
+	defer func() {
+		report.print(os.Stdout, *jsonOutput)
+		if code := report.exitCode(*strict); code != 0 {
+			os.Exit(code)
+		}
+	}()
+
+	// Rebuild the bundle after schema files have been written
+	if *bundle && !verify {
+		defer func() {
+			count, err := writeSchemaBundle(outputPath)
+			if err != nil {
+				fatalf("Failed to write schema bundle: %v", err)
+			}
+			log.Printf("Bundled %d schema files into %s", count, filepath.Join(outputPath, schemaBundleFileName))
+		}()
+	}
+
+	// Only bundle existing schema files when no Go files are given
+	if len(args) == 0 {
+		return
 	}
 
 	// Check if we're using the new flag-based approach
-	if *requestType != "" || *responseType != "" || *handlerName != "" {
+	if *requestType != "" || *responseType != "" || *handlerName != "" || *status != 0 {
 		// Single annotation mode using flags
 		if *handlerName == "" {
 			// Try to extract handler name from the first file
@@ -101,19 +283,17 @@ func main() {
 			if *handlerName == "" {
 				// If we can't extract the handler name, use a generic name based on the request/response types
 				if *requestType != "" {
-					parts := strings.Split(*requestType, ".")
-					if len(parts) > 1 {
-						*handlerName = strings.TrimSuffix(parts[1], "Request") + "Handler"
+					if dot := strings.LastIndex(*requestType, "."); dot != -1 {
+						*handlerName = strings.TrimSuffix((*requestType)[dot+1:], "Request") + "Handler"
 					}
 				} else if *responseType != "" {
-					parts := strings.Split(*responseType, ".")
-					if len(parts) > 1 {
-						*handlerName = strings.TrimSuffix(parts[1], "Response") + "Handler"
+					if dot := strings.LastIndex(*responseType, "."); dot != -1 {
+						*handlerName = strings.TrimSuffix((*responseType)[dot+1:], "Response") + "Handler"
 					}
 				}
 			}
 			if *handlerName == "" {
-				log.Fatal("Handler name is required when using flags")
+				fatalf("Handler name is required when using flags")
 			}
 		}
 
@@ -121,51 +301,133 @@ func main() {
 			HandlerName:  *handlerName,
 			RequestType:  *requestType,
 			ResponseType: *responseType,
+			Status:       *status,
 			FilePath:     args[0], // Use first file as reference
 			LineNumber:   1,
 		}
+		annotations := handlerAnnotations(annotation)
 
 		if *verbose {
 			log.Printf("Generating schema for handler: %s", *handlerName)
 		}
 
-		if err := generateSchemaFile(annotation, outputPath, *verbose); err != nil {
-			log.Fatalf("Error generating schema for %s: %v", *handlerName, err)
+		schemaFile, err := generateSchemaFile(annotations, schemaFileDir(outputPath, packageRoot, annotation, *layout), verify, *verbose, *fieldDocs, *timeFormat, required, search)
+		report.add(schemaFileResult(annotations[0], schemaFile, err))
+		if err != nil && !errors.Is(err, errStaleSchema) && schemaFile == "" {
+			fatalf("Error generating schema for %s: %v", *handlerName, err)
 		}
-
-		log.Printf("Generated 1 schema file in %s", outputPath)
 		return
 	}
 
 	// Original comment-based parsing mode
 	annotations := make([]SchemaAnnotation, 0)
 
-	// Process each file
-	for _, filePath := range args {
-		fileAnnotations, err := processFile(filePath, *verbose)
-		if err != nil {
-			log.Printf("Error processing %s: %v", filePath, err)
+	// Process the files in parallel, their annotations are collected in the order of the files
+	fileAnnotations := make([][]SchemaAnnotation, len(args))
+	fileReports := make([]RunReport, len(args))
+	fileErrs := make([]error, len(args))
+	forEachParallel(len(args), *workers, func(i int) {
+		fileAnnotations[i], fileErrs[i] = processFile(args[i], *verbose, &fileReports[i])
+	})
+	for i, filePath := range args {
+		for _, result := range fileReports[i].Results {
+			report.add(result)
+		}
+		if fileErrs[i] != nil {
+			log.Printf("Error processing %s: %v", filePath, fileErrs[i])
+			report.add(AnnotationResult{File: filePath, Outcome: outcomeFailed, Errors: []string{fileErrs[i].Error()}})
 			continue
 		}
-		annotations = append(annotations, fileAnnotations...)
+		annotations = append(annotations, fileAnnotations[i]...)
 	}
 
 	if *verbose {
 		log.Printf("Found %d schema annotations", len(annotations))
 	}
 
-	// Generate schema files
+	// Generate schema files, the annotations of a handler are aggregated into one file
+	type schemaTarget struct{ dir, handlerName string }
+	var targets []schemaTarget
+	handlers := make(map[schemaTarget][]SchemaAnnotation)
 	for _, annotation := range annotations {
-		if err := generateSchemaFile(annotation, outputPath, *verbose); err != nil {
-			log.Printf("Error generating schema for %s: %v", annotation.HandlerName, err)
+		target := schemaTarget{dir: schemaFileDir(outputPath, packageRoot, annotation, *layout), handlerName: annotation.HandlerName}
+		if _, seen := handlers[target]; !seen {
+			targets = append(targets, target)
+		}
+		handlers[target] = append(handlers[target], annotation)
+	}
+	schemaFiles := make([]string, len(targets))
+	errs := make([]error, len(targets))
+	forEachParallel(len(targets), *workers, func(i int) {
+		schemaFiles[i], errs[i] = generateSchemaFile(handlers[targets[i]], targets[i].dir, verify, *verbose, *fieldDocs, *timeFormat, required, search)
+	})
+	for i, target := range targets {
+		schemaFile, err := schemaFiles[i], errs[i]
+		if errors.Is(err, errStaleSchema) {
+			log.Print(err)
+		} else if err != nil && schemaFile == "" {
+			log.Printf("Error generating schema for %s: %v", target.handlerName, err)
+		}
+		report.add(schemaFileResult(handlers[target][0], schemaFile, err))
+	}
+}
+
+// schemaFileResult is the report entry of the schema file generated for the annotations of
+// a handler, err is the error generateSchemaFile returned
+func schemaFileResult(annotation SchemaAnnotation, schemaFile string, err error) AnnotationResult {
+	result := AnnotationResult{
+		Handler:    annotation.HandlerName,
+		File:       annotation.FilePath,
+		Line:       annotation.LineNumber,
+		SchemaFile: schemaFile,
+		Outcome:    outcomeGenerated,
+	}
+	switch {
+	case errors.Is(err, errStaleSchema):
+		result.Outcome = outcomeStale
+	case err != nil:
+		result.Outcome = outcomeFailed
+	}
+	if err != nil {
+		result.Errors = []string{err.Error()}
+	}
+	return result
+}
+
+// handlerAnnotations returns the annotations of the handler of an annotation given with flags
+//
+// go generate runs every annotation of a handler on its own. When the annotation is one of
+// those of its source file, each run writes the schemas of all of them instead of the last
+// run overwriting the others.
+func handlerAnnotations(annotation SchemaAnnotation) []SchemaAnnotation {
+	fileAnnotations, err := processFile(annotation.FilePath, false, nil)
+	if err != nil {
+		return []SchemaAnnotation{annotation}
+	}
+	var same []SchemaAnnotation
+	found := false
+	for _, fileAnnotation := range fileAnnotations {
+		if fileAnnotation.HandlerName != annotation.HandlerName {
+			continue
 		}
+		same = append(same, fileAnnotation)
+		found = found || (fileAnnotation.RequestType == annotation.RequestType &&
+			fileAnnotation.ResponseType == annotation.ResponseType && fileAnnotation.Status == annotation.Status)
 	}
+	if !found {
+		return []SchemaAnnotation{annotation}
+	}
+	return same
+}
 
-	log.Printf("Generated %d schema files in %s", len(annotations), outputPath)
+// validStatus reports whether a status code of an annotation is an HTTP status code
+func validStatus(status int) bool {
+	return status >= 100 && status <= 599
 }
 
-// processFile parses a Go file and extracts schema annotations
-func processFile(filePath string, verbose bool) ([]SchemaAnnotation, error) {
+// processFile parses a Go file and extracts schema annotations, the annotations it skips
+// are added to report
+func processFile(filePath string, verbose bool, report *RunReport) ([]SchemaAnnotation, error) {
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -178,24 +440,28 @@ func processFile(filePath string, verbose bool) ([]SchemaAnnotation, error) {
 	for _, commentGroup := range node.Comments {
 		for _, comment := range commentGroup.List {
 			if strings.Contains(comment.Text, "go:generate") && strings.Contains(comment.Text, "openapi-gen") {
-				annotation, err := parseAnnotation(comment.Text, filePath, fset.Position(comment.Pos()).Line)
+				line := fset.Position(comment.Pos()).Line
+				annotation, err := parseAnnotation(comment.Text, filePath, line)
 				if err != nil {
 					if verbose {
 						log.Printf("Warning: Failed to parse annotation in %s: %v", filePath, err)
 					}
+					report.add(AnnotationResult{File: filePath, Line: line, Outcome: outcomeSkipped, Errors: []string{err.Error()}})
 					continue
 				}
 
-				// Extract handler name from the function context
-				handlerName := extractHandlerName(node, comment.Pos())
-				if handlerName == "" {
+				// A -handler argument names the handler, otherwise the annotated declaration does
+				if annotation.HandlerName == "" {
+					annotation.HandlerName = extractHandlerName(node, comment.Pos())
+				}
+				if annotation.HandlerName == "" {
 					if verbose {
-						log.Printf("Warning: Could not extract handler name for annotation in %s", filePath)
+						log.Printf("Warning: Could not extract handler name for annotation in %s:%d, annotate a function, method or type, or add -handler", filePath, annotation.LineNumber)
 					}
+					report.add(AnnotationResult{File: filePath, Line: line, Outcome: outcomeSkipped, Errors: []string{"no handler name, annotate a function, method or type, or add -handler"}})
 					continue
 				}
 
-				annotation.HandlerName = handlerName
 				annotations = append(annotations, *annotation)
 			}
 		}
@@ -235,6 +501,22 @@ func parseAnnotation(comment, filePath string, lineNumber int) (*SchemaAnnotatio
 		annotation.ResponseType = respMatch[1]
 	}
 
+	// Parse response status code
+	statusMatch := regexp.MustCompile(`-status\s+(\S+)`).FindStringSubmatch(args)
+	if len(statusMatch) > 1 {
+		status, err := strconv.Atoi(statusMatch[1])
+		if err != nil || !validStatus(status) {
+			return nil, fmt.Errorf("invalid status %q", statusMatch[1])
+		}
+		annotation.Status = status
+	}
+
+	// Parse handler name
+	handlerMatch := regexp.MustCompile(`-handler\s+(\S+)`).FindStringSubmatch(args)
+	if len(handlerMatch) > 1 {
+		annotation.HandlerName = handlerMatch[1]
+	}
+
 	return annotation, nil
 }
 
@@ -250,8 +532,10 @@ func extractHandlerNameFromFile(filePath string) string {
 	for _, commentGroup := range node.Comments {
 		for _, comment := range commentGroup.List {
 			if strings.Contains(comment.Text, "go:generate") && strings.Contains(comment.Text, "openapi-gen") {
-				handlerName := extractHandlerName(node, comment.Pos())
-				if handlerName != "" {
+				if annotation, err := parseAnnotation(comment.Text, filePath, fset.Position(comment.Pos()).Line); err == nil && annotation.HandlerName != "" {
+					return annotation.HandlerName
+				}
+				if handlerName := extractHandlerName(node, comment.Pos()); handlerName != "" {
 					return handlerName
 				}
 			}
@@ -261,142 +545,325 @@ func extractHandlerNameFromFile(filePath string) string {
 	return ""
 }
 
-// extractHandlerName extracts the function name that follows a go:generate comment
+// extractHandlerName names the handler of an annotation after the declaration it is attached
+// to: the top-level declaration following the comment, or enclosing it. Methods are named
+// after their receiver, e.g. UserHandler.CreateUser, types after themselves. Annotations above
+// the package clause, or followed by an import, variable or constant, name no handler.
 func extractHandlerName(node *ast.File, commentPos token.Pos) string {
-	var handlerName string
-
-	// Find the function declaration that follows this comment
-	ast.Inspect(node, func(n ast.Node) bool {
-		if handlerName != "" {
-			return false // Stop once we found it
+	if commentPos < node.Package {
+		return ""
+	}
+	for _, decl := range node.Decls {
+		if decl.End() < commentPos {
+			continue
 		}
-
-		if funcDecl, ok := n.(*ast.FuncDecl); ok {
-			// Check if this function comes after the comment
-			if funcDecl.Pos() > commentPos {
-				handlerName = funcDecl.Name.Name
-				return false
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if receiver := receiverTypeName(decl); receiver != "" {
+				return receiver + "." + decl.Name.Name
+			}
+			return decl.Name.Name
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				return ""
+			}
+			// The type following the comment in a group of types, or the first one
+			for _, declSpec := range decl.Specs {
+				if typeSpec, ok := declSpec.(*ast.TypeSpec); ok && typeSpec.End() >= commentPos {
+					return typeSpec.Name.Name
+				}
 			}
 		}
-		return true
-	})
-
-	return handlerName
+		return ""
+	}
+	return ""
 }
 
-// generateSchemaFile generates a JSON schema file for a handler
-func generateSchemaFile(annotation SchemaAnnotation, outputDir string, verbose bool) error {
+// generateSchemaFile generates the JSON schema file of a handler from its annotations
+//
+// Each response type is documented under the status code of its annotation, 200 by default.
+// A status without a response type documents a response without a body, e.g. 204. With
+// verify the file is compared with the one on disk instead of written, an errStaleSchema
+// error reports a difference.
+//
+// The path of the file is returned once it has been written or verified, together with the
+// types that could not be resolved; the file then documents the others.
+func generateSchemaFile(annotations []SchemaAnnotation, outputDir string, verify, verbose, fieldDocs bool, timeFormat string, required schemagen.RequiredRules, search SearchOptions) (string, error) {
+	handlerName := annotations[0].HandlerName
 	schemaFile := SchemaFile{
-		HandlerName: annotation.HandlerName,
+		HandlerName: handlerName,
 	}
 
 	// Get the package root directory to search for schemas
 	packageRoot, err := findPackageRoot()
 	if err != nil {
-		return fmt.Errorf("failed to find package root: %w", err)
+		return "", fmt.Errorf("failed to find package root: %w", err)
 	}
 
 	// Generate schemas by analyzing the actual struct definitions
-	if annotation.RequestType != "" {
-		schema, err := generateSchemaFromType(annotation.RequestType, packageRoot, verbose)
-		if err != nil {
-			log.Printf("Warning: Could not generate request schema for %s: %v", annotation.RequestType, err)
-		} else {
-			schemaFile.RequestSchema = schema
-			if verbose {
-				log.Printf("Successfully generated request schema for %s", annotation.RequestType)
+	sources := sha256.New()
+	documented := make(map[int]bool)
+	var unresolved []error
+	for _, annotation := range annotations {
+		if annotation.RequestType != "" {
+			if schemaFile.RequestSchema != nil {
+				log.Printf("Warning: %s:%d declares another request type for %s, keeping the first", annotation.FilePath, annotation.LineNumber, handlerName)
+			} else if schema, err := generateSchemaFromType(annotation.RequestType, packageRoot, annotation.FilePath, verbose, fieldDocs, timeFormat, required, search, sources); err != nil {
+				log.Printf("Warning: Could not generate request schema for %s: %v", annotation.RequestType, err)
+				unresolved = append(unresolved, fmt.Errorf("request type %s: %w", annotation.RequestType, err))
+			} else {
+				schemaFile.RequestSchema = &schema
+				if verbose {
+					log.Printf("Successfully generated request schema for %s", annotation.RequestType)
+				}
 			}
 		}
-	}
 
-	if annotation.ResponseType != "" {
-		schema, err := generateSchemaFromType(annotation.ResponseType, packageRoot, verbose)
-		if err != nil {
-			log.Printf("Warning: Could not generate response schema for %s: %v", annotation.ResponseType, err)
-		} else {
-			schemaFile.ResponseSchema = schema
+		if annotation.ResponseType == "" && annotation.Status == 0 {
+			continue
+		}
+		status := annotation.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if documented[status] {
+			log.Printf("Warning: %s:%d declares another %d response for %s, keeping the first", annotation.FilePath, annotation.LineNumber, status, handlerName)
+			continue
+		}
+		documented[status] = true
+
+		schema := &spec.Schema{}
+		if annotation.ResponseType != "" {
+			generated, err := generateSchemaFromType(annotation.ResponseType, packageRoot, annotation.FilePath, verbose, fieldDocs, timeFormat, required, search, sources)
+			if err != nil {
+				log.Printf("Warning: Could not generate response schema for %s: %v", annotation.ResponseType, err)
+				unresolved = append(unresolved, fmt.Errorf("response type %s: %w", annotation.ResponseType, err))
+				continue
+			}
+			schema = &generated
 			if verbose {
-				log.Printf("Successfully generated response schema for %s", annotation.ResponseType)
+				log.Printf("Successfully generated %d response schema for %s", status, annotation.ResponseType)
 			}
 		}
+		if status == http.StatusOK {
+			schemaFile.ResponseSchema = schema
+		} else {
+			if schemaFile.Responses == nil {
+				schemaFile.Responses = make(map[string]*spec.Schema)
+			}
+			schemaFile.Responses[strconv.Itoa(status)] = schema
+		}
+	}
+
+	if schemaFile.RequestSchema != nil || schemaFile.ResponseSchema != nil || schemaFile.Responses != nil {
+		schemaFile.SourceHash = "sha256:" + hex.EncodeToString(sources.Sum(nil))
 	}
 
 	// Generate file name
-	fileName := fmt.Sprintf("%s.json", sanitizeFileName(annotation.HandlerName))
+	fileName := fmt.Sprintf("%s.json", sanitizeFileName(handlerName))
 	filePath := filepath.Join(outputDir, fileName)
 
-	// Write JSON file
+	schemaFile.Examples = existingExamples(filePath)
 	jsonData, err := json.MarshalIndent(schemaFile, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal schema: %w", err)
+		return "", fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	if verify {
+		if err := verifySchemaFile(filePath, schemaFile, jsonData); err != nil {
+			return filePath, err
+		}
+		return filePath, errors.Join(unresolved...)
+	}
+
+	// Write JSON file
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create schema directory: %w", err)
 	}
 
 	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write schema file: %w", err)
+		return "", fmt.Errorf("failed to write schema file: %w", err)
 	}
 
 	if verbose {
 		log.Printf("Generated schema file: %s", filePath)
 	}
 
+	return filePath, errors.Join(unresolved...)
+}
+
+// existingExamples returns the examples of the schema file at filePath, nil when there is none
+func existingExamples(filePath string) map[string]json.RawMessage {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	var existing SchemaFile
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil
+	}
+	return existing.Examples
+}
+
+// verifySchemaFile compares a schema file on disk with its regenerated contents
+func verifySchemaFile(filePath string, generated SchemaFile, generatedData []byte) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("%w: %s is missing", errStaleSchema, filePath)
+	}
+	var existing SchemaFile
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return fmt.Errorf("%w: %s is not a schema file: %v", errStaleSchema, filePath, err)
+	}
+	if existing.SourceHash != generated.SourceHash {
+		return fmt.Errorf("%w: the structs of %s changed since %s was generated", errStaleSchema, generated.HandlerName, filePath)
+	}
+	// Compare the decoded file, hand-written examples need not be formatted like generated files
+	existingData, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil || !bytes.Equal(existingData, generatedData) {
+		return fmt.Errorf("%w: regenerating %s changes its schemas", errStaleSchema, filePath)
+	}
 	return nil
 }
 
+// Schema file layouts of the -layout flag
+const (
+	layoutPackage = "package" // In the subdirectory of the handler's package, e.g. schemas/internal/users
+	layoutFlat    = "flat"    // Every file in the output directory
+)
+
+// schemaFileDir returns the directory the schema file of an annotation is written to
+//
+// With the package layout it is the directory of the handler's package relative to the
+// package root, so handlers of the same name in different packages do not overwrite each
+// other. The library gives the schema to the handlers of the package whose import path
+// ends in that directory.
+func schemaFileDir(outputDir, packageRoot string, annotation SchemaAnnotation, layout string) string {
+	if layout == layoutFlat {
+		return outputDir
+	}
+	sourceDir, err := filepath.Abs(filepath.Dir(annotation.FilePath))
+	if err != nil {
+		return outputDir
+	}
+	relative, err := filepath.Rel(packageRoot, sourceDir)
+	if err != nil || relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return outputDir
+	}
+	return filepath.Join(outputDir, relative)
+}
+
+// writeSchemaBundle packs every schema file under outputDir into a single bundle file
+func writeSchemaBundle(outputDir string) (int, error) {
+	var files []string
+	err := filepath.WalkDir(outputDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != outputDir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".json" && entry.Name() != schemaBundleFileName {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list schema files: %w", err)
+	}
+
+	bundle := SchemaBundle{Version: 1, Schemas: make([]json.RawMessage, 0, len(files))}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		var schemaFile struct {
+			HandlerName string `json:"handlerName"`
+		}
+		if err := json.Unmarshal(data, &schemaFile); err != nil || schemaFile.HandlerName == "" {
+			// Not a generated schema file, leave it out of the bundle
+			continue
+		}
+
+		// Compact the file as is, the bundle is meant to be embedded and hand-edited
+		// files may use keywords the generator does not emit
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return 0, fmt.Errorf("failed to encode %s: %w", file, err)
+		}
+		bundle.Schemas = append(bundle.Schemas, compact.Bytes())
+		relative, err := filepath.Rel(outputDir, file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to locate %s: %w", file, err)
+		}
+		bundle.Paths = append(bundle.Paths, filepath.ToSlash(relative))
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal schema bundle: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, schemaBundleFileName), data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write schema bundle: %w", err)
+	}
+
+	return len(bundle.Schemas), nil
+}
+
 // isBuiltinType checks if a type is a built-in Go type or standard library type
 func isBuiltinType(typeName string) bool {
-	// Check for simple built-in types
-	builtinTypes := map[string]bool{
-		"string": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
-		"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
-		"float32": true, "float64": true, "bool": true, "byte": true, "rune": true,
-		"interface{}": true, "any": true,
+	if _, basic := schemagen.BasicType(typeName); basic {
+		return true
 	}
-
-	if builtinTypes[typeName] {
+	if typeName == "interface{}" || typeName == "any" {
 		return true
 	}
 
-	// Check for standard library types
-	stdTypes := map[string]bool{
-		"time.Time":                true,
-		"time.Duration":            true,
-		"net/url.URL":              true,
-		"encoding/json.RawMessage": true,
-		"encoding/json.Number":     true,
-		"io.Reader":                true, "io.Writer": true, "io.ReadWriter": true,
-		"net/http.Cookie":  true,
-		"net/mail.Address": true,
-		"math/big.Int":     true, "math/big.Float": true,
+	// Check for standard library types, e.g. time.Time or net/url.URL
+	if dot := strings.LastIndex(typeName, "."); dot > 0 {
+		_, known := schemagen.KnownType(typeName[:dot], typeName[dot+1:])
+		return known
 	}
-
-	return stdTypes[typeName]
+	return false
 }
 
 // parseComplexTypeExpression parses complex type expressions like arrays, maps, and pointers
-func parseComplexTypeExpression(typeName string) (map[string]interface{}, error) {
+func parseComplexTypeExpression(typeName string) (spec.Schema, error) {
 	// Handle pointer types
 	if strings.HasPrefix(typeName, "*") {
-		innerType := strings.TrimPrefix(typeName, "*")
-		innerSchema, err := parseComplexTypeExpression(innerType)
-		if err != nil {
-			return nil, err
-		}
 		// Pointers reference the underlying type
-		return innerSchema, nil
+		return parseComplexTypeExpression(strings.TrimPrefix(typeName, "*"))
 	}
 
 	// Handle array/slice types
 	if strings.HasPrefix(typeName, "[]") {
 		elementType := strings.TrimPrefix(typeName, "[]")
+		if schemagen.IsByteType(elementType) {
+			return schemagen.Bytes(), nil
+		}
 		elementSchema, err := parseComplexTypeExpression(elementType)
 		if err != nil {
-			return nil, err
+			return spec.Schema{}, err
+		}
+		return schemagen.ArrayOf(elementSchema), nil
+	}
+
+	// Handle fixed-size arrays, e.g. [4]byte
+	if matches := regexp.MustCompile(`^\[(\d+)\](.+)`).FindStringSubmatch(typeName); matches != nil {
+		length, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return spec.Schema{}, fmt.Errorf("invalid array length in %s: %w", typeName, err)
+		}
+		elementSchema, err := parseComplexTypeExpression(matches[2])
+		if err != nil {
+			return spec.Schema{}, err
 		}
-		return map[string]interface{}{
-			"type":        "array",
-			"items":       elementSchema,
-			"description": fmt.Sprintf("Array of %s", elementType),
-		}, nil
+		return schemagen.ArrayOfLength(elementSchema, length), nil
 	}
 
 	// Handle map types
@@ -405,111 +872,40 @@ func parseComplexTypeExpression(typeName string) (map[string]interface{}, error)
 		mapRegex := regexp.MustCompile(`map\[([^\]]+)\](.+)`)
 		matches := mapRegex.FindStringSubmatch(typeName)
 		if len(matches) != 3 {
-			return nil, fmt.Errorf("invalid map type format: %s", typeName)
+			return spec.Schema{}, fmt.Errorf("invalid map type format: %s", typeName)
 		}
 
 		keyType := matches[1]
 		valueType := matches[2]
 
-		// For OpenAPI, map keys should be strings
-		if keyType != "string" {
-			return map[string]interface{}{
-				"type":        "object",
-				"description": fmt.Sprintf("Map with %s keys (non-string keys not supported in OpenAPI)", keyType),
-			}, nil
-		}
-
 		valueSchema, err := parseComplexTypeExpression(valueType)
 		if err != nil {
-			return nil, err
+			return spec.Schema{}, err
 		}
-
-		return map[string]interface{}{
-			"type":                 "object",
-			"additionalProperties": valueSchema,
-		}, nil
+		// JSON keys are strings, other key types are documented in x-key-type
+		return schemagen.MapWithKeys(keyType, valueSchema), nil
 	}
 
-	// Handle simple built-in types
+	// Handle simple built-in and standard library types (e.g. time.Time)
 	if isBuiltinType(typeName) {
 		return generateBasicTypeSchema(typeName), nil
 	}
 
-	// Handle package-qualified types (e.g., time.Time, mypackage.MyType)
-	if strings.Contains(typeName, ".") {
-		parts := strings.Split(typeName, ".")
-		if len(parts) == 2 {
-			packageName := parts[0]
-			typeNameOnly := parts[1]
-
-			// Handle known standard library types
-			if packageName == "time" && typeNameOnly == "Time" {
-				return generateBasicTypeSchema("time.Time"), nil
-			}
-			if packageName == "time" && typeNameOnly == "Duration" {
-				return map[string]interface{}{
-					"type":        "string",
-					"format":      "duration",
-					"description": "Time duration",
-				}, nil
-			}
-			if packageName == "net/url" && typeNameOnly == "URL" {
-				return map[string]interface{}{
-					"type":        "string",
-					"format":      "uri",
-					"description": "URL",
-				}, nil
-			}
-			if packageName == "encoding/json" && typeNameOnly == "RawMessage" {
-				return map[string]interface{}{
-					"type":        "object",
-					"description": "Raw JSON message",
-				}, nil
-			}
-			if packageName == "encoding/json" && typeNameOnly == "Number" {
-				return map[string]interface{}{
-					"type":        "number",
-					"description": "JSON number",
-				}, nil
-			}
-			if packageName == "io" && (typeNameOnly == "Reader" || typeNameOnly == "Writer" || typeNameOnly == "ReadWriter") {
-				return map[string]interface{}{
-					"type":        "string",
-					"format":      "binary",
-					"description": fmt.Sprintf("IO %s", typeNameOnly),
-				}, nil
-			}
-			if packageName == "net/http" && typeNameOnly == "Cookie" {
-				return map[string]interface{}{
-					"type":        "object",
-					"description": "HTTP cookie",
-				}, nil
-			}
-			if packageName == "net/mail" && typeNameOnly == "Address" {
-				return map[string]interface{}{
-					"type":        "string",
-					"format":      "email",
-					"description": "Email address",
-				}, nil
-			}
-			if packageName == "math/big" && (typeNameOnly == "Int" || typeNameOnly == "Float") {
-				return map[string]interface{}{
-					"type":        "string",
-					"description": fmt.Sprintf("Big %s number", typeNameOnly),
-				}, nil
-			}
-		}
-	}
-
 	// Unknown type - return a generic object schema
-	return map[string]interface{}{
-		"type":        "object",
-		"description": fmt.Sprintf("Unknown type: %s", typeName),
+	return spec.Schema{
+		Type:        "object",
+		Description: fmt.Sprintf("Unknown type: %s", typeName),
 	}, nil
 }
 
 // generateSchemaFromType generates an OpenAPI schema by analyzing the actual Go struct
-func generateSchemaFromType(typeName, searchDir string, verbose bool) (map[string]interface{}, error) {
+//
+// The package qualifier is resolved like the compiler does for sourceFile, the annotated
+// file: through its imports, aliases included, or as its own package. Packages it does not
+// import are searched by name under searchDir.
+//
+// The declarations of the structs the schema is generated from are written to sources.
+func generateSchemaFromType(typeName, searchDir, sourceFile string, verbose, fieldDocs bool, timeFormat string, required schemagen.RequiredRules, search SearchOptions, sources io.Writer) (spec.Schema, error) {
 	if verbose {
 		log.Printf("Analyzing type: %s", typeName)
 	}
@@ -518,26 +914,27 @@ func generateSchemaFromType(typeName, searchDir string, verbose bool) (map[strin
 	if !strings.Contains(typeName, ".") || isBuiltinType(typeName) {
 		schema, err := parseComplexTypeExpression(typeName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse type expression %s: %w", typeName, err)
+			return spec.Schema{}, fmt.Errorf("failed to parse type expression %s: %w", typeName, err)
 		}
 		return schema, nil
 	}
 
-	// Parse the type name (e.g., "dto.LoginRequest" -> package="dto", typeName="LoginRequest")
-	parts := strings.Split(typeName, ".")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid type name format: %s, expected package.TypeName", typeName)
+	// Parse the type name (e.g., "dto.LoginRequest" -> package="dto", typeName="LoginRequest"),
+	// the package may be given by its import path (e.g., "myapp/internal/dto.LoginRequest")
+	dot := strings.LastIndex(typeName, ".")
+	packageName := typeName[:dot]
+	structName := typeName[dot+1:]
+	qualified := strings.Contains(packageName, "/")
+	if structName == "" || (!qualified && strings.Contains(packageName, ".")) {
+		return spec.Schema{}, fmt.Errorf("invalid type name format: %s, expected package.TypeName or import/path.TypeName", typeName)
 	}
 
-	packageName := parts[0]
-	structName := parts[1]
-
 	// Check if this is a standard library type we can handle directly
 	fullTypeName := fmt.Sprintf("%s.%s", packageName, structName)
 	if isBuiltinType(fullTypeName) {
 		schema, err := parseComplexTypeExpression(fullTypeName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse standard library type %s: %w", fullTypeName, err)
+			return spec.Schema{}, fmt.Errorf("failed to parse standard library type %s: %w", fullTypeName, err)
 		}
 		return schema, nil
 	}
@@ -546,32 +943,72 @@ func generateSchemaFromType(typeName, searchDir string, verbose bool) (map[strin
 		log.Printf("Analyzing custom struct type: %s from package: %s", structName, packageName)
 	}
 
-	// Find the package and struct definition
-	structDef, err := findStructDefinition(packageName, structName, searchDir, verbose)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find struct definition: %w", err)
-	}
-
 	// Generate OpenAPI schema from the struct with proper package context
 	packageRoot, err := findPackageRoot()
 	if err != nil {
 		packageRoot = "." // fallback to current directory
 	}
+	modules := moduleRoots(searchDir, search)
+
+	// Types resolved in a package found below, its directory and name are set by the lookup
+	context := &PackageContext{
+		RootSearchDir: packageRoot,
+		VisitedTypes:  make(map[string]bool),
+		FieldDocs:     fieldDocs,
+		TimeFormat:    timeFormat,
+		Required:      required,
+		Search:        search,
+		Modules:       modules,
+		Sources:       sources,
+	}
+
+	// Import-path-qualified names select the package exactly
+	if qualified {
+		dir := locatePackageDir(modules, packageName, search)
+		if dir == "" {
+			return spec.Schema{}, fmt.Errorf("package %s is not in the module at %s or its dependencies", packageName, searchDir)
+		}
+		pkg, err := loadPackage(dir, "", search)
+		if err != nil {
+			return spec.Schema{}, fmt.Errorf("failed to load package %s: %w", packageName, err)
+		}
+		if !pkg.HasType(structName) {
+			return spec.Schema{}, fmt.Errorf("type %s not found in package %s", structName, packageName)
+		}
+		return resolvePackageType(pkg, structName, context), nil
+	}
+
+	// Prefer the exact package the annotated file refers to
+	if pkg := referencedPackage(sourceFile, packageName, modules, search); pkg != nil && pkg.HasType(structName) {
+		if verbose {
+			log.Printf("Resolved package %s through %s: %s", packageName, sourceFile, pkg.Dir)
+		}
+		return resolvePackageType(pkg, structName, context), nil
+	}
+
+	// Find the package and struct definition
+	structDef, err := findStructDefinition(packageName, structName, searchDir, search, verbose)
+	if err != nil {
+		return spec.Schema{}, fmt.Errorf("failed to find struct definition: %w", err)
+	}
 
 	// Find the actual package directory for the target package
 	// We need to find the directory that contains the specific struct we found
-	packageDirs, err := findPackageDirectories(packageName, searchDir, verbose)
+	packageDirs, err := findPackageDirectories(packageName, searchDir, search, verbose)
 	var targetPackageDir string
 	if err == nil && len(packageDirs) > 0 {
-		// If we have multiple package directories, try to find the one that contains our struct
+		// If we have multiple package directories, find every one that contains our struct
 		targetPackageDir = packageDirs[0] // Default to first match
+		var candidates []string
 		for _, dir := range packageDirs {
-			// Check if this directory contains the struct we're looking for
-			if structExistsInDirectory(structName, dir, packageName) {
-				targetPackageDir = dir
-				break
+			if structExistsInDirectory(structName, dir, packageName, search) {
+				candidates = append(candidates, dir)
 			}
 		}
+		if len(candidates) > 0 {
+			targetPackageDir = candidates[0]
+		}
+		warnAmbiguousType(fullTypeName, candidates, modules)
 		if verbose {
 			log.Printf("Found package directory for %s: %s (from %d candidates)", packageName, targetPackageDir, len(packageDirs))
 		}
@@ -583,26 +1020,25 @@ func generateSchemaFromType(typeName, searchDir string, verbose bool) (map[strin
 	}
 
 	// Create proper package context
-	context := &PackageContext{
-		RootSearchDir:      packageRoot,
-		CurrentPackageDir:  targetPackageDir,
-		CurrentPackageName: packageName,
-		VisitedTypes:       make(map[string]bool),
-	}
+	context.CurrentPackageDir = targetPackageDir
+	context.CurrentPackageName = packageName
 
 	if verbose {
 		log.Printf("Created context for %s.%s - packageDir: %s, packageName: %s", packageName, structName, context.CurrentPackageDir, context.CurrentPackageName)
 	}
 
 	// Generate schema with proper context
-	schema := generateStructSchemaWithContext(structDef, context)
+	schema := generateStructDefinitionSchema(structDef, context)
 
 	return schema, nil
 }
 
 // findPackageDirectories recursively searches for directories containing Go files with the target package name
-func findPackageDirectories(packageName, searchDir string, verbose bool) ([]string, error) {
-	var packageDirs []string
+//
+// vendor/ is only searched with search.IncludeVendor, its directories come after the others.
+func findPackageDirectories(packageName, searchDir string, search SearchOptions, verbose bool) ([]string, error) {
+	var packageDirs, vendorDirs []string
+	vendorRoot := filepath.Join(searchDir, "vendor")
 
 	// Walk through all directories in searchDir
 	err := filepath.Walk(searchDir, func(path string, info os.FileInfo, err error) error {
@@ -614,14 +1050,17 @@ func findPackageDirectories(packageName, searchDir string, verbose bool) ([]stri
 		if info.IsDir() {
 			// Skip hidden directories and common non-package directories
 			dirName := filepath.Base(path)
+			if dirName == "vendor" && search.IncludeVendor && path == vendorRoot {
+				return nil
+			}
 			if strings.HasPrefix(dirName, ".") || dirName == "vendor" || dirName == "node_modules" {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Only process .go files
-		if !strings.HasSuffix(path, ".go") {
+		// Only process .go files that are part of the build
+		if !strings.HasSuffix(path, ".go") || !search.matchFile(path) {
 			return nil
 		}
 
@@ -635,7 +1074,14 @@ func findPackageDirectories(packageName, searchDir string, verbose bool) ([]stri
 		// If this file has the target package name, add its directory to our list
 		if node.Name.Name == packageName {
 			dir := filepath.Dir(path)
-			if !slices.Contains(packageDirs, dir) {
+			if strings.HasPrefix(dir, vendorRoot+string(filepath.Separator)) {
+				if !slices.Contains(vendorDirs, dir) {
+					vendorDirs = append(vendorDirs, dir)
+					if verbose {
+						log.Printf("Found vendored package directory: %s", dir)
+					}
+				}
+			} else if !slices.Contains(packageDirs, dir) {
 				packageDirs = append(packageDirs, dir)
 				if verbose {
 					log.Printf("Found package directory: %s", dir)
@@ -650,17 +1096,17 @@ func findPackageDirectories(packageName, searchDir string, verbose bool) ([]stri
 		return nil, fmt.Errorf("failed to walk directory tree: %w", err)
 	}
 
-	return packageDirs, nil
+	return append(packageDirs, vendorDirs...), nil
 }
 
 // findStructDefinition finds a struct definition in the specified package
-func findStructDefinition(packageName, structName, searchDir string, verbose bool) (*ast.StructType, error) {
+func findStructDefinition(packageName, structName, searchDir string, search SearchOptions, verbose bool) (*StructDefinition, error) {
 	if verbose {
 		log.Printf("Searching for struct %s.%s in directory: %s", packageName, structName, searchDir)
 	}
 
 	// First, try to find all directories that contain the target package
-	packageDirs, err := findPackageDirectories(packageName, searchDir, verbose)
+	packageDirs, err := findPackageDirectories(packageName, searchDir, search, verbose)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find package directories: %w", err)
 	}
@@ -679,21 +1125,16 @@ func findStructDefinition(packageName, structName, searchDir string, verbose boo
 			log.Printf("Searching in package directory: %s", packageDir)
 		}
 
-		// Get all Go files in this package directory
-		packageFiles, err := filepath.Glob(filepath.Join(packageDir, "*.go"))
+		// The whole package is parsed, the struct can be declared in any of its files
+		pkg, err := loadPackage(packageDir, packageName, search)
 		if err != nil {
 			continue // Skip this directory if we can't read it
 		}
-
-		// Search for the struct in each file
-		for _, file := range packageFiles {
-			structDef, err := findStructInFile(file, packageName, structName)
-			if err == nil {
-				if verbose {
-					log.Printf("Found struct %s.%s in file: %s", packageName, structName, file)
-				}
-				return structDef, nil
+		if structDef := pkg.Struct(structName); structDef != nil {
+			if verbose {
+				log.Printf("Found struct %s.%s in package directory: %s", packageName, structName, packageDir)
 			}
+			return structDef, nil
 		}
 	}
 
@@ -710,6 +1151,9 @@ func findStructDefinition(packageName, structName, searchDir string, verbose boo
 	}
 
 	for _, file := range files {
+		if !search.matchFile(file) {
+			continue
+		}
 		structDef, err := findStructInFile(file, packageName, structName)
 		if err == nil {
 			if verbose {
@@ -724,7 +1168,7 @@ func findStructDefinition(packageName, structName, searchDir string, verbose boo
 }
 
 // findStructInFile searches for a struct definition in a specific file
-func findStructInFile(filePath, packageName, structName string) (*ast.StructType, error) {
+func findStructInFile(filePath, packageName, structName string) (*StructDefinition, error) {
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -736,53 +1180,106 @@ func findStructInFile(filePath, packageName, structName string) (*ast.StructType
 		return nil, fmt.Errorf("wrong package name: %s, expected %s", node.Name.Name, packageName)
 	}
 
-	var foundStruct *ast.StructType
-
 	// Search for the struct definition
+	foundStruct := lookupStruct(node, structName)
+	if foundStruct == nil {
+		return nil, fmt.Errorf("struct %s not found in file", structName)
+	}
+
+	return foundStruct, nil
+}
+
+// lookupStruct finds a struct type declaration by name in a parsed file
+func lookupStruct(node *ast.File, structName string) *StructDefinition {
+	var foundStruct *StructDefinition
+
 	ast.Inspect(node, func(n ast.Node) bool {
 		if foundStruct != nil {
 			return false
 		}
 
-		if typeSpec, ok := n.(*ast.TypeSpec); ok && typeSpec.Name.Name == structName {
-			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-				foundStruct = structType
+		// Keep the GenDecl, ungrouped type declarations carry their doc comment on it
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
+			return true
+		}
+		for _, declSpec := range decl.Specs {
+			typeSpec := declSpec.(*ast.TypeSpec)
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.Name.Name == structName {
+				foundStruct = &StructDefinition{Name: structName, Type: structType, Doc: schemagen.TypeDoc(decl, typeSpec), Imports: fileImports(node)}
 				return false
 			}
 		}
 		return true
 	})
 
-	if foundStruct == nil {
-		return nil, fmt.Errorf("struct %s not found in file", structName)
+	return foundStruct
+}
+
+// generateStructDefinitionSchema generates the schema of a struct declaration, described by its doc comment
+func generateStructDefinitionSchema(structDef *StructDefinition, context *PackageContext) spec.Schema {
+	// Field types are written against the imports of the file declaring the struct
+	imports := context.Imports
+	context.Imports = structDef.Imports
+	defer func() { context.Imports = imports }()
+
+	if context.Sources != nil {
+		writeStructSource(context.Sources, structDef)
 	}
 
-	return foundStruct, nil
+	schema := generateStructSchemaWithContext(structDef.Type, context)
+	schemagen.ApplyTypeDoc(&schema, structDef.Name, structDef.Doc)
+	return schema
+}
+
+// writeStructSource writes the declaration of a struct with its doc comments, independent of
+// its formatting, so a source hash changes with the fields, tags or docs of the struct
+func writeStructSource(w io.Writer, structDef *StructDefinition) {
+	// Without the positions of the parsed file the struct is printed the same however it is laid out
+	printer.Fprint(w, token.NewFileSet(), structDef.Type)
+	io.WriteString(w, "\n"+structDef.Name+"\n"+structDef.Doc+"\n")
+	for _, field := range structDef.Type.Fields.List {
+		io.WriteString(w, field.Doc.Text()+field.Comment.Text()+"\n")
+	}
 }
 
 // generateStructSchemaWithContext generates an OpenAPI schema with package context and cycle detection
-func generateStructSchemaWithContext(structDef *ast.StructType, context *PackageContext) map[string]interface{} {
-	schema := map[string]interface{}{
-		"type":       "object",
-		"properties": make(map[string]interface{}),
-		"required":   make([]string, 0),
+func generateStructSchemaWithContext(structDef *ast.StructType, context *PackageContext) spec.Schema {
+	schema := spec.Schema{
+		Type:       "object",
+		Properties: make(map[string]spec.Schema),
+		Required:   []string{},
 	}
 
 	for _, field := range structDef.Fields.List {
+		tag := schemagen.FieldTag(field)
 		for _, name := range field.Names {
-			fieldSchema := resolveFieldTypeSchema(field.Type, context)
+			// encoding/json skips unexported fields, tagged or not
+			if !name.IsExported() {
+				continue
+			}
+			if name.Name == "XMLName" {
+				schema.XML = schemagen.XMLName(tag) // The element encoding/xml renders the struct as
+			}
+
+			// Name, constraints and required rules are shared with the runtime SchemaGenerator
+			fieldName := schemagen.FieldName(name.Name, tag)
+			if fieldName == "" {
+				continue // Skip fields marked as ignored
+			}
 
-			// Get field name from JSON tag first, then form tag as fallback
-			fieldName := getJSONTagName(field, name.Name)
-			if fieldName == name.Name {
-				// No JSON tag found, try form tag
-				fieldName = getFormTagName(field, name.Name)
+			fieldSchema := resolveFieldTypeSchema(field.Type, context)
+			if format := tag.Get(schemagen.TimeFormatTag); format != "" && typeExprName(field.Type) == "time.Time" {
+				fieldSchema = schemagen.TimeSchema(format)
+			}
+			if doc := schemagen.FieldDoc(field); context.FieldDocs && doc != "" {
+				fieldSchema.Description = doc
 			}
-			schema["properties"].(map[string]interface{})[fieldName] = fieldSchema
+			schemagen.ApplyFieldTags(&fieldSchema, tag)
+			schema.Properties[fieldName] = fieldSchema
 
-			// Check if field has a JSON or form tag that indicates it's required
-			if hasRequiredTag(field) {
-				schema["required"] = append(schema["required"].([]string), fieldName)
+			if _, pointer := field.Type.(*ast.StarExpr); context.Required.IsRequired(tag, pointer) {
+				schema.Required = append(schema.Required, fieldName)
 			}
 		}
 	}
@@ -791,7 +1288,7 @@ func generateStructSchemaWithContext(structDef *ast.StructType, context *Package
 }
 
 // resolveFieldTypeSchema analyzes a field type and generates the appropriate OpenAPI schema
-func resolveFieldTypeSchema(expr ast.Expr, context *PackageContext) map[string]interface{} {
+func resolveFieldTypeSchema(expr ast.Expr, context *PackageContext) spec.Schema {
 	switch t := expr.(type) {
 	case *ast.Ident:
 		// Handle both basic types and custom structs in the current package
@@ -814,27 +1311,24 @@ func resolveFieldTypeSchema(expr ast.Expr, context *PackageContext) map[string]i
 
 			return resolveCrossPackageStruct(packageName, typeName, context)
 		}
-		return map[string]interface{}{
-			"type":        "object",
-			"description": "External type",
+		return spec.Schema{
+			Type:        "object",
+			Description: "External type",
 		}
 
 	case *ast.ArrayType:
-		// Handle arrays/slices with recursive element analysis
-		elemSchema := resolveFieldTypeSchema(t.Elt, context)
-		return map[string]interface{}{
-			"type":        "array",
-			"items":       elemSchema,
-			"description": fmt.Sprintf("Array of %s", getTypeDescription(elemSchema)),
+		// Handle arrays/slices with recursive element analysis, byte slices are base64 strings
+		if length, fixed := schemagen.ArrayLength(t); fixed {
+			return schemagen.ArrayOfLength(resolveFieldTypeSchema(t.Elt, context), length)
 		}
+		if elt, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && schemagen.IsByteType(elt.Name) {
+			return schemagen.Bytes()
+		}
+		return schemagen.ArrayOf(resolveFieldTypeSchema(t.Elt, context))
 
 	case *ast.MapType:
-		// Handle maps with recursive value analysis
-		valueSchema := resolveFieldTypeSchema(t.Value, context)
-		return map[string]interface{}{
-			"type":                 "object",
-			"additionalProperties": valueSchema,
-		}
+		// Handle maps with recursive value analysis, documenting non-string keys
+		return schemagen.MapWithKeys(typeExprName(t.Key), resolveFieldTypeSchema(t.Value, context))
 
 	case *ast.StarExpr:
 		// Handle pointers (dereference to underlying type)
@@ -842,108 +1336,64 @@ func resolveFieldTypeSchema(expr ast.Expr, context *PackageContext) map[string]i
 
 	case *ast.InterfaceType:
 		// Handle interface{} as any type
-		return map[string]interface{}{
-			"type":        "object",
-			"description": "Interface type",
+		return spec.Schema{
+			Type:        "object",
+			Description: "Interface type",
 		}
 
 	default:
-		return map[string]interface{}{
-			"type":        "object",
-			"description": "Unknown type",
+		return spec.Schema{
+			Type:        "object",
+			Description: "Unknown type",
 		}
 	}
 }
 
-// generateBasicTypeSchema generates OpenAPI schema for basic Go types
-func generateBasicTypeSchema(typeName string) map[string]interface{} {
-	switch typeName {
-	case "string":
-		return map[string]interface{}{"type": "string"}
-	case "int", "int8", "int16", "int32", "int64":
-		return map[string]interface{}{"type": "integer", "format": "int64"}
-	case "uint", "uint8", "uint16", "uint32", "uint64":
-		return map[string]interface{}{"type": "integer", "format": "int64"}
-	case "float32", "float64":
-		return map[string]interface{}{"type": "number", "format": "double"}
-	case "bool":
-		return map[string]interface{}{"type": "boolean"}
-	case "time.Time":
-		return map[string]interface{}{"type": "string", "format": "date-time"}
-	default:
-		return map[string]interface{}{
-			"type":        "object",
-			"description": fmt.Sprintf("Type: %s", typeName),
+// typeExprName returns the name of a type expression as written, e.g. "int" or "dto.Currency", empty when unnamed
+func typeExprName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return x.Name + "." + t.Sel.Name
 		}
+	case *ast.StarExpr:
+		return typeExprName(t.X)
 	}
+	return ""
 }
 
-// getJSONTagName extracts the JSON tag name from a field
-func getJSONTagName(field *ast.Field, defaultName string) string {
-	if field.Tag != nil {
-		tagValue := strings.Trim(field.Tag.Value, "`")
-		if strings.Contains(tagValue, "json:") {
-			// Extract JSON tag content
-			jsonTag := regexp.MustCompile(`json:"([^"]*)"`).FindStringSubmatch(tagValue)
-			if len(jsonTag) > 1 {
-				// Split by comma to handle options like omitempty
-				tagParts := strings.Split(jsonTag[1], ",")
-				if tagParts[0] != "" {
-					return tagParts[0]
-				}
-			}
-		}
+// generateBasicTypeSchema generates OpenAPI schema for basic Go types and well-known standard library types
+func generateBasicTypeSchema(typeName string) spec.Schema {
+	if schema, basic := schemagen.BasicType(typeName); basic {
+		return schema
 	}
-	return defaultName
-}
-
-// getFormTagName extracts the form tag name from a field
-func getFormTagName(field *ast.Field, defaultName string) string {
-	if field.Tag != nil {
-		tagValue := strings.Trim(field.Tag.Value, "`")
-		if strings.Contains(tagValue, "form:") {
-			// Extract form tag content
-			formTag := regexp.MustCompile(`form:"([^"]*)"`).FindStringSubmatch(tagValue)
-			if len(formTag) > 1 {
-				// Split by comma to handle options like omitempty
-				tagParts := strings.Split(formTag[1], ",")
-				if tagParts[0] != "" {
-					return tagParts[0]
-				}
-			}
+	if dot := strings.LastIndex(typeName, "."); dot > 0 {
+		if schema, known := schemagen.KnownType(typeName[:dot], typeName[dot+1:]); known {
+			return schema
 		}
 	}
-	return defaultName
+	return spec.Schema{
+		Type:        "object",
+		Description: fmt.Sprintf("Type: %s", typeName),
+	}
 }
 
-// hasRequiredTag checks if a field has a JSON or form tag indicating it's required
-func hasRequiredTag(field *ast.Field) bool {
-	if field.Tag != nil {
-		tagValue := strings.Trim(field.Tag.Value, "`")
-
-		// Check JSON tag first
-		if strings.Contains(tagValue, "json:") {
-			jsonTag := regexp.MustCompile(`json:"([^"]*)"`).FindStringSubmatch(tagValue)
-			if len(jsonTag) > 1 {
-				// Check if the tag contains omitempty
-				return !strings.Contains(jsonTag[1], "omitempty")
-			}
+// findPackageRoot finds the root directory of the Go package by looking for go.mod
+func findPackageRoot() (string, error) {
+	// -module names the module, e.g. when run from the root of a repository holding several
+	if moduleDir != "" {
+		dir, err := filepath.Abs(moduleDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve module directory: %w", err)
 		}
-
-		// If no JSON tag, check form tag
-		if strings.Contains(tagValue, "form:") {
-			formTag := regexp.MustCompile(`form:"([^"]*)"`).FindStringSubmatch(tagValue)
-			if len(formTag) > 1 {
-				// Check if the tag contains omitempty
-				return !strings.Contains(formTag[1], "omitempty")
-			}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			return "", fmt.Errorf("go.mod not found in module directory %s", moduleDir)
 		}
+		return dir, nil
 	}
-	return false
-}
 
-// findPackageRoot finds the root directory of the Go package by looking for go.mod
-func findPackageRoot() (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
@@ -968,6 +1418,10 @@ func findPackageRoot() (string, error) {
 	return "", fmt.Errorf("go.mod not found in directory tree")
 }
 
+// moduleDir is the module directory given with -module, empty for the module holding the
+// working directory
+var moduleDir string
+
 // sanitizeFileName creates a safe filename from handler name
 func sanitizeFileName(handlerName string) string {
 	// Replace common problematic characters
@@ -983,30 +1437,15 @@ func sanitizeFileName(handlerName string) string {
 	return strings.TrimSpace(safeName)
 }
 
-// getTypeDescription extracts a readable description from a schema
-func getTypeDescription(schema map[string]interface{}) string {
-	if desc, ok := schema["description"]; ok {
-		if descStr, ok := desc.(string); ok {
-			return descStr
-		}
-	}
-	if typeVal, ok := schema["type"]; ok {
-		if typeStr, ok := typeVal.(string); ok {
-			return typeStr
-		}
-	}
-	return "unknown"
-}
-
 // resolveNestedStructInCurrentPackage resolves a struct reference within the current package context
-func resolveNestedStructInCurrentPackage(structName string, context *PackageContext) map[string]interface{} {
+func resolveNestedStructInCurrentPackage(structName string, context *PackageContext) spec.Schema {
 	fullTypeName := fmt.Sprintf("%s.%s", context.CurrentPackageName, structName)
 
 	// Check for circular references
 	if context.VisitedTypes[fullTypeName] {
-		return map[string]interface{}{
-			"type":        "object",
-			"description": fmt.Sprintf("Circular reference to %s", fullTypeName),
+		return spec.Schema{
+			Type:        "object",
+			Description: fmt.Sprintf("Circular reference to %s", fullTypeName),
 		}
 	}
 
@@ -1014,7 +1453,7 @@ func resolveNestedStructInCurrentPackage(structName string, context *PackageCont
 	currentPackageName := context.CurrentPackageName
 	if currentPackageName == "" && context.CurrentPackageDir != context.RootSearchDir {
 		// Discover the package name from the directory
-		packageFiles, err := filepath.Glob(filepath.Join(context.CurrentPackageDir, "*.go"))
+		packageFiles, err := context.Search.packageFiles(context.CurrentPackageDir)
 		if err == nil && len(packageFiles) > 0 {
 			fset := token.NewFileSet()
 			node, err := parser.ParseFile(fset, packageFiles[0], nil, parser.PackageClauseOnly)
@@ -1025,8 +1464,18 @@ func resolveNestedStructInCurrentPackage(structName string, context *PackageCont
 		}
 	}
 
+	// Named non-struct types and types with custom encoding, declared in any file of the package
+	if pkg, err := loadPackage(context.CurrentPackageDir, currentPackageName, context.Search); err == nil {
+		context.VisitedTypes[fullTypeName] = true
+		schema, named := namedTypeSchema(pkg, structName, context)
+		delete(context.VisitedTypes, fullTypeName)
+		if named {
+			return schema
+		}
+	}
+
 	// Try to find the struct in the current package directory
-	structDef, err := findStructInPackageDirectory(structName, context.CurrentPackageDir, currentPackageName)
+	structDef, err := findStructInPackageDirectory(structName, context.CurrentPackageDir, currentPackageName, context.Search)
 	if err == nil && structDef != nil {
 		// Update fullTypeName with discovered package name if needed
 		if context.CurrentPackageName != currentPackageName {
@@ -1037,7 +1486,7 @@ func resolveNestedStructInCurrentPackage(structName string, context *PackageCont
 		context.VisitedTypes[fullTypeName] = true
 
 		// Generate schema with current context
-		schema := generateStructSchemaWithContext(structDef, context)
+		schema := generateStructDefinitionSchema(structDef, context)
 
 		// Remove from visited after processing (allow reuse in different branches)
 		delete(context.VisitedTypes, fullTypeName)
@@ -1045,137 +1494,108 @@ func resolveNestedStructInCurrentPackage(structName string, context *PackageCont
 	}
 
 	// Fall back to basic type schema if struct not found
-	return map[string]interface{}{
-		"type":        "object",
-		"description": fmt.Sprintf("Type: %s (not found in package %s at %s)", structName, currentPackageName, context.CurrentPackageDir),
+	return spec.Schema{
+		Type:        "object",
+		Description: fmt.Sprintf("Type: %s (not found in package %s at %s)", structName, currentPackageName, context.CurrentPackageDir),
 	}
 }
 
 // resolveCrossPackageStruct resolves a struct reference from another package (e.g., dto.UserDTO)
-func resolveCrossPackageStruct(packageName, typeName string, context *PackageContext) map[string]interface{} {
-	fullTypeName := packageName + "." + typeName
-
-	// Handle known standard library types first
+//
+// The package is looked up through the imports of the file being analyzed, which finds the
+// right package in a third package's module too. Packages whose import cannot be followed
+// are searched by name under the root search directory.
+func resolveCrossPackageStruct(packageName, typeName string, context *PackageContext) spec.Schema {
 	if packageName == "time" && typeName == "Time" {
-		return map[string]interface{}{
-			"type":   "string",
-			"format": "date-time",
-		}
+		return schemagen.TimeSchema(context.TimeFormat)
 	}
 
-	// Check for circular references
-	if context.VisitedTypes[fullTypeName] {
-		return map[string]interface{}{
-			"type":        "object",
-			"description": fmt.Sprintf("Circular reference to %s", fullTypeName),
-		}
+	// Handle known standard library types first
+	if schema, known := schemagen.KnownType(packageName, typeName); known {
+		return schema
 	}
 
-	// Try to find and analyze the cross-package struct
-	structDef, err := findStructDefinition(packageName, typeName, context.RootSearchDir, false)
-	if err == nil && structDef != nil {
-		// Find the package directory for the target package
-		packageDirs, err := findPackageDirectories(packageName, context.RootSearchDir, false) // Disable verbose
-		var targetPackageDir string
-		if err == nil && len(packageDirs) > 0 {
-			targetPackageDir = packageDirs[0] // Use the first match
-		} else {
-			targetPackageDir = context.RootSearchDir // Fallback
-		}
-
-		// Verify the package name from the actual directory
-		actualPackageName := packageName
-		if targetPackageDir != context.RootSearchDir {
-			packageFiles, err := filepath.Glob(filepath.Join(targetPackageDir, "*.go"))
-			if err == nil && len(packageFiles) > 0 {
-				fset := token.NewFileSet()
-				node, err := parser.ParseFile(fset, packageFiles[0], nil, parser.PackageClauseOnly)
-				if err == nil {
-					actualPackageName = node.Name.Name
-				}
-			}
-		}
+	if pkg := importedPackage(packageName, context); pkg != nil && pkg.HasType(typeName) {
+		return resolvePackageType(pkg, typeName, context)
+	}
 
-		// Create new context for the target package with verified package name
-		newContext := &PackageContext{
-			RootSearchDir:      context.RootSearchDir,
-			CurrentPackageDir:  targetPackageDir,
-			CurrentPackageName: actualPackageName,    // Use verified package name
-			VisitedTypes:       context.VisitedTypes, // Share visited types to prevent cross-package cycles
+	// Find the package declaring the type, each candidate is parsed as a whole
+	packageDirs, _ := findPackageDirectories(packageName, context.RootSearchDir, context.Search, false)
+	var candidates []*SourcePackage
+	var candidateDirs []string
+	for _, packageDir := range packageDirs {
+		pkg, err := loadPackage(packageDir, packageName, context.Search)
+		if err != nil || !pkg.HasType(typeName) {
+			continue
 		}
-
-		// Mark as visited to prevent cycles
-		context.VisitedTypes[fullTypeName] = true
-
-		// Generate schema with the new package context
-		schema := generateStructSchemaWithContext(structDef, newContext)
-
-		// Remove from visited after processing
-		delete(context.VisitedTypes, fullTypeName)
-		return schema
+		candidates = append(candidates, pkg)
+		candidateDirs = append(candidateDirs, packageDir)
+	}
+	if len(candidates) > 0 {
+		warnAmbiguousType(packageName+"."+typeName, candidateDirs, context.Modules)
+		return resolvePackageType(candidates[0], typeName, context)
 	}
 
-	return map[string]interface{}{
-		"type":        "object",
-		"description": fmt.Sprintf("External type: %s.%s", packageName, typeName),
+	return spec.Schema{
+		Type:        "object",
+		Description: fmt.Sprintf("External type: %s.%s", packageName, typeName),
 	}
 }
 
-// findStructInPackageDirectory finds a struct definition in a specific package directory
-func findStructInPackageDirectory(structName, packageDir, expectedPackageName string) (*ast.StructType, error) {
-	// Get all Go files in the package directory
-	packageFiles, err := filepath.Glob(filepath.Join(packageDir, "*.go"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to find Go files in %s: %w", packageDir, err)
-	}
+// resolvePackageType generates the schema of a type declared in another package
+func resolvePackageType(pkg *SourcePackage, typeName string, context *PackageContext) spec.Schema {
+	fullTypeName := pkg.Name + "." + typeName
 
-	if len(packageFiles) == 0 {
-		return nil, fmt.Errorf("no Go files found in directory %s", packageDir)
+	// Check for circular references
+	if context.VisitedTypes[fullTypeName] {
+		return spec.Schema{
+			Type:        "object",
+			Description: fmt.Sprintf("Circular reference to %s", fullTypeName),
+		}
 	}
 
-	// Verify package name if provided
-	if expectedPackageName != "" {
-		fset := token.NewFileSet()
-		node, err := parser.ParseFile(fset, packageFiles[0], nil, parser.PackageClauseOnly)
-		if err != nil || node.Name.Name != expectedPackageName {
-			return nil, fmt.Errorf("package name mismatch in directory %s", packageDir)
-		}
+	// Create new context for the target package
+	newContext := &PackageContext{
+		RootSearchDir:      context.RootSearchDir,
+		CurrentPackageDir:  pkg.Dir,
+		CurrentPackageName: pkg.Name,
+		VisitedTypes:       context.VisitedTypes, // Share visited types to prevent cross-package cycles
+		FieldDocs:          context.FieldDocs,
+		TimeFormat:         context.TimeFormat,
+		Required:           context.Required,
+		Search:             context.Search,
+		Modules:            context.Modules,
+		Sources:            context.Sources,
 	}
 
-	// Search for the struct in all files of this package
-	for _, file := range packageFiles {
-		fset := token.NewFileSet()
-		node, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
-		if err != nil {
-			continue // Skip files that can't be parsed
-		}
+	// Mark as visited to prevent cycles
+	context.VisitedTypes[fullTypeName] = true
 
-		// Look for the struct definition
-		var foundStruct *ast.StructType
-		ast.Inspect(node, func(n ast.Node) bool {
-			if foundStruct != nil {
-				return false
-			}
+	// Generate schema with the new package context
+	schema, named := namedTypeSchema(pkg, typeName, newContext)
+	if !named {
+		schema = generateStructDefinitionSchema(pkg.Struct(typeName), newContext)
+	}
 
-			if typeSpec, ok := n.(*ast.TypeSpec); ok && typeSpec.Name.Name == structName {
-				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-					foundStruct = structType
-					return false
-				}
-			}
-			return true
-		})
+	// Remove from visited after processing
+	delete(context.VisitedTypes, fullTypeName)
+	return schema
+}
 
-		if foundStruct != nil {
-			return foundStruct, nil
-		}
+// findStructInPackageDirectory finds a struct definition in a specific package directory
+func findStructInPackageDirectory(structName, packageDir, expectedPackageName string, search SearchOptions) (*StructDefinition, error) {
+	pkg, err := loadPackage(packageDir, expectedPackageName, search)
+	if err != nil {
+		return nil, err
+	}
+	if structDef := pkg.Struct(structName); structDef != nil {
+		return structDef, nil
 	}
-
 	return nil, fmt.Errorf("struct %s not found in package directory %s", structName, packageDir)
 }
 
 // structExistsInDirectory checks if a struct exists in a specific package directory
-func structExistsInDirectory(structName, packageDir, expectedPackageName string) bool {
-	_, err := findStructInPackageDirectory(structName, packageDir, expectedPackageName)
+func structExistsInDirectory(structName, packageDir, expectedPackageName string, search SearchOptions) bool {
+	_, err := findStructInPackageDirectory(structName, packageDir, expectedPackageName, search)
 	return err == nil
 }
@@ -0,0 +1,526 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zainokta/openapi-gen/schemagen"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// SourcePackage is a package parsed as a whole, a type, its methods and its constants can
+// be declared in different files
+type SourcePackage struct {
+	Name string
+	Dir  string
+	// types maps type names to their declaration
+	types map[string]typeDeclaration
+	// methods maps receiver type names to the names of their methods
+	methods map[string][]string
+	// enums maps type names to the values of the string constants declared with that type
+	enums map[string][]string
+}
+
+// typeDeclaration is a type spec and the declaration holding its doc comment
+type typeDeclaration struct {
+	decl *ast.GenDecl
+	spec *ast.TypeSpec
+	// imports are the imports of the file declaring the type, its field types refer to them
+	imports importTable
+}
+
+// importSpec is an import declaration, Name is its alias and empty for a plain import
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// importTable is the import declarations of a source file
+type importTable []importSpec
+
+// fileImports returns the import declarations of a parsed file
+func fileImports(file *ast.File) importTable {
+	imports := make(importTable, 0, len(file.Imports))
+	for _, imported := range file.Imports {
+		importPath, err := strconv.Unquote(imported.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if imported.Name != nil {
+			name = imported.Name.Name
+		}
+		imports = append(imports, importSpec{Name: name, Path: importPath})
+	}
+	return imports
+}
+
+// importedPackage returns the package a file imports as name, following the import path
+// through the module roots instead of guessing the directory from the package name
+//
+// Plain imports match by the package clause of the imported package, which may differ from
+// the last element of its path. Nil is returned when the file does not import name or the
+// package sources are not in a module root.
+func importedPackage(name string, context *PackageContext) *SourcePackage {
+	for _, imported := range context.Imports {
+		if imported.Name == "_" || imported.Name == "." || (imported.Name != "" && imported.Name != name) {
+			continue
+		}
+		dir := locatePackageDir(context.Modules, imported.Path, context.Search)
+		if dir == "" {
+			continue
+		}
+		pkg, err := loadPackage(dir, "", context.Search)
+		if err != nil {
+			continue
+		}
+		if imported.Name == name || pkg.Name == name {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// referencedPackage returns the package sourceFile refers to as name, the package it imports
+// under that name or its own package, nil when it refers to neither
+func referencedPackage(sourceFile, name string, modules []moduleRoot, search SearchOptions) *SourcePackage {
+	if sourceFile == "" {
+		return nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), sourceFile, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	if pkg := importedPackage(name, &PackageContext{Imports: fileImports(file), Modules: modules, Search: search}); pkg != nil {
+		return pkg
+	}
+	if file.Name.Name != name {
+		return nil
+	}
+	pkg, err := loadPackage(filepath.Dir(sourceFile), name, search)
+	if err != nil {
+		return nil
+	}
+	return pkg
+}
+
+// warnedTypes holds the ambiguous type names already warned about during this run
+var warnedTypes = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// warnAmbiguousType warns when several packages sharing a name declare typeName, the
+// package in the first directory is used
+func warnAmbiguousType(typeName string, dirs []string, modules []moduleRoot) {
+	if len(dirs) < 2 {
+		return
+	}
+	warnedTypes.Lock()
+	warned := warnedTypes.names[typeName]
+	warnedTypes.names[typeName] = true
+	warnedTypes.Unlock()
+	if warned {
+		return
+	}
+
+	candidates := make([]string, len(dirs))
+	for i, dir := range dirs {
+		candidates[i] = dir
+		if importPath := packageImportPath(modules, dir); importPath != "" {
+			candidates[i] = importPath
+		}
+	}
+	log.Printf("Warning: %s is declared in %d packages, using %s; qualify the type with its import path to pick another: %s",
+		typeName, len(dirs), candidates[0], strings.Join(candidates, ", "))
+}
+
+// moduleRoot is a module whose sources are on disk
+type moduleRoot struct {
+	Path string // Module path, e.g. github.com/acme/shop
+	Dir  string // Directory of the module sources
+}
+
+// moduleRoots returns the modules import paths are resolved in: the module holding searchDir,
+// followed by its vendored modules when vendor/ is searched
+func moduleRoots(searchDir string, search SearchOptions) []moduleRoot {
+	dir, err := filepath.Abs(searchDir)
+	if err != nil {
+		return nil
+	}
+	for {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			roots := []moduleRoot{{Path: modulePath(data), Dir: dir}}
+			if search.IncludeVendor {
+				roots = append(roots, vendorModuleRoots(dir)...)
+			}
+			return roots
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// modulePath returns the module path declared by a go.mod file
+func modulePath(goMod []byte) string {
+	for _, line := range strings.Split(string(goMod), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
+
+// vendorModuleRoots returns the modules listed in vendor/modules.txt of a module, "# path version" lines
+func vendorModuleRoots(moduleDir string) []moduleRoot {
+	data, err := os.ReadFile(filepath.Join(moduleDir, "vendor", "modules.txt"))
+	if err != nil {
+		return nil
+	}
+	var roots []moduleRoot
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "#" {
+			roots = append(roots, moduleRoot{Path: fields[1], Dir: filepath.Join(moduleDir, "vendor", filepath.FromSlash(fields[1]))})
+		}
+	}
+	return roots
+}
+
+// packageImportPath maps a package directory to its import path in the module with the
+// longest matching directory, empty when no module contains the directory
+func packageImportPath(roots []moduleRoot, dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	var match moduleRoot
+	for _, root := range roots {
+		if root.Path == "" || len(root.Dir) <= len(match.Dir) {
+			continue
+		}
+		if dir == root.Dir || strings.HasPrefix(dir, root.Dir+string(filepath.Separator)) {
+			match = root
+		}
+	}
+	if match.Path == "" {
+		return ""
+	}
+	relative, err := filepath.Rel(match.Dir, dir)
+	if err != nil || relative == "." {
+		return match.Path
+	}
+	return match.Path + "/" + filepath.ToSlash(relative)
+}
+
+// resolvePackageDir maps an import path to its directory in the module with the longest
+// matching path, empty when no module contains the package
+func resolvePackageDir(roots []moduleRoot, importPath string) string {
+	var match moduleRoot
+	for _, root := range roots {
+		if root.Path != "" && len(root.Path) > len(match.Path) && (importPath == root.Path || strings.HasPrefix(importPath, root.Path+"/")) {
+			match = root
+		}
+	}
+	if match.Path == "" {
+		return ""
+	}
+	return filepath.Join(match.Dir, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, match.Path), "/")))
+}
+
+// locatePackageDir returns the directory of the package with an import path, empty when it
+// cannot be found
+//
+// Packages of the module roots are found on disk. Others are looked up with go list, which
+// knows the other modules of a go.work workspace, modules replaced by a local directory and
+// dependencies in the module cache. Standard library packages are not looked up.
+func locatePackageDir(roots []moduleRoot, importPath string, search SearchOptions) string {
+	if dir := resolvePackageDir(roots, importPath); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	// Module paths start with a domain name, standard library paths have no dot in their first element
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		return ""
+	}
+	moduleDir := ""
+	if len(roots) > 0 {
+		moduleDir = roots[0].Dir
+	}
+	return goListPackageDir(importPath, moduleDir, search)
+}
+
+// goListCache holds the directories go list reported during this run, keyed by import path
+var goListCache = struct {
+	sync.Mutex
+	dirs map[string]string
+}{dirs: make(map[string]string)}
+
+// goListPackageDir asks the go command run in moduleDir for the directory of a package
+func goListPackageDir(importPath, moduleDir string, search SearchOptions) string {
+	goListCache.Lock()
+	dir, cached := goListCache.dirs[importPath]
+	goListCache.Unlock()
+	if cached {
+		return dir
+	}
+	args := []string{"list", "-find", "-f", "{{.Dir}}"}
+	if search.Build != nil && len(search.Build.BuildTags) > 0 {
+		args = append(args, "-tags", strings.Join(search.Build.BuildTags, ","))
+	}
+	cmd := exec.Command("go", append(args, "--", importPath)...)
+	cmd.Dir = moduleDir
+	output, err := cmd.Output()
+	if err == nil {
+		dir = strings.TrimSpace(string(output))
+	}
+	goListCache.Lock()
+	goListCache.dirs[importPath] = dir
+	goListCache.Unlock()
+	return dir
+}
+
+// packageCache holds the packages parsed during this run, keyed by directory. Workers
+// generating schemas in parallel share it, each directory is parsed once.
+var packageCache = struct {
+	sync.Mutex
+	entries map[string]*packageEntry
+}{entries: make(map[string]*packageEntry)}
+
+// packageEntry is a package of the cache, parsed by the first worker loading it
+type packageEntry struct {
+	once sync.Once
+	pkg  *SourcePackage
+	err  error
+}
+
+// loadPackage parses every file of the build in dir that belongs to packageName
+//
+// An empty packageName accepts the package of the first file. Test files and files
+// excluded by build constraints are skipped, see SearchOptions.
+func loadPackage(dir, packageName string, search SearchOptions) (*SourcePackage, error) {
+	packageCache.Lock()
+	entry, cached := packageCache.entries[dir]
+	if !cached {
+		entry = &packageEntry{}
+		packageCache.entries[dir] = entry
+	}
+	packageCache.Unlock()
+
+	entry.once.Do(func() {
+		entry.pkg, entry.err = parsePackage(dir, search)
+	})
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	if packageName != "" && entry.pkg.Name != packageName {
+		return nil, fmt.Errorf("package name mismatch in directory %s", dir)
+	}
+	return entry.pkg, nil
+}
+
+// parsePackage parses the files of the build in dir belonging to the package of the first one
+func parsePackage(dir string, search SearchOptions) (*SourcePackage, error) {
+	packageFiles, err := search.packageFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Go files in %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	name := ""
+	for _, file := range packageFiles {
+		node, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			continue // Skip files that can't be parsed
+		}
+		if name == "" {
+			name = node.Name.Name
+		}
+		if node.Name.Name == name {
+			files = append(files, node)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in directory %s", dir)
+	}
+
+	pkg := &SourcePackage{
+		Name:    name,
+		Dir:     dir,
+		types:   make(map[string]typeDeclaration),
+		methods: make(map[string][]string),
+		enums:   packageEnums(fset, name, files),
+	}
+	for _, file := range files {
+		imports := fileImports(file)
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, declSpec := range decl.Specs {
+					typeSpec := declSpec.(*ast.TypeSpec)
+					pkg.types[typeSpec.Name.Name] = typeDeclaration{decl: decl, spec: typeSpec, imports: imports}
+				}
+			case *ast.FuncDecl:
+				if receiver := receiverTypeName(decl); receiver != "" {
+					pkg.methods[receiver] = append(pkg.methods[receiver], decl.Name.Name)
+				}
+			}
+		}
+	}
+
+	return pkg, nil
+}
+
+// receiverTypeName returns the type name of a method receiver, "User" for (u *User) or (l List[T])
+func receiverTypeName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	expr := decl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// packageEnums type-checks the package to evaluate its constants, iota included, and groups the
+// string constants by their named type
+//
+// Imports are not loaded, constants whose value depends on another package are skipped.
+func packageEnums(fset *token.FileSet, name string, files []*ast.File) map[string][]string {
+	config := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			return nil, fmt.Errorf("package %s is not loaded", path)
+		}),
+		Error: func(error) {}, // Keep checking past unresolved imports
+	}
+	checked, _ := config.Check(name, fset, files, nil)
+	if checked == nil {
+		return nil
+	}
+
+	var consts []*types.Const
+	for _, objectName := range checked.Scope().Names() {
+		if c, ok := checked.Scope().Lookup(objectName).(*types.Const); ok {
+			consts = append(consts, c)
+		}
+	}
+	// Scope names are sorted, document values in declaration order
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	enums := make(map[string][]string)
+	for _, c := range consts {
+		named, ok := c.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != checked || c.Val().Kind() != constant.String {
+			continue
+		}
+		typeName := named.Obj().Name()
+		if value := constant.StringVal(c.Val()); !slices.Contains(enums[typeName], value) {
+			enums[typeName] = append(enums[typeName], value)
+		}
+	}
+	return enums
+}
+
+// importerFunc adapts a function to types.Importer
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// Struct returns the struct type declared as name, nil when name is not a struct
+func (p *SourcePackage) Struct(name string) *StructDefinition {
+	declaration, exists := p.types[name]
+	if !exists {
+		return nil
+	}
+	structType, ok := declaration.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	return &StructDefinition{
+		Name:    name,
+		Type:    structType,
+		Doc:     schemagen.TypeDoc(declaration.decl, declaration.spec),
+		Imports: declaration.imports,
+	}
+}
+
+// HasType reports whether the package declares a type called name
+func (p *SourcePackage) HasType(name string) bool {
+	_, exists := p.types[name]
+	return exists
+}
+
+// HasMethod reports whether a method is declared on the type, in any file of the package
+func (p *SourcePackage) HasMethod(typeName, method string) bool {
+	return slices.Contains(p.methods[typeName], method)
+}
+
+// namedTypeSchema documents named types whose schema does not come from their struct fields
+//
+// Types with a MarshalText method are strings on the wire, types with a MarshalJSON method
+// encode themselves and are left free-form. Non-struct types are documented by their
+// underlying type, with the string constants declared with the type as enum. Plain structs
+// return false and are walked field by field.
+func namedTypeSchema(pkg *SourcePackage, typeName string, context *PackageContext) (spec.Schema, bool) {
+	declaration, exists := pkg.types[typeName]
+	if !exists {
+		return spec.Schema{}, false
+	}
+
+	// The underlying type is written against the imports of the file declaring it
+	imports := context.Imports
+	context.Imports = declaration.imports
+	defer func() { context.Imports = imports }()
+
+	var schema spec.Schema
+	switch {
+	case pkg.HasMethod(typeName, "MarshalJSON"):
+		schema = spec.Schema{Description: fmt.Sprintf("Encoded by %s.MarshalJSON", typeName)}
+	case pkg.HasMethod(typeName, "MarshalText"):
+		schema = spec.Schema{Type: "string"}
+	default:
+		if _, isStruct := declaration.spec.Type.(*ast.StructType); isStruct {
+			return spec.Schema{}, false
+		}
+		schema = resolveFieldTypeSchema(declaration.spec.Type, context)
+		if values := pkg.enums[typeName]; len(values) > 0 && schema.Type == "string" {
+			schema.Enum = values
+		}
+	}
+
+	schemagen.ApplyTypeDoc(&schema, typeName, schemagen.TypeDoc(declaration.decl, declaration.spec))
+	return schema, true
+}
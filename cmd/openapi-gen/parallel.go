@@ -0,0 +1,26 @@
+package main
+
+import "sync"
+
+// forEachParallel calls fn with the indexes 0 to n-1 on up to workers goroutines
+//
+// fn writes its results at its index, so they come out in the order of the inputs
+// however the work was scheduled.
+func forEachParallel(n, workers int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(workers, n)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestForEachParallel(t *testing.T) {
+	forEachParallel(0, 4, func(i int) {
+		t.Errorf("fn called with %d for no inputs", i)
+	})
+
+	// More workers than inputs, and no workers at all, still visit every index once
+	for _, workers := range []int{8, 0} {
+		results := make([]int, 3)
+		forEachParallel(len(results), workers, func(i int) {
+			results[i]++
+		})
+		for i, calls := range results {
+			if calls != 1 {
+				t.Errorf("workers=%d: index %d visited %d times, want 1", workers, i, calls)
+			}
+		}
+	}
+}
+
+func TestLoadPackageConcurrently(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("example", "dto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packages := make([]*SourcePackage, 8)
+	errs := make([]error, len(packages))
+	var wg sync.WaitGroup
+	for i := range packages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			packages[i], errs[i] = loadPackage(dir, "dto", SearchOptions{})
+		}()
+	}
+	wg.Wait()
+
+	// The directory is parsed once, every worker gets the cached package
+	for i, pkg := range packages {
+		if errs[i] != nil {
+			t.Fatalf("loadPackage: %v", errs[i])
+		}
+		if pkg != packages[0] {
+			t.Errorf("worker %d got a different package than worker 0", i)
+		}
+	}
+	if packages[0].Name != "dto" {
+		t.Errorf("package name = %q, want dto", packages[0].Name)
+	}
+
+	if _, err := loadPackage(dir, "handlers", SearchOptions{}); err == nil || !strings.Contains(err.Error(), "package name mismatch") {
+		t.Errorf("loadPackage with the wrong package name: err = %v, want a package name mismatch", err)
+	}
+}
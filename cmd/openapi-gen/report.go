@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// Outcomes of an annotation in the run report
+const (
+	outcomeGenerated = "generated" // The schema file was written, or is up to date with verify
+	outcomeFailed    = "failed"    // A type could not be resolved or the file could not be written
+	outcomeSkipped   = "skipped"   // The annotation could not be parsed or names no handler
+	outcomeStale     = "stale"     // verify found the schema file missing or out of date
+)
+
+// AnnotationResult is the outcome of the annotations of a handler, or of an annotation
+// skipped before its handler was known
+type AnnotationResult struct {
+	Handler    string   `json:"handler,omitempty"`
+	File       string   `json:"file"`
+	Line       int      `json:"line,omitempty"`
+	SchemaFile string   `json:"schemaFile,omitempty"`
+	Outcome    string   `json:"outcome"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// RunReport summarizes a run, printed as JSON with -json, and decides the exit code
+type RunReport struct {
+	OutputDir string             `json:"outputDir"`
+	Verify    bool               `json:"verify,omitempty"`
+	Generated int                `json:"generated"`
+	Failed    int                `json:"failed"`
+	Skipped   int                `json:"skipped"`
+	Stale     int                `json:"stale,omitempty"`
+	Results   []AnnotationResult `json:"results"`
+}
+
+// add records the outcome of an annotation, a nil report records nothing
+func (r *RunReport) add(result AnnotationResult) {
+	if r == nil {
+		return
+	}
+	switch result.Outcome {
+	case outcomeGenerated:
+		r.Generated++
+	case outcomeFailed:
+		r.Failed++
+	case outcomeSkipped:
+		r.Skipped++
+	case outcomeStale:
+		r.Stale++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// print writes the report as JSON to w, or logs a one-line summary
+func (r *RunReport) print(w io.Writer, jsonOutput bool) {
+	if jsonOutput {
+		if r.Results == nil {
+			r.Results = []AnnotationResult{}
+		}
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			fatalf("Failed to encode report: %v", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	if len(r.Results) == 0 {
+		return
+	}
+	if r.Verify {
+		log.Printf("Verified %d schema files in %s: %d stale, %d failed, %d skipped", r.Generated+r.Stale, r.OutputDir, r.Stale, r.Failed, r.Skipped)
+		return
+	}
+	log.Printf("Generated %d schema files in %s: %d failed, %d skipped", r.Generated, r.OutputDir, r.Failed, r.Skipped)
+}
+
+// exitCode is 1 when verify found stale schema files, or with strict when an annotation
+// failed or was skipped
+func (r *RunReport) exitCode(strict bool) int {
+	if r.Stale > 0 || (strict && (r.Failed > 0 || r.Skipped > 0)) {
+		return 1
+	}
+	return 0
+}
+
+// fatalf reports an error ending the run, also with -quiet
+func fatalf(format string, args ...any) {
+	log.SetOutput(os.Stderr)
+	log.Fatalf(format, args...)
+}
@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"slices"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// applyCompression documents the configured content codings of request and response bodies
+//
+// Responses with a body get a Content-Encoding header listing the response codings, and
+// operations with a request body an optional Content-Encoding header parameter listing the
+// request codings. Both lists are repeated in the x-compression extension.
+func (g *Generator) applyCompression(operation *spec.Operation) {
+	extension := &spec.CompressionExtension{}
+
+	if len(g.config.ResponseEncodings) > 0 {
+		for code, response := range operation.Responses {
+			if len(response.Content) == 0 {
+				continue
+			}
+			if _, exists := response.Headers["Content-Encoding"]; exists {
+				continue
+			}
+			headers := make(map[string]spec.Header, len(response.Headers)+1)
+			for name, header := range response.Headers {
+				headers[name] = header
+			}
+			headers["Content-Encoding"] = spec.Header{
+				Description: "Content coding of the body when the client accepts it, absent when uncompressed",
+				Schema:      spec.Schema{Type: "string", Enum: slices.Clone(g.config.ResponseEncodings)},
+			}
+			response.Headers = headers
+			operation.Responses[code] = response
+			extension.Response = g.config.ResponseEncodings
+		}
+	}
+
+	if len(g.config.RequestEncodings) > 0 && operation.RequestBody != nil {
+		if !hasParameter(operation.Parameters, "Content-Encoding", "header") {
+			operation.Parameters = append(operation.Parameters, spec.Parameter{
+				Name:        "Content-Encoding",
+				In:          "header",
+				Description: "Content coding of the request body, absent when uncompressed",
+				Schema:      spec.Schema{Type: "string", Enum: slices.Clone(g.config.RequestEncodings)},
+			})
+		}
+		extension.Request = g.config.RequestEncodings
+	}
+
+	if extension.Request != nil || extension.Response != nil {
+		operation.XCompression = extension
+	}
+}
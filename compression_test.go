@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compressedOrderRequest struct {
+	Items []string `json:"items"`
+}
+
+func TestCompressionDocumentation(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "POST", Path: "/orders", HandlerName: "CreateOrder"},
+		{Method: "DELETE", Path: "/orders/:id", HandlerName: "DeleteOrder"},
+	}
+	cfg := NewConfig(WithResponseCompression("gzip", "br"), WithRequestCompression("gzip"))
+	cfg.SchemaDir = ""
+	generator := newTestGenerator(t, routes, WithConfig(cfg))
+	generator.OverrideRequest("POST", "/orders", compressedOrderRequest{})
+	generator.OverrideResponse("DELETE", "/orders/:id", 204, nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	create := openAPISpec.Paths["/orders"].Post
+	assert.Equal(t, &spec.CompressionExtension{Request: []string{"gzip"}, Response: []string{"gzip", "br"}}, create.XCompression)
+	assert.Equal(t, []string{"gzip", "br"}, create.Responses["200"].Headers["Content-Encoding"].Schema.Enum)
+	assert.True(t, hasParameter(create.Parameters, "Content-Encoding", "header"))
+
+	remove := openAPISpec.Paths["/orders/{id}"].Delete
+	assert.NotContains(t, remove.Responses["204"].Headers, "Content-Encoding", "Responses without a body are not compressed")
+	assert.False(t, hasParameter(remove.Parameters, "Content-Encoding", "header"))
+
+	// Nothing is documented without configured codings
+	openAPISpec, err = newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Nil(t, openAPISpec.Paths["/orders"].Post.XCompression)
+}
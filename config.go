@@ -1,7 +1,30 @@
 package openapi
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/schemagen"
+)
+
+const (
+	// DefaultSchemaDir is the schema directory used when none is configured
+	DefaultSchemaDir = "./schemas"
+	// DefaultVersion is the API version used when a config leaves it empty
+	DefaultVersion = "0.0.1"
+	// DefaultServerPort is the server port used when a config leaves it empty
+	DefaultServerPort = 8080
+	// DefaultDocsPath is the path Swagger UI is served on
+	DefaultDocsPath = "/docs"
+	// DefaultSpecPath is the path the OpenAPI JSON document is served on
+	DefaultSpecPath = "/openapi.json"
 )
 
 // Config represents the configuration for the OpenAPI generator
@@ -14,8 +37,70 @@ type Config struct {
 	Version     string  `json:"version,omitempty"`
 	Contact     Contact `json:"contact,omitempty"`
 
+	// API catalog metadata, left out of the spec when empty
+	TermsOfService string       `json:"terms_of_service,omitempty"` // URL of the terms of service
+	License        License      `json:"license,omitempty"`
+	ExternalDocs   ExternalDocs `json:"external_docs,omitempty"` // Top-level link to further documentation
+
 	// Schema directory configuration
 	SchemaDir   string  `json:"schema_dir,omitempty"`         // Path to generated schema files
+
+	// Endpoint paths
+	DocsPath string `json:"docs_path,omitempty"` // Swagger UI path, defaults to /docs
+	SpecPath string `json:"spec_path,omitempty"` // OpenAPI JSON path, defaults to /openapi.json
+
+	// GenericRequestBodies documents a generic JSON body on POST, PUT and PATCH routes whose
+	// request schema is unknown. By default such routes are documented without a body.
+	GenericRequestBodies bool `json:"generic_request_bodies,omitempty"`
+
+	// ExcludeInternal leaves operations marked internal out of the spec instead of marking
+	// them x-internal, e.g. when generating the public artifact.
+	ExcludeInternal bool `json:"exclude_internal,omitempty"`
+
+	// GraphQL endpoints, detected from their handler or a path ending in /graphql, are
+	// documented with the GraphQL-over-HTTP request and response envelopes. ExcludeGraphQL
+	// leaves them out of the spec instead.
+	ExcludeGraphQL bool `json:"exclude_graphql,omitempty"`
+
+	// Paths are documented in OpenAPI syntax ("/users/{id}") with trailing slashes removed.
+	// RawPaths keeps them exactly as registered, KeepTrailingSlashes only keeps the slashes.
+	RawPaths            bool `json:"raw_paths,omitempty"`
+	KeepTrailingSlashes bool `json:"keep_trailing_slashes,omitempty"`
+
+	// ExcludeWildcardRoutes leaves catch-all routes such as /static/*filepath out of the spec.
+	// By default they are documented with an x-wildcard path parameter.
+	ExcludeWildcardRoutes bool `json:"exclude_wildcard_routes,omitempty"`
+
+	// HEAD routes next to a GET route and OPTIONS routes next to other methods are usually
+	// registered by the framework or for CORS preflight, they are left out of the spec.
+	// KeepAutoMethods documents them like any other route.
+	KeepAutoMethods bool `json:"keep_auto_methods,omitempty"`
+
+	// DocumentCORS adds one OPTIONS preflight operation to every path served behind a CORS middleware
+	DocumentCORS bool `json:"document_cors,omitempty"`
+
+	// TimeFormat is how time.Time values are serialized: RFC 3339 strings by default, "unix",
+	// "unixmilli", "unixmicro" or "unixnano" for epoch integers, or a Go time layout such as
+	// "2006-01-02". A time_format tag sets it for one field, e.g. `time_format:"unixmilli"`.
+	TimeFormat string `json:"time_format,omitempty"`
+
+	// RequiredTags are the tags whose "required" rule makes a field a required property,
+	// validate and binding by default; omitempty has no say. PointersOptional keeps pointer
+	// fields optional whatever their rules. `openapi:"required"` and `openapi:"optional"`
+	// settle single fields.
+	RequiredTags     []string `json:"required_tags,omitempty"`
+	PointersOptional bool     `json:"pointers_optional,omitempty"`
+
+	// ResponseEncodings are the content codings the API compresses response bodies with,
+	// e.g. gzip or br, and RequestEncodings the ones it accepts request bodies in. They are
+	// documented as Content-Encoding headers and an x-compression operation extension.
+	ResponseEncodings []string `json:"response_encodings,omitempty"`
+	RequestEncodings  []string `json:"request_encodings,omitempty"`
+
+	// DisableASTAnalysis stops the handler analyzers from reading handler source, handlers
+	// are then documented from registered schemas and overrides only. Source is never read
+	// in production.
+	DisableASTAnalysis bool `json:"disable_ast_analysis,omitempty"`
 }
 
 
@@ -26,11 +111,216 @@ type Contact struct {
 	URL   string `json:"url,omitempty"`
 }
 
+// License represents the license the API is offered under
+type License struct {
+	Name string `json:"name,omitempty"` // e.g. "Apache 2.0", required when URL is set
+	URL  string `json:"url,omitempty"`
+}
+
+// ExternalDocs links to documentation outside the spec, e.g. a developer portal
+type ExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ConfigOption is a functional option for building a Config
+type ConfigOption func(*Config)
+
+// WithTitle sets the API title
+func WithTitle(title string) ConfigOption {
+	return func(c *Config) {
+		c.Title = title
+	}
+}
+
+// WithVersion sets the API version
+func WithVersion(version string) ConfigOption {
+	return func(c *Config) {
+		c.Version = version
+	}
+}
+
+// WithDescription sets the API description
+func WithDescription(description string) ConfigOption {
+	return func(c *Config) {
+		c.Description = description
+	}
+}
+
+// WithEnvironment sets the environment ("development" or "production")
+func WithEnvironment(environment string) ConfigOption {
+	return func(c *Config) {
+		c.Environment = environment
+	}
+}
+
+// WithServerPort sets the port used to build the default server URL
+func WithServerPort(port int) ConfigOption {
+	return func(c *Config) {
+		c.ServerPort = port
+	}
+}
+
+// WithServerURL overrides the server URL advertised in the spec
+func WithServerURL(serverURL string) ConfigOption {
+	return func(c *Config) {
+		c.ServerURL = serverURL
+	}
+}
+
+// WithDocsPath sets the path Swagger UI is served on
+func WithDocsPath(docsPath string) ConfigOption {
+	return func(c *Config) {
+		c.DocsPath = docsPath
+	}
+}
+
+// WithSpecPath sets the path the OpenAPI JSON document is served on
+func WithSpecPath(specPath string) ConfigOption {
+	return func(c *Config) {
+		c.SpecPath = specPath
+	}
+}
+
+// WithGenericRequestBodies documents a generic body on POST, PUT and PATCH routes without a known request schema
+func WithGenericRequestBodies(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.GenericRequestBodies = enabled
+	}
+}
+
+// WithExcludeInternal leaves internal operations out of the spec instead of marking them x-internal
+func WithExcludeInternal(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.ExcludeInternal = enabled
+	}
+}
+
+// WithASTAnalysis enables or disables reading handler source to find request and response types
+func WithASTAnalysis(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.DisableASTAnalysis = !enabled
+	}
+}
+
+// WithExcludeGraphQL leaves GraphQL endpoints out of the spec instead of documenting their envelopes
+func WithExcludeGraphQL(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.ExcludeGraphQL = enabled
+	}
+}
+
+// WithRawPaths documents paths exactly as registered with the framework, e.g. "/users/:id"
+func WithRawPaths(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.RawPaths = enabled
+	}
+}
+
+// WithKeepTrailingSlashes documents /users and /users/ as separate paths
+func WithKeepTrailingSlashes(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.KeepTrailingSlashes = enabled
+	}
+}
+
+// WithExcludeWildcardRoutes leaves catch-all routes such as /static/*filepath out of the spec
+func WithExcludeWildcardRoutes(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.ExcludeWildcardRoutes = enabled
+	}
+}
+
+// WithKeepAutoMethods documents HEAD and OPTIONS variants of routes instead of leaving them out
+func WithKeepAutoMethods(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.KeepAutoMethods = enabled
+	}
+}
+
+// WithDocumentCORS documents the CORS preflight of paths served behind a CORS middleware
+func WithDocumentCORS(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.DocumentCORS = enabled
+	}
+}
+
+// WithTimeFormat sets how time.Time values are documented, e.g. "unixmilli" for epoch milliseconds
+func WithTimeFormat(format string) ConfigOption {
+	return func(c *Config) {
+		c.TimeFormat = format
+	}
+}
+
+// WithRequiredTags sets the tags whose "required" rule makes a field required, e.g. "validate" to ignore binding tags
+func WithRequiredTags(tags ...string) ConfigOption {
+	return func(c *Config) {
+		c.RequiredTags = tags
+	}
+}
+
+// WithPointersOptional keeps pointer fields optional even when validated as required
+func WithPointersOptional(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.PointersOptional = enabled
+	}
+}
+
+// WithResponseCompression documents the content codings response bodies may be compressed with, e.g. "gzip", "br"
+func WithResponseCompression(encodings ...string) ConfigOption {
+	return func(c *Config) {
+		c.ResponseEncodings = encodings
+	}
+}
+
+// WithRequestCompression documents the content codings request bodies may be sent in
+func WithRequestCompression(encodings ...string) ConfigOption {
+	return func(c *Config) {
+		c.RequestEncodings = encodings
+	}
+}
+
+// WithContact sets the API contact information
+func WithContact(contact Contact) ConfigOption {
+	return func(c *Config) {
+		c.Contact = contact
+	}
+}
+
+// WithTermsOfService sets the URL of the API's terms of service
+func WithTermsOfService(termsURL string) ConfigOption {
+	return func(c *Config) {
+		c.TermsOfService = termsURL
+	}
+}
+
+// WithLicense sets the license the API is offered under
+func WithLicense(license License) ConfigOption {
+	return func(c *Config) {
+		c.License = license
+	}
+}
+
+// WithExternalDocs links the spec to documentation outside it
+func WithExternalDocs(docs ExternalDocs) ConfigOption {
+	return func(c *Config) {
+		c.ExternalDocs = docs
+	}
+}
+
 // NewConfig creates a new OpenAPI configuration with defaults
-func NewConfig() *Config {
-	return &Config{
+//
+// Options are applied on top of the defaults:
+//
+//	cfg := openapi.NewConfig(
+//		openapi.WithTitle("Orders API"),
+//		openapi.WithVersion("2.1.0"),
+//		openapi.WithServerURL("https://api.example.com"),
+//	)
+func NewConfig(opts ...ConfigOption) *Config {
+	config := &Config{
 		Environment: "development",
-		ServerPort:  8080,
+		ServerPort:  DefaultServerPort,
 		Title:       "API Documentation",
 		Description: "Automatically generated API documentation",
 		Version:     "1.0.0",
@@ -38,8 +328,52 @@ func NewConfig() *Config {
 			Name: "API Team",
 		},
 		// Default schema directory
-		SchemaDir: "./schemas",
+		SchemaDir: DefaultSchemaDir,
+		DocsPath:  DefaultDocsPath,
+		SpecPath:  DefaultSpecPath,
 	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// ApplyDefaults fills zero-valued fields with sensible defaults
+//
+// The title falls back to the main module name (e.g. "orders-service" for
+// github.com/acme/orders-service), the version to DefaultVersion, the
+// environment to "development" and the endpoint paths to /docs and
+// /openapi.json. Fields that are already set are left untouched.
+func (c *Config) ApplyDefaults() *Config {
+	if c.Environment == "" {
+		c.Environment = "development"
+	}
+	if c.ServerPort == 0 {
+		c.ServerPort = DefaultServerPort
+	}
+	if c.Title == "" {
+		c.Title = defaultTitle()
+	}
+	if c.Version == "" {
+		c.Version = DefaultVersion
+	}
+	if c.DocsPath == "" {
+		c.DocsPath = DefaultDocsPath
+	}
+	if c.SpecPath == "" {
+		c.SpecPath = DefaultSpecPath
+	}
+	return c
+}
+
+// defaultTitle derives a title from the main module path
+func defaultTitle() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" {
+		return path.Base(info.Main.Path)
+	}
+	return "API Documentation"
 }
 
 // NewProductionConfig creates a configuration suitable for Docker/production environments
@@ -70,19 +404,141 @@ func (c *Config) GetServerDescription() string {
 }
 
 // Validate validates the configuration
+//
+// All problems are reported at once so a misconfigured service can be fixed in one pass.
 func (c *Config) Validate() error {
-	if c.ServerPort <= 0 {
-		return fmt.Errorf("server port must be positive, got %d", c.ServerPort)
+	var errs []error
+
+	if c.ServerPort <= 0 || c.ServerPort > 65535 {
+		errs = append(errs, fmt.Errorf("server port must be between 1 and 65535, got %d", c.ServerPort))
 	}
-	if c.Title == "" {
-		return fmt.Errorf("title cannot be empty")
+	if strings.TrimSpace(c.Title) == "" {
+		errs = append(errs, fmt.Errorf("title cannot be empty (set Config.Title or use WithTitle)"))
 	}
-	if c.Version == "" {
-		return fmt.Errorf("version cannot be empty")
+	if strings.TrimSpace(c.Version) == "" {
+		errs = append(errs, fmt.Errorf("version cannot be empty (set Config.Version or use WithVersion)"))
+	}
+	if c.ServerURL != "" {
+		if err := validateServerURL(c.ServerURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.License.URL != "" && strings.TrimSpace(c.License.Name) == "" {
+		errs = append(errs, fmt.Errorf("license name cannot be empty when a license URL is set"))
+	}
+	if c.ExternalDocs.Description != "" && c.ExternalDocs.URL == "" {
+		errs = append(errs, fmt.Errorf("external docs need a URL"))
+	}
+	for _, link := range []struct{ field, value string }{
+		{"terms of service", c.TermsOfService},
+		{"license", c.License.URL},
+		{"external docs", c.ExternalDocs.URL},
+	} {
+		if err := validateDocumentURL(link.field, link.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.DocsPath != "" && !strings.HasPrefix(c.DocsPath, "/") {
+		errs = append(errs, fmt.Errorf("docs path %q must start with /", c.DocsPath))
+	}
+	if c.SpecPath != "" && !strings.HasPrefix(c.SpecPath, "/") {
+		errs = append(errs, fmt.Errorf("spec path %q must start with /", c.SpecPath))
+	}
+	if !schemagen.ValidTimeFormat(c.TimeFormat) {
+		errs = append(errs, fmt.Errorf("time format %q is neither unix, unixmilli, unixmicro, unixnano nor a Go time layout", c.TimeFormat))
+	}
+	for _, tag := range c.RequiredTags {
+		if tag == "" || strings.ContainsAny(tag, " :\"`") {
+			errs = append(errs, fmt.Errorf("required tag %q is not a struct tag key", tag))
+		}
+	}
+	for _, encoding := range slices.Concat(c.ResponseEncodings, c.RequestEncodings) {
+		if encoding == "" || strings.ContainsAny(encoding, " ,;\t\"") {
+			errs = append(errs, fmt.Errorf("content coding %q is not an HTTP token", encoding))
+		}
+	}
+	if c.DocsPath != "" && c.DocsPath == c.SpecPath {
+		errs = append(errs, fmt.Errorf("docs path and spec path must differ, both are %q", c.DocsPath))
+	}
+
+	return errors.Join(errs...)
+}
+
+// serverVariablePattern matches server variable templates such as {scheme} or {region}
+var serverVariablePattern = regexp.MustCompile(`\{[^{}]+\}`)
+
+// validateServerURL accepts absolute http(s) URLs and relative paths such as "/api"
+//
+// Server variables are filled in by clients, so "{scheme}://{region}.example.com" is
+// checked with placeholder values and any scheme.
+func validateServerURL(serverURL string) error {
+	parsed, err := url.Parse(serverVariablePattern.ReplaceAllString(serverURL, "x"))
+	if err != nil {
+		return fmt.Errorf("server URL %q is malformed: %w", serverURL, err)
+	}
+	if strings.HasPrefix(serverURL, "/") {
+		return nil
+	}
+	scheme, _, found := strings.Cut(serverURL, "://")
+	templatedScheme := found && serverVariablePattern.MatchString(scheme)
+	if !templatedScheme && parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("server URL %q must use http or https, or be a relative path starting with /", serverURL)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("server URL %q is missing a host", serverURL)
+	}
+	return nil
+}
+
+// validateDocumentURL accepts empty values and absolute http(s) URLs
+func validateDocumentURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s URL %q must be an absolute http or https URL", field, value)
 	}
 	return nil
 }
 
+// validateSchemaDir checks the schema directory configuration
+//
+// In development a missing directory is fine because handlers are analyzed from
+// source, but production relies on the generated schema files, so a missing
+// directory would silently produce generic placeholder schemas.
+func (c *Config) validateSchemaDir() error {
+	if c.SchemaDir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(c.SchemaDir)
+	switch {
+	case os.IsNotExist(err):
+		if c.IsProductionMode() {
+			return fmt.Errorf("schema directory %q does not exist, run go generate before building for production or fix WithSchemaDir", c.SchemaDir)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("schema directory %q is not accessible: %w", c.SchemaDir, err)
+	case !info.IsDir():
+		return fmt.Errorf("schema directory %q is not a directory", c.SchemaDir)
+	}
+	return nil
+}
+
+// IsProductionMode reports whether the config targets a production environment
+//
+// Other environments, e.g. "staging" or "test", are treated like development.
+func (c *Config) IsProductionMode() bool {
+	return c.Environment == "production"
+}
+
+// IsASTAnalysisEnabled reports whether handler analyzers read handler source, see DisableASTAnalysis
+func (c *Config) IsASTAnalysisEnabled() bool {
+	return !c.DisableASTAnalysis
+}
+
 // SetSchemaDir sets the schema directory path
 func (c *Config) SetSchemaDir(path string) *Config {
 	c.SchemaDir = path
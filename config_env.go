@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read by ConfigFromEnv
+const (
+	EnvTitle          = "OPENAPI_TITLE"
+	EnvDescription    = "OPENAPI_DESCRIPTION"
+	EnvVersion        = "OPENAPI_VERSION"
+	EnvEnvironment    = "OPENAPI_ENVIRONMENT"
+	EnvProductionMode = "OPENAPI_PRODUCTION_MODE"
+	EnvServerURL      = "OPENAPI_SERVER_URL"
+	EnvServerPort     = "OPENAPI_SERVER_PORT"
+	EnvSchemaDir      = "OPENAPI_SCHEMA_DIR"
+	EnvDocsPath       = "OPENAPI_DOCS_PATH"
+	EnvSpecPath       = "OPENAPI_SPEC_PATH"
+	EnvContactName    = "OPENAPI_CONTACT_NAME"
+	EnvContactEmail   = "OPENAPI_CONTACT_EMAIL"
+	EnvContactURL     = "OPENAPI_CONTACT_URL"
+	EnvTermsOfService = "OPENAPI_TERMS_OF_SERVICE"
+	EnvLicenseName    = "OPENAPI_LICENSE_NAME"
+	EnvLicenseURL     = "OPENAPI_LICENSE_URL"
+	EnvExternalDocs   = "OPENAPI_EXTERNAL_DOCS_URL"
+)
+
+// ConfigFromEnv creates a configuration from NewConfig defaults overridden by OPENAPI_* environment variables
+//
+// Unset or empty variables keep their defaults, so deployments only need to set what they change.
+// OPENAPI_PRODUCTION_MODE accepts any value understood by strconv.ParseBool and takes
+// precedence over OPENAPI_ENVIRONMENT.
+//
+// Example:
+//
+//	cfg, err := openapi.ConfigFromEnv()
+//	if err != nil {
+//		return err
+//	}
+//	err = openapi.EnableDocs(h, integration.NewHertzServerAdapter(h),
+//		openapi.WithConfig(cfg),
+//	)
+func ConfigFromEnv() (*Config, error) {
+	config := NewConfig()
+	var errs []error
+
+	stringVars := map[string]*string{
+		EnvTitle:          &config.Title,
+		EnvDescription:    &config.Description,
+		EnvVersion:        &config.Version,
+		EnvEnvironment:    &config.Environment,
+		EnvServerURL:      &config.ServerURL,
+		EnvSchemaDir:      &config.SchemaDir,
+		EnvDocsPath:       &config.DocsPath,
+		EnvSpecPath:       &config.SpecPath,
+		EnvContactName:    &config.Contact.Name,
+		EnvContactEmail:   &config.Contact.Email,
+		EnvContactURL:     &config.Contact.URL,
+		EnvTermsOfService: &config.TermsOfService,
+		EnvLicenseName:    &config.License.Name,
+		EnvLicenseURL:     &config.License.URL,
+		EnvExternalDocs:   &config.ExternalDocs.URL,
+	}
+	for name, target := range stringVars {
+		if value, ok := lookupEnv(name); ok {
+			*target = value
+		}
+	}
+
+	if value, ok := lookupEnv(EnvServerPort); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be an integer, got %q", EnvServerPort, value))
+		} else {
+			config.ServerPort = port
+		}
+	}
+
+	if value, ok := lookupEnv(EnvProductionMode); ok {
+		production, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a boolean, got %q", EnvProductionMode, value))
+		} else if production {
+			config.Environment = "production"
+		} else if config.Environment == "production" {
+			config.Environment = "development"
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// lookupEnv returns the trimmed value of a non-empty environment variable
+func lookupEnv(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
@@ -0,0 +1,61 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvTitle, "Orders API")
+	t.Setenv(EnvVersion, "2.0.0")
+	t.Setenv(EnvServerURL, "https://orders.example.com")
+	t.Setenv(EnvServerPort, "9090")
+	t.Setenv(EnvDocsPath, "/internal/docs")
+	t.Setenv(EnvSpecPath, "/internal/openapi.json")
+	t.Setenv(EnvProductionMode, "true")
+	t.Setenv(EnvContactEmail, "orders@example.com")
+	t.Setenv(EnvLicenseName, "Apache 2.0")
+
+	cfg, err := ConfigFromEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Orders API", cfg.Title)
+	assert.Equal(t, "2.0.0", cfg.Version)
+	assert.Equal(t, "https://orders.example.com", cfg.GetServerURL())
+	assert.Equal(t, 9090, cfg.ServerPort)
+	assert.Equal(t, "/internal/docs", cfg.DocsPath)
+	assert.Equal(t, "/internal/openapi.json", cfg.SpecPath)
+	assert.True(t, cfg.IsProductionMode())
+	assert.Equal(t, "orders@example.com", cfg.Contact.Email)
+	assert.Equal(t, "Apache 2.0", cfg.License.Name)
+
+	// Unset variables keep the defaults
+	assert.Equal(t, DefaultSchemaDir, cfg.SchemaDir)
+	assert.Equal(t, "API Team", cfg.Contact.Name)
+}
+
+func TestConfigFromEnvInvalidValues(t *testing.T) {
+	t.Setenv(EnvServerPort, "eighty")
+	t.Setenv(EnvProductionMode, "maybe")
+
+	_, err := ConfigFromEnv()
+	assert.ErrorContains(t, err, EnvServerPort)
+	assert.ErrorContains(t, err, EnvProductionMode)
+}
+
+func TestServeSwaggerUICustomPaths(t *testing.T) {
+	cfg := NewConfig(WithDocsPath("/internal/docs"), WithSpecPath("/internal/openapi.json"))
+	cfg.SchemaDir = ""
+	generator := newTestGenerator(t, nil, WithConfig(cfg))
+
+	server := newRecordingServer()
+	assert.NoError(t, generator.ServeSwaggerUI(server))
+
+	assert.Contains(t, server.handlers, "/internal/docs")
+	assert.Contains(t, server.handlers, "/internal/openapi.json")
+	assert.NotContains(t, server.handlers, "/docs")
+
+	recorder := server.serve("/internal/docs", nil)
+	assert.Contains(t, recorder.Body.String(), "url: '/internal/openapi.json'")
+}
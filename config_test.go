@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/integration"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestNewConfigOptions(t *testing.T) {
+	cfg := NewConfig(
+		WithTitle("Orders API"),
+		WithVersion("2.1.0"),
+		WithServerURL("https://api.example.com"),
+		WithContact(Contact{Name: "Orders Team"}),
+		WithGenericRequestBodies(true),
+	)
+
+	assert.Equal(t, "Orders API", cfg.Title)
+	assert.Equal(t, "2.1.0", cfg.Version)
+	assert.Equal(t, "https://api.example.com", cfg.GetServerURL())
+	assert.Equal(t, "Orders Team", cfg.Contact.Name)
+	assert.True(t, cfg.GenericRequestBodies)
+	assert.Equal(t, DefaultSchemaDir, cfg.SchemaDir)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestConfigApplyDefaults(t *testing.T) {
+	cfg := (&Config{}).ApplyDefaults()
+
+	assert.Equal(t, DefaultVersion, cfg.Version)
+	assert.Equal(t, DefaultServerPort, cfg.ServerPort)
+	assert.Equal(t, "development", cfg.Environment)
+	assert.NotEmpty(t, cfg.Title)
+
+	// Explicit values are kept
+	cfg = (&Config{Title: "Mine", Version: "3.0.0"}).ApplyDefaults()
+	assert.Equal(t, "Mine", cfg.Title)
+	assert.Equal(t, "3.0.0", cfg.Version)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "relative server URL", modify: func(c *Config) { c.ServerURL = "/api" }},
+		{name: "templated server URL", modify: func(c *Config) { c.ServerURL = "{scheme}://{region}.api.example.com" }},
+		{name: "other environment", modify: func(c *Config) { c.Environment = "staging" }},
+		{name: "empty title", modify: func(c *Config) { c.Title = " " }, wantErr: "title cannot be empty"},
+		{name: "empty version", modify: func(c *Config) { c.Version = "" }, wantErr: "version cannot be empty"},
+		{name: "port out of range", modify: func(c *Config) { c.ServerPort = 70000 }, wantErr: "server port"},
+		{name: "server URL without scheme", modify: func(c *Config) { c.ServerURL = "api.example.com" }, wantErr: "must use http or https"},
+		{name: "server URL without host", modify: func(c *Config) { c.ServerURL = "https://" }, wantErr: "missing a host"},
+		{name: "catalog metadata", modify: func(c *Config) {
+			c.TermsOfService = "https://example.com/terms"
+			c.License = License{Name: "Apache 2.0", URL: "https://www.apache.org/licenses/LICENSE-2.0"}
+			c.ExternalDocs = ExternalDocs{Description: "Developer portal", URL: "https://developers.example.com"}
+		}},
+		{name: "license URL without name", modify: func(c *Config) { c.License.URL = "https://opensource.org/license/mit" }, wantErr: "license name"},
+		{name: "relative terms of service", modify: func(c *Config) { c.TermsOfService = "/terms" }, wantErr: "terms of service URL"},
+		{name: "epoch time format", modify: func(c *Config) { c.TimeFormat = "unixmilli" }},
+		{name: "time layout", modify: func(c *Config) { c.TimeFormat = "2006-01-02 15:04" }},
+		{name: "unknown time format", modify: func(c *Config) { c.TimeFormat = "epoch" }, wantErr: `time format "epoch"`},
+		{name: "required tags", modify: func(c *Config) { c.RequiredTags = []string{"validate", "binding"} }},
+		{name: "invalid required tag", modify: func(c *Config) { c.RequiredTags = []string{"validate:required"} }, wantErr: `required tag "validate:required"`},
+		{name: "compression", modify: func(c *Config) { c.ResponseEncodings = []string{"gzip", "br"} }},
+		{name: "invalid content coding", modify: func(c *Config) { c.RequestEncodings = []string{"gzip, br"} }, wantErr: `content coding "gzip, br"`},
+		{name: "external docs without URL", modify: func(c *Config) { c.ExternalDocs.Description = "Portal" }, wantErr: "external docs need a URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SchemaDir = ""
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaDirValidation(t *testing.T) {
+	schemaDir := t.TempDir()
+	schemaFile := filepath.Join(schemaDir, "schema.json")
+	assert.NoError(t, os.WriteFile(schemaFile, []byte("{}"), 0o644))
+	missing := filepath.Join(schemaDir, "nope")
+
+	production := func(dir string) *Config {
+		cfg := NewProductionConfig()
+		cfg.SchemaDir = dir
+		return cfg
+	}
+
+	assert.NoError(t, processOptions(WithConfig(production(schemaDir))).validate())
+	assert.NoError(t, processOptions(WithSchemaDir(missing)).validate(), "development tolerates a missing directory")
+	assert.ErrorContains(t, processOptions(WithConfig(production(missing))).validate(), "does not exist")
+	assert.ErrorContains(t, processOptions(WithConfig(production(schemaFile))).validate(), "not a directory")
+
+	// Embedded schemas replace the directory
+	embedded := fstest.MapFS{}
+	assert.NoError(t, processOptions(WithConfig(production(missing)), WithSchemaFS(embedded, ".")).validate())
+	assert.NoError(t, processOptions(WithConfig(production(missing)), WithSchemaBundle(embedded)).validate())
+}
+
+func TestNewGeneratorKeepsCallerConfig(t *testing.T) {
+	cfg := &Config{}
+	generator := newTestGenerator(t, nil, WithConfig(cfg))
+
+	assert.Empty(t, cfg.Version)
+	assert.Equal(t, DefaultVersion, generator.config.Version)
+}
+
+func TestCatalogMetadataInSpec(t *testing.T) {
+	cfg := NewConfig(
+		WithTermsOfService("https://example.com/terms"),
+		WithLicense(License{Name: "MIT"}),
+		WithExternalDocs(ExternalDocs{URL: "https://developers.example.com"}),
+	)
+	cfg.SchemaDir = ""
+	generator := newTestGenerator(t, nil, WithConfig(cfg))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/terms", openAPISpec.Info.TermsOfService)
+	assert.Equal(t, &spec.License{Name: "MIT"}, openAPISpec.Info.License)
+	assert.Equal(t, &spec.ExternalDocs{URL: "https://developers.example.com"}, openAPISpec.ExternalDocs)
+
+	// Unset metadata stays out of the document
+	bare, err := newTestGenerator(t, nil).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Nil(t, bare.Info.License)
+	assert.Nil(t, bare.ExternalDocs)
+}
+
+func TestNewGeneratorPassesConfigToAnalyzer(t *testing.T) {
+	cfg := NewConfig(WithASTAnalysis(false))
+	assert.False(t, cfg.IsASTAnalysisEnabled())
+
+	generator := newTestGenerator(t, nil, WithConfig(cfg))
+	handlerAnalyzer, ok := generator.handlerAnalyzer.(*integration.HertzHandlerAnalyzer)
+	assert.True(t, ok)
+	assert.False(t, handlerAnalyzer.Config().IsASTAnalysisEnabled())
+	assert.Same(t, generator.logger, handlerAnalyzer.Config().Logger())
+}
+
+func TestNewGeneratorRejectsInvalidConfig(t *testing.T) {
+	cfg := NewConfig(WithServerURL("not a url"))
+	cfg.SchemaDir = ""
+
+	_, err := NewGenerator(nil, nil, processOptions(
+		WithConfig(cfg),
+		WithRouteDiscoverer(&staticDiscoverer{}),
+	))
+	assert.ErrorContains(t, err, "invalid OpenAPI configuration")
+}
+
+func TestConflictingOptions(t *testing.T) {
+	schemaDir := t.TempDir()
+
+	// WithSchemaDir is kept even when WithConfig comes later, the caller's config is left as given
+	given := NewConfig()
+	options := processOptions(WithSchemaDir(schemaDir), WithConfig(given))
+	assert.Equal(t, schemaDir, options.config.SchemaDir)
+	assert.Equal(t, DefaultSchemaDir, given.SchemaDir)
+	assert.NoError(t, options.validate())
+
+	cfg := NewConfig()
+	cfg.SchemaDir = t.TempDir()
+	options = processOptions(WithConfig(cfg), WithSchemaDir(schemaDir))
+	assert.ErrorContains(t, options.validate(), "conflicts with Config.SchemaDir")
+
+	options = processOptions(WithConfig(NewConfig()), WithConfig(NewConfig()))
+	assert.ErrorContains(t, options.validate(), "WithConfig was given more than once")
+}
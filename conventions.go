@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// Convention is an organization-wide standard documented on every operation it applies to,
+// such as pagination parameters on list endpoints or rate limit headers on every response
+//
+// Parameters are added to the operations that do not document one with the same name and
+// location, ResponseHeaders to every response that does not document one with the same
+// name, and Responses to the operations that do not document the status code.
+type Convention struct {
+	AppliesTo       RouteFilter              // Routes the convention applies to, nil matches every route
+	Parameters      []spec.Parameter         // e.g. page and per_page query parameters
+	ResponseHeaders map[string]spec.Header   // e.g. X-Request-Id on every response
+	Responses       map[string]spec.Response // e.g. a 429 Too Many Requests response, by status code
+}
+
+// RegisterConvention documents a convention on the operations of the routes it applies to
+//
+// Conventions are applied after the operation has been generated and overridden, so
+// what a route documents itself wins.
+//
+// Example:
+//
+//	g.RegisterConvention(openapi.PagePagination("page", "per_page"))
+//	g.RegisterConvention(openapi.Convention{
+//		ResponseHeaders: map[string]spec.Header{"X-Request-Id": {Schema: spec.Schema{Type: "string"}}},
+//	})
+func (g *Generator) RegisterConvention(convention Convention) {
+	g.conventions = append(g.conventions, convention)
+}
+
+// WithConventions registers conventions declared once for the whole API, see RegisterConvention
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithConventions(
+//			openapi.PagePagination("page", "per_page"),
+//			openapi.RateLimitHeaders(),
+//		),
+//	)
+func WithConventions(conventions ...Convention) Option {
+	return WithCustomizer(func(g *Generator) error {
+		for _, convention := range conventions {
+			g.RegisterConvention(convention)
+		}
+		return nil
+	})
+}
+
+// ListRoutes matches the GET routes of collections, whose path does not end in a parameter,
+// e.g. /users but not /users/:id
+func ListRoutes() RouteFilter {
+	return func(route spec.RouteInfo) bool {
+		if !strings.EqualFold(route.Method, http.MethodGet) {
+			return false
+		}
+		path := strings.TrimSuffix(route.Path, "/")
+		last := path[strings.LastIndex(path, "/")+1:]
+		return last != "" && !strings.HasPrefix(last, ":") && !strings.HasPrefix(last, "*") && !strings.HasPrefix(last, "{")
+	}
+}
+
+// PagePagination is the convention of list endpoints taking a 1-based page number and a page size
+func PagePagination(pageParam, sizeParam string) Convention {
+	first := 1.0
+	return Convention{
+		AppliesTo: ListRoutes(),
+		Parameters: []spec.Parameter{
+			{Name: pageParam, In: "query", Description: "Page number, starting at 1", Schema: spec.Schema{Type: "integer", Minimum: &first, Default: 1}},
+			{Name: sizeParam, In: "query", Description: "Number of items per page", Schema: spec.Schema{Type: "integer", Minimum: &first}},
+		},
+	}
+}
+
+// RateLimitHeaders is the convention of every response carrying the X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset headers, and of a 429 response with Retry-After
+func RateLimitHeaders() Convention {
+	integer := spec.Schema{Type: "integer"}
+	return Convention{
+		ResponseHeaders: map[string]spec.Header{
+			"X-RateLimit-Limit":     {Description: "Requests allowed in the current window", Schema: integer},
+			"X-RateLimit-Remaining": {Description: "Requests left in the current window", Schema: integer},
+			"X-RateLimit-Reset":     {Description: "Seconds until the current window resets", Schema: integer},
+		},
+		Responses: map[string]spec.Response{
+			"429": {
+				Description: "Too Many Requests",
+				Headers: map[string]spec.Header{
+					"Retry-After": {Description: "Seconds to wait before retrying", Schema: integer},
+				},
+			},
+		},
+	}
+}
+
+// applyConventions documents the registered conventions on an operation
+func (g *Generator) applyConventions(route spec.RouteInfo, operation *spec.Operation) {
+	for _, convention := range g.conventions {
+		if convention.AppliesTo != nil && !convention.AppliesTo(route) {
+			continue
+		}
+		for _, parameter := range convention.Parameters {
+			if !hasParameter(operation.Parameters, parameter.Name, parameter.In) {
+				operation.Parameters = append(operation.Parameters, parameter)
+			}
+		}
+		for code, response := range convention.Responses {
+			if _, exists := operation.Responses[code]; !exists {
+				// Copy the headers, the response of the convention is shared by every operation
+				response.Headers = maps.Clone(response.Headers)
+				operation.Responses[code] = response
+			}
+		}
+		for name, header := range convention.ResponseHeaders {
+			addResponseHeader(operation, name, header)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterConvention(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/api/users", HandlerName: "ListUsers"},
+		{Method: "GET", Path: "/api/users/:id", HandlerName: "GetUser"},
+		{Method: "POST", Path: "/api/users", HandlerName: "CreateUser"},
+	})
+	generator.RegisterConvention(PagePagination("page", "per_page"))
+	rateLimits := RateLimitHeaders()
+	generator.RegisterConvention(rateLimits)
+	generator.RegisterConvention(Convention{
+		ResponseHeaders: map[string]spec.Header{"X-Request-Id": {Schema: spec.Schema{Type: "string"}}},
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	list := openAPISpec.Paths["/api/users"].Get
+	assert.True(t, hasParameter(list.Parameters, "page", "query"))
+	assert.True(t, hasParameter(list.Parameters, "per_page", "query"))
+	assert.False(t, hasParameter(openAPISpec.Paths["/api/users/{id}"].Get.Parameters, "page", "query"))
+	assert.False(t, hasParameter(openAPISpec.Paths["/api/users"].Post.Parameters, "page", "query"))
+
+	create := openAPISpec.Paths["/api/users"].Post
+	if assert.Contains(t, create.Responses, "429") {
+		assert.Contains(t, create.Responses["429"].Headers, "Retry-After")
+		assert.Contains(t, create.Responses["429"].Headers, "X-RateLimit-Remaining")
+	}
+	for code, response := range create.Responses {
+		assert.Contains(t, response.Headers, "X-Request-Id", code)
+		assert.Contains(t, response.Headers, "X-RateLimit-Limit", code)
+	}
+
+	// Adding headers to one operation's 429 must not leak into the convention's response
+	assert.Len(t, rateLimits.Responses["429"].Headers, 1)
+}
+
+func TestListRoutes(t *testing.T) {
+	list := ListRoutes()
+	assert.True(t, list(spec.RouteInfo{Method: "GET", Path: "/users"}))
+	assert.True(t, list(spec.RouteInfo{Method: "GET", Path: "/users/:id/orders/"}))
+	assert.False(t, list(spec.RouteInfo{Method: "GET", Path: "/users/:id"}))
+	assert.False(t, list(spec.RouteInfo{Method: "GET", Path: "/files/*filepath"}))
+	assert.False(t, list(spec.RouteInfo{Method: "POST", Path: "/users"}))
+	assert.False(t, list(spec.RouteInfo{Method: "GET", Path: "/"}))
+}
@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// WithDefaultResponses replaces the error responses documented on every operation, 400, 401
+// and 500 by default
+//
+// Keys are status codes, ranges such as 4XX, or default. A response without a description
+// is described by the text of its status, one without content has no body, and an empty
+// map documents no error responses. Responses a route documents itself, e.g. with
+// OverrideResponse, take precedence.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithDefaultResponses(map[string]spec.Response{
+//			"401": openapi.ErrorResponse(http.StatusUnauthorized),
+//			"403": openapi.ErrorResponse(http.StatusForbidden),
+//			"404": openapi.ErrorResponse(http.StatusNotFound),
+//			"422": openapi.ErrorResponse(http.StatusUnprocessableEntity),
+//			"500": openapi.ErrorResponse(http.StatusInternalServerError),
+//		}),
+//	)
+func WithDefaultResponses(responses map[string]spec.Response) Option {
+	return func(opts *Options) {
+		opts.defaultResponses = validDefaultResponses(opts, "WithDefaultResponses", responses)
+	}
+}
+
+// WithTagDefaultResponses replaces the default error responses of the operations of a tag,
+// see WithDefaultResponses
+//
+// The tag is the one the operation is documented under, after overrides.
+//
+// Example:
+//
+//	openapi.WithTagDefaultResponses("auth", map[string]spec.Response{
+//		"400": openapi.ErrorResponse(http.StatusBadRequest),
+//		"429": openapi.ErrorResponse(http.StatusTooManyRequests),
+//	})
+func WithTagDefaultResponses(tag string, responses map[string]spec.Response) Option {
+	return func(opts *Options) {
+		if opts.tagDefaultResponses == nil {
+			opts.tagDefaultResponses = make(map[string]map[string]spec.Response)
+		}
+		opts.tagDefaultResponses[tag] = validDefaultResponses(opts, fmt.Sprintf("WithTagDefaultResponses(%q)", tag), responses)
+	}
+}
+
+// ErrorResponse is a response of a status code with the standard error schema, the one of
+// the built-in default responses
+func ErrorResponse(status int) spec.Response {
+	return spec.Response{
+		Description: http.StatusText(status),
+		Content: map[string]spec.MediaType{
+			"application/json": {Schema: errorSchema()},
+		},
+	}
+}
+
+// validDefaultResponses copies default responses, describing those without a description,
+// an invalid status code is a conflict of the options
+func validDefaultResponses(opts *Options, option string, responses map[string]spec.Response) map[string]spec.Response {
+	valid := make(map[string]spec.Response, len(responses))
+	for code, response := range responses {
+		if !validResponseCode(code) {
+			opts.conflicts = append(opts.conflicts, fmt.Errorf("%s: invalid response status %q, use a status code, a range such as 4XX or default", option, code))
+			continue
+		}
+		if response.Description == "" {
+			response.Description = responseCodeDescription(code)
+		}
+		valid[code] = response
+	}
+	return valid
+}
+
+// validResponseCode reports whether a key of the responses of an operation is a status
+// code from 100 to 599, a range from 1XX to 5XX or default
+func validResponseCode(code string) bool {
+	if code == "default" {
+		return true
+	}
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return false
+	}
+	if code[1:] == "XX" {
+		return true
+	}
+	_, err := strconv.Atoi(code)
+	return err == nil
+}
+
+// responseCodeDescription describes a response by the text of its status
+func responseCodeDescription(code string) string {
+	switch {
+	case code == "default":
+		return "Unexpected error"
+	case strings.HasSuffix(code, "XX"):
+		return code[:1] + "xx response"
+	}
+	status, _ := strconv.Atoi(code)
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	return "Response " + code
+}
+
+// defaultErrorResponses returns the error responses of an operation of a tag
+//
+// Every operation gets its own copy, later steps modify the content and headers of its responses.
+func (g *Generator) defaultErrorResponses(tag string) map[string]spec.Response {
+	responses, configured := g.tagDefaultResponses[tag]
+	if !configured {
+		responses = g.defaultResponses
+	}
+	if responses == nil {
+		return g.generateDefaultResponses()
+	}
+
+	copied := make(map[string]spec.Response, len(responses))
+	for code, response := range responses {
+		response.Content = maps.Clone(response.Content)
+		response.Headers = maps.Clone(response.Headers)
+		copied[code] = response
+	}
+	return copied
+}
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaultResponses(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "POST", Path: "/login"},
+		{Method: "DELETE", Path: "/users/:id"},
+	},
+		WithDefaultResponses(map[string]spec.Response{
+			"403": ErrorResponse(http.StatusForbidden),
+			"404": ErrorResponse(http.StatusNotFound),
+			"5XX": {Content: map[string]spec.MediaType{"application/json": {Schema: spec.Schema{Type: "object"}}}},
+		}),
+		WithTagDefaultResponses("auth", map[string]spec.Response{
+			"429": {},
+		}),
+		WithProblemJSONErrors(),
+	)
+	generator.GetOverrideManager().Override("POST", "/login", RouteMetadata{Tags: "auth"})
+	generator.OverrideResponse("DELETE", "/users/:id", http.StatusNotFound, nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	user := openAPISpec.Paths["/users/{id}"].Get.Responses
+	assert.ElementsMatch(t, []string{"200", "403", "404", "5XX"}, slices.Collect(maps.Keys(user)))
+	assert.Equal(t, "Forbidden", user["403"].Description)
+	assert.Contains(t, user["404"].Content, ProblemJSONContentType)
+	assert.Equal(t, "5xx response", user["5XX"].Description)
+
+	// The defaults of a tag replace those of the API
+	login := openAPISpec.Paths["/login"].Post.Responses
+	assert.ElementsMatch(t, []string{"200", "429"}, slices.Collect(maps.Keys(login)))
+	assert.Equal(t, spec.Response{Description: "Too Many Requests"}, login["429"])
+
+	// A response the route documents wins
+	assert.Empty(t, openAPISpec.Paths["/users/{id}"].Delete.Responses["404"].Content)
+}
+
+func TestWithDefaultResponsesEmpty(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health"}}, WithDefaultResponses(nil))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200"}, slices.Collect(maps.Keys(openAPISpec.Paths["/health"].Get.Responses)))
+}
+
+func TestWithDefaultResponsesInvalidStatus(t *testing.T) {
+	options := processOptions(WithConfig(NewConfig()),
+		WithDefaultResponses(map[string]spec.Response{"404": {}, "4xx": {}}),
+		WithTagDefaultResponses("auth", map[string]spec.Response{"600": {}}),
+	)
+	err := options.validate()
+	assert.ErrorContains(t, err, `WithDefaultResponses: invalid response status "4xx"`)
+	assert.ErrorContains(t, err, `WithTagDefaultResponses("auth"): invalid response status "600"`)
+}
@@ -0,0 +1,328 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// SchemaDrift reports a served response that does not match its documented schema
+//
+// Drift means the analysis produced an incomplete schema or the code changed without the
+// docs, e.g. a field was added to a response struct that the schema files do not have yet.
+type SchemaDrift struct {
+	Method   string // HTTP method of the route
+	Path     string // Path documented in the spec
+	Status   int    // Status code of the response
+	Location string // JSON path of the mismatching value, e.g. "$.items[0].price"
+	Problem  string // What does not match, e.g. "undocumented field" or "expected integer, got string"
+}
+
+// WithDriftDetection checks the JSON responses a service sends against the generated spec
+//
+// The detector validates the responses seen by the GinExampleMiddleware or
+// HertzExampleMiddleware of the integration package. Missing required fields, fields the
+// schema does not document, values of another type or outside the enum, and status codes
+// without a documented response are reported once each: logged as warnings, counted in the
+// openapi.schema.drift metric and returned by SchemaDrifts.
+//
+// Example:
+//
+//	detector := openapi.NewDriftDetector()
+//	router.Use(integration.GinExampleMiddleware(detector)) // Before the routes
+//	// ... register routes ...
+//	err := openapi.EnableDocs(router, httpServer,
+//		openapi.WithDriftDetection(detector),
+//	)
+func WithDriftDetection(detector *DriftDetector) Option {
+	return func(opts *Options) {
+		opts.driftDetector = detector
+	}
+}
+
+// DriftDetector validates served responses against the spec, see WithDriftDetection
+type DriftDetector struct {
+	generator atomic.Pointer[Generator] // Spec responses are checked against, nil until attached
+
+	mu       sync.Mutex
+	reported map[SchemaDrift]bool
+	drifts   []SchemaDrift
+}
+
+// NewDriftDetector creates a drift detector, it checks responses once given to WithDriftDetection
+func NewDriftDetector() *DriftDetector {
+	return &DriftDetector{reported: make(map[SchemaDrift]bool)}
+}
+
+// Recording reports whether responses are checked
+func (d *DriftDetector) Recording() bool {
+	return d.generator.Load() != nil
+}
+
+// RecordExample checks the response of a request to a route, e.g. "GET" "/users/:id"
+//
+// Responses are checked against the spec as last generated, nothing is checked before the
+// first generation. The request body is not checked.
+func (d *DriftDetector) RecordExample(method, path string, status int, _, responseBody []byte) {
+	generator := d.generator.Load()
+	if generator == nil {
+		return
+	}
+	generator.mu.Lock()
+	openAPISpec := generator.spec
+	specPath := path
+	if openAPISpec != nil {
+		specPath = generator.specPath(path)
+	}
+	generator.mu.Unlock()
+	if openAPISpec == nil {
+		return
+	}
+
+	pathItem, exists := openAPISpec.Paths[specPath]
+	operation := operationForMethod(&pathItem, method)
+	if !exists || operation == nil {
+		return // Undocumented routes are not drift, they were hidden or ignored
+	}
+	report := func(location, problem string) {
+		d.report(generator, SchemaDrift{Method: strings.ToUpper(method), Path: specPath, Status: status, Location: location, Problem: problem})
+	}
+
+	response, documented := operation.Responses[strconv.Itoa(status)]
+	if !documented {
+		if response, documented = operation.Responses["default"]; !documented {
+			report("", "status code not documented")
+			return
+		}
+	}
+	if len(responseBody) == 0 {
+		return
+	}
+	var value any
+	if err := json.Unmarshal(responseBody, &value); err != nil {
+		return
+	}
+	for contentType, mediaType := range response.Content {
+		if contentType == "application/json" || strings.HasSuffix(contentType, "+json") {
+			validator := &driftValidator{schemas: openAPISpec.Components.Schemas, report: report}
+			validator.validate(mediaType.Schema, value, "$")
+			return
+		}
+	}
+	report("", "JSON response not documented")
+}
+
+// report records a drift, each one is logged and counted once
+func (d *DriftDetector) report(generator *Generator, drift SchemaDrift) {
+	d.mu.Lock()
+	if d.reported[drift] {
+		d.mu.Unlock()
+		return
+	}
+	d.reported[drift] = true
+	d.drifts = append(d.drifts, drift)
+	d.mu.Unlock()
+
+	generator.logger.Warn("Response does not match the OpenAPI spec",
+		"method", drift.Method, "path", drift.Path, "status", drift.Status, "location", drift.Location, "problem", drift.Problem)
+	generator.telemetry.drift.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("http.request.method", drift.Method),
+		attribute.String("http.route", drift.Path),
+		attribute.Int("http.response.status_code", drift.Status),
+	))
+}
+
+// SchemaDrifts returns the drifts found so far, in the order they were found
+func (d *DriftDetector) SchemaDrifts() []SchemaDrift {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.drifts)
+}
+
+// operationForMethod returns the operation of a path item for an HTTP method, nil when there is none
+func operationForMethod(pathItem *spec.PathItem, method string) *spec.Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return pathItem.Get
+	case "PUT":
+		return pathItem.Put
+	case "POST":
+		return pathItem.Post
+	case "DELETE":
+		return pathItem.Delete
+	case "OPTIONS":
+		return pathItem.Options
+	case "HEAD":
+		return pathItem.Head
+	case "PATCH":
+		return pathItem.Patch
+	case "TRACE":
+		return pathItem.Trace
+	}
+	return nil
+}
+
+// driftValidator checks JSON values against the schemas of a spec
+type driftValidator struct {
+	schemas map[string]spec.Schema
+	report  func(location, problem string)
+}
+
+// validate reports where value does not match schema, location is the JSON path of value
+func (v *driftValidator) validate(schema spec.Schema, value any, location string) {
+	schema, resolved := v.flatten(schema)
+	if !resolved {
+		return // Unknown references are not the response's fault
+	}
+	if value == nil {
+		if !schema.Nullable && !schema.XNullable && schema.Type != "" && schema.Type != "any" {
+			v.report(location, "unexpected null")
+		}
+		return
+	}
+
+	for _, variants := range [][]spec.Schema{schema.OneOf, schema.AnyOf} {
+		if len(variants) > 0 && !slices.ContainsFunc(variants, func(variant spec.Schema) bool { return v.matches(variant, value) }) {
+			v.report(location, "matches no documented variant")
+			return
+		}
+	}
+
+	if actual := jsonType(value); !typeMatches(schema.Type, actual) {
+		v.report(location, fmt.Sprintf("expected %s, got %s", schema.Type, actual))
+		return
+	}
+	if len(schema.Enum) > 0 {
+		if text, isString := value.(string); isString && !slices.Contains(schema.Enum, text) {
+			v.report(location, fmt.Sprintf("value %q not in enum", text))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, exists := value[name]; !exists {
+				v.report(location+"."+name, "missing required field")
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			if property, exists := schema.Properties[name]; exists {
+				v.validate(property, value[name], location+"."+name)
+			} else if schema.AdditionalProperties != nil {
+				v.validate(*schema.AdditionalProperties, value[name], location+"."+name)
+			} else if closedObject(schema) {
+				v.report(location+"."+name, "undocumented field")
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range value {
+				v.validate(*schema.Items, item, location+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	}
+}
+
+// matches reports whether value matches schema without reporting anything
+func (v *driftValidator) matches(schema spec.Schema, value any) bool {
+	matched := true
+	(&driftValidator{schemas: v.schemas, report: func(string, string) { matched = false }}).validate(schema, value, "")
+	return matched
+}
+
+// flatten resolves a schema and merges its allOf members into it, false when a reference points to no component
+//
+// The members of an allOf share one object, so a field is only undocumented when no member has it.
+func (v *driftValidator) flatten(schema spec.Schema) (spec.Schema, bool) {
+	schema, resolved := v.resolve(schema)
+	if !resolved || len(schema.AllOf) == 0 {
+		return schema, resolved
+	}
+	members := schema.AllOf
+	schema.AllOf = nil
+	properties := maps.Clone(schema.Properties)
+	if properties == nil {
+		properties = make(map[string]spec.Schema)
+	}
+	required := slices.Clone(schema.Required)
+	for _, member := range members {
+		member, resolved := v.flatten(member)
+		if !resolved {
+			return schema, false
+		}
+		maps.Copy(properties, member.Properties)
+		required = append(required, member.Required...)
+		if schema.Type == "" {
+			schema.Type = member.Type
+		}
+		if member.AdditionalPropertiesAllowed != nil && *member.AdditionalPropertiesAllowed {
+			schema.AdditionalPropertiesAllowed = member.AdditionalPropertiesAllowed
+		}
+	}
+	schema.Properties, schema.Required = properties, required
+	return schema, true
+}
+
+// closedObject reports whether an object schema documents all its fields: it lists
+// properties and does not allow others
+func closedObject(schema spec.Schema) bool {
+	if schema.AdditionalPropertiesAllowed != nil {
+		return !*schema.AdditionalPropertiesAllowed
+	}
+	return len(schema.Properties) > 0
+}
+
+// resolve follows a component reference, false when it points to no component
+func (v *driftValidator) resolve(schema spec.Schema) (spec.Schema, bool) {
+	for seen := 0; schema.Ref != "" && seen < 16; seen++ {
+		component, exists := v.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !exists {
+			return schema, false
+		}
+		component.Nullable = component.Nullable || schema.Nullable
+		schema = component
+	}
+	return schema, schema.Ref == ""
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "null"
+}
+
+// typeMatches reports whether a value of the actual JSON type fits the documented type
+func typeMatches(documented, actual string) bool {
+	switch documented {
+	case "", "any":
+		return true
+	case "number":
+		return actual == "number" || actual == "integer"
+	}
+	return documented == actual
+}
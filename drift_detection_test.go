@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+type driftAddress struct {
+	City string `json:"city"`
+}
+
+type driftUser struct {
+	ID      int           `json:"id" validate:"required"`
+	Name    string        `json:"name"`
+	Role    string        `json:"role"`
+	Address *driftAddress `json:"address"`
+	Tags    []string      `json:"tags"`
+}
+
+func TestWithDriftDetection(t *testing.T) {
+	detector := NewDriftDetector()
+	assert.False(t, detector.Recording(), "Nothing to check against before the detector is attached")
+
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/users/:id"}}, WithDriftDetection(detector))
+	generator.OverrideResponse("GET", "/users/:id", http.StatusOK, driftUser{})
+	require.True(t, detector.Recording())
+
+	// Checked once the spec exists
+	detector.RecordExample("GET", "/users/:id", http.StatusOK, nil, []byte(`{"nickname":"x"}`))
+	assert.Empty(t, detector.SchemaDrifts())
+
+	_, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	detector.RecordExample("GET", "/users/:id", http.StatusOK, nil, []byte(`{"id":7,"name":"Ada","address":{"city":"London"},"tags":["a"]}`))
+	assert.Empty(t, detector.SchemaDrifts(), "A matching response is no drift")
+
+	body := []byte(`{"name":5,"nickname":"ada","address":{"city":"London","zip":"N1"},"tags":["a",1]}`)
+	detector.RecordExample("GET", "/users/:id", http.StatusOK, nil, body)
+	detector.RecordExample("GET", "/users/:id", http.StatusOK, nil, body)
+	detector.RecordExample("GET", "/users/:id", http.StatusTeapot, nil, nil)
+	detector.RecordExample("GET", "/unknown", http.StatusOK, nil, body)
+
+	drift := func(status int, location, problem string) SchemaDrift {
+		return SchemaDrift{Method: "GET", Path: "/users/{id}", Status: status, Location: location, Problem: problem}
+	}
+	assert.Equal(t, []SchemaDrift{
+		drift(http.StatusOK, "$.id", "missing required field"),
+		drift(http.StatusOK, "$.address.zip", "undocumented field"),
+		drift(http.StatusOK, "$.name", "expected string, got integer"),
+		drift(http.StatusOK, "$.nickname", "undocumented field"),
+		drift(http.StatusOK, "$.tags[1]", "expected string, got integer"),
+		drift(http.StatusTeapot, "", "status code not documented"),
+	}, detector.SchemaDrifts(), "Each drift is reported once")
+}
+
+func TestDriftValidatorComposition(t *testing.T) {
+	schemas := map[string]spec.Schema{
+		"Base": {Type: "object", Properties: map[string]spec.Schema{"id": {Type: "integer"}}},
+		"Cat":  {Type: "object", Properties: map[string]spec.Schema{"meows": {Type: "boolean"}}},
+		"Dog":  {Type: "object", Properties: map[string]spec.Schema{"barks": {Type: "boolean"}}},
+	}
+	var problems []string
+	validator := &driftValidator{schemas: schemas, report: func(location, problem string) {
+		problems = append(problems, location+" "+problem)
+	}}
+
+	// allOf members share the object, fields of any member are documented
+	extended := spec.Schema{AllOf: []spec.Schema{
+		{Ref: "#/components/schemas/Base"},
+		{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}},
+	}}
+	validator.validate(extended, map[string]any{"id": float64(1), "name": "x"}, "$")
+	assert.Empty(t, problems)
+
+	pet := spec.Schema{OneOf: []spec.Schema{{Ref: "#/components/schemas/Cat"}, {Ref: "#/components/schemas/Dog"}}}
+	validator.validate(pet, map[string]any{"barks": true}, "$")
+	assert.Empty(t, problems)
+	validator.validate(pet, map[string]any{"quacks": true}, "$")
+	assert.Equal(t, []string{"$ matches no documented variant"}, problems)
+
+	problems = nil
+	freeForm := true
+	validator.validate(spec.Schema{Type: "object", AdditionalPropertiesAllowed: &freeForm}, map[string]any{"any": 1.5}, "$")
+	validator.validate(spec.Schema{Type: "object", Nullable: true}, nil, "$")
+	validator.validate(spec.Schema{Type: "number"}, float64(3), "$")
+	assert.Empty(t, problems)
+}
@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSchemaBundle(t *testing.T) {
+	bundle := fstest.MapFS{
+		analyzer.SchemaBundleFileName: {Data: []byte(`{"version": 1, "schemas": [
+			{"handlerName": "GetUser", "responseSchema": {"type": "object", "properties": {"id": {"type": "string"}}}}
+		]}`)},
+	}
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", HandlerName: "GetUser"},
+	}, WithSchemaBundle(bundle))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	response := openAPISpec.Paths["/users/{id}"].Get.Responses["200"]
+	ref := response.Content["application/json"].Schema.Ref
+	if assert.NotEmpty(t, ref) {
+		schema := openAPISpec.Components.Schemas[ref[len("#/components/schemas/"):]]
+		assert.Contains(t, schema.Properties, "id")
+	}
+}
+
+func TestWithSchemaBundleMissing(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SchemaDir = ""
+
+	_, err := NewGenerator(nil, nil, processOptions(
+		WithConfig(cfg),
+		WithRouteDiscoverer(&staticDiscoverer{}),
+		WithSchemaBundle(fstest.MapFS{}),
+	))
+	assert.ErrorContains(t, err, "failed to load schema bundle")
+}
+
+func TestWithSchemaFS(t *testing.T) {
+	files := fstest.MapFS{
+		"schemas/GetUser.json": {Data: []byte(`{"handlerName": "GetUser", "responseSchema": {"type": "object", "properties": {"name": {"type": "string"}}}}`)},
+	}
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", HandlerName: "GetUser"},
+	}, WithSchemaFS(files, "schemas"))
+
+	schema, exists := generator.schemaRegistry.GetHandlerSchema("GetUser")
+	if assert.True(t, exists) {
+		assert.Contains(t, schema.ResponseSchema.Properties, "name")
+	}
+
+	cfg := NewConfig()
+	cfg.SchemaDir = ""
+	_, err := NewGenerator(nil, nil, processOptions(
+		WithConfig(cfg),
+		WithRouteDiscoverer(&staticDiscoverer{}),
+		WithSchemaFS(files, "missing"),
+	))
+	assert.ErrorContains(t, err, "failed to load embedded schemas")
+}
+
+// namespacedCreate stands in for a Create handler of this package, whose import path ends in openapi-gen
+func namespacedCreate() {}
+
+func TestWithSchemaFSNamespaces(t *testing.T) {
+	files := fstest.MapFS{
+		"schemas/billing/Create.json":     {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "properties": {"amount": {"type": "integer"}}}}`)},
+		"schemas/openapi-gen/Create.json": {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "properties": {"email": {"type": "string"}}}}`)},
+	}
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "Create", Handler: namespacedCreate},
+		{Method: "POST", Path: "/invoices", HandlerName: "Create"},
+	}, WithSchemaFS(files, "schemas"))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	users := openAPISpec.Paths["/users"].Post.RequestBody.Content["application/json"].Schema
+	assert.Contains(t, resolveRef(t, openAPISpec, users).Properties, "email")
+	// Without a handler function the route gets the first file declaring the handler
+	invoices := openAPISpec.Paths["/invoices"].Post.RequestBody.Content["application/json"].Schema
+	assert.Contains(t, resolveRef(t, openAPISpec, invoices).Properties, "amount")
+}
+
+type qualifiedHandler struct{}
+
+func (qualifiedHandler) Create() {}
+
+func TestWithSchemaFSReceiverQualifiedNames(t *testing.T) {
+	files := fstest.MapFS{
+		"schemas/Create.json":                  {Data: []byte(`{"handlerName": "Create", "requestSchema": {"type": "object", "properties": {"amount": {"type": "integer"}}}}`)},
+		"schemas/qualifiedHandler.Create.json": {Data: []byte(`{"handlerName": "qualifiedHandler.Create", "requestSchema": {"type": "object", "properties": {"email": {"type": "string"}}}}`)},
+	}
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "Create", Handler: qualifiedHandler{}.Create},
+		{Method: "POST", Path: "/invoices", HandlerName: "Create", Handler: namespacedCreate},
+	}, WithSchemaFS(files, "schemas"))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	// The schema named after the receiver of the method wins over the bare method name
+	users := openAPISpec.Paths["/users"].Post.RequestBody.Content["application/json"].Schema
+	assert.Contains(t, resolveRef(t, openAPISpec, users).Properties, "email")
+	invoices := openAPISpec.Paths["/invoices"].Post.RequestBody.Content["application/json"].Schema
+	assert.Contains(t, resolveRef(t, openAPISpec, invoices).Properties, "amount")
+}
+
+func TestHandlerQualifiedName(t *testing.T) {
+	assert.Equal(t, "qualifiedHandler.Create", handlerQualifiedName(qualifiedHandler{}.Create, "Create"))
+	assert.Equal(t, "qualifiedHandler.Create", handlerQualifiedName((&qualifiedHandler{}).Create, "Create"))
+	assert.Empty(t, handlerQualifiedName(qualifiedHandler{}.Create, "Update"))
+	assert.Empty(t, handlerQualifiedName(namespacedCreate, "namespacedCreate"))
+	assert.Empty(t, handlerQualifiedName(nil, "Create"))
+}
+
+func TestWithSchemaFSResponses(t *testing.T) {
+	files := fstest.MapFS{
+		"schemas/CreateUser.json": {Data: []byte(`{"handlerName": "CreateUser", "responses": {
+			"201": {"type": "object", "properties": {"id": {"type": "string"}}},
+			"409": {"type": "object", "properties": {"conflict": {"type": "string"}}},
+			"422": {"type": "object", "properties": {"fields": {"type": "object"}}}
+		}}`)},
+	}
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+	}, WithSchemaFS(files, "schemas"))
+	// Overrides win over the responses of schema files
+	generator.OverrideResponse("POST", "/users", 422, overrideErrorResponse{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	responses := openAPISpec.Paths["/users"].Post.Responses
+	assert.Equal(t, "Created", responses["201"].Description)
+	assert.Contains(t, resolveRef(t, openAPISpec, responses["201"].Content["application/json"].Schema).Properties, "id")
+	assert.Contains(t, resolveRef(t, openAPISpec, responses["409"].Content["application/json"].Schema).Properties, "conflict")
+	assert.Contains(t, resolveRef(t, openAPISpec, responses["422"].Content["application/json"].Schema).Properties, "reason")
+	// The handler responds 201 instead of 200
+	assert.NotContains(t, responses, "200")
+}
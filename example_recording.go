@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// redactedValue replaces the values of redacted fields in recorded examples
+const redactedValue = "REDACTED"
+
+// defaultRedactedFields are redacted from every recorded example
+var defaultRedactedFields = []string{
+	"password", "secret", "token", "access_token", "refresh_token", "id_token",
+	"api_key", "apikey", "authorization", "client_secret", "credit_card", "ssn",
+}
+
+// ExampleRedactor rewrites a recorded body before it becomes an example, e.g. to mask user data
+//
+// body is the decoded JSON, the returned value is recorded. method and path identify the
+// route as registered, e.g. "GET" and "/users/:id".
+type ExampleRedactor func(method, path string, body any) any
+
+// RedactFields returns a redactor replacing the values of the named object fields with
+// "REDACTED", at any depth; names are matched case-insensitively
+func RedactFields(names ...string) ExampleRedactor {
+	redacted := make(map[string]bool, len(names))
+	for _, name := range names {
+		redacted[strings.ToLower(name)] = true
+	}
+	var redact func(value any) any
+	redact = func(value any) any {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, field := range value {
+				if redacted[strings.ToLower(key)] {
+					value[key] = redactedValue
+				} else {
+					value[key] = redact(field)
+				}
+			}
+		case []any:
+			for i, item := range value {
+				value[i] = redact(item)
+			}
+		}
+		return value
+	}
+	return func(_, _ string, body any) any {
+		return redact(body)
+	}
+}
+
+// WithExampleRecording documents routes with the JSON bodies they actually receive and send
+//
+// The recorder collects the first request body and the first response body of each status
+// code per route, through the GinExampleMiddleware or HertzExampleMiddleware of the
+// integration package. They become the examples of the matching request body and
+// responses, unless those already have one. Recording stops when the config targets
+// production, production traffic carries real user data.
+//
+// Example:
+//
+//	recorder := openapi.NewExampleRecorder(openapi.RedactFields("email", "phone"))
+//	router.Use(integration.GinExampleMiddleware(recorder)) // Before the routes
+//	// ... register routes ...
+//	err := openapi.EnableDocs(router, httpServer,
+//		openapi.WithExampleRecording(recorder),
+//	)
+func WithExampleRecording(recorder *ExampleRecorder) Option {
+	return func(opts *Options) {
+		opts.exampleRecorder = recorder
+	}
+}
+
+// ExampleRecorder collects the bodies of served requests as examples, see WithExampleRecording
+type ExampleRecorder struct {
+	enabled   atomic.Bool
+	redactors []ExampleRedactor
+
+	mu        sync.Mutex
+	generator *Generator                   // Updated when an example is added, nil until the recorder is attached
+	examples  map[string]*recordedExamples // By route key
+}
+
+// recordedExamples are the examples of one route
+type recordedExamples struct {
+	request   any
+	responses map[int]any
+}
+
+// NewExampleRecorder creates a recorder, the redactors rewrite bodies before they are recorded
+//
+// Common secret fields such as password, token or api_key are always redacted, after the
+// given redactors ran.
+func NewExampleRecorder(redactors ...ExampleRedactor) *ExampleRecorder {
+	recorder := &ExampleRecorder{
+		redactors: append(append([]ExampleRedactor{}, redactors...), RedactFields(defaultRedactedFields...)),
+		examples:  make(map[string]*recordedExamples),
+	}
+	recorder.enabled.Store(true)
+	return recorder
+}
+
+// attach makes the recorder update the spec of a generator
+func (r *ExampleRecorder) attach(generator *Generator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generator = generator
+}
+
+// Recording reports whether bodies are recorded
+func (r *ExampleRecorder) Recording() bool {
+	return r.enabled.Load()
+}
+
+// RecordExample records the JSON bodies of a request to a route, e.g. "GET" "/users/:id"
+//
+// Only the first body of the request and of each response status is kept, bodies that are
+// nil or not valid JSON are ignored. The spec is updated when an example was added.
+func (r *ExampleRecorder) RecordExample(method, path string, status int, requestBody, responseBody []byte) {
+	if !r.Recording() {
+		return
+	}
+	request, hasRequest := r.decode(method, path, requestBody)
+	response, hasResponse := r.decode(method, path, responseBody)
+
+	r.mu.Lock()
+	key := routeKey(method, path)
+	examples, exists := r.examples[key]
+	if !exists {
+		examples = &recordedExamples{responses: make(map[int]any)}
+		r.examples[key] = examples
+	}
+	added := false
+	if hasRequest && examples.request == nil {
+		examples.request, added = request, true
+	}
+	if _, recorded := examples.responses[status]; hasResponse && !recorded {
+		examples.responses[status], added = response, true
+	}
+	generator := r.generator
+	r.mu.Unlock()
+
+	if !added || generator == nil {
+		return
+	}
+	generator.mu.Lock()
+	if generator.spec == nil {
+		generator.mu.Unlock()
+		return // Picked up by the first GenerateSpec
+	}
+	if err := generator.updateSpec(); err != nil {
+		generator.logger.Warn("Failed to add recorded example to the spec", "route", key, "error", err)
+	}
+}
+
+// decode parses a body and applies the redactors, false when there is no JSON body
+func (r *ExampleRecorder) decode(method, path string, body []byte) (any, bool) {
+	if len(body) == 0 {
+		return nil, false
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil || value == nil {
+		return nil, false
+	}
+	for _, redactor := range r.redactors {
+		value = redactor(method, path, value)
+	}
+	return value, true
+}
+
+// apply sets the recorded examples on the JSON media types of an operation that have none
+func (r *ExampleRecorder) apply(route spec.RouteInfo, operation *spec.Operation) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	examples, exists := r.examples[routeKey(route.Method, route.Path)]
+	if !exists {
+		return
+	}
+
+	if operation.RequestBody != nil && examples.request != nil {
+		operation.RequestBody.Content = withContentExample(operation.RequestBody.Content, examples.request)
+	}
+	for status, example := range examples.responses {
+		code := strconv.Itoa(status)
+		if response, documented := operation.Responses[code]; documented {
+			response.Content = withContentExample(response.Content, example)
+			operation.Responses[code] = response
+		}
+	}
+}
+
+// withContentExample returns a copy of content with the example set on the JSON media types without one
+//
+// Content maps may be shared by the responses of several operations, so they are not changed.
+func withContentExample(content map[string]spec.MediaType, example any) map[string]spec.MediaType {
+	if len(content) == 0 {
+		return content
+	}
+	updated := make(map[string]spec.MediaType, len(content))
+	for contentType, mediaType := range content {
+		isJSON := contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+		if isJSON && mediaType.Example == nil && len(mediaType.Examples) == 0 {
+			mediaType.Example = example
+		}
+		updated[contentType] = mediaType
+	}
+	return updated
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestWithExampleRecording(t *testing.T) {
+	recorder := NewExampleRecorder(RedactFields("Email"))
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "POST", Path: "/users/:id"}}, WithExampleRecording(recorder))
+	generator.OverrideRequest("POST", "/users/:id", struct {
+		Name string `json:"name"`
+	}{})
+	require.True(t, recorder.Recording())
+
+	// Recorded before the spec exists, picked up by the first generation
+	recorder.RecordExample("POST", "/users/:id", http.StatusOK,
+		[]byte(`{"name":"Ada","password":"hunter2","contacts":[{"email":"ada@example.com"}]}`),
+		[]byte(`{"id":7,"token":"abc"}`))
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	operation := openAPISpec.Paths["/users/{id}"].Post
+	require.NotNil(t, operation)
+	assert.Equal(t, map[string]any{
+		"name":     "Ada",
+		"password": "REDACTED",
+		"contacts": []any{map[string]any{"email": "REDACTED"}},
+	}, operation.RequestBody.Content["application/json"].Example)
+	assert.Equal(t, map[string]any{"id": float64(7), "token": "REDACTED"}, operation.Responses["200"].Content["application/json"].Example)
+
+	// Later examples update the spec, only the first one of each status is kept
+	var updated *spec.OpenAPISpec
+	generator.OnSpecChange(func(changed *spec.OpenAPISpec) { updated = changed })
+	recorder.RecordExample("POST", "/users/:id", http.StatusOK, []byte(`{"name":"Grace"}`), nil)
+	assert.Nil(t, updated)
+	recorder.RecordExample("POST", "/users/:id", http.StatusBadRequest, nil, []byte(`{"error":"invalid"}`))
+	if assert.NotNil(t, updated) {
+		users := updated.Paths["/users/{id}"].Post
+		assert.Equal(t, map[string]any{"error": "invalid"}, users.Responses["400"].Content["application/json"].Example)
+		assert.Nil(t, users.Responses["401"].Content["application/json"].Example, "Shared error responses are not changed")
+	}
+
+	// Bodies that are not JSON are ignored
+	recorder.RecordExample("POST", "/users/:id", http.StatusNotFound, nil, []byte("not found"))
+	assert.Nil(t, updated.Paths["/users/{id}"].Post.Responses["404"].Content["application/json"].Example)
+}
+
+func TestWithExampleRecordingProduction(t *testing.T) {
+	cfg := NewProductionConfig()
+	cfg.SchemaDir = ""
+	recorder := NewExampleRecorder()
+	newTestGenerator(t, nil, WithConfig(cfg), WithExampleRecording(recorder))
+	assert.False(t, recorder.Recording())
+}
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// defaultFileContentType is used for downloads without a registered content type
+const defaultFileContentType = "application/octet-stream"
+
+// RegisterFileDownload marks a route as returning a file
+//
+// Downloads served with c.File, c.FileAttachment or http.ServeFile are detected
+// automatically when handler sources are available; use this when they are not,
+// or to document a more specific content type.
+//
+// Example:
+//
+//	g.RegisterFileDownload("GET", "/reports/:id", "application/pdf")
+func (g *Generator) RegisterFileDownload(method, path, contentType string) {
+	g.schemaRegistry.RegisterFileResponse(method, path, contentType)
+}
+
+// applyFileDownloadDocumentation documents a binary file response with a Content-Disposition header
+func (g *Generator) applyFileDownloadDocumentation(route spec.RouteInfo, operation *spec.Operation) {
+	contentType, exists := g.schemaRegistry.GetFileResponse(route.Method, route.Path)
+	if !exists || contentType == "" {
+		contentType = defaultFileContentType
+	}
+
+	operation.Responses["200"] = spec.Response{
+		Description: "File download",
+		Headers: map[string]spec.Header{
+			"Content-Disposition": {
+				Description: "Suggested file name for the download",
+				Schema:      spec.Schema{Type: "string"},
+				Example:     `attachment; filename="file.bin"`,
+			},
+		},
+		Content: map[string]spec.MediaType{
+			contentType: {
+				Schema: spec.Schema{Type: "string", Format: "binary"},
+			},
+		},
+	}
+}
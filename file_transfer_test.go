@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type uploadAvatarRequest struct {
+	Avatar *multipart.FileHeader `form:"avatar" json:"-"`
+}
+
+func TestFileUploadRequestBody(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users/avatar", HandlerName: "UploadAvatar"},
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+	})
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/users/avatar", reflect.TypeOf(uploadAvatarRequest{}), nil)
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/users", reflect.TypeOf(overrideLoginRequest{}), nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	upload := openAPISpec.Paths["/users/avatar"].Post
+	if assert.NotNil(t, upload) && assert.NotNil(t, upload.RequestBody) {
+		assert.Contains(t, upload.RequestBody.Content, "multipart/form-data")
+		assert.NotContains(t, upload.RequestBody.Content, "application/json")
+	}
+
+	create := openAPISpec.Paths["/users"].Post
+	if assert.NotNil(t, create) && assert.NotNil(t, create.RequestBody) {
+		assert.Contains(t, create.RequestBody.Content, "application/json")
+	}
+}
+
+func TestFileDownloadResponse(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/reports/:id", HandlerName: "DownloadReport"},
+		{Method: "GET", Path: "/files/:name", HandlerName: "DownloadFile"},
+	})
+	generator.RegisterFileDownload("GET", "/reports/:id", "application/pdf")
+	generator.RegisterFileDownload("GET", "/files/:name", "")
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	report := openAPISpec.Paths["/reports/{id}"].Get.Responses["200"]
+	assert.Contains(t, report.Headers, "Content-Disposition")
+	if assert.Contains(t, report.Content, "application/pdf") {
+		assert.Equal(t, "binary", report.Content["application/pdf"].Schema.Format)
+	}
+
+	file := openAPISpec.Paths["/files/{name}"].Get.Responses["200"]
+	assert.Contains(t, file.Content, "application/octet-stream")
+}
@@ -1,13 +1,22 @@
 package openapi
 
 import (
-	"maps"
-	"encoding/json"
+	"context"
 	"fmt"
+	"html/template"
+	"maps"
 	"net/http"
-	"regexp"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
@@ -15,30 +24,75 @@ import (
 	"github.com/zainokta/openapi-gen/integration"
 	"github.com/zainokta/openapi-gen/logger"
 	"github.com/zainokta/openapi-gen/parser"
+	"github.com/zainokta/openapi-gen/schemagen"
 	"github.com/zainokta/openapi-gen/spec"
 )
 
 // Generator is the main OpenAPI specification generator
 type Generator struct {
-	config          *Config
-	logger          logger.Logger
-	discoverer      integration.RouteDiscoverer
-	pathParser      *parser.PathParser
-	overrideManager *OverrideManager
-	structParser    *parser.StructParser
-	schemaRegistry  *analyzer.SchemaRegistry
-	handlerAnalyzer analyzer.HandlerAnalyzer
-	spec            *spec.OpenAPISpec
+	config              *Config
+	logger              logger.Logger
+	discoverer          integration.RouteDiscoverer
+	pathParser          *parser.PathParser
+	overrideManager     *OverrideManager
+	structParser        *parser.StructParser
+	schemaRegistry      *analyzer.SchemaRegistry
+	handlerAnalyzer     analyzer.HandlerAnalyzer
+	nameResolver        func(spec.RouteInfo) string
+	schemaNamer         analyzer.SchemaNamer
+	commonHeaders       []commonResponseHeader
+	conventions         []Convention
+	hiddenRoutes        []routePattern
+	internalRoutes      []routePattern
+	securitySchemes     map[string]spec.SecurityScheme
+	problemJSON         bool
+	defaultResponses    map[string]spec.Response            // Replace the built-in error responses, nil unless WithDefaultResponses
+	tagDefaultResponses map[string]map[string]spec.Response // Error responses of operations by tag, nil unless WithTagDefaultResponses
+	errorExamples       map[int]any                         // Example payloads of error statuses, nil unless WithErrorExamples
+	standardPaths       map[string]StandardEndpoint         // Route paths mapped to standard endpoints, nil unless WithStandardEndpoints
+	operationIDCasing   OperationIDCasing
+	tagOrder            SortOrder
+	pathOrder           SortOrder
+	operationOrder      SortOrder
+	strictOutput        bool
+	enumCasing          EnumCasing
+	nullableStyle       NullableStyle
+	telemetry           *telemetry
+	examples            *ExampleRecorder
+
+	// mu guards the spec and the analyzed routes, AddRoute and RefreshRoutes can run while the docs are served
+	mu             sync.Mutex
+	routes         map[string]analyzedRoute
+	routeOrder     map[string]int // Position of each route in discovery order, kept when the route goes away
+	addedRoutes    []spec.RouteInfo
+	listeners      []func(*spec.OpenAPISpec)
+	pathConflicts  []PathConflict
+	fragments      []specFragment
+	mergeConflicts []MergeConflict
+	spec           *spec.OpenAPISpec
+	document       atomic.Pointer[specDocument]
 }
 
 // NewGenerator creates a new OpenAPI generator with options
 func NewGenerator(framework any, httpServer integration.HTTPServer, options *Options) (*Generator, error) {
+	// Fill unset fields on a copy, the caller's config is left as given, then fail fast
+	// instead of generating a half-broken spec
+	if options.config != nil {
+		config := *options.config
+		options.config = config.ApplyDefaults()
+	}
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI configuration: %w", err)
+	}
+
 	var discoverer integration.RouteDiscoverer
 	var err error
 
-	// Use custom discoverer if provided, otherwise auto-discover
+	// Use custom discoverer if provided, then routes read from source, otherwise auto-discover
 	if options.customDiscoverer != nil {
 		discoverer = options.customDiscoverer
+	} else if options.staticRoutesDir != "" {
+		discoverer = integration.NewStaticRouteDiscoverer(options.staticRoutesDir)
 	} else {
 		// Create framework-agnostic discoverer
 		discoverer, err = integration.NewAutoDiscoverer(framework)
@@ -46,28 +100,95 @@ func NewGenerator(framework any, httpServer integration.HTTPServer, options *Opt
 			return nil, fmt.Errorf("failed to create route discoverer: %w", err)
 		}
 	}
+	if options.routeAnnotations != nil {
+		annotated, ok := discoverer.(integration.AnnotatedRouteDiscoverer)
+		if !ok {
+			return nil, fmt.Errorf("route discoverer %s does not read route annotations", discoverer.GetFrameworkName())
+		}
+		annotated.SetAnnotations(options.routeAnnotations)
+	}
 
 	// Create components with configuration
 	pathParser := parser.NewPathParser()
+	pathParser.AddCommonPrefixes(options.pathPrefixes...)
 	overrideManager := NewOverrideManager()
 	structParser := parser.NewStructParser()
 	schemaRegistry := analyzer.NewSchemaRegistry()
-	handlerAnalyzer := integration.NewHertzHandlerAnalyzer()
+	schemaRegistry.MarkReadOnlyProperties(options.readOnly...)
+	schemaRegistry.MarkWriteOnlyProperties(options.writeOnly...)
+
+	// Analyze handlers with the analyzer of their framework, unless one was provided
+	handlerAnalyzer := options.customAnalyzer
+	if handlerAnalyzer == nil {
+		handlerAnalyzer = integration.NewHandlerAnalyzer(framework, discoverer.GetFrameworkName())
+	}
+	sourceAnalyzer, readsSource := handlerAnalyzer.(integration.SourceHandlerAnalyzer)
+	if readsSource {
+		sourceAnalyzer.SetUnions(schemaRegistry.GetSchemaGenerator().Unions())
+		sourceAnalyzer.SetVendorSources(options.vendorSources)
+		sourceAnalyzer.SetBuildTags(options.buildTags)
+		sourceAnalyzer.SetSourceExclude(options.sourceExclude)
+	}
 
 	// Configure the handler analyzer based on config settings
+	handlerAnalyzer.SetConfig(analysisConfig{config: options.config, sourceDirs: options.sourceDirs, logger: options.logger})
 	if options.config != nil {
-		handlerAnalyzer.SetConfig(options.config)
+		schemaRegistry.GetSchemaGenerator().SetTimeFormat(options.config.TimeFormat)
+
+		requiredRules := schemagen.RequiredRules{Tags: options.config.RequiredTags, PointersOptional: options.config.PointersOptional}
+		structParser.SetRequiredRules(requiredRules)
+		schemaRegistry.GetSchemaGenerator().SetRequiredRules(requiredRules)
+		if readsSource {
+			sourceAnalyzer.GetSchemaGenerator().SetTimeFormat(options.config.TimeFormat)
+			sourceAnalyzer.GetSchemaGenerator().SetRequiredRules(requiredRules)
+		}
+	}
+
+	telemetry, err := newTelemetry(options.tracerProvider, options.meterProvider)
+	if err != nil {
+		return nil, err
 	}
 
 	generator := &Generator{
-		config:          options.config,
-		logger:          options.logger,
-		discoverer:      discoverer,
-		pathParser:      pathParser,
-		overrideManager: overrideManager,
-		structParser:    structParser,
-		schemaRegistry:  schemaRegistry,
-		handlerAnalyzer: handlerAnalyzer,
+		config:              options.config,
+		logger:              options.logger,
+		discoverer:          discoverer,
+		pathParser:          pathParser,
+		overrideManager:     overrideManager,
+		structParser:        structParser,
+		schemaRegistry:      schemaRegistry,
+		handlerAnalyzer:     handlerAnalyzer,
+		nameResolver:        options.nameResolver,
+		schemaNamer:         options.schemaNamer,
+		problemJSON:         options.problemJSON,
+		defaultResponses:    options.defaultResponses,
+		tagDefaultResponses: options.tagDefaultResponses,
+		errorExamples:       options.errorExamples,
+		standardPaths:       options.standardPaths,
+		operationIDCasing:   options.operationIDCasing,
+		strictOutput:        options.strictOutput,
+		enumCasing:          options.enumCasing,
+		nullableStyle:       options.nullableStyle,
+		telemetry:           telemetry,
+		routes:              make(map[string]analyzedRoute),
+		routeOrder:          make(map[string]int),
+		tagOrder:            options.tagOrder,
+		pathOrder:           options.pathOrder,
+		operationOrder:      options.operationOrder,
+	}
+
+	// Examples are recorded while developing, production traffic carries real user data
+	if recorder := options.exampleRecorder; recorder != nil {
+		if options.config != nil && options.config.IsProductionMode() {
+			recorder.enabled.Store(false)
+			generator.logger.Info("Example recording is disabled in production")
+		} else {
+			recorder.attach(generator)
+			generator.examples = recorder
+		}
+	}
+	if options.driftDetector != nil {
+		options.driftDetector.generator.Store(generator)
 	}
 
 	// Load static schemas if configured
@@ -79,6 +200,38 @@ func NewGenerator(framework any, httpServer integration.HTTPServer, options *Opt
 		}
 	}
 
+	// Load embedded schema files, they override files from the schema directory
+	if options.schemaFS != nil {
+		if err := generator.schemaRegistry.LoadStaticSchemasFS(options.schemaFS, options.schemaFSDir); err != nil {
+			return nil, fmt.Errorf("failed to load embedded schemas: %w", err)
+		}
+		generator.logger.Info("Loaded embedded schemas", "dir", options.schemaFSDir)
+	}
+
+	// Load the embedded schema bundle, a missing bundle means a broken build so it is an error
+	if options.schemaBundle != nil {
+		if err := generator.schemaRegistry.LoadSchemaBundle(options.schemaBundle); err != nil {
+			return nil, fmt.Errorf("failed to load schema bundle: %w", err)
+		}
+		generator.logger.Info("Loaded schema bundle", "handlers", len(generator.schemaRegistry.GetAllHandlerNames()))
+	}
+
+	// A handler name declared by several schema files resolves by namespace, or to the first file
+	duplicates := generator.schemaRegistry.DuplicateHandlerSchemas()
+	for _, handlerName := range slices.Sorted(maps.Keys(duplicates)) {
+		generator.logger.Warn("Handler declared by several schema files, handlers outside their directories get the first one",
+			"handler", handlerName, "files", duplicates[handlerName])
+	}
+
+	// Load the hand-written fragments merged into the spec, a missing file is a broken deployment
+	for _, path := range options.specFiles {
+		fragment, err := loadSpecFragment(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OpenAPI spec file %s: %w", path, err)
+		}
+		generator.fragments = append(generator.fragments, specFragment{source: path, spec: fragment})
+	}
+
 	// Initialize common DTO schemas
 	generator.structParser.RegisterDTOSchemas()
 	generator.schemaRegistry.RegisterCommonDTOs()
@@ -86,33 +239,169 @@ func NewGenerator(framework any, httpServer integration.HTTPServer, options *Opt
 	return generator, nil
 }
 
+// analysisConfig is the analyzer.Config the generator passes to its handler analyzer
+type analysisConfig struct {
+	config     *Config
+	sourceDirs []string
+	logger     logger.Logger
+}
+
+func (c analysisConfig) IsProductionMode() bool {
+	return c.config != nil && c.config.IsProductionMode()
+}
+
+func (c analysisConfig) IsASTAnalysisEnabled() bool {
+	return c.config == nil || c.config.IsASTAnalysisEnabled()
+}
+
+func (c analysisConfig) SourceDirs() []string {
+	return c.sourceDirs
+}
+
+func (c analysisConfig) Logger() logger.Logger {
+	return c.logger
+}
+
 // GetOverrideManager returns the override manager for customization
 func (g *Generator) GetOverrideManager() *OverrideManager {
 	return g.overrideManager
 }
 
+// OverrideGroup sets defaults for every route under a path prefix, see OverrideManager.OverrideGroup
+//
+// Example:
+//
+//	g.RegisterSecurityScheme("adminAuth", spec.SecurityScheme{Type: "apiKey", In: "header", Name: "X-Admin-Key"})
+//	g.OverrideGroup("/api/v1/admin").Tags("admin").Security("adminAuth")
+func (g *Generator) OverrideGroup(prefix string) *GroupOverride {
+	return g.overrideManager.OverrideGroup(prefix)
+}
+
+// RegisterSecurityScheme adds a security scheme to the spec components, or replaces one with the same name
+//
+// Operations use it through the Security of an override or group.
+func (g *Generator) RegisterSecurityScheme(name string, scheme spec.SecurityScheme) {
+	if g.securitySchemes == nil {
+		g.securitySchemes = make(map[string]spec.SecurityScheme)
+	}
+	g.securitySchemes[name] = scheme
+}
+
+// WarmUp caches the schemas of types before the first spec is generated
+//
+// Both the schema registry and the handler analyzer cache schemas per Go type, warming
+// them at init moves that cost out of the first request for the docs.
+func (g *Generator) WarmUp(types ...reflect.Type) {
+	g.schemaRegistry.GetSchemaGenerator().WarmUp(types)
+	if withGenerator, ok := g.handlerAnalyzer.(interface {
+		GetSchemaGenerator() *analyzer.SchemaGenerator
+	}); ok {
+		withGenerator.GetSchemaGenerator().WarmUp(types)
+	}
+}
+
 // GenerateSpec generates the complete OpenAPI specification
 func (g *Generator) GenerateSpec() (*spec.OpenAPISpec, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	start := time.Now()
+	ctx, span := g.telemetry.tracer.Start(context.Background(), "openapi.GenerateSpec")
+	defer span.End()
+	defer func() {
+		g.telemetry.duration.Record(ctx, time.Since(start).Seconds())
+	}()
+
 	// Discover routes from the framework
+	routes, err := g.discoverRoutes(ctx)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("openapi.routes", len(routes)))
+
+	// Analyze every route, then build paths, tags and components from the results
+	g.routes = make(map[string]analyzedRoute, len(routes))
+	for _, route := range routes {
+		if err := g.processRoute(ctx, g.resolveHandlerName(route)); err != nil {
+			g.logger.Warn("Failed to process route", "method", route.Method, "path", route.Path, "error", err)
+			g.telemetry.analysisFailures.Add(ctx, 1)
+			continue
+		}
+		g.telemetry.routesProcessed.Add(ctx, 1)
+	}
+	g.spec = g.buildSpec()
+
+	g.logger.Info("Generated OpenAPI spec",
+		"paths", len(g.spec.Paths),
+		"tags", len(g.spec.Tags),
+		"schemas", len(g.spec.Components.Schemas))
+
+	return g.spec, nil
+}
+
+// discoverRoutes discovers the routes of the framework, plus the routes added with AddRoute
+func (g *Generator) discoverRoutes(ctx context.Context) ([]spec.RouteInfo, error) {
+	_, span := g.telemetry.tracer.Start(ctx, "openapi.DiscoverRoutes",
+		trace.WithAttributes(attribute.String("openapi.framework", g.discoverer.GetFrameworkName())))
+	defer span.End()
+
 	routes, err := g.discoverer.DiscoverRoutes()
+	recordError(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover routes: %w", err)
 	}
 
 	g.logger.Info("Discovered routes", "count", len(routes), "framework", g.discoverer.GetFrameworkName())
 
-	// Initialize OpenAPI spec
-	g.spec = &spec.OpenAPISpec{
+	// Routes added with AddRoute are documented even if the discoverer cannot see them
+	return g.withAddedRoutes(routes), nil
+}
+
+// specLicense returns the configured license, nil when none is set
+func (g *Generator) specLicense() *spec.License {
+	if g.config.License.Name == "" {
+		return nil
+	}
+	return &spec.License{Name: g.config.License.Name, URL: g.config.License.URL}
+}
+
+// specExternalDocs returns the configured external documentation link, nil when none is set
+func (g *Generator) specExternalDocs() *spec.ExternalDocs {
+	if g.config.ExternalDocs.URL == "" {
+		return nil
+	}
+	return &spec.ExternalDocs{Description: g.config.ExternalDocs.Description, URL: g.config.ExternalDocs.URL}
+}
+
+// resolveHandlerName lets the consumer rename handlers the discoverer could not see through
+func (g *Generator) resolveHandlerName(route spec.RouteInfo) spec.RouteInfo {
+	if g.nameResolver != nil {
+		if name := g.nameResolver(route); name != "" {
+			route.HandlerName = name
+		}
+	}
+	return route
+}
+
+// buildSpec builds the spec from the analyzed routes
+//
+// Operations are created from the cached analysis results and the registered schemas
+// are named and deduplicated again, so adding a route never analyzes the others twice.
+func (g *Generator) buildSpec() *spec.OpenAPISpec {
+	openAPISpec := &spec.OpenAPISpec{
 		OpenAPI: "3.0.3",
 		Info: spec.Info{
-			Title:       g.config.Title,
-			Description: g.config.Description,
-			Version:     g.config.Version,
+			Title:          g.config.Title,
+			Description:    g.config.Description,
+			Version:        g.config.Version,
+			TermsOfService: g.config.TermsOfService,
 			Contact: spec.Contact{
 				Name:  g.config.Contact.Name,
 				Email: g.config.Contact.Email,
 				URL:   g.config.Contact.URL,
 			},
+			License: g.specLicense(),
 		},
 		Servers: []spec.Server{
 			{
@@ -130,17 +419,38 @@ func (g *Generator) GenerateSpec() (*spec.OpenAPISpec, error) {
 				"bearerAuth": []string{},
 			},
 		},
-		Tags: make([]spec.Tag, 0),
+		Tags:         make([]spec.Tag, 0),
+		ExternalDocs: g.specExternalDocs(),
 	}
+	g.spec = openAPISpec
 
-	// Process routes and generate OpenAPI paths
+	// Generate OpenAPI paths and collect their tags, in route order so conflicts resolve the same way every time
 	tags := make(map[string]bool)
-	for _, route := range routes {
-		if err := g.processRoute(route, tags); err != nil {
-			g.logger.Warn("Failed to process route", "method", route.Method, "path", route.Path, "error", err)
+	documented := make(map[string]string)
+	g.pathConflicts = nil
+	for _, key := range slices.Sorted(maps.Keys(g.routes)) {
+		analyzed := g.routes[key]
+		if analyzed.hidden || g.isAutoMethod(analyzed.route) {
+			continue
+		}
+
+		// Routes that normalize to an operation already documented, e.g. GET /users/, are merged into it
+		path := g.specPath(analyzed.route.Path)
+		operationKey := routeKey(analyzed.route.Method, path)
+		if first, exists := documented[operationKey]; exists {
+			if first != analyzed.route.Path {
+				g.reportPathConflict(PathConflict{Method: strings.ToUpper(analyzed.route.Method), Path: path, Routes: []string{first, analyzed.route.Path}})
+			}
 			continue
 		}
+		documented[operationKey] = analyzed.route.Path
+		g.addRouteOperation(analyzed.route, path, analyzed.handlerSchema, tags)
 	}
+	g.detectParameterNameConflicts()
+	g.addCORSPreflights()
+	g.disambiguateOperationIDs(openAPISpec)
+
+	g.describePathItems()
 
 	// Generate tags from collected unique tags
 	g.spec.Tags = g.generateTagsFromSet(tags)
@@ -154,46 +464,160 @@ func (g *Generator) GenerateSpec() (*spec.OpenAPISpec, error) {
 	// Add schemas from schema registry (handler DTOs)
 	maps.Copy(allSchemas, g.schemaRegistry.GetAllSchemas())
 
-	g.spec.Components.Schemas = allSchemas
+	if g.problemJSON {
+		allSchemas[analyzer.ProblemDetailsSchemaName] = analyzer.ProblemDetailsSchema()
+	}
+	if g.documentsGraphQL() {
+		allSchemas[analyzer.GraphQLRequestSchemaName] = analyzer.GraphQLRequestSchema()
+		allSchemas[analyzer.GraphQLResponseSchemaName] = analyzer.GraphQLResponseSchema()
+	}
+	if g.documentsStandardEndpoint(HealthEndpoint, ReadyEndpoint) {
+		allSchemas[HealthStatusSchemaName] = healthStatusSchema()
+	}
+	if g.documentsStandardEndpoint(VersionEndpoint) {
+		allSchemas[VersionInfoSchemaName] = versionInfoSchema()
+	}
 
-	g.logger.Info("Generated OpenAPI spec",
-		"paths", len(g.spec.Paths),
-		"tags", len(g.spec.Tags),
-		"schemas", len(g.spec.Components.Schemas))
+	// Name route schemas after their Go types (or the configured namer), then let routes
+	// sharing a DTO share one component
+	named, renames := analyzer.ApplySchemaNames(allSchemas, g.schemaRegistry.ResolveComponentNames(g.schemaNamer))
+	preferred := make(map[string]bool, len(renames))
+	for _, name := range renames {
+		preferred[name] = true
+	}
+	componentTypes := g.schemaRegistry.GetComponentTypes()
+	for internal, name := range renames {
+		if goType, exists := componentTypes[internal]; exists {
+			componentTypes[name] = goType
+		}
+	}
+	schemas, merged := analyzer.DeduplicateSchemas(named, g.schemaRegistry.GetTypeNamedSchemas(), preferred, componentTypes)
+	for internal, name := range renames {
+		if final, exists := merged[name]; exists {
+			renames[internal] = final
+		}
+	}
+	maps.Copy(renames, merged)
+	g.spec.Components.Schemas = schemas
+	g.rewriteComponentRefs(renames)
+	nameXMLRoots(openAPISpec, componentTypes)
 
-	return g.spec, nil
+	// Hand-written fragments last, so they never shadow what was generated
+	g.mergeFragments(openAPISpec)
+	g.applySortOrders(openAPISpec)
+
+	if g.strictOutput {
+		g.applyStrictOutput(openAPISpec)
+	}
+	g.applyClientNaming(openAPISpec)
+
+	return openAPISpec
+}
+
+// analyzedRoute is a route and the schemas found for its handler
+type analyzedRoute struct {
+	route         spec.RouteInfo
+	handlerSchema analyzer.HandlerSchema
+	hidden        bool // Left out of the spec, by Hide, an //openapi:ignore handler comment, ExcludeInternal or ExcludeGraphQL
 }
 
-// processRoute processes a single route and adds it to the OpenAPI spec
-func (g *Generator) processRoute(route spec.RouteInfo, tags map[string]bool) error {
-	var handlerSchema analyzer.HandlerSchema
+// processRoute analyzes a single route and records it for the next buildSpec
+func (g *Generator) processRoute(ctx context.Context, route spec.RouteInfo) error {
+	key := routeKey(route.Method, route.Path)
+	if _, seen := g.routeOrder[key]; !seen {
+		g.routeOrder[key] = len(g.routeOrder)
+	}
 
-	// First, try to get pre-registered schema by handler name
-	if route.HandlerName != "" {
-		if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(route.HandlerName); exists {
-			handlerSchema = preRegisteredSchema
-			g.logger.Info("Using pre-registered schema", "handler", route.HandlerName)
-		} else {
-			// Try fallback matching strategies
-			handlerSchema = g.tryFallbackSchemaMatching(route)
-		}
+	// Hidden routes are remembered, but neither analyzed nor documented
+	_, wildcard := wildcardParameter(route.Path)
+	if g.isHidden(route) || (wildcard && g.config.ExcludeWildcardRoutes) {
+		g.routes[key] = analyzedRoute{route: route, hidden: true}
+		return nil
 	}
 
-	// If no pre-registered schema found, try to analyze the handler
-	if (handlerSchema.RequestSchema.Type == "" && handlerSchema.ResponseSchema.Type == "") && route.Handler != nil {
+	// Schemas registered for the handler, from static schema files or explicit registration,
+	// win over analysis even when they only carry flags such as Stream or NoRequestBody
+	handlerSchema, registered := g.registeredHandlerSchema(route)
+
+	// Otherwise analyze the handler, the analyzer falls back to placeholder schemas
+	if !registered && route.Handler != nil {
+		_, span := g.telemetry.tracer.Start(ctx, "openapi.AnalyzeHandler", routeAttributes(route.Method, route.Path, route.HandlerName))
 		handlerSchema = g.handlerAnalyzer.AnalyzeHandler(route.Handler)
+		placeholder := handlerSchema.RequestSchema.Description == analyzer.FallbackRequestDescription ||
+			handlerSchema.ResponseSchema.Description == analyzer.FallbackResponseDescription
+		span.SetAttributes(attribute.Bool("openapi.fallback", placeholder))
+		span.End()
+		if placeholder {
+			g.telemetry.fallbacks.Add(ctx, 1)
+		}
+	}
+
+	// The handler asked to stay out of the docs, do not register its schemas either
+	handlerSchema.GraphQL = handlerSchema.GraphQL || isGraphQLRoute(route)
+	if handlerSchema.HasDirective(analyzer.DirectiveIgnore) || (g.config.ExcludeInternal && g.isInternal(route, handlerSchema)) ||
+		(g.config.ExcludeGraphQL && handlerSchema.GraphQL) {
+		g.routes[key] = analyzedRoute{route: route, hidden: true}
+		return nil
+	}
+	if _, standard := g.standardEndpoint(route, handlerSchema); standard || handlerSchema.GraphQL {
+		// The placeholders found for a GraphQL or standard endpoint say nothing, canned schemas replace them
+		handlerSchema.RequestSchema = spec.Schema{}
+		handlerSchema.ResponseSchema = spec.Schema{}
 	}
 
-	// Register the discovered schemas with the schema registry
-	if handlerSchema.RequestSchema.Type != "" {
+	// Overrides registered with OverrideRequest/OverrideResponse take precedence over analysis
+	if schema, exists := g.schemaRegistry.GetRequestOverride(route.Method, route.Path); exists {
+		handlerSchema.RequestSchema = schema
+	}
+	if schema, exists := g.schemaRegistry.GetResponseOverrides(route.Method, route.Path)[http.StatusOK]; exists {
+		handlerSchema.ResponseSchema = schema
+	}
+	// Responses of further status codes from schema files document the route like overrides, which win
+	overrides := g.schemaRegistry.GetResponseOverrides(route.Method, route.Path)
+	for status, schema := range handlerSchema.Responses {
+		if _, overridden := overrides[status]; !overridden {
+			g.schemaRegistry.RegisterResponseOverride(route.Method, route.Path, status, schema)
+		}
+	}
+
+	// Register the discovered schemas with the schema registry, query structs become parameters instead
+	if _, query := g.queryRequestSchema(route, handlerSchema); handlerSchema.RequestSchema.Type != "" && !query {
 		g.schemaRegistry.RegisterRequestSchema(route.Method, route.Path, handlerSchema.RequestSchema)
 	}
 	if handlerSchema.ResponseSchema.Type != "" {
 		g.schemaRegistry.RegisterResponseSchema(route.Method, route.Path, handlerSchema.ResponseSchema)
 	}
 
-	// Parse route using algorithm
+	// Registered stream messages and file responses take precedence over detected handler patterns
+	if registered, exists := g.schemaRegistry.GetStreamSchema(route.Method, route.Path); exists {
+		handlerSchema.Stream = registered.Kind
+	}
+	if _, exists := g.schemaRegistry.GetFileResponse(route.Method, route.Path); exists {
+		handlerSchema.FileResponse = true
+	}
+
+	g.routes[key] = analyzedRoute{route: route, handlerSchema: handlerSchema}
+
+	return nil
+}
+
+// addRouteOperation creates the operation of an analyzed route and adds it to the spec
+func (g *Generator) addRouteOperation(route spec.RouteInfo, path string, handlerSchema analyzer.HandlerSchema, tags map[string]bool) {
+	// Parse route using algorithm, a descriptive handler name makes a better summary than the path
 	parsed := g.pathParser.ParseRoute(route.Method, route.Path)
+	if summary := g.pathParser.SummaryFromHandlerName(route.HandlerName); summary != "" {
+		parsed.Summary = summary
+	}
+	// Documentation declared with the route by the discoverer wins over the generated one
+	if len(route.Tags) > 0 {
+		parsed.Tag = route.Tags[0]
+	}
+	if route.Summary != "" {
+		parsed.Summary = route.Summary
+	}
+	if route.Description != "" {
+		parsed.Description = route.Description
+	}
 
 	// Apply overrides
 	metadata := g.overrideManager.GetMetadata(route.Method, route.Path, parsed)
@@ -202,25 +626,108 @@ func (g *Generator) processRoute(route spec.RouteInfo, tags map[string]bool) err
 	tags[metadata.Tags] = true
 
 	// Create OpenAPI operation
-	operation := g.createOperation(route, metadata)
+	operation := g.createOperation(route, metadata, handlerSchema)
+	operation.XInternal = g.isInternal(route, handlerSchema)
+	operation.Deprecated = route.Deprecated
+	// Further tags of the route are kept unless an override replaced the first one
+	if len(route.Tags) > 1 && metadata.Tags == route.Tags[0] {
+		operation.Tags = route.Tags
+		for _, tag := range route.Tags[1:] {
+			tags[tag] = true
+		}
+	}
 
-	// Add to spec
-	g.addOperationToSpec(route.Method, route.Path, operation)
+	// Add to spec under its normalized path
+	g.addOperationToSpec(route.Method, path, operation)
+}
 
-	return nil
+// registeredHandlerSchema looks up the schema registered for the handler of a route, by its
+// name first and then by the fallback strategies
+func (g *Generator) registeredHandlerSchema(route spec.RouteInfo) (analyzer.HandlerSchema, bool) {
+	if route.HandlerName == "" {
+		return g.tryFallbackSchemaMatching(route)
+	}
+	// Schema files of methods are named after their receiver, e.g. UserHandler.CreateUser
+	handlerNames := []string{route.HandlerName}
+	if qualifiedName := handlerQualifiedName(route.Handler, route.HandlerName); qualifiedName != "" {
+		handlerNames = []string{qualifiedName, route.HandlerName}
+	}
+	// Schema files in a subdirectory of the schema directory belong to the package of that path
+	if packagePath := handlerPackagePath(route.Handler); packagePath != "" {
+		for _, handlerName := range handlerNames {
+			if namespacedSchema, exists := g.schemaRegistry.GetNamespacedHandlerSchema(packagePath, handlerName); exists {
+				g.logger.Info("Using pre-registered schema of the handler package", "handler", handlerName, "package", packagePath)
+				return namespacedSchema, true
+			}
+		}
+	}
+	for _, handlerName := range handlerNames {
+		if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(handlerName); exists {
+			g.logger.Info("Using pre-registered schema", "handler", handlerName)
+			return preRegisteredSchema, true
+		}
+	}
+	return g.tryFallbackSchemaMatching(route)
 }
 
-// tryFallbackSchemaMatching attempts to match schemas using fallback strategies
-func (g *Generator) tryFallbackSchemaMatching(route spec.RouteInfo) analyzer.HandlerSchema {
-	var handlerSchema analyzer.HandlerSchema
+// handlerFuncName returns the runtime name of a handler function, e.g.
+// github.com/acme/app/users.(*Handler).Create-fm
+func handlerFuncName(handler any) string {
+	value := reflect.ValueOf(handler)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+// handlerPackagePath returns the import path of the package a handler function is declared in
+func handlerPackagePath(handler any) string {
+	name := handlerFuncName(handler)
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot == -1 {
+		return ""
+	}
+	return name[:slash+1+dot]
+}
 
-	// Strategy 1: Try with generated path-based handler name
+// handlerQualifiedName returns the name of a method handler qualified by its receiver type,
+// e.g. Handler.Create, or "" when the handler is not the method named handlerName
+func handlerQualifiedName(handler any, handlerName string) string {
+	packagePath := handlerPackagePath(handler)
+	if packagePath == "" {
+		return ""
+	}
+	// e.g. (*Handler).Create-fm, Handler.Create-fm or (*List[...]).Get-fm
+	name := strings.TrimSuffix(handlerFuncName(handler)[len(packagePath)+1:], "-fm")
+	dot := strings.LastIndex(name, ".")
+	if dot == -1 || name[dot+1:] != handlerName {
+		return ""
+	}
+	receiver, method := name[:dot], name[dot+1:]
+	receiver = strings.TrimSuffix(strings.TrimPrefix(receiver, "(*"), ")")
+	if bracket := strings.Index(receiver, "["); bracket != -1 {
+		receiver = receiver[:bracket]
+	}
+	return receiver + "." + method
+}
+
+// tryFallbackSchemaMatching attempts to match schemas using fallback strategies
+func (g *Generator) tryFallbackSchemaMatching(route spec.RouteInfo) (analyzer.HandlerSchema, bool) {
+	// Strategy 1: Try with generated path-based handler name, the only one for unnamed handlers
 	pathBasedName := g.pathParser.GenerateHandlerName(route.Method, route.Path)
 	if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(pathBasedName); exists {
-		g.logger.Info("Using pre-registered schema with path-based matching", 
-			"original_handler", route.HandlerName, 
+		g.logger.Info("Using pre-registered schema with path-based matching",
+			"original_handler", route.HandlerName,
 			"path_based_handler", pathBasedName)
-		return preRegisteredSchema
+		return preRegisteredSchema, true
+	}
+	if route.HandlerName == "" {
+		return analyzer.HandlerSchema{}, false
 	}
 
 	// Strategy 2: Try case-insensitive matching for all registered handlers
@@ -229,10 +736,10 @@ func (g *Generator) tryFallbackSchemaMatching(route spec.RouteInfo) analyzer.Han
 	for _, registeredHandler := range allHandlers {
 		if strings.ToLower(registeredHandler) == lowerHandlerName {
 			if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(registeredHandler); exists {
-				g.logger.Info("Using pre-registered schema with case-insensitive matching", 
-					"original_handler", route.HandlerName, 
+				g.logger.Info("Using pre-registered schema with case-insensitive matching",
+					"original_handler", route.HandlerName,
 					"matched_handler", registeredHandler)
-				return preRegisteredSchema
+				return preRegisteredSchema, true
 			}
 		}
 	}
@@ -242,80 +749,139 @@ func (g *Generator) tryFallbackSchemaMatching(route spec.RouteInfo) analyzer.Han
 		// Check if the route handler name contains the registered handler name
 		if strings.Contains(strings.ToLower(route.HandlerName), strings.ToLower(registeredHandler)) {
 			if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(registeredHandler); exists {
-				g.logger.Info("Using pre-registered schema with partial matching", 
-					"original_handler", route.HandlerName, 
+				g.logger.Info("Using pre-registered schema with partial matching",
+					"original_handler", route.HandlerName,
 					"matched_handler", registeredHandler)
-				return preRegisteredSchema
+				return preRegisteredSchema, true
 			}
 		}
 		// Check if the registered handler name contains the route handler name
 		if strings.Contains(strings.ToLower(registeredHandler), strings.ToLower(route.HandlerName)) {
 			if preRegisteredSchema, exists := g.schemaRegistry.GetHandlerSchema(registeredHandler); exists {
-				g.logger.Info("Using pre-registered schema with reverse partial matching", 
-					"original_handler", route.HandlerName, 
+				g.logger.Info("Using pre-registered schema with reverse partial matching",
+					"original_handler", route.HandlerName,
 					"matched_handler", registeredHandler)
-				return preRegisteredSchema
+				return preRegisteredSchema, true
 			}
 		}
 	}
 
 	g.logger.Debug("No fallback schema match found", "handler", route.HandlerName)
-	return handlerSchema
+	return analyzer.HandlerSchema{}, false
 }
 
 // createOperation creates an OpenAPI operation from route information
-func (g *Generator) createOperation(route spec.RouteInfo, metadata RouteMetadata) spec.Operation {
+func (g *Generator) createOperation(route spec.RouteInfo, metadata RouteMetadata, handlerSchema analyzer.HandlerSchema) spec.Operation {
 	operation := spec.Operation{
 		Tags:        []string{metadata.Tags},
 		Summary:     metadata.Summary,
 		Description: metadata.Description,
 		OperationID: g.generateOperationID(route.Method, route.Path),
-		Parameters:  g.extractParameters(route.Path),
-		Responses:   g.generateResponses(route),
+		Parameters:  g.extractParameters(route, handlerSchema),
+		Responses:   g.generateResponses(route, metadata.Tags),
 	}
 
-	// Add request body for methods that typically have one
-	if g.hasRequestBody(route.Method) {
+	if g.documentsRequestBody(route, handlerSchema) {
 		requestBody := g.generateRequestBodyFromRoute(route)
 		operation.RequestBody = &requestBody
 	}
 
-	// Add security if not a public endpoint
-	if !g.isPublicEndpoint(route.Path) {
+	// Security set by an override or group wins, otherwise add security if not a public endpoint
+	if metadata.Security != nil {
+		operation.Security = make([]spec.SecurityRequirement, 0, len(metadata.Security))
+		for _, scheme := range metadata.Security {
+			operation.Security = append(operation.Security, spec.SecurityRequirement{scheme: []string{}})
+		}
+	} else if !g.isPublicEndpoint(route.Path) {
 		operation.Security = []spec.SecurityRequirement{
 			{"bearerAuth": []string{}},
 		}
 	} else {
 		operation.Security = []spec.SecurityRequirement{} // No auth required
 	}
+	if len(operation.Security) == 0 {
+		// Public operations cannot reject the credentials of the caller, unless a route documents it
+		delete(operation.Responses, "401")
+	}
+
+	// Streaming endpoints are not plain JSON request/response exchanges
+	switch handlerSchema.Stream {
+	case analyzer.StreamWebSocket:
+		g.applyWebSocketDocumentation(route, &operation)
+	case analyzer.StreamSSE:
+		g.applySSEDocumentation(route, &operation)
+	case analyzer.StreamChunked:
+		g.applyChunkedDocumentation(&operation)
+	}
+
+	if handlerSchema.FileResponse {
+		g.applyFileDownloadDocumentation(route, &operation)
+	}
+	if handlerSchema.GraphQL {
+		g.applyGraphQLDocumentation(route, &operation)
+	}
+	endpoint, standard := g.standardEndpoint(route, handlerSchema)
+	if standard {
+		g.applyStandardEndpoint(endpoint, &operation)
+		if metadata.Security == nil {
+			// Probes and scrapers call them without credentials
+			operation.Security = []spec.SecurityRequirement{}
+		}
+	}
+
+	g.applyResponseOverrides(route, &operation)
+	if g.respondsOtherSuccess(route, handlerSchema) {
+		// The placeholder 200 of a handler responding 201 or 204 instead would mislead clients
+		delete(operation.Responses, "200")
+	}
+	if !handlerSchema.FileResponse && !handlerSchema.GraphQL && !standard && handlerSchema.Stream == analyzer.StreamNone {
+		g.applyResponseFormats(route, &operation, handlerSchema)
+	}
+	g.applySchemaVariants(route, &operation)
+	if g.problemJSON {
+		g.applyProblemJSON(route, &operation)
+	}
+	g.applyMediaTypeOverrides(route, &operation)
+	g.applyCommonResponseHeaders(route, &operation)
+	g.applyConventions(route, &operation)
+	g.applyCompression(&operation)
+	g.applyResponseExamples(route, &operation, handlerSchema)
+	g.examples.apply(route, &operation)
 
 	return operation
 }
 
-// extractParameters extracts parameters from route path
-func (g *Generator) extractParameters(path string) []spec.Parameter {
-	var params []spec.Parameter
+// respondsOtherSuccess reports whether the schemas of a handler document a success status other
+// than 200 and no 200 response
+func (g *Generator) respondsOtherSuccess(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) bool {
+	if handlerSchema.ResponseSchema.Type != "" || handlerSchema.ResponseSchema.Ref != "" {
+		return false
+	}
+	if _, overridden := g.schemaRegistry.GetResponseOverrides(route.Method, route.Path)[http.StatusOK]; overridden {
+		return false
+	}
+	for status := range handlerSchema.Responses {
+		if status > http.StatusOK && status < http.StatusMultipleChoices {
+			return true
+		}
+	}
+	return false
+}
 
-	// Extract path parameters (e.g., :id, :token)
-	paramRegex := regexp.MustCompile(`:(\w+)`)
-	matches := paramRegex.FindAllStringSubmatch(path, -1)
+// extractParameters extracts parameters from the route path and the request struct of methods without a body
+func (g *Generator) extractParameters(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) []spec.Parameter {
+	path := route.Path
 
-	for _, match := range matches {
-		if len(match) > 1 {
-			paramName := match[1]
-			param := spec.Parameter{
-				Name:        paramName,
-				In:          "path",
-				Required:    true,
-				Description: fmt.Sprintf("Path parameter: %s", paramName),
-				Schema:      spec.Schema{Type: "string"},
-			}
-			params = append(params, param)
-		}
+	// Extract path parameters (e.g., :id, *filepath, {id})
+	params := pathParameters(path)
+
+	// GET and DELETE handlers bind their request struct (e.g. ShouldBindQuery) from the query string
+	if querySchema, query := g.queryRequestSchema(route, handlerSchema); query {
+		params = append(params, g.queryParameters(querySchema, params)...)
 	}
 
 	// Add common query parameters for certain endpoints
-	if strings.Contains(path, "mfa") && strings.Contains(path, "verify") {
+	if strings.Contains(path, "mfa") && strings.Contains(path, "verify") && !hasParameter(params, "challenge", "query") {
 		params = append(params, spec.Parameter{
 			Name:        "challenge",
 			In:          "query",
@@ -328,8 +894,72 @@ func (g *Generator) extractParameters(path string) []spec.Parameter {
 	return params
 }
 
+// queryRequestSchema returns the request schema of a route whose method has no body, its fields are query parameters
+//
+// Overridden requests are documented as bodies, and fallback placeholders have no real fields.
+func (g *Generator) queryRequestSchema(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) (spec.Schema, bool) {
+	schema := handlerSchema.RequestSchema
+	if g.hasRequestBody(route.Method) || schema.Type != "object" || len(schema.Properties) == 0 {
+		return spec.Schema{}, false
+	}
+	if _, overridden := g.schemaRegistry.GetRequestOverride(route.Method, route.Path); overridden {
+		return spec.Schema{}, false
+	}
+	if schema.Description == analyzer.FallbackRequestDescription {
+		return spec.Schema{}, false
+	}
+	return schema, true
+}
+
+// queryParameters promotes the properties of a request schema to query parameters, in name order
+//
+// Parameters are named like the framework binds them, by the query or form tag of the field
+// rather than its JSON name. Properties already documented as parameters, e.g. path parameters
+// bound from the same struct, and properties that are not bound are skipped.
+func (g *Generator) queryParameters(schema spec.Schema, existing []spec.Parameter) []spec.Parameter {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	params := make([]spec.Parameter, 0, len(schema.Properties))
+	for propertyName, property := range schema.Properties {
+		name := propertyName
+		if property.BindName != "" {
+			name = property.BindName
+		}
+		if name == "-" || hasParameter(existing, name, "path") {
+			continue
+		}
+
+		// Description and example belong to the parameter
+		description, example := property.Description, property.Example
+		property.Description, property.Example, property.BindName = "", nil, ""
+		params = append(params, spec.Parameter{
+			Name:        name,
+			In:          "query",
+			Required:    required[propertyName],
+			Description: description,
+			Schema:      property,
+			Example:     example,
+		})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// hasParameter reports whether a parameter with this name and location is already documented
+func hasParameter(params []spec.Parameter, name, in string) bool {
+	for _, param := range params {
+		if param.Name == name && param.In == in {
+			return true
+		}
+	}
+	return false
+}
+
 // generateResponses generates responses using dynamic schema resolution
-func (g *Generator) generateResponses(route spec.RouteInfo) map[string]spec.Response {
+func (g *Generator) generateResponses(route spec.RouteInfo, tag string) map[string]spec.Response {
 	responses := make(map[string]spec.Response)
 
 	// Get response schema from registry
@@ -358,8 +988,8 @@ func (g *Generator) generateResponses(route spec.RouteInfo) map[string]spec.Resp
 		},
 	}
 
-	// Error responses (reuse existing logic)
-	errorResponses := g.generateDefaultResponses()
+	// Error responses, configured per tag or for the whole API or else the built-in ones
+	errorResponses := g.defaultErrorResponses(tag)
 	for code, response := range errorResponses {
 		if code != "200" { // Don't override success response
 			responses[code] = response
@@ -394,7 +1024,7 @@ func (g *Generator) generateDefaultResponses() map[string]spec.Response {
 		Description: "Bad Request",
 		Content: map[string]spec.MediaType{
 			"application/json": {
-				Schema: g.getErrorSchema(),
+				Schema: errorSchema(),
 			},
 		},
 	}
@@ -403,7 +1033,7 @@ func (g *Generator) generateDefaultResponses() map[string]spec.Response {
 		Description: "Unauthorized",
 		Content: map[string]spec.MediaType{
 			"application/json": {
-				Schema: g.getErrorSchema(),
+				Schema: errorSchema(),
 			},
 		},
 	}
@@ -412,7 +1042,7 @@ func (g *Generator) generateDefaultResponses() map[string]spec.Response {
 		Description: "Internal Server Error",
 		Content: map[string]spec.MediaType{
 			"application/json": {
-				Schema: g.getErrorSchema(),
+				Schema: errorSchema(),
 			},
 		},
 	}
@@ -420,8 +1050,8 @@ func (g *Generator) generateDefaultResponses() map[string]spec.Response {
 	return responses
 }
 
-// getErrorSchema returns the standard error schema
-func (g *Generator) getErrorSchema() spec.Schema {
+// errorSchema returns the standard error schema
+func errorSchema() spec.Schema {
 	return spec.Schema{
 		Type: "object",
 		Properties: map[string]spec.Schema{
@@ -437,9 +1067,15 @@ func (g *Generator) getErrorSchema() spec.Schema {
 func (g *Generator) generateRequestBodyFromRoute(route spec.RouteInfo) spec.RequestBody {
 	// Get request schema from registry
 	var schema spec.Schema
-	if _, exists := g.schemaRegistry.GetRequestSchema(route.Method, route.Path); exists {
+	contentType := "application/json"
+	if registered, exists := g.schemaRegistry.GetRequestSchema(route.Method, route.Path); exists {
 		// Use schema reference instead of inline schema
 		schema = g.generateSchemaReference(route.Method, route.Path, "request")
+
+		// File uploads can only be sent as multipart forms
+		if analyzer.ContainsBinary(registered) {
+			contentType = "multipart/form-data"
+		}
 	} else {
 		// Fallback to generic schema
 		schema = spec.Schema{
@@ -450,16 +1086,39 @@ func (g *Generator) generateRequestBodyFromRoute(route spec.RouteInfo) spec.Requ
 		}
 	}
 
+	required := true
+	if registered, exists := g.schemaRegistry.GetRequestRequired(route.Method, route.Path); exists {
+		required = registered
+	}
+
 	return spec.RequestBody{
-		Required: true,
+		Required: required,
 		Content: map[string]spec.MediaType{
-			"application/json": {
+			contentType: {
 				Schema: schema,
 			},
 		},
 	}
 }
 
+// documentsRequestBody reports whether an operation is documented with a request body
+//
+// An override decides first, a nil override documenting no body. Otherwise methods that
+// typically have a body get one when a request schema was discovered or registered, or a
+// generic one when Config.GenericRequestBodies is set and the handler may read a body.
+func (g *Generator) documentsRequestBody(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) bool {
+	if override, overridden := g.schemaRegistry.GetRequestOverride(route.Method, route.Path); overridden {
+		return !reflect.DeepEqual(override, spec.Schema{})
+	}
+	if !g.hasRequestBody(route.Method) {
+		return false
+	}
+	if g.schemaRegistry.HasRequestSchema(route.Method, route.Path) {
+		return true
+	}
+	return g.config != nil && g.config.GenericRequestBodies && !handlerSchema.NoRequestBody
+}
+
 // hasRequestBody determines if an operation should have a request body
 func (g *Generator) hasRequestBody(method string) bool {
 	return method == "POST" || method == "PUT" || method == "PATCH"
@@ -470,8 +1129,8 @@ func (g *Generator) isPublicEndpoint(path string) bool {
 	publicPaths := []string{
 		"/",
 		"/health",
-		"/docs",
-		"/openapi.json",
+		g.config.DocsPath,
+		g.config.SpecPath,
 		"/api/v1/auth/register",
 		"/api/v1/auth/login",
 		"/api/v1/oauth/login",
@@ -482,7 +1141,11 @@ func (g *Generator) isPublicEndpoint(path string) bool {
 	}
 
 	for _, publicPath := range publicPaths {
-		if path == publicPath || strings.HasPrefix(path, publicPath) {
+		if path == publicPath {
+			return true
+		}
+		// The root only matches itself, every path lies under it
+		if publicPath != "" && publicPath != "/" && strings.HasPrefix(path, publicPath) {
 			return true
 		}
 	}
@@ -495,29 +1158,23 @@ func (g *Generator) isPublicEndpoint(path string) bool {
 	return false
 }
 
-// generateOperationID generates a unique operation ID
-func (g *Generator) generateOperationID(method, path string) string {
-	// Use path parser to generate consistent ID
-	return g.pathParser.GenerateHandlerName(method, path)
-}
-
 // generateSchemaReference creates a schema reference for registered schemas
 func (g *Generator) generateSchemaReference(method, path, schemaType string) spec.Schema {
 	// Create route key same as schema registry
 	routeKey := strings.ToUpper(method) + " " + path
-	
+
 	// Generate schema name using same logic as schema registry
 	cleanKey := strings.ReplaceAll(routeKey, " ", "")
 	cleanKey = strings.ReplaceAll(cleanKey, "/", "_")
 	cleanKey = strings.ReplaceAll(cleanKey, ":", "")
-	
+
 	// Capitalize first letter
 	if len(cleanKey) > 0 {
 		cleanKey = strings.ToUpper(cleanKey[:1]) + cleanKey[1:]
 	}
-	
+
 	schemaName := cleanKey + schemaType
-	
+
 	return spec.Schema{
 		Ref: "#/components/schemas/" + schemaName,
 	}
@@ -551,11 +1208,68 @@ func (g *Generator) addOperationToSpec(method, path string, operation spec.Opera
 	g.spec.Paths[path] = pathItem
 }
 
+// OverridePath sets the path-level summary and description of a path, see OverrideManager.OverridePath
+//
+// Example:
+//
+//	g.OverridePath("/users/{id}").Summary("A single user")
+func (g *Generator) OverridePath(path string) *PathOverride {
+	return g.overrideManager.OverridePath(path)
+}
+
+// Override sets the media types and examples of a single route, see OverrideManager.OverrideRoute
+//
+// Example:
+//
+//	g.Override("POST", "/upload").RequestContentType("multipart/form-data")
+//	g.Override("GET", "/reports/:id").ResponseContentType(200, "text/csv")
+//	g.Override("POST", "/users").ResponseExample(422, ValidationError{Field: "email", Message: "is invalid"})
+func (g *Generator) Override(method, path string) *RouteOverride {
+	return g.overrideManager.OverrideRoute(method, path)
+}
+
+// describePathItems sets the summary and description of every path item
+//
+// Tools that list paths show the path-level summary, it defaults to the one of the
+// path's first operation. OverridePath takes precedence, field by field.
+func (g *Generator) describePathItems() {
+	for path, pathItem := range g.spec.Paths {
+		for _, operation := range []*spec.Operation{
+			pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Patch,
+			pathItem.Delete, pathItem.Head, pathItem.Options, pathItem.Trace,
+		} {
+			if operation != nil {
+				pathItem.Summary = operation.Summary
+				pathItem.Description = operation.Description
+				break
+			}
+		}
+
+		if override, exists := g.overrideManager.GetPathMetadata(path); exists {
+			if override.Summary != "" {
+				pathItem.Summary = override.Summary
+			}
+			if override.Description != "" {
+				pathItem.Description = override.Description
+			}
+		}
+		g.spec.Paths[path] = pathItem
+	}
+}
+
 // generateTagsFromSet generates tag definitions from collected tags
+//
+// Tags are emitted in alphabetical order so repeated generation of the same
+// API produces byte-identical output (maps are already sorted by encoding/json).
 func (g *Generator) generateTagsFromSet(tags map[string]bool) []spec.Tag {
-	var result []spec.Tag
-
+	tagNames := make([]string, 0, len(tags))
 	for tagName := range tags {
+		tagNames = append(tagNames, tagName)
+	}
+	sort.Strings(tagNames)
+
+	result := make([]spec.Tag, 0, len(tagNames))
+	for _, tagName := range tagNames {
 		tag := spec.Tag{
 			Name:        tagName,
 			Description: g.generateTagDescription(tagName),
@@ -594,7 +1308,7 @@ func (g *Generator) generateTagDescription(tagName string) string {
 
 // generateSecuritySchemes generates security scheme definitions
 func (g *Generator) generateSecuritySchemes() map[string]spec.SecurityScheme {
-	return map[string]spec.SecurityScheme{
+	schemes := map[string]spec.SecurityScheme{
 		"bearerAuth": {
 			Type:         "http",
 			Scheme:       "bearer",
@@ -602,6 +1316,8 @@ func (g *Generator) generateSecuritySchemes() map[string]spec.SecurityScheme {
 			Description:  "JWT Bearer token authentication",
 		},
 	}
+	maps.Copy(schemes, g.securitySchemes)
+	return schemes
 }
 
 // ServeSwaggerUI serves the Swagger UI and OpenAPI spec
@@ -612,30 +1328,53 @@ func (g *Generator) ServeSwaggerUI(h integration.HTTPServer) error {
 		return fmt.Errorf("failed to generate OpenAPI spec: %w", err)
 	}
 
-	// Serve OpenAPI spec JSON
-	h.GET("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(spec)
-	})
+	// Serialize once so every request shares the same body, ETag and gzip payload
+	document, err := newSpecDocument(spec)
+	if err != nil {
+		return err
+	}
+	g.document.Store(document)
+
+	// Serve OpenAPI spec JSON, AddRoute and RefreshRoutes swap the document behind it. HEAD
+	// lets clients check the ETag without downloading the spec.
+	serveSpec := func(w http.ResponseWriter, r *http.Request) {
+		g.document.Load().ServeHTTP(w, r)
+	}
+	h.GET(g.config.SpecPath, serveSpec)
+	h.HEAD(g.config.SpecPath, serveSpec)
 
 	// Serve Swagger UI
-	h.GET("/docs", func(w http.ResponseWriter, r *http.Request) {
+	h.GET(g.config.DocsPath, func(w http.ResponseWriter, r *http.Request) {
 		html := g.generateSwaggerHTML()
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(html))
 	})
 
-	g.logger.Info("Swagger UI endpoints registered", "spec_url", "/openapi.json", "docs_url", "/docs")
+	g.logger.Info("Swagger UI endpoints registered", "spec_url", g.config.SpecPath, "docs_url", g.config.DocsPath)
 
 	return nil
 }
 
+// SpecChecksum returns the SHA-256 checksum of the served spec document
+//
+// The checksum is also used as the ETag of the spec endpoint. It is empty until
+// ServeSwaggerUI has been called.
+func (g *Generator) SpecChecksum() string {
+	document := g.document.Load()
+	if document == nil {
+		return ""
+	}
+	return document.Checksum()
+}
+
 // generateSwaggerHTML generates the Swagger UI HTML
 func (g *Generator) generateSwaggerHTML() string {
-	return `
+	return strings.ReplaceAll(swaggerHTMLTemplate, "{{SPEC_URL}}", template.JSEscapeString(g.config.SpecPath))
+}
+
+// swaggerHTMLTemplate is the Swagger UI page, {{SPEC_URL}} is replaced with the spec path
+const swaggerHTMLTemplate = `
 <!DOCTYPE html>
 <html lang="en">
 <head>
@@ -679,7 +1418,7 @@ func (g *Generator) generateSwaggerHTML() string {
             console.log('Initializing Swagger UI...');
             
             const ui = SwaggerUIBundle({
-                url: '/openapi.json',
+                url: '{{SPEC_URL}}',
                 dom_id: '#swagger-ui',
                 deepLinking: true,
                 presets: [
@@ -701,8 +1440,8 @@ func (g *Generator) generateSwaggerHTML() string {
                 }
             });
 
-            // Test if openapi.json is accessible
-            fetch('/openapi.json')
+            // Test if the spec is accessible
+            fetch('{{SPEC_URL}}')
                 .then(response => {
                     if (!response.ok) {
                         throw new Error('HTTP ' + response.status + ': ' + response.statusText);
@@ -719,4 +1458,3 @@ func (g *Generator) generateSwaggerHTML() string {
     </script>
 </body>
 </html>`
-}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// GraphQLResponseContentType is the media type of GraphQL-over-HTTP responses, next to application/json
+const GraphQLResponseContentType = "application/graphql-response+json"
+
+// isGraphQLRoute reports whether a route serves GraphQL by convention, a GET or POST on a path ending in /graphql
+func isGraphQLRoute(route spec.RouteInfo) bool {
+	if !strings.EqualFold(route.Method, http.MethodGet) && !strings.EqualFold(route.Method, http.MethodPost) {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(route.Path, "/")), "/graphql")
+}
+
+// documentsGraphQL reports whether a documented route serves GraphQL, its envelopes are then components
+func (g *Generator) documentsGraphQL() bool {
+	for _, analyzed := range g.routes {
+		if !analyzed.hidden && analyzed.handlerSchema.GraphQL {
+			return true
+		}
+	}
+	return false
+}
+
+// applyGraphQLDocumentation documents a route with the GraphQL-over-HTTP request and response envelopes
+//
+// POST routes take the request envelope as body, GET routes its members as query parameters
+// with JSON-encoded variables and extensions. Request and response overrides are kept.
+func (g *Generator) applyGraphQLDocumentation(route spec.RouteInfo, operation *spec.Operation) {
+	if operation.Description == "" {
+		operation.Description = "GraphQL endpoint, the operations it executes are described by its GraphQL schema"
+	}
+
+	if _, overridden := g.schemaRegistry.GetRequestOverride(route.Method, route.Path); !overridden {
+		if strings.EqualFold(route.Method, http.MethodGet) {
+			operation.RequestBody = nil
+			for _, parameter := range graphQLQueryParameters() {
+				if !hasParameter(operation.Parameters, parameter.Name, parameter.In) {
+					operation.Parameters = append(operation.Parameters, parameter)
+				}
+			}
+		} else {
+			operation.RequestBody = &spec.RequestBody{
+				Required: true,
+				Content: map[string]spec.MediaType{
+					"application/json": {Schema: spec.Schema{Ref: "#/components/schemas/" + analyzer.GraphQLRequestSchemaName}},
+				},
+			}
+		}
+	}
+
+	envelope := spec.MediaType{Schema: spec.Schema{Ref: "#/components/schemas/" + analyzer.GraphQLResponseSchemaName}}
+	success := operation.Responses["200"]
+	success.Description = "GraphQL response, execution errors are listed in errors"
+	success.Content = map[string]spec.MediaType{
+		"application/json":         envelope,
+		GraphQLResponseContentType: envelope,
+	}
+	operation.Responses["200"] = success
+}
+
+// graphQLQueryParameters returns the members of the GraphQL request envelope sent as query parameters of a GET
+func graphQLQueryParameters() []spec.Parameter {
+	return []spec.Parameter{
+		{Name: "query", In: "query", Required: true, Description: "GraphQL document with the operations to execute", Schema: spec.Schema{Type: "string"}},
+		{Name: "operationName", In: "query", Description: "Operation to execute when the document has several", Schema: spec.Schema{Type: "string"}},
+		{Name: "variables", In: "query", Description: "JSON-encoded values of the variables of the operation", Schema: spec.Schema{Type: "string"}},
+		{Name: "extensions", In: "query", Description: "JSON-encoded request extensions", Schema: spec.Schema{Type: "string"}},
+	}
+}
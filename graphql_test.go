@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLDocumentation(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "POST", Path: "/graphql", HandlerName: "GraphQL"},
+		{Method: "GET", Path: "/api/graphql", HandlerName: "Playground"},
+		{Method: "POST", Path: "/query", HandlerName: "Query"},
+		{Method: "GET", Path: "/users", HandlerName: "ListUsers"},
+	}
+	generator := newTestGenerator(t, routes)
+	// Detected from the handler source when the path gives no hint
+	generator.schemaRegistry.RegisterHandlerSchema("Query", analyzer.HandlerSchema{GraphQL: true})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, analyzer.GraphQLRequestSchema(), openAPISpec.Components.Schemas[analyzer.GraphQLRequestSchemaName])
+	assert.Equal(t, analyzer.GraphQLResponseSchema(), openAPISpec.Components.Schemas[analyzer.GraphQLResponseSchemaName])
+
+	for _, operation := range []*spec.Operation{openAPISpec.Paths["/graphql"].Post, openAPISpec.Paths["/query"].Post} {
+		if !assert.NotNil(t, operation) || !assert.NotNil(t, operation.RequestBody) {
+			continue
+		}
+		assert.Equal(t, "#/components/schemas/GraphQLRequest", operation.RequestBody.Content["application/json"].Schema.Ref)
+		content := operation.Responses["200"].Content
+		assert.Equal(t, "#/components/schemas/GraphQLResponse", content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/GraphQLResponse", content[GraphQLResponseContentType].Schema.Ref)
+	}
+
+	get := openAPISpec.Paths["/api/graphql"].Get
+	if assert.NotNil(t, get) {
+		assert.Nil(t, get.RequestBody)
+		assert.True(t, hasParameter(get.Parameters, "query", "query"))
+		assert.True(t, hasParameter(get.Parameters, "variables", "query"))
+	}
+
+	users := openAPISpec.Paths["/users"].Get
+	if assert.NotNil(t, users) {
+		assert.NotContains(t, users.Responses["200"].Content, GraphQLResponseContentType)
+	}
+}
+
+func TestExcludeGraphQL(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "POST", Path: "/graphql", HandlerName: "GraphQL"},
+		{Method: "GET", Path: "/users", HandlerName: "ListUsers"},
+	}
+	cfg := NewConfig(WithExcludeGraphQL(true))
+	cfg.SchemaDir = ""
+
+	openAPISpec, err := newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotContains(t, openAPISpec.Paths, "/graphql")
+	assert.Contains(t, openAPISpec.Paths, "/users")
+	assert.NotContains(t, openAPISpec.Components.Schemas, analyzer.GraphQLRequestSchemaName)
+}
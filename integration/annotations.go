@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/gin-gonic/gin"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// RouteAnnotation documents a route where it is registered, e.g. from the fields of a Route
+// struct an application registers its routes from
+type RouteAnnotation struct {
+	Tags        []string
+	Summary     string
+	Description string
+	Deprecated  bool
+}
+
+// RouteAnnotations holds the annotations of routes by method and path as registered with the
+// framework, e.g. /users/:id. Discoverers given them with SetAnnotations copy them into the
+// RouteInfo of the routes they discover.
+type RouteAnnotations struct {
+	mu     sync.RWMutex
+	routes map[string]RouteAnnotation
+}
+
+// NewRouteAnnotations creates an empty set of route annotations
+func NewRouteAnnotations() *RouteAnnotations {
+	return &RouteAnnotations{routes: make(map[string]RouteAnnotation)}
+}
+
+// Annotate records the annotation of a route, replacing an earlier one
+func (a *RouteAnnotations) Annotate(method, path string, annotation RouteAnnotation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.routes[annotationKey(method, path)] = annotation
+}
+
+// Get returns the annotation of a route, if any
+func (a *RouteAnnotations) Get(method, path string) (RouteAnnotation, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	annotation, exists := a.routes[annotationKey(method, path)]
+	return annotation, exists
+}
+
+// Apply copies the annotation of a route into its RouteInfo, what the discoverer already set is kept
+func (a *RouteAnnotations) Apply(routeInfo *spec.RouteInfo) {
+	annotation, exists := a.Get(routeInfo.Method, routeInfo.Path)
+	if !exists {
+		return
+	}
+	if len(routeInfo.Tags) == 0 {
+		routeInfo.Tags = annotation.Tags
+	}
+	if routeInfo.Summary == "" {
+		routeInfo.Summary = annotation.Summary
+	}
+	if routeInfo.Description == "" {
+		routeInfo.Description = annotation.Description
+	}
+	routeInfo.Deprecated = routeInfo.Deprecated || annotation.Deprecated
+}
+
+// GinHandle registers a Gin route and records its annotation under its full path
+//
+// Example:
+//
+//	annotations.GinHandle(api, http.MethodGet, "/users/:id", integration.RouteAnnotation{
+//		Tags:    []string{"users"},
+//		Summary: "Get a user",
+//	}, handler.GetUser)
+func (a *RouteAnnotations) GinHandle(routes gin.IRoutes, method, relativePath string, annotation RouteAnnotation, handlers ...gin.HandlerFunc) gin.IRoutes {
+	a.Annotate(method, fullRoutePath(routes, relativePath), annotation)
+	return routes.Handle(method, relativePath, handlers...)
+}
+
+// HertzHandle registers a Hertz route and records its annotation under its full path
+func (a *RouteAnnotations) HertzHandle(routes route.IRoutes, method, relativePath string, annotation RouteAnnotation, handlers ...app.HandlerFunc) route.IRoutes {
+	a.Annotate(method, fullRoutePath(routes, relativePath), annotation)
+	return routes.Handle(method, relativePath, handlers...)
+}
+
+// fullRoutePath joins the base path of a router group and the path of a route registered on it
+func fullRoutePath(routes any, relativePath string) string {
+	group, ok := routes.(interface{ BasePath() string })
+	if !ok || relativePath == "" {
+		return relativePath
+	}
+	joined := path.Join(group.BasePath(), relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// annotationKey is the key of a route in the annotations
+func annotationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// TestRouteAnnotations_Gin tests that annotations recorded at registration reach the discovered routes
+func TestRouteAnnotations_Gin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	annotations := NewRouteAnnotations()
+
+	api := engine.Group("/api")
+	annotations.GinHandle(api, http.MethodGet, "/users/:id", RouteAnnotation{
+		Tags:       []string{"users", "accounts"},
+		Summary:    "Get a user",
+		Deprecated: true,
+	}, sampleGinHandler)
+	engine.GET("/health", sampleGinHandler)
+
+	discoverer, err := NewAutoDiscoverer(engine)
+	require.NoError(t, err)
+	discoverer.SetAnnotations(annotations)
+
+	routes, err := discoverer.DiscoverRoutes()
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+
+	byPath := make(map[string]spec.RouteInfo)
+	for _, route := range routes {
+		byPath[route.Path] = route
+	}
+	assert.Equal(t, []string{"users", "accounts"}, byPath["/api/users/:id"].Tags)
+	assert.Equal(t, "Get a user", byPath["/api/users/:id"].Summary)
+	assert.True(t, byPath["/api/users/:id"].Deprecated)
+	assert.Empty(t, byPath["/health"].Summary)
+}
+
+// TestRouteAnnotations_Hertz tests annotations of routes registered on Hertz groups
+func TestRouteAnnotations_Hertz(t *testing.T) {
+	h := server.New()
+	annotations := NewRouteAnnotations()
+
+	v1 := h.Group("/v1")
+	annotations.HertzHandle(v1.Group("/orders"), http.MethodPost, "/", RouteAnnotation{
+		Description: "Places an order",
+	}, sampleHandler)
+
+	discoverer := NewHertzRouteDiscoverer(h)
+	discoverer.SetAnnotations(annotations)
+	routes, err := discoverer.DiscoverRoutes()
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	assert.Equal(t, "/v1/orders/", routes[0].Path)
+	assert.Equal(t, "Places an order", routes[0].Description)
+}
+
+// TestRouteAnnotations_Apply tests that what a discoverer set itself is kept
+func TestRouteAnnotations_Apply(t *testing.T) {
+	annotations := NewRouteAnnotations()
+	annotations.Annotate("get", "/users", RouteAnnotation{Tags: []string{"users"}, Summary: "List users"})
+
+	route := spec.RouteInfo{Method: "GET", Path: "/users", Summary: "All users"}
+	annotations.Apply(&route)
+
+	assert.Equal(t, []string{"users"}, route.Tags)
+	assert.Equal(t, "All users", route.Summary)
+}
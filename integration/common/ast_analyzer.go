@@ -2,12 +2,17 @@ package common
 
 import (
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/zainokta/openapi-gen/analyzer"
@@ -15,8 +20,13 @@ import (
 
 // ASTAnalyzer provides utilities for AST-based handler analysis
 type ASTAnalyzer struct {
-	typeRegistry *analyzer.DynamicTypeRegistry
-	schemaGen    *analyzer.SchemaGenerator
+	typeRegistry  *analyzer.DynamicTypeRegistry
+	schemaGen     *analyzer.SchemaGenerator
+	vendorSources bool           // Search the vendor/ directories of the local modules
+	buildContext  *build.Context // Source files excluded by its build constraints are skipped
+	sourceDirs    []string       // Directories sources are searched from, the working directory when empty
+	sourceModules []ModuleRoot   // Module roots found from sourceDirs
+	sourceExclude []string       // Glob patterns of source paths that are never analyzed
 }
 
 // NewASTAnalyzer creates a new AST analyzer
@@ -24,6 +34,7 @@ func NewASTAnalyzer() *ASTAnalyzer {
 	return &ASTAnalyzer{
 		typeRegistry: analyzer.NewDynamicTypeRegistry(),
 		schemaGen:    analyzer.NewSchemaGenerator(),
+		buildContext: &build.Default,
 	}
 }
 
@@ -32,6 +43,162 @@ func (a *ASTAnalyzer) GetTypeRegistry() *analyzer.DynamicTypeRegistry {
 	return a.typeRegistry
 }
 
+// SetUnions shares a union registry with the schema generator used for resolved handler types
+func (a *ASTAnalyzer) SetUnions(unions *analyzer.UnionRegistry) {
+	a.schemaGen.SetUnions(unions)
+}
+
+// SetVendorSources controls whether packages are also looked up in vendor/, for go mod vendor builds
+func (a *ASTAnalyzer) SetVendorSources(enabled bool) {
+	a.vendorSources = enabled
+}
+
+// SetBuildTags sets the build tags source files are matched against, GOOS and GOARCH are the running ones
+//
+// Files guarded by //go:build lines or _GOOS/_GOARCH suffixes that the build would not
+// compile are skipped, like go build -tags.
+func (a *ASTAnalyzer) SetBuildTags(tags []string) {
+	buildContext := build.Default
+	buildContext.BuildTags = slices.Clone(tags)
+	a.buildContext = &buildContext
+}
+
+// SetSourceDirs sets the directories sources are searched from, instead of the working directory
+//
+// Relative directories are resolved against the working directory. The modules holding the
+// directories, or the modules of their workspace, are searched first.
+func (a *ASTAnalyzer) SetSourceDirs(dirs []string) {
+	fs := NewFileSystemUtilities()
+	a.sourceDirs = nil
+	a.sourceModules = nil
+	for _, dir := range dirs {
+		dir = fs.RealPath(dir)
+		a.sourceDirs = append(a.sourceDirs, dir)
+		for _, root := range FindModuleRoots(dir) {
+			if !slices.Contains(a.sourceModules, root) {
+				a.sourceModules = append(a.sourceModules, root)
+			}
+		}
+	}
+}
+
+// SetSourceExclude sets glob patterns of source files and directories that are never analyzed
+//
+// Patterns use path.Match syntax and are matched against every trailing part of a path:
+// "*_gen.go" excludes generated files anywhere, "internal/mocks" excludes that directory and
+// everything below it.
+func (a *ASTAnalyzer) SetSourceExclude(patterns []string) {
+	a.sourceExclude = nil
+	for _, pattern := range patterns {
+		if pattern = strings.Trim(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/"); pattern != "" {
+			a.sourceExclude = append(a.sourceExclude, pattern)
+		}
+	}
+}
+
+// ExcludedSourcePath reports whether a source file or directory matches an exclusion pattern
+func (a *ASTAnalyzer) ExcludedSourcePath(sourcePath string) bool {
+	if len(a.sourceExclude) == 0 {
+		return false
+	}
+	elements := strings.Split(strings.Trim(filepath.ToSlash(sourcePath), "/"), "/")
+	for _, pattern := range a.sourceExclude {
+		// Patterns match a run of path elements, a matched directory excludes what it holds
+		for start := range elements {
+			for end := start + 1; end <= len(elements); end++ {
+				if match, _ := path.Match(pattern, strings.Join(elements[start:end], "/")); match {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MatchSourceFile reports whether a Go file is compiled in the analyzer's build context, test files
+// and excluded files never are
+func (a *ASTAnalyzer) MatchSourceFile(dir, name string) bool {
+	if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || a.ExcludedSourcePath(filepath.Join(dir, name)) {
+		return false
+	}
+	match, err := a.buildContext.MatchFile(dir, name)
+	return err == nil && match
+}
+
+// searchDirs returns the directories sources are searched from
+func (a *ASTAnalyzer) searchDirs() []string {
+	if len(a.sourceDirs) > 0 {
+		return a.sourceDirs
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	return []string{wd}
+}
+
+// SourceFilesAvailable reports whether Go sources can be analyzed, they are usually missing in
+// Docker images and other deployments that only ship the binary
+//
+// Configured source directories are searched at any depth, the working directory only at its
+// top level and in internal/, pkg/ and cmd/.
+func (a *ASTAnalyzer) SourceFilesAvailable() bool {
+	if len(a.sourceDirs) > 0 {
+		for _, dir := range a.sourceDirs {
+			if a.containsGoFiles(dir) {
+				return true
+			}
+		}
+	} else if wd, err := os.Getwd(); err == nil {
+		// Check for .go files in current directory and common subdirectories
+		for _, dir := range []string{wd, filepath.Join(wd, "internal"), filepath.Join(wd, "pkg"), filepath.Join(wd, "cmd")} {
+			if a.FindGoFilesInDirectory(dir) != "" {
+				return true
+			}
+		}
+	}
+
+	// Started from a directory without sources inside a module or workspace, e.g. a monorepo service
+	return len(a.moduleRoots()) > 0
+}
+
+// containsGoFiles reports whether a directory or any directory below it holds a source file
+//
+// Hidden, vendor/, node_modules/ and excluded directories are skipped.
+func (a *ASTAnalyzer) containsGoFiles(root string) bool {
+	found := false
+	_ = filepath.WalkDir(root, func(current string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable directories
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if current != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || a.ExcludedSourcePath(current)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if a.MatchSourceFile(filepath.Dir(current), entry.Name()) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// moduleRoots returns the module roots package sources are resolved against
+//
+// The modules of the configured source directories come first. Vendored modules come before
+// the module cache, a vendored build uses the vendor/ copy.
+func (a *ASTAnalyzer) moduleRoots() []ModuleRoot {
+	if !a.vendorSources {
+		return slices.Concat(a.sourceModules, SourceModuleRoots())
+	}
+	local := slices.Concat(a.sourceModules, localModuleRoots())
+	return slices.Concat(local, VendorModuleRoots(local), DependencyModuleRoots())
+}
+
 // FindHandlerSourceFile attempts to find the source file containing the handler for library usage
 func (a *ASTAnalyzer) FindHandlerSourceFile(handlerFuncName string) string {
 	// Extract package path from handler function name
@@ -51,6 +218,19 @@ func (a *ASTAnalyzer) FindHandlerSourceFile(handlerFuncName string) string {
 	return a.FindSourceFileInConsumerModule(pkgPath)
 }
 
+// FindHandlerSourceFileForPC finds the source file of the handler function at pc
+//
+// The file recorded in the binary is used when it exists on disk, it does not depend on
+// the working directory. Otherwise the package of handlerFuncName is searched for.
+func (a *ASTAnalyzer) FindHandlerSourceFileForPC(pc uintptr, handlerFuncName string) string {
+	if pc != 0 {
+		if sourceFile := SourceFileForPC(pc); sourceFile != "" && !a.ExcludedSourcePath(sourceFile) {
+			return sourceFile
+		}
+	}
+	return a.FindHandlerSourceFile(handlerFuncName)
+}
+
 // ExtractPackagePathFromFunction extracts clean package path from function name
 func (a *ASTAnalyzer) ExtractPackagePathFromFunction(handlerFuncName string) string {
 	// Handle different function name patterns:
@@ -79,10 +259,11 @@ func (a *ASTAnalyzer) ExtractPackagePathFromFunction(handlerFuncName string) str
 
 // FindSourceFileInConsumerModule finds source files in the consuming application's module
 func (a *ASTAnalyzer) FindSourceFileInConsumerModule(pkgPath string) string {
-	// Get the consuming application's working directory
-	wd, err := os.Getwd()
-	if err != nil {
-		return ""
+	// Modules of the workspace, or the module, around the working directory and the executable
+	if pkgDir := ResolvePackageDir(a.moduleRoots(), pkgPath); pkgDir != "" {
+		if sourceFile := a.FindGoFilesInDirectory(pkgDir); sourceFile != "" {
+			return sourceFile
+		}
 	}
 
 	// Get the consuming application's module name
@@ -103,26 +284,26 @@ func (a *ASTAnalyzer) FindSourceFileInConsumerModule(pkgPath string) string {
 		}
 	}
 
-	// Convert package path to file system path
-	pkgDir := filepath.Join(wd, filepath.FromSlash(relativePkgPath))
-
-	// Strategy 1: Look for .go files in the exact package directory
-	if sourceFile := a.FindGoFilesInDirectory(pkgDir); sourceFile != "" {
-		return sourceFile
-	}
+	// Search from the configured source directories, or the consuming application's working directory
+	for _, dir := range a.searchDirs() {
+		// Strategy 1: Look for .go files in the exact package directory
+		if sourceFile := a.FindGoFilesInDirectory(filepath.Join(dir, filepath.FromSlash(relativePkgPath))); sourceFile != "" {
+			return sourceFile
+		}
 
-	// Strategy 2: Try common handler directory patterns
-	commonPatterns := []string{
-		filepath.Join(wd, "handlers"),
-		filepath.Join(wd, "internal", "handlers"),
-		filepath.Join(wd, "pkg", "handlers"),
-		filepath.Join(wd, "api", "handlers"),
-		filepath.Join(wd, "internal", "api", "handlers"),
-	}
+		// Strategy 2: Try common handler directory patterns
+		commonPatterns := []string{
+			filepath.Join(dir, "handlers"),
+			filepath.Join(dir, "internal", "handlers"),
+			filepath.Join(dir, "pkg", "handlers"),
+			filepath.Join(dir, "api", "handlers"),
+			filepath.Join(dir, "internal", "api", "handlers"),
+		}
 
-	for _, pattern := range commonPatterns {
-		if sourceFile := a.FindGoFilesInDirectory(pattern); sourceFile != "" {
-			return sourceFile
+		for _, pattern := range commonPatterns {
+			if sourceFile := a.FindGoFilesInDirectory(pattern); sourceFile != "" {
+				return sourceFile
+			}
 		}
 	}
 
@@ -202,25 +383,12 @@ func (a *ASTAnalyzer) FindGoModPath(startDir string) string {
 
 // GetModuleNameFromGoMod extracts module name from go.mod file
 func (a *ASTAnalyzer) GetModuleNameFromGoMod(goModPath string) string {
-	content, err := os.ReadFile(goModPath)
-	if err != nil {
-		return ""
-	}
-
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimPrefix(line, "module ")
-		}
-	}
-
-	return ""
+	return NewFileSystemUtilities().GetModuleNameFromGoMod(goModPath)
 }
 
 // FindGoFilesInDirectory looks for Go source files in a directory
 func (a *ASTAnalyzer) FindGoFilesInDirectory(dir string) string {
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) || a.ExcludedSourcePath(dir) {
 		return ""
 	}
 
@@ -230,7 +398,7 @@ func (a *ASTAnalyzer) FindGoFilesInDirectory(dir string) string {
 	}
 
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".go") {
+		if !file.IsDir() && a.MatchSourceFile(dir, file.Name()) {
 			return filepath.Join(dir, file.Name())
 		}
 	}
@@ -298,6 +466,14 @@ func (a *ASTAnalyzer) ExtractHertzHandlerTypes(methodDecl *ast.FuncDecl, sourceF
 		schema.ResponseSchema = a.schemaGen.GenerateSchemaFromType(respType)
 	}
 
+	schema.Stream = a.DetectStreamKind(methodDecl)
+	schema.FileResponse = a.DetectFileResponse(methodDecl)
+	schema.GraphQL = a.DetectGraphQL(methodDecl)
+	schema.Metrics = a.DetectPrometheusMetrics(methodDecl)
+	schema.ResponseFormats = a.DetectResponseFormats(methodDecl)
+	schema.NoRequestBody = schema.RequestSchema.Type == "" && !a.DetectRequestBody(methodDecl)
+	schema.Directives = a.HandlerDirectives(methodDecl)
+
 	return schema
 }
 
@@ -315,9 +491,484 @@ func (a *ASTAnalyzer) ExtractGinHandlerTypes(methodDecl *ast.FuncDecl, sourceFil
 		schema.ResponseSchema = a.schemaGen.GenerateSchemaFromType(respType)
 	}
 
+	schema.Stream = a.DetectStreamKind(methodDecl)
+	schema.FileResponse = a.DetectFileResponse(methodDecl)
+	schema.GraphQL = a.DetectGraphQL(methodDecl)
+	schema.Metrics = a.DetectPrometheusMetrics(methodDecl)
+	schema.ResponseFormats = a.DetectResponseFormats(methodDecl)
+	schema.NoRequestBody = schema.RequestSchema.Type == "" && !a.DetectRequestBody(methodDecl)
+	schema.Directives = a.HandlerDirectives(methodDecl)
+
 	return schema
 }
 
+// DetectStreamKind detects handlers that upgrade to WebSocket or stream server-sent events
+//
+// WebSocket handlers are recognized by an Upgrade call on a gorilla websocket.Upgrader or a
+// hertz-contrib websocket.HertzUpgrader. SSE handlers are recognized by Gin's SSEvent on the
+// request context, the hertz-contrib/sse package or a text/event-stream Content-Type header.
+// Other handlers writing their response in chunks are recognized by Gin's Stream or Hertz's
+// SetBodyStream on the request context, a Flush of the response writer, an http.Flusher type
+// assertion or an io.Copy to the response writer.
+func (a *ASTAnalyzer) DetectStreamKind(methodDecl *ast.FuncDecl) analyzer.StreamKind {
+	if methodDecl == nil || methodDecl.Body == nil {
+		return analyzer.StreamNone
+	}
+
+	contextName := contextParamName(methodDecl)
+	kind := analyzer.StreamNone
+	chunked := false
+	ast.Inspect(methodDecl.Body, func(n ast.Node) bool {
+		// WebSocket wins over SSE, stop once an upgrade has been found
+		if kind == analyzer.StreamWebSocket {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if pkgIdent, ok := node.X.(*ast.Ident); ok && pkgIdent.Obj == nil {
+				if strings.HasSuffix(a.typeRegistry.GetPackagePath(pkgIdent.Name), "/sse") {
+					kind = analyzer.StreamSSE
+				}
+			}
+		case *ast.TypeAssertExpr:
+			if a.isPackageSelector(node.Type, "net/http", "Flusher") {
+				chunked = true
+			}
+		case *ast.CallExpr:
+			selExpr, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch {
+			case selExpr.Sel.Name == "Upgrade" && a.isUpgraderExpr(selExpr.X):
+				kind = analyzer.StreamWebSocket
+			case selExpr.Sel.Name == "SSEvent" && isIdentNamed(selExpr.X, contextName):
+				kind = analyzer.StreamSSE
+			case isEventStreamContentType(selExpr.Sel.Name, node.Args):
+				kind = analyzer.StreamSSE
+			case (selExpr.Sel.Name == "Stream" || selExpr.Sel.Name == "SetBodyStream") && isIdentNamed(selExpr.X, contextName):
+				chunked = true
+			case selExpr.Sel.Name == "Flush" && (isIdentNamed(selExpr.X, contextName) || isResponseWriterExpr(selExpr.X, contextName)):
+				chunked = true
+			case a.isPackageSelector(selExpr, "io", "Copy", "CopyBuffer", "CopyN") && len(node.Args) > 0 && isResponseWriterExpr(node.Args[0], contextName):
+				chunked = true
+			}
+		}
+		return true
+	})
+
+	// SSE streams are written in chunks too, they are documented as events
+	if kind == analyzer.StreamNone && chunked {
+		return analyzer.StreamChunked
+	}
+	return kind
+}
+
+// isPackageSelector reports whether expr selects one of names from the imported package path,
+// e.g. http.Flusher or io.Copy
+func (a *ASTAnalyzer) isPackageSelector(expr ast.Expr, pkgPath string, names ...string) bool {
+	selExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || !slices.Contains(names, selExpr.Sel.Name) {
+		return false
+	}
+	pkgIdent, ok := selExpr.X.(*ast.Ident)
+	return ok && pkgIdent.Obj == nil && a.typeRegistry.GetPackagePath(pkgIdent.Name) == pkgPath
+}
+
+// isResponseWriterExpr reports whether expr is the response writer of a handler: Gin's
+// c.Writer, Hertz's c.Response.BodyWriter() or an http.ResponseWriter parameter
+func isResponseWriterExpr(expr ast.Expr, contextName string) bool {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return e.Sel.Name == "Writer" && isIdentNamed(e.X, contextName)
+	case *ast.CallExpr:
+		selExpr, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || selExpr.Sel.Name != "BodyWriter" {
+			return false
+		}
+		response, ok := selExpr.X.(*ast.SelectorExpr)
+		return ok && response.Sel.Name == "Response" && isIdentNamed(response.X, contextName)
+	case *ast.Ident:
+		if e.Obj == nil {
+			return false
+		}
+		field, ok := e.Obj.Decl.(*ast.Field)
+		if !ok {
+			return false
+		}
+		typeExpr, ok := field.Type.(*ast.SelectorExpr)
+		return ok && typeExpr.Sel.Name == "ResponseWriter"
+	}
+	return false
+}
+
+// isEventStreamContentType matches calls that set a text/event-stream Content-Type, e.g.
+// c.Header("Content-Type", "text/event-stream"), w.Header().Set(...) or c.SetContentType(...)
+func isEventStreamContentType(method string, args []ast.Expr) bool {
+	var value ast.Expr
+	switch {
+	case (method == "SetContentType" || method == "ContentType") && len(args) == 1:
+		value = args[0]
+	case (method == "Header" || method == "Set" || method == "Add" || method == "SetHeader") && len(args) == 2:
+		key, ok := args[0].(*ast.BasicLit)
+		if !ok || key.Kind != token.STRING || !strings.EqualFold(strings.Trim(key.Value, "\"`"), "Content-Type") {
+			return false
+		}
+		value = args[1]
+	default:
+		return false
+	}
+	lit, ok := value.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING && strings.Contains(lit.Value, "text/event-stream")
+}
+
+// isUpgraderExpr reports whether expr is a websocket upgrader, following the declarations of
+// variables, parameters and struct fields in the handler's file
+func (a *ASTAnalyzer) isUpgraderExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return a.isUpgraderExpr(e.X)
+	case *ast.UnaryExpr:
+		return a.isUpgraderExpr(e.X)
+	case *ast.CompositeLit:
+		return a.isUpgraderType(e.Type)
+	case *ast.Ident:
+		if e.Obj == nil {
+			return false
+		}
+		switch decl := e.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			if decl.Type != nil {
+				return a.isUpgraderType(decl.Type)
+			}
+			for i, name := range decl.Names {
+				if name.Name == e.Name && i < len(decl.Values) {
+					return a.isUpgraderExpr(decl.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range decl.Lhs {
+				if isIdentNamed(lhs, e.Name) && i < len(decl.Rhs) && len(decl.Lhs) == len(decl.Rhs) {
+					return a.isUpgraderExpr(decl.Rhs[i])
+				}
+			}
+		case *ast.Field:
+			return a.isUpgraderType(decl.Type)
+		}
+	case *ast.SelectorExpr:
+		// h.upgrader, a field of the receiver's struct type
+		owner, ok := e.X.(*ast.Ident)
+		if !ok || owner.Obj == nil {
+			return false
+		}
+		field, ok := owner.Obj.Decl.(*ast.Field)
+		if !ok {
+			return false
+		}
+		ownerType := field.Type
+		if star, ok := ownerType.(*ast.StarExpr); ok {
+			ownerType = star.X
+		}
+		typeIdent, ok := ownerType.(*ast.Ident)
+		if !ok || typeIdent.Obj == nil {
+			return false
+		}
+		typeSpec, ok := typeIdent.Obj.Decl.(*ast.TypeSpec)
+		if !ok {
+			return false
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, structField := range structType.Fields.List {
+			for _, name := range structField.Names {
+				if name.Name == e.Sel.Name {
+					return a.isUpgraderType(structField.Type)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isUpgraderType matches websocket.Upgrader (gorilla) and websocket.HertzUpgrader (hertz-contrib), or pointers to them
+func (a *ASTAnalyzer) isUpgraderType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	selExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || (selExpr.Sel.Name != "Upgrader" && selExpr.Sel.Name != "HertzUpgrader") {
+		return false
+	}
+	pkgIdent, ok := selExpr.X.(*ast.Ident)
+	return ok && strings.HasSuffix(a.typeRegistry.GetPackagePath(pkgIdent.Name), "websocket")
+}
+
+// contextParamName returns the name of a handler's request context, the last parameter:
+// c in func(ctx, c) and func(c)
+func contextParamName(methodDecl *ast.FuncDecl) string {
+	if params := methodDecl.Type.Params.List; len(params) > 0 {
+		if names := params[len(params)-1].Names; len(names) > 0 {
+			if name := names[len(names)-1].Name; name != "_" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// isIdentNamed reports whether expr is the identifier name
+func isIdentNamed(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && name != "" && ident.Name == name
+}
+
+// DetectFileResponse detects handlers that respond with a file download
+//
+// Recognizes File, FileAttachment and FileFromFS on the handler's request context (Gin
+// and Hertz) as well as http.ServeFile and http.ServeContent. Methods of the same name on
+// other receivers, e.g. store.File(id), are not downloads.
+func (a *ASTAnalyzer) DetectFileResponse(methodDecl *ast.FuncDecl) bool {
+	if methodDecl == nil || methodDecl.Body == nil {
+		return false
+	}
+
+	contextName := contextParamName(methodDecl)
+	found := false
+	ast.Inspect(methodDecl.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch selExpr.Sel.Name {
+		case "File", "FileAttachment", "FileFromFS":
+			found = isIdentNamed(selExpr.X, contextName)
+		case "ServeFile", "ServeContent":
+			found = a.isPackageSelector(selExpr, "net/http", selExpr.Sel.Name)
+		}
+		return true
+	})
+
+	return found
+}
+
+// graphQLPackages are the path prefixes of GraphQL server packages: gqlgen, graph-gophers/graphql-go
+// and graphql-go with its handler package
+var graphQLPackages = []string{"github.com/99designs/gqlgen", "github.com/graph-gophers/graphql-go", "github.com/graphql-go/"}
+
+// DetectGraphQL detects handlers serving a GraphQL endpoint
+//
+// Recognizes handlers, or the functions building them, that use a package of a GraphQL
+// server such as gqlgen's handler.NewDefaultServer or graph-gophers' relay.Handler.
+func (a *ASTAnalyzer) DetectGraphQL(methodDecl *ast.FuncDecl) bool {
+	return a.usesPackage(methodDecl, graphQLPackages...)
+}
+
+// DetectPrometheusMetrics detects handlers, or the functions building them, serving
+// Prometheus metrics with promhttp, e.g. gin.WrapH(promhttp.Handler())
+func (a *ASTAnalyzer) DetectPrometheusMetrics(methodDecl *ast.FuncDecl) bool {
+	return a.usesPackage(methodDecl, "github.com/prometheus/client_golang/prometheus/promhttp")
+}
+
+// usesPackage reports whether a function body selects from an imported package whose path
+// starts with one of prefixes
+func (a *ASTAnalyzer) usesPackage(methodDecl *ast.FuncDecl, prefixes ...string) bool {
+	if methodDecl == nil || methodDecl.Body == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(methodDecl.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		selExpr, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkgIdent, ok := selExpr.X.(*ast.Ident)
+		if !ok || pkgIdent.Obj != nil {
+			return true
+		}
+		pkgPath := a.typeRegistry.GetPackagePath(pkgIdent.Name)
+		found = pkgPath != "" && slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(pkgPath, prefix)
+		})
+		return true
+	})
+
+	return found
+}
+
+// responseRenderers are the request context methods rendering a response, by the media type they write
+var responseRenderers = map[string]string{
+	"JSON": "application/json", "IndentedJSON": "application/json", "SecureJSON": "application/json",
+	"PureJSON": "application/json", "AsciiJSON": "application/json",
+	"XML": "application/xml", "IndentedXML": "application/xml",
+	"YAML": "application/yaml", "TOML": "application/toml",
+	"ProtoBuf": "application/x-protobuf",
+	"String":   "text/plain",
+}
+
+// dataContentTypeArgs are the request context methods writing raw data, by the index of their
+// content type argument: Data(code, contentType, data) and DataFromReader(code, length,
+// contentType, reader, headers)
+var dataContentTypeArgs = map[string]int{"Data": 1, "DataFromReader": 2}
+
+// DetectResponseFormats returns the media types a handler renders its response in, in the
+// order of the handler source
+//
+// Recognizes the renderers of the handler's request context (Gin and Hertz), see
+// responseRenderers, and Data or DataFromReader with a literal content type such as
+// "text/csv". Calls with a status other than 2xx, e.g. c.String(http.StatusBadRequest, ...),
+// render errors and are skipped. Handlers rendering nothing this way, e.g. through a
+// helper, return nil.
+func (a *ASTAnalyzer) DetectResponseFormats(methodDecl *ast.FuncDecl) []string {
+	if methodDecl == nil || methodDecl.Body == nil {
+		return nil
+	}
+
+	contextName := contextParamName(methodDecl)
+	var formats []string
+	ast.Inspect(methodDecl.Body, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok || !isIdentNamed(selExpr.X, contextName) || len(callExpr.Args) == 0 || !maySucceed(callExpr.Args[0]) {
+			return true
+		}
+
+		format := responseRenderers[selExpr.Sel.Name]
+		if index, data := dataContentTypeArgs[selExpr.Sel.Name]; data && index < len(callExpr.Args) {
+			if literal, ok := callExpr.Args[index].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				if value, err := strconv.Unquote(literal.Value); err == nil {
+					format, _, _ = strings.Cut(value, ";")
+					format = strings.TrimSpace(format)
+				}
+			}
+		}
+		if format != "" && !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+		return true
+	})
+
+	return formats
+}
+
+// maySucceed reports whether a status code expression may be a 2xx status, it is unless it
+// is a literal or an http or Hertz consts Status constant outside 2xx
+func maySucceed(status ast.Expr) bool {
+	switch expr := status.(type) {
+	case *ast.BasicLit:
+		code, err := strconv.Atoi(expr.Value)
+		return err != nil || (code >= 200 && code < 300)
+	case *ast.SelectorExpr:
+		if code, known := statusCodes[expr.Sel.Name]; known {
+			return code >= 200 && code < 300
+		}
+	}
+	return true
+}
+
+// statusCodes are the status codes of the Status constants of net/http and Hertz's consts,
+// by name: the constants are named after the status text, e.g. StatusBadRequest, and the few
+// named otherwise such as StatusTeapot are missing
+var statusCodes = func() map[string]int {
+	codes := make(map[string]int)
+	for code := 100; code < 600; code++ {
+		if text := http.StatusText(code); text != "" {
+			codes["Status"+strings.NewReplacer(" ", "", "-", "", "'", "").Replace(text)] = code
+		}
+	}
+	return codes
+}()
+
+// bodyReaders are the request context methods that read the request body
+//
+// Binders of the query string, path, headers or cookies (BindQuery, ShouldBindUri, ...)
+// are not listed, handlers using only those take no body.
+var bodyReaders = map[string]bool{
+	"Bind": true, "BindAndValidate": true, "BindJSON": true, "BindXML": true, "BindYAML": true,
+	"BindTOML": true, "BindProtobuf": true, "BindForm": true, "BindByContentType": true,
+	"MustBindWith": true, "ShouldBind": true, "ShouldBindJSON": true, "ShouldBindXML": true,
+	"ShouldBindYAML": true, "ShouldBindTOML": true, "ShouldBindWith": true, "ShouldBindBodyWith": true,
+	"ShouldBindBodyWithJSON": true, "GetRawData": true, "Body": true, "BodyStream": true,
+	"PostForm": true, "DefaultPostForm": true, "GetPostForm": true, "PostFormArray": true,
+	"PostFormMap": true, "FormFile": true, "MultipartForm": true, "FormValue": true, "ParseForm": true,
+	"ParseMultipartForm": true,
+}
+
+// HandlerDirectives returns the //openapi:<directive> comments on a handler, e.g. "ignore" for //openapi:ignore
+//
+// Like other Go directives they are written without a space after the slashes, so they
+// stay out of the handler's godoc.
+func (a *ASTAnalyzer) HandlerDirectives(methodDecl *ast.FuncDecl) []string {
+	if methodDecl == nil || methodDecl.Doc == nil {
+		return nil
+	}
+
+	var directives []string
+	for _, comment := range methodDecl.Doc.List {
+		directive, found := strings.CutPrefix(comment.Text, "//openapi:")
+		if fields := strings.Fields(directive); found && len(fields) > 0 {
+			directives = append(directives, fields[0])
+		}
+	}
+	return directives
+}
+
+// DetectRequestBody reports whether a handler may read its request body
+//
+// A body is read through a binder or body accessor of the request context (see bodyReaders),
+// or through Request.Body. Handlers that pass their request context on to another function
+// are assumed to read it, the callee cannot be inspected.
+func (a *ASTAnalyzer) DetectRequestBody(methodDecl *ast.FuncDecl) bool {
+	if methodDecl == nil || methodDecl.Body == nil {
+		return true
+	}
+
+	contextName := contextParamName(methodDecl)
+
+	found := false
+	ast.Inspect(methodDecl.Body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if node.Sel.Name == "Body" {
+				if inner, ok := node.X.(*ast.SelectorExpr); ok && inner.Sel.Name == "Request" {
+					found = true
+				}
+			}
+		case *ast.CallExpr:
+			if selExpr, ok := node.Fun.(*ast.SelectorExpr); ok && bodyReaders[selExpr.Sel.Name] {
+				found = true
+			}
+			for _, arg := range node.Args {
+				if isIdentNamed(arg, contextName) {
+					found = true
+				}
+			}
+		}
+		return true
+	})
+
+	return found
+}
+
 // ExtractHertzRequestType extracts request type from Hertz handler AST
 func (a *ASTAnalyzer) ExtractHertzRequestType(methodDecl *ast.FuncDecl) reflect.Type {
 	// Look for BindAndValidate calls in the function body
@@ -0,0 +1,501 @@
+package common
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestASTAnalyzer_DetectStreamKind(t *testing.T) {
+	src := `package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hertz-contrib/sse"
+
+	"example.com/app/billing"
+)
+
+var upgrader = websocket.Upgrader{}
+
+type ChatHandler struct {
+	upgrader *websocket.Upgrader
+	plans    *billing.Service
+}
+
+func GorillaChat(w http.ResponseWriter, r *http.Request) {
+	conn, _ := upgrader.Upgrade(w, r, nil)
+	defer conn.Close()
+}
+
+func HertzEvents(ctx context.Context, c *app.RequestContext) {
+	stream := sse.NewStream(c)
+	stream.Publish(&sse.Event{Data: []byte("hi")})
+}
+
+func GinEvents(c *gin.Context) {
+	c.SSEvent("message", "hi")
+}
+
+func RawEvents(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+}
+
+func (h *ChatHandler) Join(c *gin.Context) {
+	conn, _ := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	defer conn.Close()
+}
+
+func LocalUpgrader(c *gin.Context) {
+	u := &websocket.Upgrader{}
+	u.Upgrade(c.Writer, c.Request, nil)
+}
+
+func HeaderEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+}
+
+func (h *ChatHandler) UpgradePlan(c *gin.Context) {
+	h.plans.Upgrade("pro")
+	billing.Upgrade("pro")
+	c.JSON(http.StatusOK, nil)
+}
+
+func LogsEventStream(c *gin.Context) {
+	log.Println("clients may switch to text/event-stream later")
+	c.Header("Accept", "text/event-stream")
+	c.JSON(http.StatusOK, nil)
+}
+
+func GinStream(c *gin.Context) {
+	c.Stream(func(w io.Writer) bool {
+		return false
+	})
+}
+
+func GinStreamEvents(c *gin.Context) {
+	c.Stream(func(w io.Writer) bool {
+		c.SSEvent("tick", "hi")
+		return true
+	})
+}
+
+func FlushWriter(c *gin.Context) {
+	c.Writer.Write([]byte("chunk"))
+	c.Writer.Flush()
+}
+
+func FlusherAssertion(w http.ResponseWriter, r *http.Request) {
+	w.(http.Flusher).Flush()
+}
+
+func CopyToWriter(w http.ResponseWriter, r *http.Request) {
+	io.Copy(w, r.Body)
+}
+
+func HertzBodyStream(ctx context.Context, c *app.RequestContext) {
+	c.SetBodyStream(nil, -1)
+}
+
+func (h *ChatHandler) CopyToBuffer(c *gin.Context) {
+	var buf bytes.Buffer
+	io.Copy(&buf, c.Request.Body)
+	h.plans.Flush()
+	c.JSON(http.StatusOK, nil)
+}
+
+func ListUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, nil)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	a.GetTypeRegistry().ParseImports(file)
+
+	expected := map[string]analyzer.StreamKind{
+		"GorillaChat":      analyzer.StreamWebSocket,
+		"HertzEvents":      analyzer.StreamSSE,
+		"GinEvents":        analyzer.StreamSSE,
+		"RawEvents":        analyzer.StreamSSE,
+		"Join":             analyzer.StreamWebSocket,
+		"LocalUpgrader":    analyzer.StreamWebSocket,
+		"HeaderEvents":     analyzer.StreamSSE,
+		"UpgradePlan":      analyzer.StreamNone,
+		"LogsEventStream":  analyzer.StreamNone,
+		"GinStream":        analyzer.StreamChunked,
+		"GinStreamEvents":  analyzer.StreamSSE,
+		"FlushWriter":      analyzer.StreamChunked,
+		"FlusherAssertion": analyzer.StreamChunked,
+		"CopyToWriter":     analyzer.StreamChunked,
+		"HertzBodyStream":  analyzer.StreamChunked,
+		"CopyToBuffer":     analyzer.StreamNone,
+		"ListUsers":        analyzer.StreamNone,
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectStreamKind(fn), fn.Name.Name)
+	}
+}
+
+func TestASTAnalyzer_DetectFileResponse(t *testing.T) {
+	src := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DownloadReport(c *gin.Context) {
+	c.FileAttachment("/tmp/report.pdf", "report.pdf")
+}
+
+func ServeRaw(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "/tmp/raw.bin")
+}
+
+func ServeVideo(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "video.mp4", time.Now(), nil)
+}
+
+func GetDocument(c *gin.Context) {
+	document := store.File(c.Param("id"))
+	c.JSON(http.StatusOK, document)
+}
+
+func ListUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, nil)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	a.GetTypeRegistry().ParseImports(file)
+	expected := map[string]bool{
+		"DownloadReport": true,
+		"ServeRaw":       true,
+		"ServeVideo":     true,
+		"GetDocument":    false,
+		"ListUsers":      false,
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectFileResponse(fn), fn.Name.Name)
+	}
+}
+
+func TestASTAnalyzer_DetectResponseFormats(t *testing.T) {
+	src := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetUser(c *gin.Context) {
+	if c.GetHeader("Accept") == "application/xml" {
+		c.XML(http.StatusOK, user)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func ExportUsers(c *gin.Context) {
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(200, "text/csv; charset=utf-8", rows)
+}
+
+func GetProto(c *gin.Context) {
+	c.ProtoBuf(status, message)
+	c.String(400, "bad request")
+}
+
+func Ping(c *gin.Context) {
+	logger.String("ping")
+	c.String(http.StatusOK, "pong")
+}
+
+func Delegate(c *gin.Context) {
+	respond(c, user)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	a.GetTypeRegistry().ParseImports(file)
+	expected := map[string][]string{
+		"GetUser":     {"application/xml", "application/json"},
+		"ExportUsers": {"text/csv"},
+		"GetProto":    {"application/x-protobuf"},
+		"Ping":        {"text/plain"},
+		"Delegate":    nil,
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectResponseFormats(fn), fn.Name.Name)
+	}
+}
+
+func TestASTAnalyzer_DetectGraphQL(t *testing.T) {
+	src := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"example.com/app/graph"
+)
+
+func GraphQLHandler() gin.HandlerFunc {
+	h := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{}))
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+func Relay(w http.ResponseWriter, r *http.Request) {
+	(&relay.Handler{}).ServeHTTP(w, r)
+}
+
+func ListGraphs(c *gin.Context) {
+	handler := graph.NewLister()
+	handler.List()
+	c.JSON(http.StatusOK, nil)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	a.GetTypeRegistry().ParseImports(file)
+
+	expected := map[string]bool{
+		"GraphQLHandler": true,
+		"Relay":          true,
+		"ListGraphs":     false,
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectGraphQL(fn), fn.Name.Name)
+	}
+}
+
+func TestASTAnalyzer_DetectPrometheusMetrics(t *testing.T) {
+	src := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func MetricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+func CountRequests(c *gin.Context) {
+	prometheus.NewCounter(prometheus.CounterOpts{}).Inc()
+	c.JSON(http.StatusOK, nil)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	a.GetTypeRegistry().ParseImports(file)
+
+	expected := map[string]bool{
+		"MetricsHandler": true,
+		"CountRequests":  false,
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectPrometheusMetrics(fn), fn.Name.Name)
+	}
+}
+
+func TestASTAnalyzer_DetectRequestBody(t *testing.T) {
+	src := `package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/gin-gonic/gin"
+)
+
+func Logout(ctx context.Context, c *app.RequestContext) {
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func Login(ctx context.Context, c *app.RequestContext) {
+	var req LoginRequest
+	_ = c.BindAndValidate(&req)
+}
+
+func Search(c *gin.Context) {
+	var query SearchQuery
+	_ = c.ShouldBindQuery(&query)
+}
+
+func Upload(c *gin.Context) {
+	data, _ := io.ReadAll(c.Request.Body)
+	c.JSON(http.StatusOK, len(data))
+}
+
+func Delegate(c *gin.Context) {
+	handleDelegate(c)
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handlers.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	a := NewASTAnalyzer()
+	expected := map[string]bool{
+		"Logout":   false,
+		"Login":    true,
+		"Search":   false,
+		"Upload":   true,
+		"Delegate": true,
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, expected[fn.Name.Name], a.DetectRequestBody(fn), fn.Name.Name)
+	}
+
+	logout := file.Decls[1].(*ast.FuncDecl)
+	assert.True(t, a.ExtractHertzHandlerTypes(logout, "handlers.go").NoRequestBody)
+}
+
+func TestASTAnalyzer_HandlerDirectives(t *testing.T) {
+	src := `package handlers
+
+// Profile serves runtime profiles
+//
+//openapi:ignore
+func Profile(c *gin.Context) {}
+
+// Login signs a user in, see openapi:ignore for hiding handlers
+func Login(c *gin.Context) {}
+
+func Logout(c *gin.Context) {}
+`
+
+	file, err := parser.ParseFile(token.NewFileSet(), "handlers.go", src, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	a := NewASTAnalyzer()
+	profile := file.Decls[0].(*ast.FuncDecl)
+	assert.Equal(t, []string{"ignore"}, a.HandlerDirectives(profile))
+	assert.True(t, a.ExtractGinHandlerTypes(profile, "handlers.go").HasDirective(analyzer.DirectiveIgnore))
+
+	// Prose mentioning the directive and undocumented handlers carry none
+	assert.Empty(t, a.HandlerDirectives(file.Decls[1].(*ast.FuncDecl)))
+	assert.Empty(t, a.HandlerDirectives(file.Decls[2].(*ast.FuncDecl)))
+}
+
+func TestASTAnalyzer_FindGoFilesInDirectoryMatchesBuildTags(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a_enterprise.go"), "//go:build enterprise\n\npackage handlers\n")
+	writeFile(t, filepath.Join(dir, "a_test.go"), "package handlers\n")
+	writeFile(t, filepath.Join(dir, "b_handlers.go"), "package handlers\n")
+
+	a := NewASTAnalyzer()
+	assert.Equal(t, filepath.Join(dir, "b_handlers.go"), a.FindGoFilesInDirectory(dir), "Tagged-out and test files are skipped")
+
+	a.SetBuildTags([]string{"enterprise"})
+	assert.Equal(t, filepath.Join(dir, "a_enterprise.go"), a.FindGoFilesInDirectory(dir))
+}
+
+func TestASTAnalyzer_ExcludedSourcePath(t *testing.T) {
+	a := NewASTAnalyzer()
+	assert.False(t, a.ExcludedSourcePath(filepath.Join("app", "gen", "api.pb.go")))
+
+	a.SetSourceExclude([]string{"*.pb.go", "./internal/mocks/", "gen"})
+	assert.True(t, a.ExcludedSourcePath(filepath.Join("app", "api", "user.pb.go")))
+	assert.True(t, a.ExcludedSourcePath(filepath.Join("app", "internal", "mocks", "store.go")), "Files below an excluded directory are excluded")
+	assert.True(t, a.ExcludedSourcePath(filepath.Join("app", "gen")))
+	assert.False(t, a.ExcludedSourcePath(filepath.Join("app", "generated", "user.go")), "Patterns match whole path elements")
+	assert.False(t, a.ExcludedSourcePath(filepath.Join("app", "mocks", "store.go")))
+}
+
+func TestASTAnalyzer_SourceDirs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/mono\n")
+	handlers := filepath.Join(root, "apps", "billing", "internal", "http", "handlers")
+	writeFile(t, filepath.Join(handlers, "invoice.go"), "package handlers\n")
+	writeFile(t, filepath.Join(root, "gen", "api", "api.go"), "package api\n")
+
+	a := NewASTAnalyzer()
+	a.SetSourceDirs([]string{filepath.Join(root, "apps")})
+	assert.True(t, a.containsGoFiles(filepath.Join(root, "apps")), "Source directories are searched at any depth")
+	assert.True(t, a.SourceFilesAvailable())
+
+	// The module holding the source directory is searched, whatever the working directory
+	handlersDir := NewFileSystemUtilities().RealPath(handlers)
+	assert.Equal(t, filepath.Join(handlersDir, "invoice.go"), a.FindSourceFileInConsumerModule("example.com/mono/apps/billing/internal/http/handlers"))
+	assert.Equal(t, filepath.Join(NewFileSystemUtilities().RealPath(root), "gen", "api", "api.go"), a.FindSourceFileInConsumerModule("example.com/mono/gen/api"))
+
+	a.SetSourceExclude([]string{"gen", "handlers"})
+	assert.False(t, a.containsGoFiles(filepath.Join(root, "apps")))
+	assert.False(t, a.containsGoFiles(filepath.Join(root, "gen")))
+	assert.Empty(t, a.FindGoFilesInDirectory(handlersDir))
+}
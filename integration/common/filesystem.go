@@ -39,17 +39,35 @@ func (fs *FileSystemUtilities) GetModuleNameFromGoMod(goModPath string) string {
 		return ""
 	}
 
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimPrefix(line, "module ")
+	// Lines may end in \r\n on Windows, the path may be quoted or followed by a comment
+	for _, line := range strings.Split(string(content), "\n") {
+		if comment := strings.Index(line, "//"); comment != -1 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
 		}
 	}
 
 	return ""
 }
 
+// RealPath returns the absolute path with symbolic links resolved, or the cleaned absolute
+// path when it cannot be resolved, e.g. because it does not exist
+//
+// Paths reached through a symlinked GOPATH or Bazel runfiles then compare equal to the
+// paths of the files they link to.
+func (fs *FileSystemUtilities) RealPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
 // IsDirectory checks if a path is a directory
 func (fs *FileSystemUtilities) IsDirectory(path string) bool {
 	info, err := os.Stat(path)
@@ -0,0 +1,230 @@
+package common
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// HandlerChains maps a route key (see HandlerChainKey) to the entry points of its full handler chain
+type HandlerChains map[string][]uintptr
+
+// HandlerChainKey builds the lookup key for a route's handler chain
+func HandlerChainKey(method, path string) string {
+	return method + " " + path
+}
+
+// CollectHandlerChains walks the route trees of a Gin or Hertz engine and returns the full
+// handler chain (middleware included) registered for every route
+//
+// Both frameworks only expose the last handler through Routes(), so the trees are read with
+// reflection. If the engine layout is not recognized, nil is returned and callers fall back
+// to the last handler only.
+func CollectHandlerChains(engine interface{}) (chains HandlerChains) {
+	defer func() {
+		if recover() != nil {
+			chains = nil
+		}
+	}()
+
+	value := reflect.ValueOf(engine)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// Hertz keeps its trees on the embedded *route.Engine, FieldByName follows the embedding
+	trees := value.FieldByName("trees")
+	if !trees.IsValid() || trees.Kind() != reflect.Slice {
+		return nil
+	}
+
+	chains = make(HandlerChains)
+	for i := 0; i < trees.Len(); i++ {
+		tree := indirect(trees.Index(i))
+		if tree.Kind() != reflect.Struct {
+			continue
+		}
+		method := tree.FieldByName("method")
+		root := tree.FieldByName("root")
+		if !method.IsValid() || method.Kind() != reflect.String || !root.IsValid() {
+			continue
+		}
+		collectNodeChains(chains, method.String(), "", root)
+	}
+	return chains
+}
+
+// collectNodeChains records the handler chain of a tree node and recurses into its children
+func collectNodeChains(chains HandlerChains, method, prefix string, nodeValue reflect.Value) {
+	node := indirect(nodeValue)
+	if node.Kind() != reflect.Struct {
+		return
+	}
+
+	// Hertz stores the full route path on the node, Gin builds it from the node path segments
+	path := prefix
+	if ppath := node.FieldByName("ppath"); ppath.IsValid() && ppath.Kind() == reflect.String {
+		path = ppath.String()
+	} else if segment := node.FieldByName("path"); segment.IsValid() && segment.Kind() == reflect.String {
+		path = prefix + segment.String()
+	}
+
+	if handlers := node.FieldByName("handlers"); handlers.IsValid() && handlers.Kind() == reflect.Slice && handlers.Len() > 0 {
+		chain := make([]uintptr, 0, handlers.Len())
+		for i := 0; i < handlers.Len(); i++ {
+			if handler := handlers.Index(i); handler.Kind() == reflect.Func && !handler.IsNil() {
+				chain = append(chain, handler.Pointer())
+			}
+		}
+		chains[HandlerChainKey(method, path)] = chain
+	}
+
+	if children := node.FieldByName("children"); children.IsValid() && children.Kind() == reflect.Slice {
+		for i := 0; i < children.Len(); i++ {
+			collectNodeChains(chains, method, path, children.Index(i))
+		}
+	}
+	for _, name := range []string{"paramChild", "anyChild"} {
+		if child := node.FieldByName(name); child.IsValid() && child.Kind() == reflect.Ptr && !child.IsNil() {
+			collectNodeChains(chains, method, path, child)
+		}
+	}
+}
+
+// indirect dereferences pointers without panicking on nil
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// IsClosureName reports whether a runtime function name belongs to an anonymous function,
+// e.g. "app/routes.Route.func1" or "app/routes.Wrap.func2.1"
+//
+// Method values ("(*UserHandler).Create-fm") are not closures.
+func (e *HandlerNameExtractor) IsClosureName(fullName string) bool {
+	if strings.HasSuffix(fullName, "-fm") {
+		return false
+	}
+	// Only look at the part after the import path, package paths may contain dots
+	if slashIdx := strings.LastIndex(fullName, "/"); slashIdx != -1 {
+		fullName = fullName[slashIdx+1:]
+	}
+	parts := strings.Split(fullName, ".")
+	for _, part := range parts[1:] {
+		if isClosureSegment(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosureSegment matches "func1", "func2", ... and the bare numbers of nested closures
+func isClosureSegment(part string) bool {
+	digits := strings.TrimPrefix(part, "func")
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCORSMiddlewareName reports whether a runtime function name belongs to a CORS middleware,
+// e.g. "github.com/gin-contrib/cors.New.func1" or "app/middleware.CORSMiddleware.func1"
+//
+// Either the package is named cors, or the function name starts or ends with CORS/Cors
+// (EnableCORS, CorsMiddleware). Handlers that merely mention CORS, such as
+// GetCorsSettings, do not count.
+func IsCORSMiddlewareName(fullName string) bool {
+	pkgPath := functionPackage(fullName)
+	if pkgPath == "" {
+		return false
+	}
+	if strings.EqualFold(path.Base(pkgPath), "cors") {
+		return true
+	}
+
+	// The function the middleware closure or method belongs to: New, CORS, (*Middleware).CORS
+	name := strings.TrimSuffix(strings.TrimPrefix(fullName, pkgPath+"."), "-fm")
+	for _, segment := range strings.Split(name, ".") {
+		if strings.HasPrefix(segment, "(") || isClosureSegment(segment) {
+			continue
+		}
+		for _, marker := range []string{"CORS", "Cors"} {
+			if strings.HasPrefix(segment, marker) || strings.HasSuffix(segment, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ChainHasCORS reports whether a handler chain runs a CORS middleware before its handler
+//
+// The last entry is the route's handler, not a middleware, and is not inspected.
+func ChainHasCORS(chain []uintptr) bool {
+	if len(chain) == 0 {
+		return false
+	}
+	for _, pc := range chain[:len(chain)-1] {
+		if fn := runtime.FuncForPC(pc); fn != nil && IsCORSMiddlewareName(fn.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerNameFromChain picks the real handler out of a handler chain whose last entry is the wrapper closure closureName
+//
+// The chain, without the closure, is scanned from last to first. Only method values declared
+// in the package of the closure are trusted, e.g. h.ListUsers followed by a respond() helper
+// closure. A wrapper such as Route(h.Create) captures its handler, which never appears in the
+// chain, and plain functions cannot be told apart from middleware, so an empty string is
+// returned and callers keep the closure name.
+func (e *HandlerNameExtractor) HandlerNameFromChain(closureName string, chain []uintptr) string {
+	closurePackage := functionPackage(closureName)
+	for i := len(chain) - 1; i >= 0; i-- {
+		fn := runtime.FuncForPC(chain[i])
+		if fn == nil {
+			continue
+		}
+		fullName := fn.Name()
+		if fullName == "" || e.IsClosureName(fullName) {
+			continue
+		}
+		isMethod := strings.HasSuffix(fullName, "-fm") || strings.Contains(fullName, ").")
+		if !isMethod || functionPackage(fullName) != closurePackage {
+			continue
+		}
+		if name := e.ParseHandlerNameFromFunction(fullName); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// functionPackage returns the import path of a runtime function name, e.g. "app/routes" for "app/routes.Route.func1"
+func functionPackage(fullName string) string {
+	slash := strings.LastIndex(fullName, "/")
+	dot := strings.Index(fullName[slash+1:], ".")
+	if dot == -1 {
+		return ""
+	}
+	return fullName[:slash+1+dot]
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsClosureName(t *testing.T) {
+	extractor := NewHandlerNameExtractor()
+
+	tests := map[string]bool{
+		"github.com/acme/app/routes.Route.func1":              true,
+		"github.com/acme/app/routes.Wrap.func2.1":             true,
+		"main.main.func3":                                     true,
+		"github.com/acme/app/handlers.(*UserHandler).List-fm": false,
+		"github.com/acme/app/handlers.GetHealth":              false,
+		"github.com/acme/func1.app/handlers.Handler":          false,
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, extractor.IsClosureName(name), name)
+	}
+}
+
+func TestFunctionPackage(t *testing.T) {
+	assert.Equal(t, "github.com/acme/app/routes", functionPackage("github.com/acme/app/routes.Route.func1"))
+	assert.Equal(t, "github.com/acme/app/handlers", functionPackage("github.com/acme/app/handlers.(*UserHandler).List-fm"))
+	assert.Equal(t, "main", functionPackage("main.main.func3"))
+}
+
+func TestIsCORSMiddlewareName(t *testing.T) {
+	tests := map[string]bool{
+		"github.com/gin-contrib/cors.New.func1":               true,
+		"github.com/hertz-contrib/cors.New.func1":             true,
+		"github.com/acme/app/middleware.CORSMiddleware.func1": true,
+		"github.com/acme/app/handlers.(*UserHandler).List-fm": false,
+		"github.com/acme/app/middleware.RequestID.func1":      false,
+		"github.com/acme/app/middleware.EnableCORS.func1":     true,
+		"github.com/acme/app/cors.Handler":                    true,
+		"github.com/acme/app/admin.GetCorsSettings":           false,
+		"github.com/acme/corsair/handlers.(*Ship).List-fm":    false,
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, IsCORSMiddlewareName(name), name)
+	}
+}
+
+func TestCollectHandlerChainsUnknownEngine(t *testing.T) {
+	assert.Nil(t, CollectHandlerChains(nil))
+	assert.Nil(t, CollectHandlerChains(struct{ Name string }{"engine"}))
+}
@@ -0,0 +1,316 @@
+package common
+
+import (
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ModuleRoot is a Go module whose sources are on disk
+type ModuleRoot struct {
+	Path string // Module path, e.g. github.com/acme/shop/services/orders
+	Dir  string // Directory holding the module's go.mod
+}
+
+// FindGoWorkPath finds the go.work file used for startDir, following the go command
+//
+// GOWORK=off disables workspaces, an explicit GOWORK path is used as is. Otherwise the
+// directories from startDir up are searched.
+func FindGoWorkPath(startDir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "", "auto":
+	default:
+		return gowork
+	}
+
+	dir := startDir
+	for {
+		goWorkPath := filepath.Join(dir, "go.work")
+		if _, err := os.Stat(goWorkPath); err == nil {
+			return goWorkPath
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ParseGoWorkUses returns the module directories listed by the use directives of a go.work file
+//
+// Both forms are supported, "use ./api" and a "use ( ... )" block. Relative directories are
+// resolved against the directory of the go.work file.
+func ParseGoWorkUses(goWorkPath string) []string {
+	content, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	addDir := func(dir string) {
+		dir = strings.Trim(dir, "\"`")
+		if dir == "" {
+			return
+		}
+		dir = filepath.FromSlash(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(goWorkPath), dir)
+		}
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if comment := strings.Index(line, "//"); comment != -1 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			addDir(line)
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			addDir(strings.TrimSpace(strings.TrimPrefix(line, "use ")))
+		}
+	}
+	return dirs
+}
+
+// FindModuleRoots returns the modules whose sources are reachable from startDir
+//
+// In a workspace these are the modules of the go.work file, otherwise the module
+// holding startDir.
+func FindModuleRoots(startDir string) []ModuleRoot {
+	fs := NewFileSystemUtilities()
+
+	var moduleDirs []string
+	if goWorkPath := FindGoWorkPath(startDir); goWorkPath != "" {
+		moduleDirs = ParseGoWorkUses(goWorkPath)
+	}
+	if goModPath := fs.FindGoModPath(startDir); goModPath != "" {
+		moduleDirs = append(moduleDirs, filepath.Dir(goModPath))
+	}
+
+	var roots []ModuleRoot
+	for _, dir := range moduleDirs {
+		modulePath := fs.GetModuleNameFromGoMod(filepath.Join(dir, "go.mod"))
+		if modulePath == "" || slices.ContainsFunc(roots, func(root ModuleRoot) bool { return root.Path == modulePath }) {
+			continue
+		}
+		roots = append(roots, ModuleRoot{Path: modulePath, Dir: dir})
+	}
+	return roots
+}
+
+// ResolvePackageDir maps an import path to its directory in one of the module roots
+//
+// Nested modules are common in monorepos, the module with the longest matching path wins.
+// An empty string is returned when no module contains the package.
+func ResolvePackageDir(roots []ModuleRoot, pkgPath string) string {
+	var match ModuleRoot
+	for _, root := range roots {
+		if len(root.Path) <= len(match.Path) {
+			continue
+		}
+		if pkgPath == root.Path || strings.HasPrefix(pkgPath, root.Path+"/") {
+			match = root
+		}
+	}
+	if match.Path == "" {
+		return ""
+	}
+	relative := strings.TrimPrefix(strings.TrimPrefix(pkgPath, match.Path), "/")
+	return filepath.Join(match.Dir, filepath.FromSlash(relative))
+}
+
+// SourceFileForPC returns the source file of the function at pc, if it exists on disk
+//
+// Binaries record the absolute path of every source file, which finds handlers however
+// the working directory relates to the module. Binaries built with -trimpath record
+// "module/package/file.go" instead, it is resolved through the module roots found from
+// the working directory and the executable's directory.
+func SourceFileForPC(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	if file == "" {
+		return ""
+	}
+
+	// Recorded paths use forward slashes on every platform, C:/src/app/main.go on Windows
+	if !filepath.IsAbs(filepath.FromSlash(file)) {
+		dir := ResolvePackageDir(SourceModuleRoots(), path.Dir(file))
+		if dir == "" {
+			return ""
+		}
+		file = filepath.Join(dir, path.Base(file))
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return NewFileSystemUtilities().RealPath(file)
+}
+
+// SourceModuleRoots returns the module roots found from the working directory and from the
+// directory of the running executable, followed by the dependencies of the binary
+//
+// A service binary started from a subdirectory of a monorepo, or from its bin/ directory,
+// still finds the workspace above it. The roots are looked up once per process, every
+// handler of a service resolves its source against the same roots. The returned slice is
+// shared and must not be modified.
+func SourceModuleRoots() []ModuleRoot {
+	return sourceModuleRoots()
+}
+
+// sourceModuleRoots walks the file system for SourceModuleRoots on first use only
+var sourceModuleRoots = sync.OnceValue(func() []ModuleRoot {
+	return slices.Concat(localModuleRoots(), DependencyModuleRoots())
+})
+
+// localModuleRoots finds the module roots above the working and executable directories once
+var localModuleRoots = sync.OnceValue(func() []ModuleRoot {
+	fs := NewFileSystemUtilities()
+
+	// Symbolic links are resolved, module directories then match the real paths of source
+	// files. An executable linked into a Bazel runfiles tree or bin/ is also searched from
+	// where the link is.
+	var startDirs []string
+	if wd, err := os.Getwd(); err == nil {
+		startDirs = append(startDirs, fs.RealPath(wd))
+	}
+	if executable, err := os.Executable(); err == nil {
+		startDirs = append(startDirs, filepath.Dir(fs.RealPath(executable)), filepath.Dir(executable))
+	}
+
+	var roots []ModuleRoot
+	for _, startDir := range startDirs {
+		for _, root := range FindModuleRoots(startDir) {
+			if !slices.Contains(roots, root) {
+				roots = append(roots, root)
+			}
+		}
+	}
+	return roots
+})
+
+// dependencyModuleRoots caches DependencyModuleRoots, the build info of a binary never changes
+var dependencyModuleRoots = sync.OnceValue(func() []ModuleRoot {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	// Local replacements are relative to the main module
+	mainDir := ResolvePackageDir(localModuleRoots(), info.Main.Path)
+	return buildInfoModuleRoots(info, mainDir, ModuleCacheDir())
+})
+
+// buildInfoModuleRoots returns the dependencies listed in build info whose sources are in
+// cacheDir, or in the directory of a replacement relative to mainDir
+func buildInfoModuleRoots(info *debug.BuildInfo, mainDir, cacheDir string) []ModuleRoot {
+	fs := NewFileSystemUtilities()
+
+	var roots []ModuleRoot
+	for _, dep := range info.Deps {
+		module := dep
+		if dep.Replace != nil {
+			module = dep.Replace
+		}
+
+		var dir string
+		switch {
+		case module.Version == "" && filepath.IsAbs(module.Path):
+			dir = module.Path
+		case module.Version == "" && mainDir != "":
+			dir = filepath.Join(mainDir, filepath.FromSlash(module.Path))
+		case module.Version != "" && cacheDir != "":
+			dir = filepath.Join(cacheDir, filepath.FromSlash(EscapeModulePath(module.Path))+"@"+EscapeModulePath(module.Version))
+		}
+		if dir != "" && fs.IsDirectory(dir) {
+			roots = append(roots, ModuleRoot{Path: dep.Path, Dir: dir})
+		}
+	}
+	return roots
+}
+
+// DependencyModuleRoots returns the modules the running binary was built with whose sources
+// are on disk, in the module cache or in the directory of a local replacement
+//
+// Types from imported modules, e.g. a shared contracts module, get real schemas on machines
+// that built the binary. The versions come from the build info of the binary.
+func DependencyModuleRoots() []ModuleRoot {
+	return dependencyModuleRoots()
+}
+
+// VendorModuleRoots returns the modules vendored into the given module roots with go mod vendor
+//
+// The modules are read from vendor/modules.txt, their sources live in vendor/<module path>.
+func VendorModuleRoots(roots []ModuleRoot) []ModuleRoot {
+	fs := NewFileSystemUtilities()
+
+	var vendored []ModuleRoot
+	for _, root := range roots {
+		data, err := os.ReadFile(filepath.Join(root.Dir, "vendor", "modules.txt"))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			// "# github.com/acme/contracts v1.2.0", "## explicit" lines annotate the module above
+			fields := strings.Fields(line)
+			if len(fields) < 2 || fields[0] != "#" {
+				continue
+			}
+			dir := filepath.Join(root.Dir, "vendor", filepath.FromSlash(fields[1]))
+			if fs.IsDirectory(dir) {
+				vendored = append(vendored, ModuleRoot{Path: fields[1], Dir: dir})
+			}
+		}
+	}
+	return vendored
+}
+
+// ModuleCacheDir returns the module cache directory, $GOMODCACHE or $GOPATH/pkg/mod like the go command
+func ModuleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+	if list := filepath.SplitList(gopath); len(list) > 0 && list[0] != "" {
+		return filepath.Join(list[0], "pkg", "mod")
+	}
+	return ""
+}
+
+// EscapeModulePath escapes a module path or version for the module cache, upper-case letters
+// become "!" and the lower-case letter, e.g. "github.com/Azure/go" -> "github.com/!azure/go"
+func EscapeModulePath(modulePath string) string {
+	var escaped strings.Builder
+	for _, r := range modulePath {
+		if unicode.IsUpper(r) {
+			escaped.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFile creates a file and its parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestFindModuleRootsInWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.25\n\nuse (\n\t./services/orders // orders API\n\t./contracts\n)\n\nuse ./tools\n")
+	writeFile(t, filepath.Join(root, "services", "orders", "go.mod"), "module github.com/acme/shop/services/orders\n")
+	writeFile(t, filepath.Join(root, "contracts", "go.mod"), "module github.com/acme/shop/contracts\n")
+	writeFile(t, filepath.Join(root, "tools", "go.mod"), "module github.com/acme/shop/tools\n")
+
+	// The service runs from a subdirectory of its module
+	startDir := filepath.Join(root, "services", "orders", "cmd", "server")
+	assert.NoError(t, os.MkdirAll(startDir, 0o755))
+
+	roots := FindModuleRoots(startDir)
+	assert.ElementsMatch(t, []ModuleRoot{
+		{Path: "github.com/acme/shop/services/orders", Dir: filepath.Join(root, "services", "orders")},
+		{Path: "github.com/acme/shop/contracts", Dir: filepath.Join(root, "contracts")},
+		{Path: "github.com/acme/shop/tools", Dir: filepath.Join(root, "tools")},
+	}, roots)
+
+	assert.Equal(t, filepath.Join(root, "contracts", "dto"), ResolvePackageDir(roots, "github.com/acme/shop/contracts/dto"))
+	assert.Equal(t, filepath.Join(root, "services", "orders"), ResolvePackageDir(roots, "github.com/acme/shop/services/orders"))
+	assert.Empty(t, ResolvePackageDir(roots, "github.com/acme/shop/contractsv2/dto"))
+
+	t.Setenv("GOWORK", "off")
+	assert.Equal(t, []ModuleRoot{
+		{Path: "github.com/acme/shop/services/orders", Dir: filepath.Join(root, "services", "orders")},
+	}, FindModuleRoots(startDir))
+}
+
+func TestResolvePackageDirPrefersNestedModule(t *testing.T) {
+	roots := []ModuleRoot{
+		{Path: "github.com/acme/shop", Dir: "/src/shop"},
+		{Path: "github.com/acme/shop/api", Dir: "/src/shop/api"},
+	}
+	assert.Equal(t, filepath.FromSlash("/src/shop/api/handlers"), ResolvePackageDir(roots, "github.com/acme/shop/api/handlers"))
+	assert.Equal(t, filepath.FromSlash("/src/shop/internal/db"), ResolvePackageDir(roots, "github.com/acme/shop/internal/db"))
+}
+
+func TestSourceFileForPC(t *testing.T) {
+	pc := reflect.ValueOf(TestSourceFileForPC).Pointer()
+	assert.Equal(t, "modules_test.go", filepath.Base(SourceFileForPC(pc)))
+	assert.Empty(t, SourceFileForPC(0))
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	assert.Equal(t, "github.com/!azure/azure-sdk-for-go", EscapeModulePath("github.com/Azure/azure-sdk-for-go"))
+	assert.Equal(t, "github.com/stretchr/testify", EscapeModulePath("github.com/stretchr/testify"))
+}
+
+func TestBuildInfoModuleRoots(t *testing.T) {
+	root := t.TempDir()
+	mainDir := filepath.Join(root, "app")
+	cacheDir := filepath.Join(root, "pkg", "mod")
+	cached := filepath.Join(cacheDir, "github.com", "!acme", "contracts@v1.2.0")
+	replaced := filepath.Join(root, "shared")
+	assert.NoError(t, os.MkdirAll(cached, 0o755))
+	assert.NoError(t, os.MkdirAll(replaced, 0o755))
+
+	roots := buildInfoModuleRoots(&debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "github.com/Acme/contracts", Version: "v1.2.0"},
+		{Path: "github.com/acme/shared", Version: "v0.1.0", Replace: &debug.Module{Path: "../shared"}},
+		{Path: "github.com/acme/missing", Version: "v1.0.0"},
+	}}, mainDir, cacheDir)
+
+	assert.Equal(t, []ModuleRoot{
+		{Path: "github.com/Acme/contracts", Dir: cached},
+		{Path: "github.com/acme/shared", Dir: replaced},
+	}, roots, "Modules without sources on disk are left out")
+}
+
+func TestVendorModuleRoots(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "vendor", "modules.txt"), "# github.com/acme/contracts v1.2.0\n## explicit; go 1.22\ngithub.com/acme/contracts/dto\n# github.com/acme/gone v0.1.0\n")
+	writeFile(t, filepath.Join(root, "vendor", "github.com", "acme", "contracts", "dto", "user.go"), "package dto\n")
+
+	roots := VendorModuleRoots([]ModuleRoot{{Path: "github.com/acme/shop", Dir: root}, {Path: "github.com/acme/plain", Dir: t.TempDir()}})
+	assert.Equal(t, []ModuleRoot{
+		{Path: "github.com/acme/contracts", Dir: filepath.Join(root, "vendor", "github.com", "acme", "contracts")},
+	}, roots, "Modules listed without vendored sources are left out")
+	assert.Equal(t, filepath.Join(root, "vendor", "github.com", "acme", "contracts", "dto"), ResolvePackageDir(roots, "github.com/acme/contracts/dto"))
+}
+
+func TestParseGoWorkUsesSlashPaths(t *testing.T) {
+	root := t.TempDir()
+	shared := filepath.Join(t.TempDir(), "shared")
+	// go.work files are written with forward slashes on every platform
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.25\n\nuse (\n\t./services/orders/\n\t"+filepath.ToSlash(shared)+"\n)\n")
+
+	assert.Equal(t, []string{filepath.Join(root, "services", "orders"), shared}, ParseGoWorkUses(filepath.Join(root, "go.work")))
+}
+
+func TestRealPathResolvesSymlinks(t *testing.T) {
+	fs := NewFileSystemUtilities()
+	root := fs.RealPath(t.TempDir())
+	writeFile(t, filepath.Join(root, "src", "app", "go.mod"), "module example.com/app // the service\r\n")
+	link := filepath.Join(root, "gopath")
+	if err := os.Symlink(filepath.Join(root, "src"), link); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+
+	assert.Equal(t, filepath.Join(root, "src", "app"), fs.RealPath(filepath.Join(link, "app")))
+	assert.Equal(t, filepath.Join(root, "missing"), fs.RealPath(filepath.Join(root, "src", "..", "missing")))
+	assert.Equal(t, "example.com/app", fs.GetModuleNameFromGoMod(filepath.Join(link, "app", "go.mod")))
+}
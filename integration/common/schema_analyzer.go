@@ -34,18 +34,19 @@ func (sa *SchemaAnalyzer) GetTypeResolver() *TypeResolver {
 // GenerateFallbackSchemas generates generic schemas for Docker/production environments
 func (sa *SchemaAnalyzer) GenerateFallbackSchemas() analyzer.HandlerSchema {
 	schema := analyzer.HandlerSchema{}
+	freeForm := true // The payload is unknown, any property is allowed
 
 	// Generate generic request schema for POST/PUT/PATCH methods
 	schema.RequestSchema = spec.Schema{
 		Type: "object",
 		Properties: map[string]spec.Schema{
 			"data": {
-				Type:        "object",
-				Description: "Request payload (schema analysis unavailable in production mode)",
-				AdditionalProperties: &spec.Schema{Type: "any"},
+				Type:                        "object",
+				Description:                 "Request payload (schema analysis unavailable in production mode)",
+				AdditionalPropertiesAllowed: &freeForm,
 			},
 		},
-		Description: "Generic request schema - AST analysis not available",
+		Description: analyzer.FallbackRequestDescription,
 	}
 
 	// Generate generic response schema
@@ -53,9 +54,9 @@ func (sa *SchemaAnalyzer) GenerateFallbackSchemas() analyzer.HandlerSchema {
 		Type: "object",
 		Properties: map[string]spec.Schema{
 			"data": {
-				Type:        "object",
-				Description: "Response data",
-				AdditionalProperties: &spec.Schema{Type: "any"},
+				Type:                        "object",
+				Description:                 "Response data",
+				AdditionalPropertiesAllowed: &freeForm,
 			},
 			"message": {
 				Type:        "string",
@@ -63,7 +64,7 @@ func (sa *SchemaAnalyzer) GenerateFallbackSchemas() analyzer.HandlerSchema {
 				Example:     "Success",
 			},
 		},
-		Description: "Generic response schema - AST analysis not available",
+		Description: analyzer.FallbackResponseDescription,
 	}
 
 	return schema
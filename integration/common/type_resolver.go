@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/schemagen"
 )
 
 // TypeResolver provides utilities for resolving Go types from various sources
@@ -90,11 +91,11 @@ func (tr *TypeResolver) ResolveTypeFromAST(typeExpr ast.Expr, currentPackage str
 	case *ast.ArrayType:
 		// Array or slice type
 		if elemType := tr.ResolveTypeFromAST(expr.Elt, currentPackage); elemType != nil {
-			if expr.Len != nil {
+			if length, fixed := schemagen.ArrayLength(expr); fixed {
 				// Array type
-				return reflect.ArrayOf(10, elemType) // Default length, can be enhanced
+				return reflect.ArrayOf(length, elemType)
 			}
-			// Slice type
+			// Slice type, or an array whose length is a constant we cannot evaluate
 			return reflect.SliceOf(elemType)
 		}
 
@@ -165,6 +166,9 @@ func (tr *TypeResolver) FindPackagePathByName(packageName, baseDir string) strin
 }
 
 // ConvertFilePathToPackagePath converts a file path to a Go package path
+//
+// The path is taken relative to the module holding baseDir, with symbolic links resolved on
+// both sides. An empty string is returned when the path is outside that module.
 func (tr *TypeResolver) ConvertFilePathToPackagePath(filePath, baseDir string) string {
 	// Get the module name
 	goModPath := tr.fileUtils.FindGoModPath(baseDir)
@@ -177,15 +181,18 @@ func (tr *TypeResolver) ConvertFilePathToPackagePath(filePath, baseDir string) s
 		return ""
 	}
 
-	// Convert relative path to package path
-	relPath, err := filepath.Rel(baseDir, filePath)
-	if err != nil {
+	// Convert the path relative to the module root to package path
+	moduleDir := tr.fileUtils.RealPath(filepath.Dir(goModPath))
+	relPath, err := filepath.Rel(moduleDir, tr.fileUtils.RealPath(filePath))
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
 		return ""
 	}
+	if relPath == "." {
+		return moduleName
+	}
 
 	// Convert to forward slashes and combine with module name
-	pkgPath := filepath.ToSlash(relPath)
-	return moduleName + "/" + pkgPath
+	return moduleName + "/" + filepath.ToSlash(relPath)
 }
 
 // ExtractTypeFromFunction extracts return types from a function declaration
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertFilePathToPackagePath(t *testing.T) {
+	root := NewFileSystemUtilities().RealPath(t.TempDir())
+	moduleDir := filepath.Join(root, "app")
+	writeFile(t, filepath.Join(moduleDir, "go.mod"), "module \"example.com/app\"\n")
+	writeFile(t, filepath.Join(moduleDir, "internal", "dto", "user.go"), "package dto\n")
+	writeFile(t, filepath.Join(root, "other", "other.go"), "package other\n")
+
+	resolver := NewTypeResolver()
+	internalDir := filepath.Join(moduleDir, "internal")
+	assert.Equal(t, "example.com/app/internal/dto", resolver.ConvertFilePathToPackagePath(filepath.Join(internalDir, "dto"), internalDir),
+		"Paths are relative to the module root, not to the base directory")
+	assert.Equal(t, "example.com/app", resolver.ConvertFilePathToPackagePath(moduleDir, moduleDir))
+	assert.Empty(t, resolver.ConvertFilePathToPackagePath(filepath.Join(root, "other"), moduleDir))
+
+	link := filepath.Join(root, "runfiles")
+	if err := os.Symlink(moduleDir, link); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+	assert.Equal(t, "example.com/app/internal/dto", resolver.ConvertFilePathToPackagePath(filepath.Join(link, "internal", "dto"), moduleDir))
+	assert.Equal(t, "example.com/app/internal/dto", resolver.ConvertFilePathToPackagePath(filepath.Join(moduleDir, "internal", "dto"), link))
+}
@@ -2,9 +2,11 @@ package integration
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/gin-gonic/gin"
+	"github.com/zainokta/openapi-gen/analyzer"
 	"github.com/zainokta/openapi-gen/spec"
 )
 
@@ -14,6 +16,12 @@ type RouteDiscoverer interface {
 	GetFrameworkName() string
 }
 
+// AnnotatedRouteDiscoverer is a RouteDiscoverer copying route annotations into the routes it discovers
+type AnnotatedRouteDiscoverer interface {
+	RouteDiscoverer
+	SetAnnotations(annotations *RouteAnnotations)
+}
+
 // AutoDiscoverer automatically detects the framework and creates appropriate discoverer
 type AutoDiscoverer struct {
 	discoverer RouteDiscoverer
@@ -40,7 +48,43 @@ func (a *AutoDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
 	return a.discoverer.DiscoverRoutes()
 }
 
+// SetAnnotations sets the annotations copied into the routes discovered, see RouteAnnotations
+func (a *AutoDiscoverer) SetAnnotations(annotations *RouteAnnotations) {
+	if annotated, ok := a.discoverer.(AnnotatedRouteDiscoverer); ok {
+		annotated.SetAnnotations(annotations)
+	}
+}
+
 // GetFrameworkName returns the detected framework name
 func (a *AutoDiscoverer) GetFrameworkName() string {
 	return a.discoverer.GetFrameworkName()
 }
+
+// SourceHandlerAnalyzer is a HandlerAnalyzer reading handler source, the generator shares its
+// schema settings and source locations with it
+type SourceHandlerAnalyzer interface {
+	analyzer.HandlerAnalyzer
+	GetSchemaGenerator() *analyzer.SchemaGenerator
+	SetUnions(unions *analyzer.UnionRegistry)
+	SetVendorSources(enabled bool)
+	SetBuildTags(tags []string)
+	SetSourceDirs(dirs []string)
+	SetSourceExclude(patterns []string)
+}
+
+// NewHandlerAnalyzer creates the handler analyzer of a framework instance
+//
+// Frameworks the instance does not identify, such as those of custom discoverers, are
+// identified by the name of their route discoverer. Hertz is the default.
+func NewHandlerAnalyzer(framework interface{}, frameworkName string) SourceHandlerAnalyzer {
+	switch framework.(type) {
+	case *gin.Engine:
+		return NewGinHandlerAnalyzer()
+	case *server.Hertz:
+		return NewHertzHandlerAnalyzer()
+	}
+	if strings.EqualFold(frameworkName, "gin") {
+		return NewGinHandlerAnalyzer()
+	}
+	return NewHertzHandlerAnalyzer()
+}
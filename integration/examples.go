@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/gin-gonic/gin"
+)
+
+// maxExampleBodySize bounds the bodies captured for examples, larger ones are not recorded
+const maxExampleBodySize = 64 << 10
+
+// ExampleSink receives the JSON bodies of served requests, e.g. openapi.ExampleRecorder or
+// openapi.DriftDetector
+type ExampleSink interface {
+	// Recording reports whether bodies are wanted, the middlewares do nothing otherwise
+	Recording() bool
+	// RecordExample records the bodies of a request to the route registered as method and
+	// path, e.g. "GET /users/:id"; a nil body was not JSON or too large
+	RecordExample(method, path string, status int, requestBody, responseBody []byte)
+}
+
+// isJSONContentType reports whether a Content-Type header is JSON, e.g. application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// exampleBody returns body when it is a JSON body small enough to record, nil otherwise
+func exampleBody(contentType string, body []byte) []byte {
+	if len(body) == 0 || len(body) > maxExampleBodySize || !isJSONContentType(contentType) {
+		return nil
+	}
+	return body
+}
+
+// GinExampleMiddleware captures the JSON bodies of every route for the sink
+//
+// Register it before the routes, e.g. router.Use(integration.GinExampleMiddleware(recorder)).
+// Requests to unregistered routes are not recorded.
+func GinExampleMiddleware(sink ExampleSink) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !sink.Recording() || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		if c.Request.Body != nil && isJSONContentType(c.GetHeader("Content-Type")) {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxExampleBodySize+1))
+			// The handler reads what was captured followed by whatever is left
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+			if err == nil {
+				requestBody = body
+			}
+		}
+
+		writer := &exampleResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		sink.RecordExample(c.Request.Method, c.FullPath(), writer.Status(),
+			exampleBody(c.GetHeader("Content-Type"), requestBody),
+			exampleBody(writer.Header().Get("Content-Type"), writer.body.Bytes()))
+	}
+}
+
+// exampleResponseWriter keeps a copy of the response body, up to one byte over the limit
+type exampleResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *exampleResponseWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *exampleResponseWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *exampleResponseWriter) capture(data []byte) {
+	if remaining := maxExampleBodySize + 1 - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+}
+
+// HertzExampleMiddleware captures the JSON bodies of every route for the sink
+//
+// Register it before the routes, e.g. h.Use(integration.HertzExampleMiddleware(recorder)).
+// Requests to unregistered routes are not recorded.
+func HertzExampleMiddleware(sink ExampleSink) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if !sink.Recording() || c.FullPath() == "" {
+			c.Next(ctx)
+			return
+		}
+
+		// Hertz reads bodies into memory, so they are still there after the handler ran
+		c.Next(ctx)
+
+		sink.RecordExample(string(c.Method()), c.FullPath(), c.Response.StatusCode(),
+			exampleBody(string(c.ContentType()), c.Request.Body()),
+			exampleBody(string(c.Response.Header.ContentType()), c.Response.Body()))
+	}
+}
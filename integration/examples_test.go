@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedExample is an example received by exampleSinkStub
+type recordedExample struct {
+	method, path string
+	status       int
+	request      string
+	response     string
+}
+
+// exampleSinkStub keeps the examples it receives
+type exampleSinkStub struct {
+	recording bool
+	examples  []recordedExample
+}
+
+func (s *exampleSinkStub) Recording() bool { return s.recording }
+
+func (s *exampleSinkStub) RecordExample(method, path string, status int, requestBody, responseBody []byte) {
+	s.examples = append(s.examples, recordedExample{method, path, status, string(requestBody), string(responseBody)})
+}
+
+func TestGinExampleMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sink := &exampleSinkStub{recording: true}
+	router := gin.New()
+	router.Use(GinExampleMiddleware(sink))
+	router.POST("/users/:id", func(c *gin.Context) {
+		var body map[string]any
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": c.Param("id"), "name": body["name"]})
+	})
+	router.GET("/report", func(c *gin.Context) { c.String(http.StatusOK, "plain text") })
+
+	request := httptest.NewRequest(http.MethodPost, "/users/7", strings.NewReader(`{"name":"Ada"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusCreated, recorder.Code, "The handler still reads the body")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	assert.Equal(t, []recordedExample{
+		{"POST", "/users/:id", http.StatusCreated, `{"name":"Ada"}`, `{"id":"7","name":"Ada"}`},
+		{"GET", "/report", http.StatusOK, "", ""},
+	}, sink.examples)
+
+	// Nothing is captured while the sink is not recording
+	sink.recording, sink.examples = false, nil
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Empty(t, sink.examples)
+}
+
+func TestHertzExampleMiddleware(t *testing.T) {
+	sink := &exampleSinkStub{recording: true}
+	h := server.Default()
+	h.Use(HertzExampleMiddleware(sink))
+	h.PUT("/orders/:id", func(ctx context.Context, c *app.RequestContext) {
+		c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+	})
+
+	response := ut.PerformRequest(h.Engine, http.MethodPut, "/orders/3", &ut.Body{Body: strings.NewReader(`{"total":12}`), Len: 12},
+		ut.Header{Key: "Content-Type", Value: "application/json"})
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	assert.Equal(t, []recordedExample{
+		{"PUT", "/orders/:id", http.StatusOK, `{"total":12}`, `{"id":"3"}`},
+	}, sink.examples)
+}
@@ -6,8 +6,6 @@ import (
 	"go/parser"
 	"go/token"
 	"net/http"
-	"os"
-	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
@@ -24,6 +22,7 @@ import (
 type GinRouteDiscoverer struct {
 	engine               *gin.Engine
 	handlerNameExtractor *common.HandlerNameExtractor
+	annotations          *RouteAnnotations
 }
 
 // NewGinRouteDiscoverer creates a new Gin route discoverer
@@ -41,12 +40,19 @@ func (g *GinRouteDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
 	// Use Gin's built-in Routes() method to get all registered routes
 	ginRoutes := g.engine.Routes()
 
+	// Full handler chains let routes registered through wrapper closures resolve the real handler
+	chains := common.CollectHandlerChains(g.engine)
+
 	for _, route := range ginRoutes {
 		routeInfo := spec.RouteInfo{
 			Method:      route.Method,
 			Path:        route.Path,
-			HandlerName: g.extractHandlerName(route),
+			HandlerName: g.extractHandlerName(route, chains),
 			Handler:     route.HandlerFunc,
+			CORS:        common.ChainHasCORS(chains[common.HandlerChainKey(route.Method, route.Path)]),
+		}
+		if g.annotations != nil {
+			g.annotations.Apply(&routeInfo)
 		}
 
 		routes = append(routes, routeInfo)
@@ -56,7 +62,7 @@ func (g *GinRouteDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
 }
 
 // extractHandlerName extracts handler name from Gin route info
-func (g *GinRouteDiscoverer) extractHandlerName(route gin.RouteInfo) string {
+func (g *GinRouteDiscoverer) extractHandlerName(route gin.RouteInfo, chains common.HandlerChains) string {
 	// Try to extract meaningful handler name from the route
 	if route.HandlerFunc != nil {
 		// Use reflection to get function name if possible
@@ -71,6 +77,14 @@ func (g *GinRouteDiscoverer) extractHandlerName(route gin.RouteInfo) string {
 				if pc := handlerValue.Pointer(); pc != 0 {
 					if fn := runtime.FuncForPC(pc); fn != nil {
 						runtimeFuncName := fn.Name()
+						// A wrapper closure hides the real handler, look for it earlier in the chain
+						if g.handlerNameExtractor.IsClosureName(runtimeFuncName) {
+							if chain := chains[common.HandlerChainKey(route.Method, route.Path)]; len(chain) > 1 {
+								if name := g.handlerNameExtractor.HandlerNameFromChain(runtimeFuncName, chain[:len(chain)-1]); name != "" {
+									return name
+								}
+							}
+						}
 						if runtimeFuncName != "" && !isGinGenericFuncSignature(runtimeFuncName) {
 							cleanName := g.handlerNameExtractor.ParseHandlerNameFromFunction(runtimeFuncName)
 							if cleanName != "" {
@@ -97,6 +111,11 @@ func (g *GinRouteDiscoverer) extractHandlerName(route gin.RouteInfo) string {
 	return parser.GenerateHandlerName(route.Method, route.Path)
 }
 
+// SetAnnotations sets the annotations copied into the routes discovered, see RouteAnnotations
+func (g *GinRouteDiscoverer) SetAnnotations(annotations *RouteAnnotations) {
+	g.annotations = annotations
+}
+
 // GetFrameworkName returns the framework name
 func (g *GinRouteDiscoverer) GetFrameworkName() string {
 	return "Gin"
@@ -121,6 +140,21 @@ func NewGinServerAdapter(gin *gin.Engine) HTTPServer {
 
 // GET implements the HTTPServer interface by adapting to Gin
 func (g *GinServerAdapter) GET(path string, handler HTTPHandler) {
+	g.Handle(http.MethodGet, path, handler)
+}
+
+// POST implements the HTTPServer interface by adapting to Gin
+func (g *GinServerAdapter) POST(path string, handler HTTPHandler) {
+	g.Handle(http.MethodPost, path, handler)
+}
+
+// HEAD implements the HTTPServer interface by adapting to Gin
+func (g *GinServerAdapter) HEAD(path string, handler HTTPHandler) {
+	g.Handle(http.MethodHead, path, handler)
+}
+
+// Handle implements the HTTPServer interface by adapting to Gin
+func (g *GinServerAdapter) Handle(method, path string, handler HTTPHandler) {
 	// Convert the generic HTTPHandler to a Gin HandlerFunc
 	ginHandler := func(c *gin.Context) {
 		// Create a response writer that adapts Gin Context to http.ResponseWriter
@@ -136,7 +170,7 @@ func (g *GinServerAdapter) GET(path string, handler HTTPHandler) {
 		handler(rw, req)
 	}
 
-	g.gin.GET(path, ginHandler)
+	g.gin.Handle(method, path, ginHandler)
 }
 
 // ginResponseWriter adapts Gin Context to http.ResponseWriter
@@ -170,8 +204,8 @@ type GinHandlerAnalyzer struct {
 	astAnalyzer          *common.ASTAnalyzer
 	typeResolver         *common.TypeResolver
 	schemaAnalyzer       *common.SchemaAnalyzer
-	sourceFilePath       string      // Path to the source file being analyzed
-	config               interface{} // Configuration passed from library consumer
+	sourceFilePath       string          // Path to the source file being analyzed
+	config               analyzer.Config // Configuration passed from library consumer
 }
 
 // NewGinHandlerAnalyzer creates a new Gin handler analyzer
@@ -194,31 +228,53 @@ func (g *GinHandlerAnalyzer) GetSchemaGenerator() *analyzer.SchemaGenerator {
 	return g.schemaAnalyzer.GetSchemaGenerator()
 }
 
+// SetUnions shares the unions of the generator's schema registry with every schema generator of the analyzer
+func (g *GinHandlerAnalyzer) SetUnions(unions *analyzer.UnionRegistry) {
+	g.schemaAnalyzer.GetSchemaGenerator().SetUnions(unions)
+	g.astAnalyzer.SetUnions(unions)
+}
+
+// SetVendorSources controls whether handler packages are also looked up in vendor/
+func (g *GinHandlerAnalyzer) SetVendorSources(enabled bool) {
+	g.astAnalyzer.SetVendorSources(enabled)
+}
+
+// SetBuildTags sets the build tags handler source files are matched against
+func (g *GinHandlerAnalyzer) SetBuildTags(tags []string) {
+	g.astAnalyzer.SetBuildTags(tags)
+}
+
+// SetSourceDirs sets the directories handler sources are searched from, instead of the working directory
+func (g *GinHandlerAnalyzer) SetSourceDirs(dirs []string) {
+	g.astAnalyzer.SetSourceDirs(dirs)
+}
+
+// SetSourceExclude sets glob patterns of source files and directories that are never analyzed
+func (g *GinHandlerAnalyzer) SetSourceExclude(patterns []string) {
+	g.astAnalyzer.SetSourceExclude(patterns)
+}
+
 // SetConfig sets the configuration for the analyzer (implements HandlerAnalyzer interface)
-func (g *GinHandlerAnalyzer) SetConfig(config interface{}) {
+func (g *GinHandlerAnalyzer) SetConfig(config analyzer.Config) {
 	g.config = config
+	if config != nil && len(config.SourceDirs()) > 0 {
+		g.SetSourceDirs(config.SourceDirs())
+	}
+}
+
+// Config returns the configuration set with SetConfig, nil when none was set
+func (g *GinHandlerAnalyzer) Config() analyzer.Config {
+	return g.config
 }
 
 // isProductionMode checks if running in production mode based on config
 func (g *GinHandlerAnalyzer) isProductionMode() bool {
-	if g.config != nil {
-		// Try to assert as our Config type
-		if cfg, ok := g.config.(interface{ IsProductionMode() bool }); ok {
-			return cfg.IsProductionMode()
-		}
-	}
-	return false
+	return g.config != nil && g.config.IsProductionMode()
 }
 
 // isASTAnalysisEnabled checks if AST analysis should be performed
 func (g *GinHandlerAnalyzer) isASTAnalysisEnabled() bool {
-	if g.config != nil {
-		// Try to assert as our Config type
-		if cfg, ok := g.config.(interface{ IsASTAnalysisEnabled() bool }); ok {
-			return cfg.IsASTAnalysisEnabled()
-		}
-	}
-	return true // Default to enabled if no config
+	return g.config == nil || g.config.IsASTAnalysisEnabled() // Default to enabled if no config
 }
 
 // ExtractTypes extracts request and response types from Gin handler function
@@ -264,43 +320,41 @@ func (g *GinHandlerAnalyzer) AnalyzeHandler(handler interface{}) analyzer.Handle
 	}
 
 	// Second, try AST analysis (only if enabled and source files are available)
-	if g.isASTAnalysisEnabled() && !g.isProductionMode() && g.areSourceFilesAvailable() {
-		if astSchema := g.tryASTAnalysis(handler); astSchema.RequestSchema.Type != "" || astSchema.ResponseSchema.Type != "" {
+	noRequestBody := false
+	var directives, formats []string
+	if g.isASTAnalysisEnabled() && !g.isProductionMode() && g.astAnalyzer.SourceFilesAvailable() {
+		astSchema := g.tryASTAnalysis(handler)
+		if astSchema.RequestSchema.Type != "" || astSchema.ResponseSchema.Type != "" || astSchema.Stream != analyzer.StreamNone || astSchema.FileResponse || astSchema.GraphQL || astSchema.Metrics {
 			return astSchema
 		}
+		noRequestBody = astSchema.NoRequestBody
+		directives = astSchema.Directives
+		formats = astSchema.ResponseFormats
 	}
 
 	// Final fallback: Generate generic schemas for Docker/production environments
-	return g.schemaAnalyzer.GenerateFallbackSchemas()
-}
-
-// areSourceFilesAvailable checks if Go source files are available (not in Docker/production)
-func (g *GinHandlerAnalyzer) areSourceFilesAvailable() bool {
-	// Quick check: try to find any .go file in common locations
-	wd, err := os.Getwd()
-	if err != nil {
-		return false
+	g.logFallback(handler)
+	schema = g.schemaAnalyzer.GenerateFallbackSchemas()
+	if noRequestBody {
+		// The handler source was found and never reads a body, no placeholder is needed
+		schema.RequestSchema = spec.Schema{}
+		schema.NoRequestBody = true
 	}
+	schema.Directives = directives
+	schema.ResponseFormats = formats
+	return schema
+}
 
-	// Check for .go files in current directory and common subdirectories
-	checkDirs := []string{
-		wd,
-		filepath.Join(wd, "internal"),
-		filepath.Join(wd, "pkg"),
-		filepath.Join(wd, "cmd"),
+// logFallback reports a handler documented with generic schemas to the logger of the config
+func (g *GinHandlerAnalyzer) logFallback(handler interface{}) {
+	if g.config == nil || g.config.Logger() == nil {
+		return
 	}
-
-	for _, dir := range checkDirs {
-		if files, err := os.ReadDir(dir); err == nil {
-			for _, file := range files {
-				if strings.HasSuffix(file.Name(), ".go") {
-					return true
-				}
-			}
-		}
+	name := ""
+	if value := reflect.ValueOf(handler); value.Kind() == reflect.Func {
+		name = g.handlerNameExtractor.GetOriginalHandlerName(value)
 	}
-
-	return false
+	g.config.Logger().Debug("No schema found for handler, documenting generic schemas", "handler", name)
 }
 
 // tryASTAnalysis attempts AST-based analysis when source files are available
@@ -328,7 +382,7 @@ func (g *GinHandlerAnalyzer) tryASTAnalysis(handler interface{}) analyzer.Handle
 				}
 			}
 			// Try to find the handler file and analyze it using AST
-			if sourceFile := g.astAnalyzer.FindHandlerSourceFile(fullName); sourceFile != "" {
+			if sourceFile := g.astAnalyzer.FindHandlerSourceFileForPC(pc, fullName); sourceFile != "" {
 				return g.astAnalyzer.AnalyzeHandlerWithAST(sourceFile, originalHandlerName, "gin")
 			}
 		}
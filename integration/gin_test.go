@@ -2,11 +2,15 @@ package integration
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/zainokta/openapi-gen/logger"
 )
 
 // TestGinHandlerAnalyzer_NewAnalyzer tests the analyzer creation
@@ -110,6 +114,45 @@ func TestGinRouteDiscoverer(t *testing.T) {
 	}
 }
 
+// ginUserHandler is a handler struct used to test handler name resolution
+type ginUserHandler struct{}
+
+func (h *ginUserHandler) ListUsers(c *gin.Context) {}
+
+// ginRespond mimics a route helper that appends a response-writing closure to the chain
+func ginRespond() gin.HandlerFunc {
+	return func(c *gin.Context) {}
+}
+
+// ginRoute mimics a route helper that wraps the handler in a closure, the handler is not in the chain
+func ginRoute(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) { handler(c) }
+}
+
+// TestGinRouteDiscoverer_WrappedHandlers tests that wrapper closures are unwrapped via the handler chain
+func TestGinRouteDiscoverer_WrappedHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	handler := &ginUserHandler{}
+
+	api := engine.Group("/api", sampleGinHandler)
+	api.GET("/users", handler.ListUsers, ginRespond())
+	api.GET("/direct", handler.ListUsers)
+	api.GET("/captured", ginRoute(handler.ListUsers))
+
+	routes, err := NewGinRouteDiscoverer(engine).DiscoverRoutes()
+	assert.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, route := range routes {
+		names[route.Path] = route.HandlerName
+	}
+	assert.Equal(t, "ListUsers", names["/api/users"], "Wrapper closure should resolve to the handler before it")
+	assert.Equal(t, "ListUsers", names["/api/direct"])
+	assert.NotEqual(t, "sampleGinHandler", names["/api/captured"], "Group middleware is not the wrapped handler")
+	assert.NotEmpty(t, names["/api/captured"])
+}
+
 // TestGinServerAdapter tests the server adapter
 func TestGinServerAdapter(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -127,18 +170,23 @@ func TestGinServerAdapter(t *testing.T) {
 	// This should not panic
 	assert.NotPanics(t, func() {
 		adapter.GET("/test-adapter", testHandler)
+		adapter.POST("/test-adapter", testHandler)
+		adapter.HEAD("/test-adapter", testHandler)
+		adapter.Handle(http.MethodPut, "/test-adapter", testHandler)
 	}, "Should not panic when adding route")
 
-	// Verify the route was added to the engine
-	routes := engine.Routes()
-	found := false
-	for _, route := range routes {
-		if route.Method == "GET" && route.Path == "/test-adapter" {
-			found = true
-			break
+	// Verify the routes were added to the engine
+	methods := make(map[string]bool)
+	for _, route := range engine.Routes() {
+		if route.Path == "/test-adapter" {
+			methods[route.Method] = true
 		}
 	}
-	assert.True(t, found, "Route should be added to the engine")
+	assert.Equal(t, map[string]bool{"GET": true, "POST": true, "HEAD": true, "PUT": true}, methods, "Routes should be added to the engine")
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/test-adapter", strings.NewReader("{}")))
+	assert.Equal(t, "test response", rec.Body.String())
 }
 
 // TestAutoDiscoverer_Gin tests the auto discoverer with Gin
@@ -159,4 +207,59 @@ func TestAutoDiscoverer_Gin(t *testing.T) {
 	assert.Len(t, routes, 1, "Should discover 1 route")
 	assert.Equal(t, "GET", routes[0].Method, "Method should be GET")
 	assert.Equal(t, "/test", routes[0].Path, "Path should be /test")
-}
\ No newline at end of file
+}
+// TestNewHandlerAnalyzer tests that handlers are analyzed by the analyzer of their framework
+func TestNewHandlerAnalyzer(t *testing.T) {
+	assert.IsType(t, &GinHandlerAnalyzer{}, NewHandlerAnalyzer(gin.New(), ""))
+	assert.IsType(t, &HertzHandlerAnalyzer{}, NewHandlerAnalyzer(server.Default(), ""))
+
+	// Custom discoverers name their framework
+	assert.IsType(t, &GinHandlerAnalyzer{}, NewHandlerAnalyzer(nil, "Gin"))
+	assert.IsType(t, &HertzHandlerAnalyzer{}, NewHandlerAnalyzer(nil, "MyFramework"))
+}
+
+// analysisConfig is a stub generator config for the analyzers
+type analysisConfig struct {
+	production bool
+	ast        bool
+	logger     logger.Logger
+}
+
+func (c analysisConfig) IsProductionMode() bool     { return c.production }
+func (c analysisConfig) IsASTAnalysisEnabled() bool { return c.ast }
+func (c analysisConfig) SourceDirs() []string       { return nil }
+func (c analysisConfig) Logger() logger.Logger      { return c.logger }
+
+// debugRecorder records the debug messages it is given
+type debugRecorder struct {
+	logger.NoOpLogger
+	messages []string
+}
+
+func (r *debugRecorder) Debug(msg string, args ...any) {
+	r.messages = append(r.messages, msg)
+}
+
+// TestGinHandlerAnalyzer_SetConfig tests that handler source is only read when the config allows it
+func TestGinHandlerAnalyzer_SetConfig(t *testing.T) {
+	handlerAnalyzer := NewGinHandlerAnalyzer()
+	assert.True(t, handlerAnalyzer.isASTAnalysisEnabled(), "Source should be read without config")
+	assert.False(t, handlerAnalyzer.isProductionMode())
+
+	handlerAnalyzer.SetConfig(analysisConfig{ast: false})
+	assert.False(t, handlerAnalyzer.isASTAnalysisEnabled())
+
+	handlerAnalyzer.SetConfig(analysisConfig{ast: true, production: true})
+	assert.True(t, handlerAnalyzer.isASTAnalysisEnabled())
+	assert.True(t, handlerAnalyzer.isProductionMode())
+}
+
+// TestGinHandlerAnalyzer_LogsFallback tests that handlers documented with generic schemas are logged
+func TestGinHandlerAnalyzer_LogsFallback(t *testing.T) {
+	recorder := &debugRecorder{}
+	handlerAnalyzer := NewGinHandlerAnalyzer()
+	handlerAnalyzer.SetConfig(analysisConfig{ast: false, logger: recorder})
+
+	handlerAnalyzer.AnalyzeHandler(sampleGinHandler)
+	assert.Len(t, recorder.messages, 1)
+}
@@ -1,20 +1,20 @@
 package integration
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"net/http"
-	"os"
-	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
 	"github.com/cloudwego/hertz/pkg/route"
 
 	"github.com/zainokta/openapi-gen/analyzer"
@@ -27,6 +27,7 @@ import (
 type HertzRouteDiscoverer struct {
 	engine               *server.Hertz
 	handlerNameExtractor *common.HandlerNameExtractor
+	annotations          *RouteAnnotations
 }
 
 // NewHertzRouteDiscoverer creates a new Hertz route discoverer
@@ -44,12 +45,19 @@ func (h *HertzRouteDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
 	// Use Hertz's built-in Routes() method to get all registered routes
 	hertzRoutes := h.engine.Routes()
 
+	// Full handler chains let routes registered through wrapper closures resolve the real handler
+	chains := common.CollectHandlerChains(h.engine)
+
 	for _, route := range hertzRoutes {
 		routeInfo := spec.RouteInfo{
 			Method:      route.Method,
 			Path:        route.Path,
-			HandlerName: h.extractHandlerName(route),
+			HandlerName: h.extractHandlerName(route, chains),
 			Handler:     route.HandlerFunc,
+			CORS:        common.ChainHasCORS(chains[common.HandlerChainKey(route.Method, route.Path)]),
+		}
+		if h.annotations != nil {
+			h.annotations.Apply(&routeInfo)
 		}
 
 		routes = append(routes, routeInfo)
@@ -59,7 +67,7 @@ func (h *HertzRouteDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
 }
 
 // extractHandlerName extracts handler name from Hertz route info
-func (h *HertzRouteDiscoverer) extractHandlerName(route route.RouteInfo) string {
+func (h *HertzRouteDiscoverer) extractHandlerName(route route.RouteInfo, chains common.HandlerChains) string {
 	// Try to extract meaningful handler name from the route
 	if route.HandlerFunc != nil {
 		// Use reflection to get function name if possible
@@ -74,6 +82,14 @@ func (h *HertzRouteDiscoverer) extractHandlerName(route route.RouteInfo) string
 				if pc := handlerValue.Pointer(); pc != 0 {
 					if fn := runtime.FuncForPC(pc); fn != nil {
 						runtimeFuncName := fn.Name()
+						// A wrapper closure hides the real handler, look for it earlier in the chain
+						if h.handlerNameExtractor.IsClosureName(runtimeFuncName) {
+							if chain := chains[common.HandlerChainKey(route.Method, route.Path)]; len(chain) > 1 {
+								if name := h.handlerNameExtractor.HandlerNameFromChain(runtimeFuncName, chain[:len(chain)-1]); name != "" {
+									return name
+								}
+							}
+						}
 						if runtimeFuncName != "" && !isGenericFuncSignature(runtimeFuncName) {
 							cleanName := h.handlerNameExtractor.ParseHandlerNameFromFunction(runtimeFuncName)
 							if cleanName != "" {
@@ -108,6 +124,11 @@ func isGenericFuncSignature(signature string) bool {
 		len(signature) < 10 // Too short to be meaningful
 }
 
+// SetAnnotations sets the annotations copied into the routes discovered, see RouteAnnotations
+func (h *HertzRouteDiscoverer) SetAnnotations(annotations *RouteAnnotations) {
+	h.annotations = annotations
+}
+
 // GetFrameworkName returns the framework name
 func (h *HertzRouteDiscoverer) GetFrameworkName() string {
 	return "CloudWeGo Hertz"
@@ -125,6 +146,21 @@ func NewHertzServerAdapter(hertz *server.Hertz) HTTPServer {
 
 // GET implements the HTTPServer interface by adapting to Hertz
 func (h *HertzServerAdapter) GET(path string, handler HTTPHandler) {
+	h.Handle(http.MethodGet, path, handler)
+}
+
+// POST implements the HTTPServer interface by adapting to Hertz
+func (h *HertzServerAdapter) POST(path string, handler HTTPHandler) {
+	h.Handle(http.MethodPost, path, handler)
+}
+
+// HEAD implements the HTTPServer interface by adapting to Hertz
+func (h *HertzServerAdapter) HEAD(path string, handler HTTPHandler) {
+	h.Handle(http.MethodHead, path, handler)
+}
+
+// Handle implements the HTTPServer interface by adapting to Hertz
+func (h *HertzServerAdapter) Handle(method, path string, handler HTTPHandler) {
 	// Convert the generic HTTPHandler to a Hertz HandlerFunc
 	hertzHandler := func(ctx context.Context, c *app.RequestContext) {
 		// Create a response writer that adapts Hertz RequestContext to http.ResponseWriter
@@ -133,28 +169,40 @@ func (h *HertzServerAdapter) GET(path string, handler HTTPHandler) {
 			headers: make(http.Header),
 		}
 
-		// Create a request from Hertz RequestContext
-		req := &http.Request{
-			Method: string(c.Method()),
-			Header: make(http.Header),
+		// Create a request from Hertz RequestContext, with its URL and body for handlers beyond GET
+		req, err := http.NewRequestWithContext(ctx, string(c.Method()), c.Request.URI().String(), bytes.NewReader(c.Request.Body()))
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
 		}
 
+		req.RequestURI = string(c.Request.RequestURI())
+		req.RemoteAddr = c.RemoteAddr().String()
+
 		// Copy headers from Hertz to standard HTTP
 		c.Request.Header.VisitAll(func(key, value []byte) {
 			req.Header.Add(string(key), string(value))
 		})
 
-		// Call the generic handler
+		// Call the generic handler, then send what it buffered
 		handler(rw, req)
+		rw.finish()
 	}
 
-	h.hertz.GET(path, hertzHandler)
+	h.hertz.Handle(method, path, hertzHandler)
 }
 
 // hertzResponseWriter adapts Hertz RequestContext to http.ResponseWriter
+//
+// Hertz sends the response once the handler returns, so the body is buffered and the status
+// and headers are applied by finish, including headers set after WriteHeader. Flush commits
+// them and switches the response to a chunked stream, later writes go straight to the client.
 type hertzResponseWriter struct {
-	ctx     *app.RequestContext
-	headers http.Header
+	ctx         *app.RequestContext
+	headers     http.Header
+	status      int
+	wroteHeader bool
+	streaming   bool
 }
 
 func (w *hertzResponseWriter) Header() http.Header {
@@ -162,18 +210,70 @@ func (w *hertzResponseWriter) Header() http.Header {
 }
 
 func (w *hertzResponseWriter) Write(data []byte) (int, error) {
-	w.ctx.Write(data)
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.streaming {
+		return w.ctx.Response.GetHijackWriter().Write(data)
+	}
+	w.ctx.Response.AppendBody(data)
 	return len(data), nil
 }
 
 func (w *hertzResponseWriter) WriteHeader(statusCode int) {
-	// Apply all stored headers to the Hertz response
+	// Like net/http, only the first status code counts
+	if w.wroteHeader {
+		return
+	}
+	w.status = statusCode
+	w.wroteHeader = true
+}
+
+// Flush implements http.Flusher
+func (w *hertzResponseWriter) Flush() {
+	w.FlushError() //nolint:errcheck
+}
+
+// FlushError sends the status, headers and buffered body to the client, it is used by http.ResponseController
+func (w *hertzResponseWriter) FlushError() error {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.streaming {
+		w.applyHeaders()
+		buffered := append([]byte(nil), w.ctx.Response.Body()...)
+		w.ctx.Response.ResetBody()
+		w.ctx.Response.HijackWriter(resp.NewChunkedBodyWriter(&w.ctx.Response, w.ctx.GetWriter()))
+		w.streaming = true
+		if len(buffered) > 0 {
+			if _, err := w.ctx.Response.GetHijackWriter().Write(buffered); err != nil {
+				return err
+			}
+		}
+	}
+	return w.ctx.Flush()
+}
+
+// finish applies the status and headers of a buffered response once the handler returned
+func (w *hertzResponseWriter) finish() {
+	if w.streaming {
+		return
+	}
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.applyHeaders()
+}
+
+// applyHeaders copies the status and headers to the Hertz response, keeping every value of a header
+func (w *hertzResponseWriter) applyHeaders() {
+	w.ctx.SetStatusCode(w.status)
 	for key, values := range w.headers {
+		w.ctx.Response.Header.Del(key)
 		for _, value := range values {
-			w.ctx.Response.Header.Set(key, value)
+			w.ctx.Response.Header.Add(key, value)
 		}
 	}
-	w.ctx.SetStatusCode(statusCode)
 }
 
 // HertzHandlerAnalyzer analyzes CloudWeGo Hertz handlers
@@ -182,8 +282,8 @@ type HertzHandlerAnalyzer struct {
 	astAnalyzer          *common.ASTAnalyzer
 	typeResolver         *common.TypeResolver
 	schemaAnalyzer       *common.SchemaAnalyzer
-	sourceFilePath       string      // Path to the source file being analyzed
-	config               interface{} // Configuration passed from library consumer
+	sourceFilePath       string          // Path to the source file being analyzed
+	config               analyzer.Config // Configuration passed from library consumer
 }
 
 // NewHertzHandlerAnalyzer creates a new Hertz handler analyzer
@@ -206,31 +306,53 @@ func (h *HertzHandlerAnalyzer) GetSchemaGenerator() *analyzer.SchemaGenerator {
 	return h.schemaAnalyzer.GetSchemaGenerator()
 }
 
+// SetUnions shares the unions of the generator's schema registry with every schema generator of the analyzer
+func (h *HertzHandlerAnalyzer) SetUnions(unions *analyzer.UnionRegistry) {
+	h.schemaAnalyzer.GetSchemaGenerator().SetUnions(unions)
+	h.astAnalyzer.SetUnions(unions)
+}
+
+// SetVendorSources controls whether handler packages are also looked up in vendor/
+func (h *HertzHandlerAnalyzer) SetVendorSources(enabled bool) {
+	h.astAnalyzer.SetVendorSources(enabled)
+}
+
+// SetBuildTags sets the build tags handler source files are matched against
+func (h *HertzHandlerAnalyzer) SetBuildTags(tags []string) {
+	h.astAnalyzer.SetBuildTags(tags)
+}
+
+// SetSourceDirs sets the directories handler sources are searched from, instead of the working directory
+func (h *HertzHandlerAnalyzer) SetSourceDirs(dirs []string) {
+	h.astAnalyzer.SetSourceDirs(dirs)
+}
+
+// SetSourceExclude sets glob patterns of source files and directories that are never analyzed
+func (h *HertzHandlerAnalyzer) SetSourceExclude(patterns []string) {
+	h.astAnalyzer.SetSourceExclude(patterns)
+}
+
 // SetConfig sets the configuration for the analyzer (implements HandlerAnalyzer interface)
-func (h *HertzHandlerAnalyzer) SetConfig(config interface{}) {
+func (h *HertzHandlerAnalyzer) SetConfig(config analyzer.Config) {
 	h.config = config
+	if config != nil && len(config.SourceDirs()) > 0 {
+		h.SetSourceDirs(config.SourceDirs())
+	}
+}
+
+// Config returns the configuration set with SetConfig, nil when none was set
+func (h *HertzHandlerAnalyzer) Config() analyzer.Config {
+	return h.config
 }
 
 // isProductionMode checks if running in production mode based on config
 func (h *HertzHandlerAnalyzer) isProductionMode() bool {
-	if h.config != nil {
-		// Try to assert as our Config type
-		if cfg, ok := h.config.(interface{ IsProductionMode() bool }); ok {
-			return cfg.IsProductionMode()
-		}
-	}
-	return false
+	return h.config != nil && h.config.IsProductionMode()
 }
 
 // isASTAnalysisEnabled checks if AST analysis should be performed
 func (h *HertzHandlerAnalyzer) isASTAnalysisEnabled() bool {
-	if h.config != nil {
-		// Try to assert as our Config type
-		if cfg, ok := h.config.(interface{ IsASTAnalysisEnabled() bool }); ok {
-			return cfg.IsASTAnalysisEnabled()
-		}
-	}
-	return true // Default to enabled if no config
+	return h.config == nil || h.config.IsASTAnalysisEnabled() // Default to enabled if no config
 }
 
 // ExtractTypes extracts request and response types from Hertz handler function
@@ -276,43 +398,41 @@ func (h *HertzHandlerAnalyzer) AnalyzeHandler(handler interface{}) analyzer.Hand
 	}
 
 	// Second, try AST analysis (only if enabled and source files are available)
-	if h.isASTAnalysisEnabled() && !h.isProductionMode() && h.areSourceFilesAvailable() {
-		if astSchema := h.tryASTAnalysis(handler); astSchema.RequestSchema.Type != "" || astSchema.ResponseSchema.Type != "" {
+	noRequestBody := false
+	var directives, formats []string
+	if h.isASTAnalysisEnabled() && !h.isProductionMode() && h.astAnalyzer.SourceFilesAvailable() {
+		astSchema := h.tryASTAnalysis(handler)
+		if astSchema.RequestSchema.Type != "" || astSchema.ResponseSchema.Type != "" || astSchema.Stream != analyzer.StreamNone || astSchema.FileResponse || astSchema.GraphQL || astSchema.Metrics {
 			return astSchema
 		}
+		noRequestBody = astSchema.NoRequestBody
+		directives = astSchema.Directives
+		formats = astSchema.ResponseFormats
 	}
 
 	// Final fallback: Generate generic schemas for Docker/production environments
-	return h.schemaAnalyzer.GenerateFallbackSchemas()
-}
-
-// areSourceFilesAvailable checks if Go source files are available (not in Docker/production)
-func (h *HertzHandlerAnalyzer) areSourceFilesAvailable() bool {
-	// Quick check: try to find any .go file in common locations
-	wd, err := os.Getwd()
-	if err != nil {
-		return false
+	h.logFallback(handler)
+	schema = h.schemaAnalyzer.GenerateFallbackSchemas()
+	if noRequestBody {
+		// The handler source was found and never reads a body, no placeholder is needed
+		schema.RequestSchema = spec.Schema{}
+		schema.NoRequestBody = true
 	}
+	schema.Directives = directives
+	schema.ResponseFormats = formats
+	return schema
+}
 
-	// Check for .go files in current directory and common subdirectories
-	checkDirs := []string{
-		wd,
-		filepath.Join(wd, "internal"),
-		filepath.Join(wd, "pkg"),
-		filepath.Join(wd, "cmd"),
+// logFallback reports a handler documented with generic schemas to the logger of the config
+func (h *HertzHandlerAnalyzer) logFallback(handler interface{}) {
+	if h.config == nil || h.config.Logger() == nil {
+		return
 	}
-
-	for _, dir := range checkDirs {
-		if files, err := os.ReadDir(dir); err == nil {
-			for _, file := range files {
-				if strings.HasSuffix(file.Name(), ".go") {
-					return true
-				}
-			}
-		}
+	name := ""
+	if value := reflect.ValueOf(handler); value.Kind() == reflect.Func {
+		name = h.handlerNameExtractor.GetOriginalHandlerName(value)
 	}
-
-	return false
+	h.config.Logger().Debug("No schema found for handler, documenting generic schemas", "handler", name)
 }
 
 // tryASTAnalysis attempts AST-based analysis when source files are available
@@ -340,7 +460,7 @@ func (h *HertzHandlerAnalyzer) tryASTAnalysis(handler interface{}) analyzer.Hand
 				}
 			}
 			// Try to find the handler file and analyze it using AST
-			if sourceFile := h.astAnalyzer.FindHandlerSourceFile(fullName); sourceFile != "" {
+			if sourceFile := h.astAnalyzer.FindHandlerSourceFileForPC(pc, fullName); sourceFile != "" {
 				return h.astAnalyzer.AnalyzeHandlerWithAST(sourceFile, originalHandlerName, "hertz")
 			}
 		}
@@ -506,6 +626,7 @@ func (h *HertzHandlerAnalyzer) isBindAndValidateCall(callExpr *ast.CallExpr) boo
 		// Support multiple binding patterns for different frameworks
 		bindMethods := []string{
 			"BindAndValidate", // Hertz
+			"BindQuery",       // Hertz, query structs of GET and DELETE handlers
 			"ShouldBind",      // Gin
 			"ShouldBindJSON",  // Gin
 			"Bind",            // Echo, Fiber
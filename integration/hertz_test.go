@@ -1,11 +1,18 @@
 package integration
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"net/http"
 	"reflect"
 	"testing"
+	"testing/fstest"
 
 	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/test/mock"
+	"github.com/cloudwego/hertz/pkg/common/ut"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,3 +80,136 @@ func TestHertzHandlerAnalyzer_ValidateSignature(t *testing.T) {
 	assert.Error(t, err, "Should error with invalid signature")
 	assert.Contains(t, err.Error(), "expected 2 parameters", "Error should mention parameter count")
 }
+
+// hertzUserHandler is a handler struct used to test handler name resolution
+type hertzUserHandler struct{}
+
+func (h *hertzUserHandler) ListUsers(ctx context.Context, c *app.RequestContext) {}
+
+// hertzRespond mimics a route helper that appends a response-writing closure to the chain
+func hertzRespond() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {}
+}
+
+// hertzRoute mimics a route helper that wraps the handler in a closure, the handler is not in the chain
+func hertzRoute(handler app.HandlerFunc) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) { handler(ctx, c) }
+}
+
+// TestHertzRouteDiscoverer_WrappedHandlers tests that wrapper closures are unwrapped via the handler chain
+func TestHertzRouteDiscoverer_WrappedHandlers(t *testing.T) {
+	h := server.New()
+	handler := &hertzUserHandler{}
+
+	api := h.Group("/api", sampleHandler)
+	api.GET("/users", handler.ListUsers, hertzRespond())
+	api.GET("/users/:id", handler.ListUsers, hertzRespond())
+	api.GET("/captured", hertzRoute(handler.ListUsers))
+
+	routes, err := NewHertzRouteDiscoverer(h).DiscoverRoutes()
+	assert.NoError(t, err)
+	assert.Len(t, routes, 3)
+
+	for _, route := range routes {
+		if route.Path == "/api/captured" {
+			assert.NotEqual(t, "sampleHandler", route.HandlerName, "Group middleware is not the wrapped handler")
+			continue
+		}
+		assert.Equal(t, "ListUsers", route.HandlerName, "Wrapper closure should resolve for %s", route.Path)
+	}
+}
+
+// TestHertzServerAdapter tests that the adapter registers every method and passes the request body on
+func TestHertzServerAdapter(t *testing.T) {
+	h := server.New()
+	adapter := NewHertzServerAdapter(h)
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(r.Method + " " + r.URL.Query().Get("q") + " " + string(body)))
+	}
+	adapter.GET("/echo", echo)
+	adapter.POST("/echo", echo)
+	adapter.HEAD("/echo", echo)
+	adapter.Handle(http.MethodPut, "/echo", echo)
+
+	methods := make(map[string]bool)
+	for _, route := range h.Routes() {
+		if route.Path == "/echo" {
+			methods[route.Method] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"GET": true, "POST": true, "HEAD": true, "PUT": true}, methods)
+
+	w := ut.PerformRequest(h.Engine, http.MethodPost, "/echo?q=1", &ut.Body{Body: bytes.NewBufferString("payload"), Len: len("payload")})
+	assert.Equal(t, "POST 1 payload", string(w.Result().Body()))
+}
+
+// TestHertzResponseWriter tests that net/http handlers keep their status, headers and body through the adapter
+func TestHertzResponseWriter(t *testing.T) {
+	h := server.New()
+	adapter := NewHertzServerAdapter(h)
+
+	adapter.GET("/late-headers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("created"))
+	})
+	assets := fstest.MapFS{"app.js": {Data: []byte("console.log(\"docs\")")}}
+	adapter.GET("/ui/*filepath", http.StripPrefix("/ui", http.FileServer(http.FS(assets))).ServeHTTP)
+
+	w := ut.PerformRequest(h.Engine, http.MethodGet, "/late-headers", nil)
+	result := w.Result()
+	assert.Equal(t, http.StatusCreated, result.StatusCode())
+	assert.Equal(t, "text/plain", string(result.Header.ContentType()))
+	var vary []string
+	result.Header.VisitAll(func(key, value []byte) {
+		if string(key) == "Vary" {
+			vary = append(vary, string(value))
+		}
+	})
+	assert.Equal(t, []string{"Accept", "Accept-Encoding"}, vary)
+	assert.Equal(t, "created", string(result.Body()))
+
+	w = ut.PerformRequest(h.Engine, http.MethodGet, "/ui/app.js", nil)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode())
+	assert.Equal(t, "text/javascript; charset=utf-8", string(w.Result().Header.ContentType()))
+	assert.Equal(t, "console.log(\"docs\")", string(w.Result().Body()))
+}
+
+// TestHertzResponseWriter_Flush tests that flushed responses are streamed as chunks
+func TestHertzResponseWriter_Flush(t *testing.T) {
+	h := server.New()
+	adapter := NewHertzServerAdapter(h)
+
+	adapter.GET("/stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !assert.True(t, ok, "the writer should implement http.Flusher") {
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: one\n\n"))
+		flusher.Flush()
+		_, err := w.Write([]byte("data: two\n\n"))
+		assert.NoError(t, err)
+	})
+
+	conn := mock.NewConn("")
+	c := app.NewContext(0)
+	c.SetConn(conn)
+	c.Request.SetMethod(http.MethodGet)
+	c.Request.SetRequestURI("/stream")
+	h.Engine.ServeHTTP(context.Background(), c)
+	assert.NoError(t, c.Response.GetHijackWriter().Finalize())
+
+	sent, err := conn.WriterRecorder().ReadBinary(conn.WriterRecorder().WroteLen())
+	assert.NoError(t, err)
+	assert.Contains(t, string(sent), "Content-Type: text/event-stream")
+	assert.Contains(t, string(sent), "Transfer-Encoding: chunked")
+	assert.Contains(t, string(sent), "data: one\n\n")
+	assert.Contains(t, string(sent), "data: two\n\n")
+}
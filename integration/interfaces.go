@@ -6,6 +6,12 @@ import "net/http"
 type HTTPHandler func(w http.ResponseWriter, r *http.Request)
 
 // HTTPServer defines the interface for HTTP server operations needed by OpenAPI docs
+//
+// Handle registers a handler for any method, GET, POST and HEAD are shorthands for it.
+// Paths use the syntax of the framework, e.g. "/docs/assets/*filepath" for static assets.
 type HTTPServer interface {
 	GET(path string, handler HTTPHandler)
+	POST(path string, handler HTTPHandler)
+	HEAD(path string, handler HTTPHandler)
+	Handle(method, path string, handler HTTPHandler)
 }
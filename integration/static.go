@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/parser"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// StaticRouteDiscoverer discovers routes from source files instead of a running router
+//
+// Route registrations such as r.GET("/users", h.List) are read from the Go files under the
+// directory, for frameworks and custom routers that do not expose their route table. Routes
+// carry no handler function, their schemas come from the schema directory or bundle.
+type StaticRouteDiscoverer struct {
+	dir         string
+	annotations *RouteAnnotations
+}
+
+// NewStaticRouteDiscoverer creates a discoverer reading the routes registered under dir
+func NewStaticRouteDiscoverer(dir string) *StaticRouteDiscoverer {
+	return &StaticRouteDiscoverer{dir: dir}
+}
+
+// DiscoverRoutes parses the Go files under the directory, test files and hidden, vendor and
+// testdata directories are skipped. A method and path registered twice is reported once.
+func (s *StaticRouteDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
+	routeParser := parser.NewRouteParser()
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != s.dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		return routeParser.ParseRoutesFromFile(path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes from %s: %w", s.dir, err)
+	}
+
+	seen := make(map[string]bool)
+	routes := make([]spec.RouteInfo, 0)
+	for _, route := range routeParser.GetRoutes() {
+		key := route.Method + " " + route.Path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if s.annotations != nil {
+			s.annotations.Apply(&route)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// SetAnnotations sets the annotations copied into the routes discovered, see RouteAnnotations
+func (s *StaticRouteDiscoverer) SetAnnotations(annotations *RouteAnnotations) {
+	s.annotations = annotations
+}
+
+// GetFrameworkName returns the framework name
+func (s *StaticRouteDiscoverer) GetFrameworkName() string {
+	return "static"
+}
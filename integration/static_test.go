@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticRouteDiscoverer_DiscoverRoutes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	write("routes.go", `package app
+
+func Register(r Router, h *Handler) {
+	r.GET("/health", Health)
+	api := r.Group("/api")
+	users := api.Group("/users")
+	users.GET("/:id", auth, h.GetUser)
+	users.POST("/", h.CreateUser)
+	r.Group("/admin").DELETE(`+"`/cache`"+`, h.ClearCache)
+	r.Handle("PATCH", "/settings", h.UpdateSettings)
+}
+`)
+	write("more/routes.go", `package more
+
+func Register(r Router, h *Handler) {
+	r.GET("/health", Health)
+	api := r.Group("/v2")
+	api.PUT("/items", h.PutItems)
+}
+`)
+	write("routes_test.go", `package app
+
+func registerTest(r Router) { r.GET("/test-only", Test) }
+`)
+	write("vendor/lib/routes.go", `package lib
+
+func Register(r Router) { r.GET("/vendored", Vendored) }
+`)
+
+	routes, err := NewStaticRouteDiscoverer(dir).DiscoverRoutes()
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, route := range routes {
+		got[route.Method+" "+route.Path] = route.HandlerName
+	}
+	assert.Equal(t, map[string]string{
+		"GET /health":         "Health",
+		"GET /api/users/:id":  "h.GetUser",
+		"POST /api/users/":    "h.CreateUser",
+		"DELETE /admin/cache": "h.ClearCache",
+		"PATCH /settings":     "h.UpdateSettings",
+		"PUT /v2/items":       "h.PutItems",
+	}, got)
+	assert.Equal(t, "static", NewStaticRouteDiscoverer(dir).GetFrameworkName())
+}
@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// routeKey identifies a route by method and path, e.g. "GET /users/:id"
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// AddRoute documents a route registered after the spec was generated
+//
+// Only the new route is analyzed, the rest of the spec is rebuilt from the cached
+// analysis of the existing routes. When the docs are served the spec endpoint switches
+// to the updated spec, and the OnSpecChange listeners are notified. A route with the
+// method and path of an existing one replaces it. Added routes are merged into every
+// discovery, so they stay documented by GenerateSpec and RefreshRoutes even if the
+// discoverer cannot see them.
+//
+// Example:
+//
+//	router.GET("/plugins/:name", pluginHandler)
+//	err := g.AddRoute(spec.RouteInfo{Method: "GET", Path: "/plugins/:name", Handler: pluginHandler})
+func (g *Generator) AddRoute(route spec.RouteInfo) error {
+	if route.Method == "" || route.Path == "" {
+		return fmt.Errorf("route needs a method and a path, got %q %q", route.Method, route.Path)
+	}
+
+	g.mu.Lock()
+	key := routeKey(route.Method, route.Path)
+	index := slices.IndexFunc(g.addedRoutes, func(added spec.RouteInfo) bool {
+		return routeKey(added.Method, added.Path) == key
+	})
+	if index >= 0 {
+		g.addedRoutes[index] = route
+	} else {
+		g.addedRoutes = append(g.addedRoutes, route)
+	}
+
+	// Nothing generated yet, the next GenerateSpec picks the route up
+	if g.spec == nil {
+		g.mu.Unlock()
+		return nil
+	}
+
+	ctx := context.Background()
+	if err := g.processRoute(ctx, g.resolveHandlerName(route)); err != nil {
+		g.mu.Unlock()
+		return fmt.Errorf("failed to process route %s: %w", key, err)
+	}
+	g.telemetry.routesProcessed.Add(ctx, 1)
+
+	return g.updateSpec()
+}
+
+// RefreshRoutes brings the spec in line with the routes the framework serves now
+//
+// Routes are discovered again: new routes are analyzed and documented, routes that are
+// no longer served are removed together with their schemas and overrides, and routes that were already documented are not
+// analyzed again. Call it after changing the router at runtime so the docs endpoint
+// reflects the live router. Listeners are only notified when the spec changed.
+//
+// Example:
+//
+//	admin := router.Group("/admin")
+//	admin.GET("/stats", statsHandler)
+//	if err := g.RefreshRoutes(); err != nil {
+//		log.Printf("failed to refresh API docs: %v", err)
+//	}
+func (g *Generator) RefreshRoutes() error {
+	g.mu.Lock()
+
+	ctx := context.Background()
+	routes, err := g.discoverRoutes(ctx)
+	if err != nil {
+		g.mu.Unlock()
+		return err
+	}
+
+	changed := g.spec == nil
+	live := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+		live[key] = true
+		if _, exists := g.routes[key]; exists {
+			continue
+		}
+		if err := g.processRoute(ctx, g.resolveHandlerName(route)); err != nil {
+			g.logger.Warn("Failed to process route", "method", route.Method, "path", route.Path, "error", err)
+			g.telemetry.analysisFailures.Add(ctx, 1)
+			continue
+		}
+		g.telemetry.routesProcessed.Add(ctx, 1)
+		changed = true
+	}
+	for key, analyzed := range g.routes {
+		if !live[key] {
+			// Its components must not outlive the route
+			g.schemaRegistry.UnregisterRoute(analyzed.route.Method, analyzed.route.Path)
+			delete(g.routes, key)
+			changed = true
+		}
+	}
+
+	if !changed {
+		g.mu.Unlock()
+		return nil
+	}
+	return g.updateSpec()
+}
+
+// OnSpecChange registers a listener called with the new spec after AddRoute or RefreshRoutes changes it
+//
+// Listeners run after the spec endpoint serves the new spec, outside the generator's
+// lock, so they may call back into the generator. They must not modify the spec.
+//
+// Example:
+//
+//	g.OnSpecChange(func(s *spec.OpenAPISpec) {
+//		log.Printf("API docs updated, %d paths", len(s.Paths))
+//	})
+func (g *Generator) OnSpecChange(listener func(*spec.OpenAPISpec)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.listeners = append(g.listeners, listener)
+}
+
+// withAddedRoutes merges the routes added with AddRoute into the discovered routes, added routes win
+func (g *Generator) withAddedRoutes(routes []spec.RouteInfo) []spec.RouteInfo {
+	if len(g.addedRoutes) == 0 {
+		return routes
+	}
+
+	// Discoverers may return their own slice, never write to it
+	routes = slices.Clone(routes)
+	for _, added := range g.addedRoutes {
+		key := routeKey(added.Method, added.Path)
+		index := slices.IndexFunc(routes, func(route spec.RouteInfo) bool {
+			return routeKey(route.Method, route.Path) == key
+		})
+		if index >= 0 {
+			routes[index] = added
+		} else {
+			routes = append(routes, added)
+		}
+	}
+	return routes
+}
+
+// updateSpec rebuilds the spec, swaps the served document and notifies the listeners
+//
+// It must be called with g.mu held and releases it before running the listeners.
+func (g *Generator) updateSpec() error {
+	updated := g.buildSpec()
+
+	// Only swap the document once ServeSwaggerUI serves one
+	if g.document.Load() != nil {
+		document, err := newSpecDocument(updated)
+		if err != nil {
+			g.mu.Unlock()
+			return err
+		}
+		g.document.Store(document)
+	}
+	listeners := slices.Clone(g.listeners)
+	g.mu.Unlock()
+
+	g.logger.Info("Updated OpenAPI spec", "paths", len(updated.Paths), "schemas", len(updated.Components.Schemas))
+	for _, listener := range listeners {
+		listener(updated)
+	}
+	return nil
+}
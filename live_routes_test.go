@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestAddRouteUpdatesServedSpec(t *testing.T) {
+	server := newRecordingServer()
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health"}})
+	assert.NoError(t, generator.ServeSwaggerUI(server))
+	checksum := generator.SpecChecksum()
+
+	var notified []*spec.OpenAPISpec
+	generator.OnSpecChange(func(updated *spec.OpenAPISpec) {
+		notified = append(notified, updated)
+	})
+
+	assert.Error(t, generator.AddRoute(spec.RouteInfo{Path: "/plugins"}))
+	assert.NoError(t, generator.AddRoute(spec.RouteInfo{Method: "GET", Path: "/plugins/:name"}))
+
+	if assert.Len(t, notified, 1) {
+		assert.Contains(t, notified[0].Paths, "/health")
+		assert.Contains(t, notified[0].Paths, "/plugins/{name}")
+	}
+	assert.NotEqual(t, checksum, generator.SpecChecksum())
+
+	var served spec.OpenAPISpec
+	assert.NoError(t, json.Unmarshal(server.serve("/openapi.json", nil).Body.Bytes(), &served))
+	assert.Contains(t, served.Paths, "/plugins/{name}")
+
+	// Added routes survive a full regeneration
+	regenerated, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, regenerated.Paths, "/plugins/{name}")
+}
+
+func TestRefreshRoutes(t *testing.T) {
+	discoverer := &staticDiscoverer{routes: []spec.RouteInfo{
+		{Method: "GET", Path: "/health"},
+		{Method: "GET", Path: "/users"},
+	}}
+	generator := newTestGenerator(t, nil, WithRouteDiscoverer(discoverer))
+	userSchema := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"user_id": {Type: "string"}}}
+	generator.schemaRegistry.RegisterResponseSchema("GET", "/users", userSchema)
+	generator.schemaRegistry.RegisterResponseOverride("GET", "/users", 404, userSchema)
+	_, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	notifications := 0
+	var updated *spec.OpenAPISpec
+	generator.OnSpecChange(func(changed *spec.OpenAPISpec) {
+		notifications++
+		updated = changed
+	})
+
+	// Nothing changed, nothing to notify
+	assert.NoError(t, generator.RefreshRoutes())
+	assert.Equal(t, 0, notifications)
+
+	discoverer.routes = []spec.RouteInfo{
+		{Method: "GET", Path: "/health"},
+		{Method: "POST", Path: "/orders"},
+	}
+	assert.NoError(t, generator.RefreshRoutes())
+	if !assert.Equal(t, 1, notifications) {
+		return
+	}
+	assert.Contains(t, updated.Paths, "/orders")
+	assert.NotContains(t, updated.Paths, "/users")
+
+	tags := make([]string, 0, len(updated.Tags))
+	for _, tag := range updated.Tags {
+		tags = append(tags, tag.Name)
+	}
+	assert.NotContains(t, tags, "users")
+
+	// The components of the removed route are gone as well
+	assert.False(t, generator.schemaRegistry.HasResponseSchema("GET", "/users"))
+	assert.Empty(t, generator.schemaRegistry.GetResponseOverrides("GET", "/users"))
+	for name, schema := range updated.Components.Schemas {
+		assert.NotContains(t, schema.Properties, "user_id", "Component of a removed route: %s", name)
+	}
+}
+
+func TestRefreshRoutesKeepsAddedRoutes(t *testing.T) {
+	discoverer := &staticDiscoverer{routes: []spec.RouteInfo{{Method: "GET", Path: "/health"}}}
+	generator := newTestGenerator(t, nil, WithRouteDiscoverer(discoverer))
+	_, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	var updated *spec.OpenAPISpec
+	generator.OnSpecChange(func(changed *spec.OpenAPISpec) { updated = changed })
+
+	// The discoverer never sees the added route
+	assert.NoError(t, generator.AddRoute(spec.RouteInfo{Method: "GET", Path: "/plugins/:name"}))
+	discoverer.routes = []spec.RouteInfo{{Method: "GET", Path: "/health"}, {Method: "GET", Path: "/status"}}
+	assert.NoError(t, generator.RefreshRoutes())
+
+	if assert.NotNil(t, updated) {
+		assert.Contains(t, updated.Paths, "/status")
+		assert.Contains(t, updated.Paths, "/plugins/{name}")
+	}
+}
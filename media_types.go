@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// applyMediaTypeOverrides documents the request body and responses of a route in the content
+// types set with Override
+//
+// The schema documented for JSON is kept for the other structured formats, forms included,
+// text is a string and other content types are binary. A response the operation does not
+// document yet is added, and so is a request body unless OverrideRequest documents none.
+func (g *Generator) applyMediaTypeOverrides(route spec.RouteInfo, operation *spec.Operation) {
+	mediaTypes, exists := g.overrideManager.GetRouteMediaTypes(route.Method, route.Path)
+	if !exists {
+		return
+	}
+
+	if mediaTypes.Request != "" {
+		override, overridden := g.schemaRegistry.GetRequestOverride(route.Method, route.Path)
+		if operation.RequestBody == nil && !(overridden && reflect.DeepEqual(override, spec.Schema{})) {
+			requestBody := g.generateRequestBodyFromRoute(route)
+			operation.RequestBody = &requestBody
+		}
+		if operation.RequestBody != nil {
+			operation.RequestBody.Content = withContentType(operation.RequestBody.Content, mediaTypes.Request)
+		}
+	}
+
+	for status, contentType := range mediaTypes.Responses {
+		code := strconv.Itoa(status)
+		response, exists := operation.Responses[code]
+		if !exists {
+			response.Description = http.StatusText(status)
+			if response.Description == "" {
+				response.Description = "Response " + code
+			}
+		}
+		response.Content = withContentType(response.Content, contentType)
+		operation.Responses[code] = response
+	}
+}
+
+// withContentType documents content in a single content type, converting the schema
+// documented for JSON, or else for any other content type
+func withContentType(content map[string]spec.MediaType, contentType string) map[string]spec.MediaType {
+	mediaType, exists := content["application/json"]
+	if !exists {
+		for _, other := range content {
+			mediaType = other
+			break
+		}
+	}
+	if mediaType.Schema.Type == "" && mediaType.Schema.Ref == "" {
+		mediaType.Schema = spec.Schema{Type: "object"}
+	}
+	if schema := mediaTypeSchema(contentType, mediaType.Schema); !reflect.DeepEqual(schema, mediaType.Schema) {
+		// Examples of the JSON content do not fit text or binary content
+		mediaType = spec.MediaType{Schema: schema}
+	}
+	return map[string]spec.MediaType{contentType: mediaType}
+}
+
+// mediaTypeSchema returns the schema of content of a content type, given its JSON schema
+//
+// Structured formats, JSON variants and forms share the JSON schema, text such as
+// text/plain or text/csv is a string and other formats, e.g. application/pdf, are binary.
+func mediaTypeSchema(contentType string, jsonSchema spec.Schema) spec.Schema {
+	switch mediaType, _, _ := strings.Cut(contentType, ";"); {
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/xml", mediaType == "application/yaml", mediaType == "application/toml",
+		mediaType == "multipart/form-data", mediaType == "application/x-www-form-urlencoded":
+		return jsonSchema
+	case strings.HasPrefix(mediaType, "text/"):
+		return spec.Schema{Type: "string"}
+	default:
+		return spec.Schema{Type: "string", Format: "binary"}
+	}
+}
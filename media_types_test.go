@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mediaTypeImport struct {
+	Name string `json:"name"`
+}
+
+func TestOverrideMediaTypes(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/imports"},
+		{Method: "POST", Path: "/upload"},
+		{Method: "POST", Path: "/logout"},
+		{Method: "GET", Path: "/reports/:id"},
+	})
+	generator.OverrideRequest("POST", "/imports", mediaTypeImport{})
+	generator.Override("POST", "/imports").
+		RequestContentType("application/x-www-form-urlencoded").
+		ResponseContentType(202, "application/vnd.api+json")
+	generator.Override("POST", "/upload").RequestContentType("application/octet-stream")
+	generator.OverrideRequest("POST", "/logout", nil)
+	generator.Override("POST", "/logout").RequestContentType("multipart/form-data")
+	// Either path syntax names the route
+	generator.Override("GET", "/reports/{id}").ResponseContentType(200, "text/csv")
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	imports := openAPISpec.Paths["/imports"].Post
+	require.NotNil(t, imports.RequestBody)
+	require.Len(t, imports.RequestBody.Content, 1)
+	assert.Equal(t, "object", resolveRef(t, openAPISpec, imports.RequestBody.Content["application/x-www-form-urlencoded"].Schema).Type)
+	accepted := imports.Responses["202"]
+	assert.Equal(t, "Accepted", accepted.Description)
+	assert.Equal(t, map[string]spec.MediaType{"application/vnd.api+json": {Schema: spec.Schema{Type: "object"}}}, accepted.Content)
+
+	upload := openAPISpec.Paths["/upload"].Post
+	require.NotNil(t, upload.RequestBody)
+	assert.Equal(t, map[string]spec.MediaType{"application/octet-stream": {Schema: spec.Schema{Type: "string", Format: "binary"}}}, upload.RequestBody.Content)
+
+	// A route overridden without a body keeps none
+	assert.Nil(t, openAPISpec.Paths["/logout"].Post.RequestBody)
+
+	report := openAPISpec.Paths["/reports/{id}"].Get.Responses
+	assert.Equal(t, map[string]spec.MediaType{"text/csv": {Schema: spec.Schema{Type: "string"}}}, report["200"].Content)
+	assert.Contains(t, report["400"].Content, "application/json")
+
+	assert.Same(t, generator.Override("get", "/reports/:id"), generator.Override("GET", "/reports/{id}"))
+}
@@ -0,0 +1,349 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// mockMaxDepth bounds how deep synthesized examples nest, recursive schemas stop there
+const mockMaxDepth = 8
+
+// MockServer serves fake responses for every operation of a spec
+//
+// Each response is the documented example of the operation's first success response or,
+// without one, a value synthesized from its schema that keeps to the documented
+// constraints: enums, formats, bounds and lengths. Clients pick another documented
+// response with a "Prefer: code=404" request header. Requests are not validated.
+//
+// Example:
+//
+//	openAPISpec, _ := generator.GenerateSpec()
+//	http.ListenAndServe(":4010", openapi.NewMockServer(openAPISpec))
+type MockServer struct {
+	spec   *spec.OpenAPISpec
+	routes []mockRoute
+}
+
+// mockRoute is a documented path, split into segments for matching
+type mockRoute struct {
+	segments []string // Literal segments, or "" for parameters
+	pathItem spec.PathItem
+}
+
+// NewMockServer creates a mock server for the operations of openAPISpec
+func NewMockServer(openAPISpec *spec.OpenAPISpec) *MockServer {
+	server := &MockServer{spec: openAPISpec}
+	for _, path := range slices.Sorted(maps.Keys(openAPISpec.Paths)) {
+		route := mockRoute{pathItem: openAPISpec.Paths[path]}
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				segment = ""
+			}
+			route.segments = append(route.segments, segment)
+		}
+		server.routes = append(server.routes, route)
+	}
+	// Literal segments win over parameters, e.g. /users/me over /users/{id}
+	slices.SortStableFunc(server.routes, func(a, b mockRoute) int {
+		for i := range min(len(a.segments), len(b.segments)) {
+			if aParameter, bParameter := a.segments[i] == "", b.segments[i] == ""; aParameter != bParameter {
+				if aParameter {
+					return 1
+				}
+				return -1
+			}
+		}
+		return 0
+	})
+	return server
+}
+
+// ServeHTTP answers a request with the mock response of the matching operation
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pathItem, found := m.match(r.URL.Path)
+	if !found {
+		http.Error(w, "no documented path matches "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	operation := operationForMethod(&pathItem, r.Method)
+	if operation == nil {
+		http.Error(w, fmt.Sprintf("%s is not documented for %s", r.Method, r.URL.Path), http.StatusMethodNotAllowed)
+		return
+	}
+
+	code, response, found := mockResponse(operation, r.Header.Get("Prefer"))
+	if !found {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		status = http.StatusOK // "default" responses
+	}
+
+	contentType, mediaType, hasBody := mockContent(response.Content)
+	if !hasBody || r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	body, err := json.Marshal(m.mediaTypeExample(mediaType))
+	if err != nil {
+		http.Error(w, "failed to encode mock response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !strings.Contains(contentType, "json") {
+		var text string
+		if json.Unmarshal(body, &text) == nil {
+			body = []byte(text) // Plain text and other media types are written as is
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// match returns the path item of the first documented path matching a request path
+func (m *MockServer) match(requestPath string) (spec.PathItem, bool) {
+	segments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for _, route := range m.routes {
+		if len(route.segments) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, segment := range route.segments {
+			if segment != "" && segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route.pathItem, true
+		}
+	}
+	return spec.PathItem{}, false
+}
+
+// mockResponse picks the response to serve: the one asked for in a "Prefer: code=..."
+// header, otherwise the first documented success response
+func mockResponse(operation *spec.Operation, prefer string) (string, spec.Response, bool) {
+	for preference := range strings.SplitSeq(prefer, ",") {
+		if code, ok := strings.CutPrefix(strings.TrimSpace(preference), "code="); ok {
+			if response, documented := operation.Responses[code]; documented {
+				return code, response, true
+			}
+		}
+	}
+	codes := slices.Sorted(maps.Keys(operation.Responses))
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code, operation.Responses[code], true
+		}
+	}
+	if response, documented := operation.Responses["default"]; documented {
+		return "default", response, true
+	}
+	return "", spec.Response{}, false
+}
+
+// mockContent picks the media type to serve, JSON when the response has it
+func mockContent(content map[string]spec.MediaType) (string, spec.MediaType, bool) {
+	if mediaType, exists := content["application/json"]; exists {
+		return "application/json", mediaType, true
+	}
+	contentTypes := slices.Sorted(maps.Keys(content))
+	if len(contentTypes) == 0 {
+		return "", spec.MediaType{}, false
+	}
+	return contentTypes[0], content[contentTypes[0]], true
+}
+
+// mediaTypeExample returns the documented example of a media type, or one synthesized from its schema
+func (m *MockServer) mediaTypeExample(mediaType spec.MediaType) any {
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	for _, name := range slices.Sorted(maps.Keys(mediaType.Examples)) {
+		if example := mediaType.Examples[name]; example.Value != nil {
+			return example.Value
+		}
+	}
+	return SchemaExample(mediaType.Schema, m.spec.Components.Schemas)
+}
+
+// SchemaExample synthesizes a value that is valid for schema, following references into schemas
+//
+// Examples and defaults are used as documented. Otherwise values keep to the schema's
+// constraints: the first enum value, a value of the format ("user@example.com" for email),
+// numbers within their bounds, strings and arrays of their minimum length. Write-only
+// properties are left out, as in responses.
+func SchemaExample(schema spec.Schema, schemas map[string]spec.Schema) any {
+	return synthesizeExample(schema, schemas, 0)
+}
+
+// synthesizeExample builds the example of a schema nested depth levels deep
+func synthesizeExample(schema spec.Schema, schemas map[string]spec.Schema, depth int) any {
+	if schema.Ref != "" {
+		component, exists := schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !exists || depth >= mockMaxDepth {
+			return nil
+		}
+		return synthesizeExample(component, schemas, depth+1)
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]any)
+		for _, member := range schema.AllOf {
+			if object, isObject := synthesizeExample(member, schemas, depth+1).(map[string]any); isObject {
+				maps.Copy(merged, object)
+			}
+		}
+		maps.Copy(merged, synthesizeObject(schema, schemas, depth))
+		return merged
+	}
+	for _, variants := range [][]spec.Schema{schema.OneOf, schema.AnyOf} {
+		if len(variants) > 0 {
+			return synthesizeExample(variants[0], schemas, depth+1)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		return enumExample(schema.Type, schema.Enum[0])
+	}
+
+	switch schema.Type {
+	case "string":
+		return stringExample(schema)
+	case "integer":
+		return int64(numberExample(schema, 1))
+	case "number":
+		return numberExample(schema, 0.5)
+	case "boolean":
+		return true
+	case "array":
+		if schema.Items == nil || depth >= mockMaxDepth {
+			return []any{}
+		}
+		count := 1
+		if schema.MinItems != nil {
+			count = *schema.MinItems
+		}
+		if schema.MaxItems != nil && *schema.MaxItems < count {
+			count = *schema.MaxItems
+		}
+		items := make([]any, count)
+		for i := range items {
+			items[i] = synthesizeExample(*schema.Items, schemas, depth+1)
+		}
+		return items
+	case "object", "":
+		if schema.Type == "" && len(schema.Properties) == 0 && schema.Nullable {
+			return nil
+		}
+		return synthesizeObject(schema, schemas, depth)
+	}
+	return nil
+}
+
+// synthesizeObject builds an object with an example of every property
+func synthesizeObject(schema spec.Schema, schemas map[string]spec.Schema, depth int) map[string]any {
+	object := make(map[string]any, len(schema.Properties))
+	if depth >= mockMaxDepth {
+		return object
+	}
+	for name, property := range schema.Properties {
+		if property.WriteOnly {
+			continue
+		}
+		object[name] = synthesizeExample(property, schemas, depth+1)
+	}
+	return object
+}
+
+// enumExample returns an enum value, which the spec keeps as a string, as a value of the schema type
+func enumExample(schemaType, value string) any {
+	switch schemaType {
+	case "integer":
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+// stringExample returns a string of the schema's format and length
+func stringExample(schema spec.Schema) string {
+	example := "string"
+	switch schema.Format {
+	case "date-time":
+		example = "2024-01-01T00:00:00Z"
+	case "date":
+		example = "2024-01-01"
+	case "time":
+		example = "00:00:00"
+	case "email":
+		example = "user@example.com"
+	case "uuid":
+		example = "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		example = "https://example.com"
+	case "hostname":
+		example = "example.com"
+	case "ipv4":
+		example = "192.0.2.1"
+	case "ipv6":
+		example = "2001:db8::1"
+	case "byte":
+		example = "c3RyaW5n"
+	case "binary":
+		example = ""
+	}
+	if schema.MinLength != nil && len(example) < *schema.MinLength {
+		example += strings.Repeat("x", *schema.MinLength-len(example))
+	}
+	if schema.MaxLength != nil && len(example) > *schema.MaxLength {
+		example = example[:*schema.MaxLength]
+	}
+	return example
+}
+
+// numberExample returns fallback, moved within the schema's bounds
+func numberExample(schema spec.Schema, fallback float64) float64 {
+	value := fallback
+	if schema.Minimum != nil && value <= *schema.Minimum {
+		value = *schema.Minimum
+		if schema.ExclusiveMinimum {
+			value++
+		}
+	}
+	if schema.Maximum != nil && value >= *schema.Maximum {
+		value = *schema.Maximum
+		if schema.ExclusiveMaximum {
+			value--
+		}
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		value = float64(int64(value / *schema.MultipleOf)) * *schema.MultipleOf
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestMockServer(t *testing.T) {
+	minLength, minItems := 10, 2
+	minimum := 18.0
+	openAPISpec := &spec.OpenAPISpec{
+		Paths: map[string]spec.PathItem{
+			"/users/{id}": {Get: &spec.Operation{Responses: map[string]spec.Response{
+				"200": {Description: "User", Content: map[string]spec.MediaType{
+					"application/json": {Schema: spec.Schema{Ref: "#/components/schemas/User"}},
+				}},
+				"404": {Description: "Missing", Content: map[string]spec.MediaType{
+					"application/json": {Example: map[string]any{"error": "not found"}},
+				}},
+			}}},
+			"/users/me": {Get: &spec.Operation{Responses: map[string]spec.Response{
+				"200": {Description: "Me", Content: map[string]spec.MediaType{
+					"application/json": {Example: map[string]any{"name": "me"}},
+				}},
+			}}},
+			"/health": {
+				Get:  &spec.Operation{Responses: map[string]spec.Response{"200": {Description: "OK", Content: map[string]spec.MediaType{"text/plain": {Schema: spec.Schema{Type: "string", Example: "ok"}}}}}},
+				Post: &spec.Operation{Responses: map[string]spec.Response{"204": {Description: "Done"}}},
+			},
+		},
+		Components: spec.Components{Schemas: map[string]spec.Schema{
+			"User": {Type: "object", Properties: map[string]spec.Schema{
+				"id":       {Type: "integer", Minimum: &minimum},
+				"email":    {Type: "string", Format: "email"},
+				"code":     {Type: "string", MinLength: &minLength},
+				"role":     {Type: "string", Enum: []string{"admin", "member"}},
+				"level":    {Type: "integer", Enum: []string{"3", "4"}},
+				"tags":     {Type: "array", Items: &spec.Schema{Type: "string"}, MinItems: &minItems},
+				"manager":  {Ref: "#/components/schemas/User"},
+				"password": {Type: "string", WriteOnly: true},
+			}},
+		}},
+	}
+	server := httptest.NewServer(NewMockServer(openAPISpec))
+	defer server.Close()
+
+	get := func(path string, header http.Header) (*http.Response, map[string]any) {
+		request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		request.Header = header
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		var body map[string]any
+		json.NewDecoder(response.Body).Decode(&body)
+		return response, body
+	}
+
+	response, user := get("/users/42", nil)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.Equal(t, float64(18), user["id"])
+	assert.Equal(t, "user@example.com", user["email"])
+	assert.Equal(t, "stringxxxx", user["code"])
+	assert.Equal(t, "admin", user["role"])
+	assert.Equal(t, float64(3), user["level"])
+	assert.Equal(t, []any{"string", "string"}, user["tags"])
+	assert.NotContains(t, user, "password")
+	assert.IsType(t, map[string]any{}, user["manager"], "References are followed, recursion is bounded")
+
+	_, me := get("/users/me", nil)
+	assert.Equal(t, map[string]any{"name": "me"}, me, "Literal paths win over parameters")
+
+	response, missing := get("/users/42", http.Header{"Prefer": {"code=404"}})
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, map[string]any{"error": "not found"}, missing)
+
+	recorder := httptest.NewRecorder()
+	NewMockServer(openAPISpec).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, "ok", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	NewMockServer(openAPISpec).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/health", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	NewMockServer(openAPISpec).ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/health", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	NewMockServer(openAPISpec).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
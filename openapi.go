@@ -1,11 +1,19 @@
 package openapi
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"reflect"
 
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/zainokta/openapi-gen/analyzer"
 	"github.com/zainokta/openapi-gen/integration"
 	"github.com/zainokta/openapi-gen/logger"
+	"github.com/zainokta/openapi-gen/spec"
 )
 
 // Option is a functional option for configuring OpenAPI generation
@@ -13,10 +21,44 @@ type Option func(*Options)
 
 // Options holds configuration for OpenAPI generation
 type Options struct {
-	config           *Config
-	logger           logger.Logger
-	customDiscoverer integration.RouteDiscoverer
-	customizers      []func(*Generator) error
+	config              *Config
+	schemaDir           string // Applied after all options so a later WithConfig cannot discard it
+	schemaBundle        fs.FS
+	schemaFS            fs.FS
+	schemaFSDir         string
+	logger              logger.Logger
+	customDiscoverer    integration.RouteDiscoverer
+	customAnalyzer      analyzer.HandlerAnalyzer
+	staticRoutesDir     string
+	nameResolver        func(spec.RouteInfo) string
+	schemaNamer         analyzer.SchemaNamer
+	readOnly            []string
+	writeOnly           []string
+	problemJSON         bool
+	defaultResponses    map[string]spec.Response
+	tagDefaultResponses map[string]map[string]spec.Response
+	errorExamples       map[int]any
+	standardPaths       map[string]StandardEndpoint
+	pathPrefixes        []string
+	routeAnnotations    *integration.RouteAnnotations
+	tagOrder            SortOrder
+	pathOrder           SortOrder
+	operationOrder      SortOrder
+	operationIDCasing   OperationIDCasing
+	strictOutput        bool
+	enumCasing          EnumCasing
+	nullableStyle       NullableStyle
+	vendorSources       bool
+	buildTags           []string
+	sourceDirs          []string
+	sourceExclude       []string
+	specFiles           []string
+	exampleRecorder     *ExampleRecorder
+	driftDetector       *DriftDetector
+	tracerProvider      trace.TracerProvider
+	meterProvider       metric.MeterProvider
+	customizers         []func(*Generator) error
+	conflicts           []error
 }
 
 // WithConfig sets a custom configuration for OpenAPI generation
@@ -33,6 +75,9 @@ type Options struct {
 //	)
 func WithConfig(cfg *Config) Option {
 	return func(opts *Options) {
+		if opts.config != nil && opts.config != cfg {
+			opts.conflicts = append(opts.conflicts, fmt.Errorf("WithConfig was given more than once, combine the settings into a single Config"))
+		}
 		opts.config = cfg
 	}
 }
@@ -49,10 +94,47 @@ func WithConfig(cfg *Config) Option {
 //	)
 func WithSchemaDir(path string) Option {
 	return func(opts *Options) {
-		if opts.config == nil {
-			opts.config = NewConfig()
-		}
-		opts.config.SetSchemaDir(path)
+		opts.schemaDir = path
+	}
+}
+
+// WithSchemaFS loads schema files from a directory inside a file system
+//
+// This lets the files generated by openapi-gen be embedded into the binary,
+// which is essential for images built from scratch, and lets tests use fstest.MapFS.
+//
+// Example:
+//
+//	//go:embed schemas
+//	var schemaFiles embed.FS
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithSchemaFS(schemaFiles, "schemas"),
+//	)
+func WithSchemaFS(fsys fs.FS, dir string) Option {
+	return func(opts *Options) {
+		opts.schemaFS = fsys
+		opts.schemaFSDir = dir
+	}
+}
+
+// WithSchemaBundle loads handler schemas from a bundle produced by `openapi-gen -bundle`
+//
+// The bundle packs every generated schema file into one JSON document that can be
+// embedded into the binary, giving full-fidelity docs in distroless containers
+// where neither source files nor a schema directory are available.
+//
+// Example:
+//
+//	//go:embed schemas/openapi-schemas.bundle.json
+//	var schemaBundle embed.FS
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithSchemaBundle(schemaBundle),
+//	)
+func WithSchemaBundle(fsys fs.FS) Option {
+	return func(opts *Options) {
+		opts.schemaBundle = fsys
 	}
 }
 
@@ -66,7 +148,7 @@ func WithSchemaDir(path string) Option {
 //	type MyLogger struct{}
 //	func (l *MyLogger) Info(msg string, args ...any) { /* implementation */ }
 //	// ... implement other methods
-//	
+//
 //	err := openapi.EnableDocs(framework, httpServer,
 //		openapi.WithLogger(&MyLogger{}),
 //	)
@@ -75,7 +157,7 @@ func WithSchemaDir(path string) Option {
 //
 //	slogLogger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 //	adapter := openapi.NewSlogAdapter(slogLogger)
-//	
+//
 //	err := openapi.EnableDocs(framework, httpServer,
 //		openapi.WithLogger(adapter),
 //	)
@@ -93,7 +175,7 @@ func WithLogger(l logger.Logger) Option {
 // Example:
 //
 //	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-//	
+//
 //	err := openapi.EnableDocs(framework, httpServer,
 //		openapi.WithSlogLogger(logger),
 //	)
@@ -130,6 +212,137 @@ func WithRouteDiscoverer(discoverer integration.RouteDiscoverer) Option {
 	}
 }
 
+// WithRouteAnnotations documents routes with the tags, summary, description and deprecation
+// recorded where they were registered, see integration.RouteAnnotations
+//
+// The route discoverer copies the annotations into the routes it discovers, which the Gin,
+// Hertz and static discoverers do. Overrides win over annotations, and annotations win over
+// the documentation generated from the path and handler name. Custom discoverers can instead
+// set the Tags, Summary, Description and Deprecated fields of spec.RouteInfo themselves.
+//
+// Example:
+//
+//	annotations := integration.NewRouteAnnotations()
+//	for _, r := range routes {
+//		annotations.GinHandle(api, r.Method, r.Path, integration.RouteAnnotation{
+//			Tags:    r.Tags,
+//			Summary: r.Summary,
+//		}, r.Handler)
+//	}
+//	err := openapi.EnableDocs(engine, httpServer,
+//		openapi.WithRouteAnnotations(annotations),
+//	)
+func WithRouteAnnotations(annotations *integration.RouteAnnotations) Option {
+	return func(opts *Options) {
+		opts.routeAnnotations = annotations
+	}
+}
+
+// WithHandlerAnalyzer sets a custom handler analyzer, for handler shapes the framework
+// analyzers do not understand such as code-generated handlers or wrapper frameworks
+//
+// By default handlers are analyzed by the analyzer of the framework passed to EnableDocs,
+// or of the framework named by a custom discoverer ("Gin" or "CloudWeGo Hertz"). Every
+// analyzer gets an analyzer.Config with the environment, source directories and logger.
+// Analyzers implementing integration.SourceHandlerAnalyzer also get the other source and
+// schema settings, so embedding a framework analyzer keeps its analysis for the handlers
+// left to it.
+//
+// Example:
+//
+//	type generatedAnalyzer struct {
+//		*integration.GinHandlerAnalyzer
+//	}
+//
+//	func (a generatedAnalyzer) AnalyzeHandler(handler interface{}) analyzer.HandlerSchema {
+//		if generated, ok := handler.(codegen.Handler); ok {
+//			return analyzer.HandlerSchema{RequestSchema: generated.RequestSchema(), ResponseSchema: generated.ResponseSchema()}
+//		}
+//		return a.GinHandlerAnalyzer.AnalyzeHandler(handler)
+//	}
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithHandlerAnalyzer(generatedAnalyzer{integration.NewGinHandlerAnalyzer()}),
+//	)
+func WithHandlerAnalyzer(handlerAnalyzer analyzer.HandlerAnalyzer) Option {
+	return func(opts *Options) {
+		opts.customAnalyzer = handlerAnalyzer
+	}
+}
+
+// WithHandlerNameResolver sets a hook that names the handler of each discovered route
+//
+// Use it when routes are registered through wrapper helpers or decorators and the
+// discovered handler name does not match the generated schemas. Returning an empty
+// string keeps the discovered name.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithHandlerNameResolver(func(route spec.RouteInfo) string {
+//			return routeHandlers[route.Method+" "+route.Path]
+//		}),
+//	)
+func WithHandlerNameResolver(resolver func(route spec.RouteInfo) string) Option {
+	return func(opts *Options) {
+		opts.nameResolver = resolver
+	}
+}
+
+// WithSchemaNamer sets how route schemas are named in components
+//
+// The namer receives the route key ("POST /auth/login"), the schema kind ("request",
+// "response" or "response<status>") and the Go type when known. Returning an empty
+// string falls back to the default: the Go type name, or a route-derived name.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithSchemaNamer(func(routeKey, kind string, goType reflect.Type) string {
+//			if goType != nil {
+//				return "V1" + goType.Name()
+//			}
+//			return ""
+//		}),
+//	)
+func WithSchemaNamer(namer func(routeKey, kind string, goType reflect.Type) string) Option {
+	return func(opts *Options) {
+		opts.schemaNamer = namer
+	}
+}
+
+// WithReadOnlyProperties marks properties read-only, they are left out of request schemas
+//
+// Properties are matched by name in every route schema, nested objects included. A
+// schema that loses properties becomes its own component, e.g. "UserRequest" next to
+// "UserResponse". Single fields can be marked with an openapi:"readOnly" tag instead.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithReadOnlyProperties("id", "created_at", "updated_at"),
+//	)
+func WithReadOnlyProperties(names ...string) Option {
+	return func(opts *Options) {
+		opts.readOnly = append(opts.readOnly, names...)
+	}
+}
+
+// WithWriteOnlyProperties marks properties write-only, they are left out of response schemas
+//
+// Single fields can be marked with an openapi:"writeOnly" tag instead.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithWriteOnlyProperties("password"),
+//	)
+func WithWriteOnlyProperties(names ...string) Option {
+	return func(opts *Options) {
+		opts.writeOnly = append(opts.writeOnly, names...)
+	}
+}
+
 // WithCustomizer adds a customization function to modify the generated OpenAPI spec
 //
 // Example:
@@ -177,9 +390,38 @@ func processOptions(opts ...Option) *Options {
 		options.logger = logger.NewSlogAdapter(slog.Default())
 	}
 
+	// WithSchemaDir wins over the config, unless the config explicitly names another directory
+	if options.schemaDir != "" {
+		configured := options.config.SchemaDir
+		if configured != "" && configured != DefaultSchemaDir && configured != options.schemaDir {
+			options.conflicts = append(options.conflicts,
+				fmt.Errorf("WithSchemaDir(%q) conflicts with Config.SchemaDir %q, set only one of them", options.schemaDir, configured))
+		}
+		// Set it on a copy, the caller's config is left as given
+		config := *options.config
+		options.config = config.SetSchemaDir(options.schemaDir)
+	}
+
 	return options
 }
 
+// validate reports conflicting options and invalid configuration
+func (o *Options) validate() error {
+	errs := append([]error{}, o.conflicts...)
+	if o.config != nil {
+		if err := o.config.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		// Embedded schemas replace the schema directory, it may be missing
+		if o.schemaFS == nil && o.schemaBundle == nil {
+			if err := o.config.validateSchemaDir(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // EnableDocs enables OpenAPI documentation with flexible configuration options
 //
 // Example usage:
@@ -230,8 +472,8 @@ func EnableDocs(framework any, h integration.HTTPServer, opts ...Option) error {
 
 	// Use logger from generator (already processed in NewGenerator)
 	generator.logger.Info("OpenAPI documentation enabled with customization",
-		"swagger_ui", "/docs",
-		"openapi_spec", "/openapi.json")
+		"swagger_ui", generator.config.DocsPath,
+		"openapi_spec", generator.config.SpecPath)
 
 	return nil
 }
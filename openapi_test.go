@@ -1,13 +1,58 @@
 package openapi
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/integration"
+	"github.com/zainokta/openapi-gen/logger"
 	"github.com/zainokta/openapi-gen/parser"
+	"github.com/zainokta/openapi-gen/spec"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// staticDiscoverer returns a fixed set of routes for generator tests
+type staticDiscoverer struct {
+	routes []spec.RouteInfo
+}
+
+func (d *staticDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
+	return d.routes, nil
+}
+
+func (d *staticDiscoverer) GetFrameworkName() string {
+	return "Static"
+}
+
+// newTestGenerator creates a generator backed by a static route list
+func newTestGenerator(t *testing.T, routes []spec.RouteInfo, opts ...Option) *Generator {
+	t.Helper()
+
+	defaults := []Option{
+		WithLogger(&logger.NoOpLogger{}),
+		WithRouteDiscoverer(&staticDiscoverer{routes: routes}),
+	}
+	// Tests that bring their own config must not also get the default one
+	probe := &Options{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	if probe.config == nil {
+		cfg := NewConfig()
+		cfg.SchemaDir = ""
+		defaults = append(defaults, WithConfig(cfg))
+	}
+	opts = append(defaults, opts...)
+
+	generator, err := NewGenerator(nil, nil, processOptions(opts...))
+	assert.NoError(t, err)
+	return generator
+}
+
 func TestPathParser(t *testing.T) {
 	parser := parser.NewPathParser()
 
@@ -66,6 +111,41 @@ func TestPathParser(t *testing.T) {
 	}
 }
 
+func TestSummaryFromHandlerName(t *testing.T) {
+	parser := parser.NewPathParser()
+
+	tests := map[string]string{
+		"CreateUser":                   "Create user",
+		"(*UserHandler).CreateUser-fm": "Create user",
+		"ListOAuthProviders":           "List oauth providers",
+		"GetUserByIDHandler":           "Get user by ID",
+		"ListUserIDs":                  "List user IDs",
+		"export_reports":               "Export reports",
+		"main.setupRoutes.func1":       "",
+		"Health":                       "",
+		"":                             "",
+	}
+	for handlerName, expected := range tests {
+		assert.Equal(t, expected, parser.SummaryFromHandlerName(handlerName), handlerName)
+	}
+}
+
+func TestSummaryFromHandlerNameInSpec(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/api/v1/users", HandlerName: "CreateUser"},
+		{Method: "GET", Path: "/api/v1/oauth/providers", HandlerName: "ListOAuthProviders"},
+		{Method: "GET", Path: "/health", HandlerName: "func1"},
+	})
+	generator.GetOverrideManager().Override("GET", "/api/v1/oauth/providers", RouteMetadata{Summary: "Sign-in providers"})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Create user", openAPISpec.Paths["/api/v1/users"].Post.Summary)
+	assert.Equal(t, "Sign-in providers", openAPISpec.Paths["/api/v1/oauth/providers"].Get.Summary)
+	assert.Equal(t, "Get Health", openAPISpec.Paths["/health"].Get.Summary)
+}
+
 func TestOverrideManager(t *testing.T) {
 	om := NewOverrideManager()
 	parser := parser.NewPathParser()
@@ -128,3 +208,389 @@ func TestTagOverrides(t *testing.T) {
 
 	assert.Equal(t, "authentication", metadata.Tags)
 }
+
+func TestGroupOverride(t *testing.T) {
+	om := NewOverrideManager()
+	parser := parser.NewPathParser()
+
+	om.OverrideGroup("/api/v1/admin/").Tags("admin").Security("adminAuth")
+	om.OverrideGroup("/api/v1/admin/audit").Tags("audit")
+	om.Override("GET", "/api/v1/admin/status", RouteMetadata{Security: []string{}})
+
+	metadata := om.GetMetadata("DELETE", "/api/v1/admin/users/:id", parser.ParseRoute("DELETE", "/api/v1/admin/users/:id"))
+	assert.Equal(t, "admin", metadata.Tags)
+	assert.Equal(t, []string{"adminAuth"}, metadata.Security)
+
+	// Nested groups inherit from their parent, the longer prefix wins
+	metadata = om.GetMetadata("GET", "/api/v1/admin/audit/logs", parser.ParseRoute("GET", "/api/v1/admin/audit/logs"))
+	assert.Equal(t, "audit", metadata.Tags)
+	assert.Equal(t, []string{"adminAuth"}, metadata.Security)
+
+	// A route override beats the group
+	metadata = om.GetMetadata("GET", "/api/v1/admin/status", parser.ParseRoute("GET", "/api/v1/admin/status"))
+	assert.Equal(t, "admin", metadata.Tags)
+	assert.Equal(t, []string{}, metadata.Security)
+
+	// Prefixes match whole segments
+	metadata = om.GetMetadata("GET", "/api/v1/administrators", parser.ParseRoute("GET", "/api/v1/administrators"))
+	assert.NotEqual(t, "admin", metadata.Tags)
+	assert.Nil(t, metadata.Security)
+
+	assert.Same(t, om.OverrideGroup("/api/v1/admin"), om.OverrideGroup("/api/v1/admin"))
+}
+
+func TestGroupOverrideSecurity(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/api/v1/admin/users"},
+		{Method: "GET", Path: "/api/v1/admin/status"},
+	})
+	generator.RegisterSecurityScheme("adminAuth", spec.SecurityScheme{Type: "apiKey", In: "header", Name: "X-Admin-Key"})
+	generator.OverrideGroup("/api/v1/admin").Security("adminAuth", "bearerAuth")
+	generator.GetOverrideManager().Override("GET", "/api/v1/admin/status", RouteMetadata{Security: []string{}})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, openAPISpec.Components.SecuritySchemes, "adminAuth")
+	assert.Contains(t, openAPISpec.Components.SecuritySchemes, "bearerAuth")
+	assert.Equal(t, []spec.SecurityRequirement{{"adminAuth": {}}, {"bearerAuth": {}}},
+		openAPISpec.Paths["/api/v1/admin/users"].Get.Security)
+	assert.Empty(t, openAPISpec.Paths["/api/v1/admin/status"].Get.Security)
+}
+
+func TestUnauthorizedOnlyForSecuredOperations(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/"},
+		{Method: "GET", Path: "/users"},
+		{Method: "POST", Path: "/api/v1/auth/login"},
+		{Method: "POST", Path: "/api/v1/auth/register"},
+		{Method: "GET", Path: "/status"},
+	})
+	generator.OverrideResponse("POST", "/api/v1/auth/register", 401, nil)
+	generator.GetOverrideManager().Override("GET", "/status", RouteMetadata{Security: []string{}})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	root := openAPISpec.Paths["/"].Get
+	assert.Empty(t, root.Security)
+	assert.NotContains(t, root.Responses, "401")
+
+	// Every path lies under the root, it does not make them public
+	users := openAPISpec.Paths["/users"].Get
+	assert.Equal(t, []spec.SecurityRequirement{{"bearerAuth": {}}}, users.Security)
+	assert.Contains(t, users.Responses, "401")
+
+	assert.NotContains(t, openAPISpec.Paths["/api/v1/auth/login"].Post.Responses, "401")
+	assert.NotContains(t, openAPISpec.Paths["/status"].Get.Responses, "401")
+	// A 401 the route documents itself is kept
+	assert.Contains(t, openAPISpec.Paths["/api/v1/auth/register"].Post.Responses, "401")
+
+	// Public operations opt out of the document-level bearerAuth explicitly
+	data, err := json.Marshal(openAPISpec)
+	assert.NoError(t, err)
+	var document struct {
+		Security []map[string][]string                 `json:"security"`
+		Paths    map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &document))
+	security := func(path, method string) any {
+		var operation map[string]any
+		assert.NoError(t, json.Unmarshal(document.Paths[path][method], &operation))
+		return operation["security"]
+	}
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, document.Security)
+	assert.Equal(t, []any{}, security("/api/v1/auth/login", "post"))
+	assert.Equal(t, []any{}, security("/", "get"))
+	assert.Equal(t, []any{map[string]any{"bearerAuth": []any{}}}, security("/users", "get"))
+}
+
+func TestPathItemSummaries(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "DELETE", Path: "/users/:id"},
+		{Method: "POST", Path: "/orders"},
+	})
+	generator.GetOverrideManager().Override("POST", "/orders", RouteMetadata{Summary: "Place Order", Description: "Places an order"})
+	generator.OverridePath("/users/{id}").Summary("A single user")
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	// The override replaces the summary, the description still comes from the GET operation
+	users := openAPISpec.Paths["/users/{id}"]
+	assert.Equal(t, "A single user", users.Summary)
+	assert.Equal(t, users.Get.Description, users.Description)
+
+	orders := openAPISpec.Paths["/orders"]
+	assert.Equal(t, "Place Order", orders.Summary)
+	assert.Equal(t, "Places an order", orders.Description)
+}
+
+func TestGenerateSpecDeterministic(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "POST", Path: "/api/v1/auth/login", HandlerName: "Login"},
+		{Method: "GET", Path: "/api/v1/user/profile", HandlerName: "GetProfile"},
+		{Method: "GET", Path: "/api/v1/oauth/providers", HandlerName: "GetProviders"},
+		{Method: "DELETE", Path: "/api/v1/user/mfa/:id", HandlerName: "DisableMFA"},
+		{Method: "GET", Path: "/health", HandlerName: "Health"},
+	}
+
+	var outputs [][]byte
+	for i := 0; i < 5; i++ {
+		generated, err := newTestGenerator(t, routes).GenerateSpec()
+		assert.NoError(t, err)
+
+		data, err := json.Marshal(generated)
+		assert.NoError(t, err)
+		outputs = append(outputs, data)
+	}
+
+	for i := 1; i < len(outputs); i++ {
+		assert.Equal(t, string(outputs[0]), string(outputs[i]), "generation %d should be byte-identical", i)
+	}
+}
+
+func TestGenerateSpecTagsSorted(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/api/v1/user/profile"},
+		{Method: "POST", Path: "/api/v1/auth/login"},
+		{Method: "GET", Path: "/health"},
+	}
+
+	generated, err := newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, tag := range generated.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"auth", "health", "user"}, names)
+}
+
+func TestWithHandlerNameResolver(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", HandlerName: "func1"},
+		{Method: "GET", Path: "/health", HandlerName: "Health"},
+	}
+
+	var resolved []string
+	generator := newTestGenerator(t, routes, WithHandlerNameResolver(func(route spec.RouteInfo) string {
+		resolved = append(resolved, route.Path)
+		if route.Path == "/users/:id" {
+			return "GetUser"
+		}
+		return ""
+	}))
+	generator.schemaRegistry.RegisterHandlerSchema("GetUser", analyzer.HandlerSchema{
+		ResponseSchema: spec.Schema{
+			Type:       "object",
+			Properties: map[string]spec.Schema{"id": {Type: "string"}},
+		},
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/users/:id", "/health"}, resolved)
+
+	response := openAPISpec.Paths["/users/{id}"].Get.Responses["200"]
+	ref := response.Content["application/json"].Schema.Ref
+	if assert.NotEmpty(t, ref) {
+		schema := openAPISpec.Components.Schemas[ref[len("#/components/schemas/"):]]
+		assert.Contains(t, schema.Properties, "id")
+	}
+}
+
+func TestRegisteredSchemasBeforeAnalysis(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/exports", HandlerName: "StreamExport", Handler: func() {}},
+		{Method: "GET", Path: "/reports"},
+		{Method: "GET", Path: "/misc"},
+	})
+	generator.handlerAnalyzer = placeholderAnalyzer{}
+
+	// A registered schema wins over analysis even without request or response schema
+	generator.schemaRegistry.RegisterHandlerSchema("StreamExport", analyzer.HandlerSchema{Stream: analyzer.StreamChunked})
+	// Unnamed handlers are looked up by the name generated from their route
+	generator.schemaRegistry.RegisterHandlerSchema(generator.pathParser.GenerateHandlerName("GET", "/reports"), analyzer.HandlerSchema{
+		ResponseSchema: spec.Schema{Type: "object", Properties: map[string]spec.Schema{"total": {Type: "integer"}}},
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	assert.True(t, openAPISpec.Paths["/exports"].Get.XStreaming)
+	reports := openAPISpec.Paths["/reports"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Contains(t, resolveRef(t, openAPISpec, reports).Properties, "total")
+	misc := openAPISpec.Paths["/misc"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Empty(t, misc.Ref)
+	assert.NotContains(t, misc.Properties, "total")
+}
+
+func TestWithHandlerAnalyzer(t *testing.T) {
+	generator := newTestGenerator(t, nil)
+	assert.Equal(t, "CloudWeGo Hertz", generator.handlerAnalyzer.GetFrameworkName())
+
+	generator = newTestGenerator(t, nil, WithHandlerAnalyzer(integration.NewGinHandlerAnalyzer()))
+	assert.Equal(t, "Gin", generator.handlerAnalyzer.GetFrameworkName())
+
+	generator = newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/generated", Handler: generatedHandler(func() {})},
+	}, WithHandlerAnalyzer(generatedAnalyzer{integration.NewGinHandlerAnalyzer()}))
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	response := resolveRef(t, openAPISpec, openAPISpec.Paths["/generated"].Get.Responses["200"].Content["application/json"].Schema)
+	assert.Contains(t, response.Properties, "generated")
+}
+
+// generatedHandler is the handler shape of a code generator the framework analyzers do not know
+type generatedHandler func()
+
+// generatedAnalyzer documents generated handlers and leaves the others to the Gin analyzer
+type generatedAnalyzer struct {
+	*integration.GinHandlerAnalyzer
+}
+
+func (a generatedAnalyzer) AnalyzeHandler(handler interface{}) analyzer.HandlerSchema {
+	if _, generated := handler.(generatedHandler); generated {
+		return analyzer.HandlerSchema{
+			ResponseSchema: spec.Schema{Type: "object", Properties: map[string]spec.Schema{"generated": {Type: "boolean"}}},
+		}
+	}
+	return a.GinHandlerAnalyzer.AnalyzeHandler(handler)
+}
+
+func TestQueryStructPromotedToParameters(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/teams/:id/members", HandlerName: "ListMembers"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("ListMembers", analyzer.HandlerSchema{
+		RequestSchema: spec.Schema{
+			Type: "object",
+			Properties: map[string]spec.Schema{
+				"id":   {Type: "string"},
+				"page": {Type: "integer", Description: "Page number", Example: "2"},
+				"role": {Type: "string"},
+			},
+			Required: []string{"role"},
+		},
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/teams/{id}/members"].Get
+	assert.Nil(t, operation.RequestBody)
+	if assert.Len(t, operation.Parameters, 3) {
+		assert.Equal(t, "path", operation.Parameters[0].In)
+		assert.Equal(t, spec.Parameter{
+			Name: "page", In: "query", Description: "Page number", Example: "2",
+			Schema: spec.Schema{Type: "integer"},
+		}, operation.Parameters[1])
+		assert.Equal(t, "role", operation.Parameters[2].Name)
+		assert.True(t, operation.Parameters[2].Required)
+	}
+	for name, schema := range openAPISpec.Components.Schemas {
+		assert.NotContains(t, schema.Properties, "role", "The query struct is not a component: %s", name)
+	}
+}
+
+// listOrdersQuery is bound with ShouldBindQuery, the form tags name the query parameters
+type listOrdersQuery struct {
+	Page     int    `json:"page" form:"p"`
+	Status   string `json:"status" query:"state" validate:"required"`
+	Internal string `json:"internal" form:"-"`
+}
+
+func TestQueryParametersNamedByBindingTags(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/orders", HandlerName: "ListOrders"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("ListOrders", analyzer.HandlerSchema{
+		RequestSchema: analyzer.NewSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(listOrdersQuery{})),
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	params := openAPISpec.Paths["/orders"].Get.Parameters
+	if assert.Len(t, params, 2) {
+		assert.Equal(t, spec.Parameter{Name: "p", In: "query", Schema: spec.Schema{Type: "integer"}}, params[0])
+		assert.Equal(t, "state", params[1].Name)
+		assert.True(t, params[1].Required)
+	}
+}
+
+func BenchmarkGenerateSpec(b *testing.B) {
+	routes := make([]spec.RouteInfo, 0, 200)
+	for i := range 200 {
+		routes = append(routes, spec.RouteInfo{Method: "POST", Path: fmt.Sprintf("/resources/%d", i), HandlerName: fmt.Sprintf("Create%d", i)})
+	}
+
+	cfg := NewConfig()
+	cfg.SchemaDir = ""
+	generator, err := NewGenerator(nil, nil, processOptions(
+		WithConfig(cfg),
+		WithLogger(&logger.NoOpLogger{}),
+		WithRouteDiscoverer(&staticDiscoverer{routes: routes}),
+	))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, route := range routes {
+		generator.OverrideRequest(route.Method, route.Path, overrideLoginRequest{})
+		generator.OverrideResponse(route.Method, route.Path, 200, overrideLoginResponse{})
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := generator.GenerateSpec(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGeneratorWarmUp(t *testing.T) {
+	generator := newTestGenerator(t, nil)
+	generator.WarmUp(reflect.TypeOf(overrideLoginRequest{}))
+
+	schema := generator.schemaRegistry.GetSchemaGenerator().GenerateSchemaFromType(reflect.TypeOf(overrideLoginRequest{}))
+	assert.Contains(t, schema.Properties, "email")
+}
+
+func TestRouteInfoDocumentation(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", Tags: []string{"accounts", "users"}, Summary: "Fetch one account", Description: "Looks the account up by ID", Deprecated: true},
+		{Method: "DELETE", Path: "/users/:id", Tags: []string{"accounts", "users"}, Summary: "Close an account"},
+		{Method: "GET", Path: "/orders"},
+	})
+	// Overrides win over the documentation of the route
+	generator.overrideManager.Override("DELETE", "/users/:id", RouteMetadata{Tags: "admin", Summary: "Delete an account"})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	get := openAPISpec.Paths["/users/{id}"].Get
+	assert.Equal(t, []string{"accounts", "users"}, get.Tags)
+	assert.Equal(t, "Fetch one account", get.Summary)
+	assert.Equal(t, "Looks the account up by ID", get.Description)
+	assert.True(t, get.Deprecated)
+
+	remove := openAPISpec.Paths["/users/{id}"].Delete
+	assert.Equal(t, []string{"admin"}, remove.Tags)
+	assert.Equal(t, "Delete an account", remove.Summary)
+	assert.False(t, remove.Deprecated)
+
+	assert.Equal(t, []string{"orders"}, openAPISpec.Paths["/orders"].Get.Tags)
+
+	var tagNames []string
+	for _, tag := range openAPISpec.Tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+	assert.ElementsMatch(t, []string{"accounts", "users", "admin", "orders"}, tagNames)
+}
+
+func TestWithRouteAnnotationsUnsupportedDiscoverer(t *testing.T) {
+	_, err := NewGenerator(nil, nil, processOptions(WithConfig(NewConfig()), WithLogger(&logger.NoOpLogger{}),
+		WithRouteDiscoverer(&staticDiscoverer{}), WithRouteAnnotations(integration.NewRouteAnnotations())))
+	assert.ErrorContains(t, err, "route discoverer Static does not read route annotations")
+}
@@ -0,0 +1,118 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// OperationIDCasing is the casing of generated operation IDs, see WithOperationIDCasing
+type OperationIDCasing string
+
+const (
+	// OperationIDPascal names GET /users/{id} GetUsersById, the default
+	OperationIDPascal OperationIDCasing = "pascal"
+	// OperationIDCamel names GET /users/{id} getUsersById, as JavaScript and Java methods
+	OperationIDCamel OperationIDCasing = "camel"
+	// OperationIDSnake names GET /users/{id} get_users_by_id, as Python and Ruby methods
+	OperationIDSnake OperationIDCasing = "snake"
+)
+
+// WithOperationIDCasing chooses the casing of generated operation IDs, OperationIDPascal by default
+//
+// Operation IDs are made of the method and the words of the path, leaving out api, version
+// segments and the prefixes given to WithPathPrefixes. Path parameters are only named to
+// tell apart operations that would otherwise share an ID, e.g. GetUsers and GetUsersById;
+// operations still sharing one are numbered.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithOperationIDCasing(openapi.OperationIDCamel),
+//	)
+func WithOperationIDCasing(casing OperationIDCasing) Option {
+	return func(opts *Options) {
+		switch casing {
+		case OperationIDPascal, OperationIDCamel, OperationIDSnake:
+			opts.operationIDCasing = casing
+		default:
+			opts.conflicts = append(opts.conflicts, fmt.Errorf("unknown operation ID casing %q", casing))
+		}
+	}
+}
+
+// WithPathPrefixes leaves path segments such as "rest" or "public" out of generated tags,
+// summaries and operation IDs, like api and version segments such as v1
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithPathPrefixes("rest", "public"),
+//	)
+func WithPathPrefixes(prefixes ...string) Option {
+	return func(opts *Options) {
+		opts.pathPrefixes = append(opts.pathPrefixes, prefixes...)
+	}
+}
+
+// generateOperationID generates the operation ID of a route, without its path parameters
+func (g *Generator) generateOperationID(method, path string) string {
+	return joinOperationID(g.pathParser.OperationIDWords(method, path, false), g.operationIDCasing)
+}
+
+// disambiguateOperationIDs names the path parameters in the operation IDs shared by several
+// operations, then numbers the operations still sharing one, in path order
+func (g *Generator) disambiguateOperationIDs(openAPISpec *spec.OpenAPISpec) {
+	type namedOperation struct {
+		method, path string
+		operation    *spec.Operation
+	}
+	var operations []namedOperation
+	shared := make(map[string]int)
+	for _, path := range slices.Sorted(maps.Keys(openAPISpec.Paths)) {
+		pathItem := openAPISpec.Paths[path]
+		for _, method := range []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"} {
+			if operation := operationForMethod(&pathItem, method); operation != nil {
+				operations = append(operations, namedOperation{method: method, path: path, operation: operation})
+				shared[operation.OperationID]++
+			}
+		}
+	}
+
+	taken := make(map[string]bool, len(operations))
+	for _, named := range operations {
+		if shared[named.operation.OperationID] > 1 {
+			named.operation.OperationID = joinOperationID(g.pathParser.OperationIDWords(named.method, named.path, true), g.operationIDCasing)
+		}
+	}
+	for _, named := range operations {
+		base := named.operation.OperationID
+		unique := base
+		for n := 2; taken[unique]; n++ {
+			unique = base + strconv.Itoa(n)
+		}
+		taken[unique] = true
+		named.operation.OperationID = unique
+	}
+}
+
+// joinOperationID joins the lower case words of an operation ID in a casing
+func joinOperationID(words []string, casing OperationIDCasing) string {
+	if casing == OperationIDSnake {
+		return strings.Join(words, "_")
+	}
+	var b strings.Builder
+	for i, word := range words {
+		letters := []rune(word)
+		if i > 0 || casing != OperationIDCamel {
+			letters[0] = unicode.ToUpper(letters[0])
+		}
+		b.WriteString(string(letters))
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationIDs(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/api/v1/users"},
+		{Method: "GET", Path: "/api/v1/users/:id"},
+		{Method: "POST", Path: "/api/v1/users"},
+		{Method: "PUT", Path: "/api/v1/users/:id/roles/:roleId"},
+		{Method: "GET", Path: "/rest/password-reset/validate"},
+		{Method: "GET", Path: "/rest/orders/{orderID}"},
+		{Method: "GET", Path: "/"},
+	}, WithPathPrefixes("rest"))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	assert.Equal(t, "GetUsers", openAPISpec.Paths["/api/v1/users"].Get.OperationID)
+	assert.Equal(t, "PostUsers", openAPISpec.Paths["/api/v1/users"].Post.OperationID)
+	// Parameters are only named to tell apart operations sharing an ID
+	assert.Equal(t, "GetUsersById", openAPISpec.Paths["/api/v1/users/{id}"].Get.OperationID)
+	assert.Equal(t, "PutUsersRoles", openAPISpec.Paths["/api/v1/users/{id}/roles/{roleId}"].Put.OperationID)
+	assert.Equal(t, "GetPasswordResetValidate", openAPISpec.Paths["/rest/password-reset/validate"].Get.OperationID)
+	assert.Equal(t, "GetOrders", openAPISpec.Paths["/rest/orders/{orderID}"].Get.OperationID)
+	assert.Equal(t, "GetRoot", openAPISpec.Paths["/"].Get.OperationID)
+}
+
+func TestOperationIDsNumberedWhenStillShared(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "GET", Path: "/v2/users/:id"},
+		{Method: "DELETE", Path: "/users/:id"},
+		{Method: "DELETE", Path: "/users/:id/sessions/:sessionId"},
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	assert.Equal(t, "GetUsersById", openAPISpec.Paths["/users/{id}"].Get.OperationID)
+	assert.Equal(t, "GetUsersById2", openAPISpec.Paths["/v2/users/{id}"].Get.OperationID)
+	assert.Equal(t, "DeleteUsers", openAPISpec.Paths["/users/{id}"].Delete.OperationID)
+	assert.Equal(t, "DeleteUsersSessions", openAPISpec.Paths["/users/{id}/sessions/{sessionId}"].Delete.OperationID)
+}
+
+func TestWithOperationIDCasing(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "GET", Path: "/users/:userId/api-keys"},
+		{Method: "GET", Path: "/users/:userId/api-keys/:keyId"},
+	}
+
+	tests := []struct {
+		casing   OperationIDCasing
+		expected []string
+	}{
+		{OperationIDPascal, []string{"GetUsers", "GetUsersApiKeysByUserId", "GetUsersApiKeysByUserIdAndKeyId"}},
+		{OperationIDCamel, []string{"getUsers", "getUsersApiKeysByUserId", "getUsersApiKeysByUserIdAndKeyId"}},
+		{OperationIDSnake, []string{"get_users", "get_users_api_keys_by_user_id", "get_users_api_keys_by_user_id_and_key_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.casing), func(t *testing.T) {
+			// Strict output keeps the casing of IDs that are identifiers
+			generator := newTestGenerator(t, routes, WithOperationIDCasing(tt.casing), WithStrictOutput())
+
+			openAPISpec, err := generator.GenerateSpec()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, []string{
+				openAPISpec.Paths["/users"].Get.OperationID,
+				openAPISpec.Paths["/users/{userId}/api-keys"].Get.OperationID,
+				openAPISpec.Paths["/users/{userId}/api-keys/{keyId}"].Get.OperationID,
+			})
+		})
+	}
+}
+
+func TestWithOperationIDCasingUnknown(t *testing.T) {
+	options := processOptions(WithConfig(NewConfig()), WithOperationIDCasing("kebab"))
+	assert.ErrorContains(t, options.validate(), `unknown operation ID casing "kebab"`)
+}
@@ -3,6 +3,7 @@ package openapi
 import (
 	"github.com/zainokta/openapi-gen/parser"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -11,6 +12,11 @@ type RouteMetadata struct {
 	Tags        string `json:"tags,omitempty"`
 	Summary     string `json:"summary,omitempty"`
 	Description string `json:"description,omitempty"`
+	Internal    bool   `json:"internal,omitempty"` // Marks the operation x-internal, see Generator.MarkInternal
+
+	// Security lists the security schemes accepted by the operation, any one of them is enough.
+	// nil keeps the default requirement, an empty list makes the operation public.
+	Security []string `json:"security,omitempty"`
 }
 
 // OverrideManager manages custom metadata overrides
@@ -18,6 +24,11 @@ type OverrideManager struct {
 	pathOverrides    map[string]RouteMetadata // Exact path matches
 	tagOverrides     map[string][]string      // Tag-level overrides
 	patternOverrides []PatternOverride        // Pattern-based overrides
+	groupOverrides   []*GroupOverride         // Defaults for every route under a path prefix
+	pathItems        map[string]*PathOverride // Path-level summaries, keyed by path template
+
+	// Media types of single routes, keyed by method and path template
+	routeItems map[string]*RouteOverride
 }
 
 // PatternOverride represents a pattern-based override
@@ -33,6 +44,8 @@ func NewOverrideManager() *OverrideManager {
 		pathOverrides:    make(map[string]RouteMetadata),
 		tagOverrides:     make(map[string][]string),
 		patternOverrides: make([]PatternOverride, 0),
+		pathItems:        make(map[string]*PathOverride),
+		routeItems:       make(map[string]*RouteOverride),
 	}
 }
 
@@ -68,7 +81,193 @@ func (om *OverrideManager) OverridePattern(pattern string, metadata RouteMetadat
 	return nil
 }
 
-// GetMetadata retrieves metadata with override precedence: Path > Pattern > Algorithm
+// GroupOverride holds defaults for every route under a path prefix, see OverrideManager.OverrideGroup
+type GroupOverride struct {
+	Prefix   string
+	Metadata RouteMetadata
+}
+
+// OverrideGroup returns the defaults of the routes under a path prefix, mirroring a router group
+//
+// Group defaults apply before pattern and path overrides, so a single route can still
+// override them. The prefix matches whole path segments: "/api/v1/admin" matches
+// "/api/v1/admin/users" but not "/api/v1/administrators". When groups are nested the
+// longer prefix wins. Calling OverrideGroup again with the same prefix returns the same group.
+//
+// Example:
+//
+//	om.OverrideGroup("/api/v1/admin").Tags("admin").Security("adminAuth")
+func (om *OverrideManager) OverrideGroup(prefix string) *GroupOverride {
+	prefix = strings.TrimSuffix(prefix, "/")
+	for _, group := range om.groupOverrides {
+		if group.Prefix == prefix {
+			return group
+		}
+	}
+
+	group := &GroupOverride{Prefix: prefix}
+	om.groupOverrides = append(om.groupOverrides, group)
+	// Shorter prefixes first, so nested groups are merged over their parents
+	sort.SliceStable(om.groupOverrides, func(i, j int) bool {
+		return len(om.groupOverrides[i].Prefix) < len(om.groupOverrides[j].Prefix)
+	})
+	return group
+}
+
+// Tags sets the tag of the group's operations
+func (g *GroupOverride) Tags(tag string) *GroupOverride {
+	g.Metadata.Tags = tag
+	return g
+}
+
+// Security sets the security schemes accepted by the group's operations, none makes them public
+func (g *GroupOverride) Security(schemes ...string) *GroupOverride {
+	g.Metadata.Security = append([]string{}, schemes...)
+	return g
+}
+
+// Internal marks the group's operations x-internal
+func (g *GroupOverride) Internal() *GroupOverride {
+	g.Metadata.Internal = true
+	return g
+}
+
+// matches reports whether a path is the group prefix or lies under it
+func (g *GroupOverride) matches(path string) bool {
+	rest, found := strings.CutPrefix(path, g.Prefix)
+	return found && (rest == "" || strings.HasPrefix(rest, "/"))
+}
+
+// PathOverride holds the summary and description of a path, shared by all of its operations
+type PathOverride struct {
+	Path     string
+	Metadata PathMetadata
+}
+
+// PathMetadata is the path-level summary and description of a path item
+type PathMetadata struct {
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OverridePath returns the path-level metadata of a path
+//
+// By default a path takes the summary and description of its first operation. The path
+// can use either the framework syntax ("/users/:id") or the OpenAPI one ("/users/{id}").
+// Calling OverridePath again with the same path returns the same override.
+//
+// Example:
+//
+//	om.OverridePath("/users/{id}").Summary("A single user").Description("Read, update or delete a user")
+func (om *OverrideManager) OverridePath(path string) *PathOverride {
+	key := pathTemplate(path)
+	if override, exists := om.pathItems[key]; exists {
+		return override
+	}
+	override := &PathOverride{Path: path}
+	om.pathItems[key] = override
+	return override
+}
+
+// GetPathMetadata returns the path-level metadata set with OverridePath
+func (om *OverrideManager) GetPathMetadata(path string) (PathMetadata, bool) {
+	override, exists := om.pathItems[pathTemplate(path)]
+	if !exists {
+		return PathMetadata{}, false
+	}
+	return override.Metadata, true
+}
+
+// Summary sets the path-level summary
+func (p *PathOverride) Summary(summary string) *PathOverride {
+	p.Metadata.Summary = summary
+	return p
+}
+
+// Description sets the path-level description
+func (p *PathOverride) Description(description string) *PathOverride {
+	p.Metadata.Description = description
+	return p
+}
+
+// RouteOverride holds the media types and examples of a single route, see OverrideManager.OverrideRoute
+type RouteOverride struct {
+	Method     string
+	Path       string
+	MediaTypes RouteMediaTypes
+	Examples   map[int]any // Example payloads of responses by status code
+}
+
+// RouteMediaTypes are the content types a route reads its request body and writes its responses in
+type RouteMediaTypes struct {
+	Request   string         `json:"request,omitempty"`
+	Responses map[int]string `json:"responses,omitempty"` // By status code
+}
+
+// OverrideRoute returns the media type and example overrides of a route, e.g. for the few
+// endpoints that do not speak JSON
+//
+// The path can use either the framework syntax ("/files/:id") or the OpenAPI one
+// ("/files/{id}"). Calling OverrideRoute again with the same method and path returns the
+// same override.
+//
+// Example:
+//
+//	om.OverrideRoute("POST", "/upload").RequestContentType("multipart/form-data")
+func (om *OverrideManager) OverrideRoute(method, path string) *RouteOverride {
+	key := om.createPathKey(method, pathTemplate(path))
+	if override, exists := om.routeItems[key]; exists {
+		return override
+	}
+	override := &RouteOverride{Method: strings.ToUpper(method), Path: path}
+	om.routeItems[key] = override
+	return override
+}
+
+// GetRouteMediaTypes returns the media types set with OverrideRoute
+func (om *OverrideManager) GetRouteMediaTypes(method, path string) (RouteMediaTypes, bool) {
+	override, exists := om.routeItems[om.createPathKey(method, pathTemplate(path))]
+	if !exists {
+		return RouteMediaTypes{}, false
+	}
+	return override.MediaTypes, true
+}
+
+// GetRouteExamples returns the response examples set with OverrideRoute, by status code
+func (om *OverrideManager) GetRouteExamples(method, path string) map[int]any {
+	override, exists := om.routeItems[om.createPathKey(method, pathTemplate(path))]
+	if !exists {
+		return nil
+	}
+	return override.Examples
+}
+
+// RequestContentType sets the content type of the request body, e.g. multipart/form-data
+func (r *RouteOverride) RequestContentType(contentType string) *RouteOverride {
+	r.MediaTypes.Request = contentType
+	return r
+}
+
+// ResponseContentType sets the content type of the response of a status code, e.g. text/csv
+func (r *RouteOverride) ResponseContentType(status int, contentType string) *RouteOverride {
+	if r.MediaTypes.Responses == nil {
+		r.MediaTypes.Responses = make(map[int]string)
+	}
+	r.MediaTypes.Responses[status] = contentType
+	return r
+}
+
+// ResponseExample sets the example payload of the response of a status code, e.g. a sample
+// 422 validation error
+func (r *RouteOverride) ResponseExample(status int, example any) *RouteOverride {
+	if r.Examples == nil {
+		r.Examples = make(map[int]any)
+	}
+	r.Examples[status] = example
+	return r
+}
+
+// GetMetadata retrieves metadata with override precedence: Path > Pattern > Group > Algorithm
 func (om *OverrideManager) GetMetadata(method, path string, algorithmicMetadata parser.ParsedRoute) RouteMetadata {
 	result := RouteMetadata{
 		Tags:        algorithmicMetadata.Tag,
@@ -76,18 +275,25 @@ func (om *OverrideManager) GetMetadata(method, path string, algorithmicMetadata
 		Description: algorithmicMetadata.Description,
 	}
 
-	// 1. Check for pattern-based overrides first (most flexible)
+	// 1. Apply the defaults of the groups the route belongs to
+	for _, group := range om.groupOverrides {
+		if group.matches(path) {
+			om.mergeMetadata(&result, group.Metadata)
+		}
+	}
+
+	// 2. Check for pattern-based overrides (most flexible)
 	if patternMetadata := om.getPatternMetadata(method, path); patternMetadata != nil {
 		om.mergeMetadata(&result, *patternMetadata)
 	}
 
-	// 2. Check for exact path overrides (highest priority)
+	// 3. Check for exact path overrides (highest priority)
 	key := om.createPathKey(method, path)
 	if pathMetadata, exists := om.pathOverrides[key]; exists {
 		om.mergeMetadata(&result, pathMetadata)
 	}
 
-	// 3. Apply tag-level overrides
+	// 4. Apply tag-level overrides
 	if newTags, exists := om.tagOverrides[algorithmicMetadata.Tag]; exists {
 		if len(newTags) > 0 {
 			result.Tags = newTags[0]
@@ -122,6 +328,12 @@ func (om *OverrideManager) mergeMetadata(result *RouteMetadata, override RouteMe
 	if override.Description != "" {
 		result.Description = override.Description
 	}
+	if override.Internal {
+		result.Internal = true
+	}
+	if override.Security != nil {
+		result.Security = override.Security
+	}
 }
 
 // createPathKey creates a unique key for method+path combination
@@ -172,15 +384,21 @@ func (om *OverrideManager) GetOverrideStats() map[string]int {
 		"path_overrides":    len(om.pathOverrides),
 		"tag_overrides":     len(om.tagOverrides),
 		"pattern_overrides": len(om.patternOverrides),
+		"group_overrides":   len(om.groupOverrides),
+		"path_items":        len(om.pathItems),
+		"route_items":       len(om.routeItems),
 	}
 }
 
 // ListOverrides returns all current overrides for debugging
 func (om *OverrideManager) ListOverrides() map[string]interface{} {
 	return map[string]interface{}{
-		"paths":    om.pathOverrides,
-		"tags":     om.tagOverrides,
-		"patterns": om.extractPatternStrings(),
+		"paths":     om.pathOverrides,
+		"tags":      om.tagOverrides,
+		"patterns":  om.extractPatternStrings(),
+		"groups":    om.groupOverrides,
+		"pathItems": om.pathItems,
+		"routes":    om.routeItems,
 	}
 }
 
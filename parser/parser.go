@@ -1,11 +1,13 @@
 package parser
 
 import (
+	"github.com/zainokta/openapi-gen/schemagen"
 	"github.com/zainokta/openapi-gen/spec"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -13,9 +15,13 @@ import (
 )
 
 // RouteParser parses route information from Go source files
+//
+// Route registrations such as r.GET("/users", h.List) are recognized, with the prefixes of
+// the groups they are registered on: api := r.Group("/api") or r.Group("/api").GET(...).
 type RouteParser struct {
 	fileSet *token.FileSet
 	routes  []spec.RouteInfo
+	groups  map[string]string // Prefixes of the group variables of the file being parsed
 }
 
 // NewRouteParser creates a new route parser
@@ -33,8 +39,12 @@ func (p *RouteParser) ParseRoutesFromFile(filename string) error {
 		return fmt.Errorf("failed to parse file %s: %w", filename, err)
 	}
 
+	// Group variables are local to the file, other files may reuse their names
+	p.groups = make(map[string]string)
 	ast.Inspect(src, func(node ast.Node) bool {
 		switch n := node.(type) {
+		case *ast.AssignStmt:
+			p.parseGroupAssign(n)
 		case *ast.CallExpr:
 			p.parseRouteCall(n)
 		}
@@ -44,30 +54,95 @@ func (p *RouteParser) ParseRoutesFromFile(filename string) error {
 	return nil
 }
 
+// parseGroupAssign records the prefix of group variables, api := r.Group("/api")
+func (p *RouteParser) parseGroupAssign(assign *ast.AssignStmt) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, rhs := range assign.Rhs {
+		ident, ok := assign.Lhs[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if prefix, isGroup := p.groupPrefix(rhs); isGroup {
+			p.groups[ident.Name] = prefix
+		}
+	}
+}
+
+// groupPrefix returns the path prefix routes registered on expr get, isGroup is false when
+// expr is not a group
+func (p *RouteParser) groupPrefix(expr ast.Expr) (prefix string, isGroup bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		prefix, isGroup = p.groups[e.Name]
+		return prefix, isGroup
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Group" || len(e.Args) == 0 {
+			return "", false
+		}
+		relative := p.extractStringLiteral(e.Args[0])
+		if relative == "" {
+			return "", false
+		}
+		parent, _ := p.groupPrefix(sel.X)
+		return joinRoutePaths(parent, relative), true
+	}
+	return "", false
+}
+
 // parseRouteCall extracts route information from method calls like h.GET, h.POST, etc.
+//
+// The handler is the last argument, middleware may come before it. Handle("GET", path, h)
+// registrations are recognized too.
 func (p *RouteParser) parseRouteCall(call *ast.CallExpr) {
-	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-		method := strings.ToUpper(sel.Sel.Name)
-		if p.isHTTPMethod(method) && len(call.Args) >= 2 {
-			route := spec.RouteInfo{
-				Method: method,
-			}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
 
-			// Extract path from first argument
-			if path := p.extractStringLiteral(call.Args[0]); path != "" {
-				route.Path = path
-			}
+	args := call.Args
+	method := strings.ToUpper(sel.Sel.Name)
+	if method == "HANDLE" && len(args) >= 3 {
+		method = strings.ToUpper(p.extractStringLiteral(args[0]))
+		args = args[1:]
+	}
+	if !p.isHTTPMethod(method) || len(args) < 2 {
+		return
+	}
 
-			// Extract handler from second argument
-			if handler := p.extractHandlerInfo(call.Args[1]); handler != "" {
-				route.HandlerName = handler
-			}
+	route := spec.RouteInfo{
+		Method: method,
+	}
 
-			if route.Path != "" && route.HandlerName != "" {
-				p.routes = append(p.routes, route)
-			}
-		}
+	// Extract path from first argument, under the prefix of the group it is registered on
+	if path := p.extractStringLiteral(args[0]); path != "" {
+		prefix, _ := p.groupPrefix(sel.X)
+		route.Path = joinRoutePaths(prefix, path)
+	}
+
+	// Extract handler from the last argument
+	if handler := p.extractHandlerInfo(args[len(args)-1]); handler != "" {
+		route.HandlerName = handler
+	}
+
+	if route.Path != "" && route.HandlerName != "" {
+		p.routes = append(p.routes, route)
+	}
+}
+
+// joinRoutePaths joins a group prefix and a route path like Gin and Hertz do, a trailing
+// slash of the route path is kept
+func joinRoutePaths(prefix, relative string) string {
+	if prefix == "" {
+		return relative
+	}
+	joined := path.Join(prefix, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
 	}
+	return joined
 }
 
 // isHTTPMethod checks if the given string is an HTTP method
@@ -81,13 +156,11 @@ func (p *RouteParser) isHTTPMethod(method string) bool {
 	return false
 }
 
-// extractStringLiteral extracts a string literal from an AST expression
+// extractStringLiteral extracts a string literal from an AST expression, quoted or raw
 func (p *RouteParser) extractStringLiteral(expr ast.Expr) string {
 	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
-		// Remove quotes
-		value := lit.Value
-		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
-			return value[1 : len(value)-1]
+		if value, err := strconv.Unquote(lit.Value); err == nil {
+			return value
 		}
 	}
 	return ""
@@ -113,7 +186,8 @@ func (p *RouteParser) GetRoutes() []spec.RouteInfo {
 
 // StructParser parses struct information for schema generation
 type StructParser struct {
-	schemas map[string]spec.Schema
+	schemas  map[string]spec.Schema
+	required schemagen.RequiredRules
 }
 
 // NewStructParser creates a new struct parser
@@ -123,6 +197,11 @@ func NewStructParser() *StructParser {
 	}
 }
 
+// SetRequiredRules sets which struct fields are required properties, see schemagen.RequiredRules
+func (p *StructParser) SetRequiredRules(rules schemagen.RequiredRules) {
+	p.required = rules
+}
+
 // ParseStruct parses a Go struct using reflection
 func (p *StructParser) ParseStruct(t reflect.Type) spec.Schema {
 	if t.Kind() == reflect.Ptr {
@@ -155,7 +234,7 @@ func (p *StructParser) ParseStruct(t reflect.Type) spec.Schema {
 		jsonTag := field.Tag.Get("json")
 		validateTag := field.Tag.Get("validate")
 
-		fieldName, omitEmpty := p.parseJSONTag(jsonTag)
+		fieldName := p.parseJSONTag(jsonTag)
 		if fieldName == "-" {
 			continue
 		}
@@ -169,8 +248,8 @@ func (p *StructParser) ParseStruct(t reflect.Type) spec.Schema {
 
 		schema.Properties[fieldName] = fieldSchema
 
-		// Add to required fields if not omitempty and not optional
-		if !omitEmpty && !p.isOptionalFromValidation(validateTag) {
+		// Add to required fields by the same rules as the schema generator
+		if p.required.IsRequired(field.Tag, field.Type.Kind() == reflect.Pointer) {
 			schema.Required = append(schema.Required, fieldName)
 		}
 	}
@@ -183,22 +262,10 @@ func (p *StructParser) ParseStruct(t reflect.Type) spec.Schema {
 	return schema
 }
 
-// parseJSONTag parses the json struct tag
-func (p *StructParser) parseJSONTag(tag string) (name string, omitEmpty bool) {
-	if tag == "" {
-		return "", false
-	}
-
-	parts := strings.Split(tag, ",")
-	name = parts[0]
-
-	for _, part := range parts[1:] {
-		if part == "omitempty" {
-			omitEmpty = true
-		}
-	}
-
-	return name, omitEmpty
+// parseJSONTag returns the name of the json struct tag, its options are ignored
+func (p *StructParser) parseJSONTag(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
 }
 
 // applyValidationTags applies validation tags to schema
@@ -272,11 +339,6 @@ func (p *StructParser) applyValidationRule(rule string, schema *spec.Schema) {
 	}
 }
 
-// isOptionalFromValidation checks if field is optional based on validation tags
-func (p *StructParser) isOptionalFromValidation(tag string) bool {
-	return !strings.Contains(tag, "required")
-}
-
 // parseBasicType converts Go basic types to OpenAPI types
 func (p *StructParser) parseBasicType(t reflect.Type) spec.Schema {
 	switch t.Kind() {
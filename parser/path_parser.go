@@ -3,6 +3,7 @@ package parser
 import (
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // PathParser handles pure algorithmic path parsing with no manual mappings
@@ -210,3 +211,143 @@ func (p *PathParser) GenerateHandlerName(method, path string) string {
 
 	return strings.Join(parts, "")
 }
+
+// AddCommonPrefixes adds path segments left out of generated tags, summaries and names,
+// next to api and version segments such as v1
+func (p *PathParser) AddCommonPrefixes(prefixes ...string) {
+	p.commonPrefixes = append(p.commonPrefixes, prefixes...)
+}
+
+// OperationIDWords returns the lower case words of the operation ID of a route: the method
+// and the words of the meaningful path segments, e.g. get, password, reset and validate for
+// GET /api/v1/password-reset/validate/:token. withParameters adds "by" and the path
+// parameters joined by "and", e.g. get, users, by and id for GET /users/{id}.
+func (p *PathParser) OperationIDWords(method, path string, withParameters bool) []string {
+	words := []string{strings.ToLower(method)}
+	var parameters []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*"):
+			parameters = append(parameters, segment[1:])
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			parameters = append(parameters, strings.Trim(segment, "{}"))
+		case p.isCommonPrefix(segment) || p.versionPattern.MatchString(segment):
+		default:
+			words = append(words, identifierWords(segment)...)
+		}
+	}
+	if len(words) == 1 {
+		words = append(words, "root")
+	}
+	if withParameters {
+		for i, parameter := range parameters {
+			if i == 0 {
+				words = append(words, "by")
+			} else {
+				words = append(words, "and")
+			}
+			words = append(words, identifierWords(parameter)...)
+		}
+	}
+	return words
+}
+
+// identifierWords splits a path segment or identifier into lower case words, e.g. "password-reset" or "passwordReset"
+func identifierWords(identifier string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(identifier, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		for _, word := range splitCamelCase(part) {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	return words
+}
+
+// SummaryFromHandlerName generates a summary from a handler name, e.g. "Create user" from
+// "(*UserHandler).CreateUser-fm" or "Get user by ID" from "GetUserByIDHandler"
+//
+// Closures, single words and ServeHTTP say less than the path, they give an empty summary.
+func (p *PathParser) SummaryFromHandlerName(handlerName string) string {
+	name := strings.TrimSuffix(handlerName, "-fm")
+	name = name[strings.LastIndex(name, ".")+1:]
+	name = strings.TrimSuffix(name, "Handler")
+	if name == "ServeHTTP" {
+		return ""
+	}
+
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		words = append(words, splitCamelCase(part)...)
+	}
+	if len(words) < 2 {
+		return ""
+	}
+
+	for i, word := range words {
+		switch {
+		case i == 0:
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		case !isInitialism(word):
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// compoundWords are mixed-case words splitCamelCase keeps whole
+var compoundWords = []string{"OAuth", "GraphQL", "gRPC"}
+
+// splitCamelCase splits an identifier into its words, keeping initialisms such as ID or
+// URLs whole: "GetUserByID" gives Get, User, By and ID
+func splitCamelCase(identifier string) []string {
+	runes := []rune(identifier)
+	var words []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if compound := compoundWordAt(string(runes[i:])); compound != "" {
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			words = append(words, compound)
+			i += len([]rune(compound)) - 1
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+
+		current, previous := runes[i], runes[i-1]
+		boundary := unicode.IsUpper(current) && !unicode.IsUpper(previous)
+		if unicode.IsUpper(current) && unicode.IsUpper(previous) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			// The last capital of an initialism starts the next word, unless it is a plural like IDs
+			plural := runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2]))
+			boundary = !plural
+		}
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// compoundWordAt returns the compound word a string starts with, if any
+func compoundWordAt(s string) string {
+	for _, compound := range compoundWords {
+		if strings.HasPrefix(s, compound) {
+			return compound
+		}
+	}
+	return ""
+}
+
+// isInitialism reports whether a word is an initialism such as ID, URL or IDs
+func isInitialism(word string) bool {
+	word = strings.TrimSuffix(word, "s")
+	return len(word) > 1 && strings.ToUpper(word) == word && strings.ToLower(word) != word
+}
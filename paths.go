@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// PathConflict reports routes that cannot be documented as registered
+//
+// Either several routes normalize to the same operation, e.g. GET /users and GET /users/,
+// in which case only the first route is documented, or paths differ only in the names of
+// their parameters, e.g. /users/{id} and /users/{userID}, which OpenAPI treats as the same
+// path.
+type PathConflict struct {
+	Method string   // HTTP method of the colliding operations, empty for parameter name conflicts
+	Path   string   // Path documented in the spec
+	Routes []string // Colliding paths as registered with the framework
+}
+
+// pathTemplate converts a framework route path to an OpenAPI path template
+//
+// Gin and Hertz write parameters as ":id" and catch-all wildcards as "*filepath", OpenAPI
+// writes both as "{id}". Paths already in OpenAPI syntax are returned unchanged.
+func pathTemplate(path string) string {
+	if !strings.ContainsAny(path, ":*") {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := pathParameterName(segment); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// pathParameterName returns the parameter name of a path segment written as ":id", "*filepath" or "{id}"
+func pathParameterName(segment string) (string, bool) {
+	if name, found := strings.CutPrefix(segment, ":"); found && name != "" {
+		return name, true
+	}
+	if name, found := strings.CutPrefix(segment, "*"); found && name != "" {
+		return name, true
+	}
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && len(segment) > 2 {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+// pathParameterNames returns the names of the parameters of a path, in order
+func pathParameterNames(path string) []string {
+	var names []string
+	for segment := range strings.SplitSeq(path, "/") {
+		if name, ok := pathParameterName(segment); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// wildcardParameter returns the name of a catch-all parameter, e.g. "filepath" in "/static/*filepath"
+func wildcardParameter(path string) (string, bool) {
+	segment := path[strings.LastIndex(path, "/")+1:]
+	if name, found := strings.CutPrefix(segment, "*"); found && name != "" {
+		return name, true
+	}
+	return "", false
+}
+
+// pathParameters documents the parameters of a route path, all of them required strings
+func pathParameters(path string) []spec.Parameter {
+	var params []spec.Parameter
+	wildcard, _ := wildcardParameter(path)
+	for _, paramName := range pathParameterNames(path) {
+		param := spec.Parameter{
+			Name:        paramName,
+			In:          "path",
+			Required:    true,
+			Description: fmt.Sprintf("Path parameter: %s", paramName),
+			Schema:      spec.Schema{Type: "string"},
+		}
+		if paramName == wildcard {
+			// OpenAPI has no catch-all template, the simple style carries the rest of the path as is
+			param.Description = fmt.Sprintf("Rest of the path, may contain slashes: %s", paramName)
+			param.Style = "simple"
+			param.XWildcard = true
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// specPath returns the path a route is documented under
+//
+// Unless Config.RawPaths is set, parameters are converted to OpenAPI syntax and trailing
+// slashes are dropped, so /users and /users/ share a path item. Config.KeepTrailingSlashes
+// documents them separately.
+func (g *Generator) specPath(path string) string {
+	if g.config.RawPaths {
+		return path
+	}
+
+	path = pathTemplate(path)
+	if !g.config.KeepTrailingSlashes && len(path) > 1 {
+		if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+			path = trimmed
+		} else {
+			path = "/"
+		}
+	}
+	return path
+}
+
+// detectParameterNameConflicts reports documented paths that differ only in their parameter names
+func (g *Generator) detectParameterNameConflicts() {
+	byShape := make(map[string][]string)
+	for path := range g.spec.Paths {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if _, ok := pathParameterName(segment); ok {
+				segments[i] = "{}"
+			}
+		}
+		shape := strings.Join(segments, "/")
+		byShape[shape] = append(byShape[shape], path)
+	}
+
+	for _, shape := range slices.Sorted(maps.Keys(byShape)) {
+		paths := byShape[shape]
+		if len(paths) < 2 {
+			continue
+		}
+		slices.Sort(paths)
+		g.reportPathConflict(PathConflict{Path: paths[0], Routes: paths})
+	}
+}
+
+// reportPathConflict records a conflict and logs it
+func (g *Generator) reportPathConflict(conflict PathConflict) {
+	g.pathConflicts = append(g.pathConflicts, conflict)
+	g.logger.Warn("Conflicting routes in OpenAPI spec", "method", conflict.Method, "path", conflict.Path, "routes", conflict.Routes)
+}
+
+// PathConflicts returns the conflicts found the last time the spec was built
+//
+// They are also logged as warnings. A CI check can fail the build on them.
+//
+// Example:
+//
+//	if conflicts := g.PathConflicts(); len(conflicts) > 0 {
+//		return fmt.Errorf("ambiguous routes: %v", conflicts)
+//	}
+func (g *Generator) PathConflicts() []PathConflict {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.pathConflicts)
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestPathTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/users":                   "/users",
+		"/users/:id":               "/users/{id}",
+		"/users/{id}":              "/users/{id}",
+		"/orgs/:org/members/:user": "/orgs/{org}/members/{user}",
+		"/static/*filepath":        "/static/{filepath}",
+		"/time/12:30":              "/time/12:30",
+	}
+
+	for path, expected := range tests {
+		assert.Equal(t, expected, pathTemplate(path), path)
+	}
+}
+
+func TestPathNormalization(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "GET", Path: "/users/"},
+		{Method: "POST", Path: "/users/"},
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "DELETE", Path: "/users/{userID}"},
+		{Method: "GET", Path: "/static/*filepath"},
+	}
+
+	generator := newTestGenerator(t, routes)
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	// Trailing-slash duplicates share a path item, the slash-less route wins
+	assert.NotContains(t, openAPISpec.Paths, "/users/")
+	assert.NotNil(t, openAPISpec.Paths["/users"].Get)
+	assert.NotNil(t, openAPISpec.Paths["/users"].Post)
+
+	static := openAPISpec.Paths["/static/{filepath}"].Get
+	if assert.NotNil(t, static) && assert.Len(t, static.Parameters, 1) {
+		assert.Equal(t, "filepath", static.Parameters[0].Name)
+		assert.Equal(t, "path", static.Parameters[0].In)
+	}
+	if userDelete := openAPISpec.Paths["/users/{userID}"].Delete; assert.NotNil(t, userDelete) {
+		assert.Equal(t, "userID", userDelete.Parameters[0].Name)
+	}
+
+	assert.Equal(t, []PathConflict{
+		{Method: "GET", Path: "/users", Routes: []string{"/users", "/users/"}},
+		{Path: "/users/{id}", Routes: []string{"/users/{id}", "/users/{userID}"}},
+	}, generator.PathConflicts())
+
+	cfg := NewConfig(WithKeepTrailingSlashes(true))
+	cfg.SchemaDir = ""
+	openAPISpec, err = newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, openAPISpec.Paths, "/users/")
+	assert.Contains(t, openAPISpec.Paths, "/users/{id}")
+
+	cfg = NewConfig(WithRawPaths(true))
+	cfg.SchemaDir = ""
+	openAPISpec, err = newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, openAPISpec.Paths, "/users/:id")
+	assert.Contains(t, openAPISpec.Paths, "/static/*filepath")
+}
+
+func TestWildcardRoutes(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/static/*filepath"},
+		{Method: "GET", Path: "/*any"},
+		{Method: "GET", Path: "/users/:id"},
+	}
+
+	openAPISpec, err := newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	for _, path := range []string{"/static/{filepath}", "/{any}"} {
+		if operation := openAPISpec.Paths[path].Get; assert.NotNil(t, operation, path) {
+			assert.True(t, operation.Parameters[0].XWildcard, path)
+			assert.Equal(t, "simple", operation.Parameters[0].Style, path)
+		}
+	}
+	assert.False(t, openAPISpec.Paths["/users/{id}"].Get.Parameters[0].XWildcard)
+
+	cfg := NewConfig(WithExcludeWildcardRoutes(true))
+	cfg.SchemaDir = ""
+	openAPISpec, err = newTestGenerator(t, routes, WithConfig(cfg)).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Len(t, openAPISpec.Paths, 1)
+	assert.Contains(t, openAPISpec.Paths, "/users/{id}")
+}
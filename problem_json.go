@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"strconv"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// ProblemJSONContentType is the media type of RFC 7807 problem details
+const ProblemJSONContentType = "application/problem+json"
+
+// WithProblemJSONErrors documents 4xx and 5xx responses as RFC 7807 problem details
+//
+// Default error responses are served as application/problem+json with the standard
+// ProblemDetails schema (type, title, status, detail, instance). Error responses
+// overridden with a problem-like struct, e.g. problem.Problem, keep their own schema
+// under the same media type; other overrides are left as they are.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithProblemJSONErrors(),
+//	)
+func WithProblemJSONErrors() Option {
+	return func(opts *Options) {
+		opts.problemJSON = true
+	}
+}
+
+// applyProblemJSON serves the error responses of an operation as problem details
+func (g *Generator) applyProblemJSON(route spec.RouteInfo, operation *spec.Operation) {
+	overrides := g.schemaRegistry.GetResponseOverrides(route.Method, route.Path)
+	for code, response := range operation.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 400 || status > 599 {
+			continue
+		}
+		media, exists := response.Content["application/json"]
+		if !exists {
+			continue
+		}
+
+		if override, overridden := overrides[status]; !overridden {
+			media = spec.MediaType{Schema: spec.Schema{Ref: "#/components/schemas/" + analyzer.ProblemDetailsSchemaName}}
+		} else if !analyzer.IsProblemDetails(override) {
+			continue
+		}
+		response.Content = map[string]spec.MediaType{ProblemJSONContentType: media}
+		operation.Responses[code] = response
+	}
+}
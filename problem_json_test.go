@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type problemResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Trace  string `json:"trace_id"`
+}
+
+func TestWithProblemJSONErrors(t *testing.T) {
+	routes := []spec.RouteInfo{{Method: "POST", Path: "/orders", HandlerName: "CreateOrder"}}
+
+	generator := newTestGenerator(t, routes, WithProblemJSONErrors())
+	generator.OverrideResponse("POST", "/orders", 409, problemResponse{})
+	generator.OverrideResponse("POST", "/orders", 422, overrideErrorResponse{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, analyzer.ProblemDetailsSchema(), openAPISpec.Components.Schemas[analyzer.ProblemDetailsSchemaName])
+
+	responses := openAPISpec.Paths["/orders"].Post.Responses
+	badRequest := responses["400"].Content
+	assert.NotContains(t, badRequest, "application/json")
+	assert.Equal(t, "#/components/schemas/ProblemDetails", badRequest[ProblemJSONContentType].Schema.Ref)
+
+	conflict := resolveRef(t, openAPISpec, responses["409"].Content[ProblemJSONContentType].Schema)
+	assert.Contains(t, conflict.Properties, "trace_id", "Problem-like overrides keep their schema")
+	assert.Contains(t, responses["422"].Content, "application/json", "Other overrides are untouched")
+	assert.Contains(t, responses["200"].Content, "application/json")
+
+	// Without the option errors stay plain JSON
+	openAPISpec, err = newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, openAPISpec.Paths["/orders"].Post.Responses["400"].Content, "application/json")
+	assert.NotContains(t, openAPISpec.Components.Schemas, analyzer.ProblemDetailsSchemaName)
+}
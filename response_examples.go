@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// WithErrorExamples documents an example payload for error statuses on every operation
+// documenting them, e.g. a sample 422 validation error
+//
+// Examples are set on the JSON content of the responses, problem details included.
+// Examples set with Override or in the schema file of a handler take precedence.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithErrorExamples(map[int]any{
+//			http.StatusUnauthorized:        ErrorResponse{Error: "token expired", Code: 401},
+//			http.StatusUnprocessableEntity: ErrorResponse{Error: "email is invalid", Code: 422},
+//		}),
+//	)
+func WithErrorExamples(examples map[int]any) Option {
+	return func(opts *Options) {
+		for status := range examples {
+			if status < 400 || status > 599 {
+				opts.conflicts = append(opts.conflicts, fmt.Errorf("WithErrorExamples: %d is not an error status", status))
+			}
+		}
+		opts.errorExamples = examples
+	}
+}
+
+// applyResponseExamples documents the example payloads configured for the responses of an operation
+//
+// Examples set with Override win over those of the handler's schema file, which win over
+// WithErrorExamples. Recorded examples only fill the responses left without one.
+func (g *Generator) applyResponseExamples(route spec.RouteInfo, operation *spec.Operation, handlerSchema analyzer.HandlerSchema) {
+	for _, examples := range []map[int]any{
+		g.overrideManager.GetRouteExamples(route.Method, route.Path),
+		handlerSchema.Examples,
+		g.errorExamples,
+	} {
+		for status, example := range examples {
+			code := strconv.Itoa(status)
+			if response, documented := operation.Responses[code]; documented {
+				response.Content = withContentExample(response.Content, example)
+				operation.Responses[code] = response
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseExamples(t *testing.T) {
+	bundle := fstest.MapFS{
+		analyzer.SchemaBundleFileName: {Data: []byte(`{"version": 1, "schemas": [
+			{"handlerName": "CreateUser", "examples": {"422": {"error": "email is taken", "code": 422}}}
+		]}`)},
+	}
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+		{Method: "GET", Path: "/orders", HandlerName: "ListOrders"},
+	},
+		WithSchemaBundle(bundle),
+		WithDefaultResponses(map[string]spec.Response{
+			"400": ErrorResponse(400),
+			"422": ErrorResponse(422),
+		}),
+		WithErrorExamples(map[int]any{
+			400: map[string]any{"error": "malformed JSON", "code": 400},
+			422: map[string]any{"error": "validation failed", "code": 422},
+			503: map[string]any{"error": "try again later", "code": 503},
+		}),
+		WithProblemJSONErrors(),
+	)
+	generator.Override("POST", "/users").ResponseExample(400, map[string]any{"error": "name is required", "code": 400})
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	// Override, then schema file, then the examples of the API
+	users := openAPISpec.Paths["/users"].Post.Responses
+	assert.Equal(t, map[string]any{"error": "name is required", "code": 400}, users["400"].Content[ProblemJSONContentType].Example)
+	assert.Equal(t, map[string]any{"error": "email is taken", "code": 422.0}, users["422"].Content[ProblemJSONContentType].Example)
+
+	orders := openAPISpec.Paths["/orders"].Get.Responses
+	assert.Equal(t, map[string]any{"error": "malformed JSON", "code": 400}, orders["400"].Content[ProblemJSONContentType].Example)
+	assert.Equal(t, map[string]any{"error": "validation failed", "code": 422}, orders["422"].Content[ProblemJSONContentType].Example)
+	// Statuses the operation does not document are not added
+	assert.NotContains(t, orders, "503")
+}
+
+func TestWithErrorExamplesInvalidStatus(t *testing.T) {
+	options := processOptions(WithConfig(NewConfig()), WithErrorExamples(map[int]any{201: "created"}))
+	assert.ErrorContains(t, options.validate(), "WithErrorExamples: 201 is not an error status")
+}
@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// applyResponseFormats documents the media types a handler renders its success response in
+//
+// XML, YAML and TOML renderings document the JSON schema, XML ones with the element name
+// set by nameXMLRoots. Text such as text/plain or text/csv is a string and other formats,
+// e.g. application/x-protobuf, are binary. The placeholder JSON response of a handler that
+// renders no JSON is dropped.
+func (g *Generator) applyResponseFormats(route spec.RouteInfo, operation *spec.Operation, handlerSchema analyzer.HandlerSchema) {
+	success, exists := operation.Responses["200"]
+	if !exists || success.Content == nil || len(handlerSchema.ResponseFormats) == 0 {
+		return // Responses without a body stay that way
+	}
+
+	content := make(map[string]spec.MediaType, len(success.Content)+len(handlerSchema.ResponseFormats))
+	for contentType, mediaType := range success.Content {
+		content[contentType] = mediaType
+	}
+	jsonSchema := content["application/json"].Schema
+	_, discovered := g.schemaRegistry.GetResponseSchema(route.Method, route.Path)
+	if !slices.Contains(handlerSchema.ResponseFormats, "application/json") &&
+		(!discovered || handlerSchema.ResponseSchema.Description == analyzer.FallbackResponseDescription) {
+		delete(content, "application/json")
+		jsonSchema = spec.Schema{Type: "object"}
+	}
+
+	for _, contentType := range handlerSchema.ResponseFormats {
+		if _, documented := content[contentType]; documented {
+			continue
+		}
+		content[contentType] = spec.MediaType{Schema: mediaTypeSchema(contentType, jsonSchema)}
+	}
+
+	success.Content = content
+	operation.Responses["200"] = success
+}
+
+// nameXMLRoots names the XML element of the components responses are rendered in as XML
+//
+// encoding/xml names the root element after its type, which is also the name of its
+// component. Components named by their XMLName field already have an xml name and keep it.
+func nameXMLRoots(openAPISpec *spec.OpenAPISpec, componentTypes map[string]reflect.Type) {
+	for _, pathItem := range openAPISpec.Paths {
+		for _, operation := range pathOperations(&pathItem) {
+			for _, response := range operation.Responses {
+				ref := response.Content["application/xml"].Schema.Ref
+				name, isComponent := strings.CutPrefix(ref, "#/components/schemas/")
+				component, exists := openAPISpec.Components.Schemas[name]
+				if !isComponent || !exists || component.XML != nil {
+					continue
+				}
+				component.XML = &spec.XML{Name: xmlRootName(name, componentTypes[name])}
+				openAPISpec.Components.Schemas[name] = component
+			}
+		}
+	}
+}
+
+// xmlRootName returns the root element name encoding/xml writes for a value of a Go type,
+// the component name when the type is unknown
+func xmlRootName(componentName string, t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && t.Name() != "" {
+		return t.Name()
+	}
+	return componentName
+}
@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type formatUser struct {
+	Name string `json:"name"`
+}
+
+type formatAccount struct {
+	XMLName xml.Name `json:"-" xml:"urn:acme account"`
+	ID      string   `json:"id"`
+}
+
+func TestResponseFormats(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", HandlerName: "GetUser"},
+		{Method: "GET", Path: "/accounts/:id", HandlerName: "GetAccount"},
+		{Method: "GET", Path: "/export", HandlerName: "Export"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("GetUser", analyzer.HandlerSchema{
+		ResponseFormats: []string{"application/json", "application/xml", "application/x-protobuf"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("GetAccount", analyzer.HandlerSchema{
+		ResponseFormats: []string{"application/xml"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("Export", analyzer.HandlerSchema{
+		ResponseSchema:  spec.Schema{Type: "object", Description: analyzer.FallbackResponseDescription},
+		ResponseFormats: []string{"text/csv"},
+	})
+	generator.OverrideResponse("GET", "/users/:id", 200, formatUser{})
+	generator.OverrideResponse("GET", "/accounts/:id", 200, formatAccount{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	user := openAPISpec.Paths["/users/{id}"].Get.Responses["200"].Content
+	assert.Len(t, user, 3)
+	assert.Equal(t, user["application/json"].Schema, user["application/xml"].Schema)
+	assert.Equal(t, spec.Schema{Type: "string", Format: "binary"}, user["application/x-protobuf"].Schema)
+	assert.Equal(t, &spec.XML{Name: "formatUser"}, resolveRef(t, openAPISpec, user["application/xml"].Schema).XML)
+
+	// A discovered schema is kept for JSON even though only XML rendering was seen
+	account := openAPISpec.Paths["/accounts/{id}"].Get.Responses["200"].Content
+	assert.Contains(t, account, "application/json")
+	assert.Equal(t, &spec.XML{Namespace: "urn:acme", Name: "account"}, resolveRef(t, openAPISpec, account["application/xml"].Schema).XML)
+
+	// The placeholder of a handler rendering only CSV is not documented as JSON
+	export := openAPISpec.Paths["/export"].Get.Responses["200"].Content
+	assert.Equal(t, map[string]spec.MediaType{"text/csv": {Schema: spec.Schema{Type: "string"}}}, export)
+}
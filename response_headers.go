@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// RouteFilter selects the routes a registration applies to, a nil filter matches every route
+type RouteFilter func(route spec.RouteInfo) bool
+
+// RoutesWithPrefix matches the routes whose path starts with prefix, e.g. "/api/v1"
+func RoutesWithPrefix(prefix string) RouteFilter {
+	return func(route spec.RouteInfo) bool {
+		return strings.HasPrefix(route.Path, prefix)
+	}
+}
+
+// commonResponseHeader is a header documented on the responses of every route its filter matches
+type commonResponseHeader struct {
+	name      string
+	header    spec.Header
+	appliesTo RouteFilter
+}
+
+// RegisterCommonResponseHeader documents a header set by middleware on the responses of matching routes
+//
+// The header is added to every response of each operation whose route appliesTo
+// matches, or of every operation when appliesTo is nil. A response that already
+// documents a header with the same name keeps its own definition.
+//
+// Example:
+//
+//	g.RegisterCommonResponseHeader("X-RateLimit-Remaining", spec.Header{
+//		Description: "Requests left in the current window",
+//		Schema:      spec.Schema{Type: "integer"},
+//	}, openapi.RoutesWithPrefix("/api"))
+func (g *Generator) RegisterCommonResponseHeader(name string, header spec.Header, appliesTo RouteFilter) {
+	g.commonHeaders = append(g.commonHeaders, commonResponseHeader{name: name, header: header, appliesTo: appliesTo})
+}
+
+// applyCommonResponseHeaders adds the registered common headers to the responses of an operation
+func (g *Generator) applyCommonResponseHeaders(route spec.RouteInfo, operation *spec.Operation) {
+	for _, common := range g.commonHeaders {
+		if common.appliesTo != nil && !common.appliesTo(route) {
+			continue
+		}
+		addResponseHeader(operation, common.name, common.header)
+	}
+}
+
+// addResponseHeader adds a header to the responses of an operation that do not document it yet
+func addResponseHeader(operation *spec.Operation, name string, header spec.Header) {
+	for code, response := range operation.Responses {
+		if _, exists := response.Headers[name]; exists {
+			continue
+		}
+		// Copy before adding, responses may share their header maps
+		headers := make(map[string]spec.Header, len(response.Headers)+1)
+		for existing, documented := range response.Headers {
+			headers[existing] = documented
+		}
+		headers[name] = header
+		response.Headers = headers
+		operation.Responses[code] = response
+	}
+}
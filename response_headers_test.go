@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterCommonResponseHeader(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/api/users", HandlerName: "ListUsers"},
+		{Method: "GET", Path: "/health", HandlerName: "Health"},
+		{Method: "GET", Path: "/api/reports/:id", HandlerName: "DownloadReport"},
+	})
+	rateLimit := spec.Header{Description: "Requests left in the current window", Schema: spec.Schema{Type: "integer"}}
+	generator.RegisterCommonResponseHeader("X-RateLimit-Remaining", rateLimit, RoutesWithPrefix("/api"))
+	generator.RegisterCommonResponseHeader("X-Request-ID", spec.Header{Schema: spec.Schema{Type: "string"}}, nil)
+	generator.RegisterCommonResponseHeader("Content-Disposition", spec.Header{Description: "Overridden"}, nil)
+	generator.RegisterFileDownload("GET", "/api/reports/:id", "application/pdf")
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	for code, response := range openAPISpec.Paths["/api/users"].Get.Responses {
+		assert.Equal(t, rateLimit, response.Headers["X-RateLimit-Remaining"], code)
+		assert.Contains(t, response.Headers, "X-Request-ID", code)
+	}
+	for code, response := range openAPISpec.Paths["/health"].Get.Responses {
+		assert.NotContains(t, response.Headers, "X-RateLimit-Remaining", code)
+		assert.Contains(t, response.Headers, "X-Request-ID", code)
+	}
+
+	download := openAPISpec.Paths["/api/reports/{id}"].Get.Responses["200"]
+	assert.Equal(t, "Suggested file name for the download", download.Headers["Content-Disposition"].Description,
+		"Headers documented by the response win")
+	assert.Contains(t, download.Headers, "X-RateLimit-Remaining")
+}
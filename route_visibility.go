@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// routePattern matches routes by method and path, see Hide
+type routePattern struct {
+	method string
+	path   string
+}
+
+// matches reports whether the pattern matches a route
+func (p routePattern) matches(route spec.RouteInfo) bool {
+	if p.method != "" && p.method != "*" && !strings.EqualFold(p.method, route.Method) {
+		return false
+	}
+	if prefix, wildcard := strings.CutSuffix(p.path, "*"); wildcard {
+		return strings.HasPrefix(route.Path, prefix)
+	}
+	return p.path == route.Path
+}
+
+// Hide leaves the routes matching method and path out of the spec
+//
+// method is an HTTP method, or "*" for every method. path is a route path as registered
+// with the framework, a trailing "*" matches every path with that prefix. Hidden routes
+// are not analyzed, so their schemas do not end up in the components either. Call Hide
+// before the spec is generated, e.g. in a customizer.
+//
+// A single handler can also be hidden with an //openapi:ignore comment on its declaration.
+//
+// Example:
+//
+//	g.Hide("GET", "/debug/pprof/*")
+//	g.Hide("*", "/internal/healthz")
+func (g *Generator) Hide(method, path string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.hiddenRoutes = append(g.hiddenRoutes, routePattern{method: method, path: path})
+}
+
+// isHidden reports whether a route matches one of the Hide patterns
+func (g *Generator) isHidden(route spec.RouteInfo) bool {
+	for _, pattern := range g.hiddenRoutes {
+		if pattern.matches(route) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkInternal keeps the routes matching method and path in the spec, marked x-internal
+//
+// Redocly and most API gateways hide x-internal operations from public documentation.
+// Patterns work as in Hide. Operations can also be marked with RouteMetadata.Internal
+// through the override manager, or with an //openapi:internal comment on the handler.
+// Set Config.ExcludeInternal to leave them out of the spec, e.g. for the public artifact.
+//
+// Example:
+//
+//	g.MarkInternal("*", "/api/v1/admin/*")
+func (g *Generator) MarkInternal(method, path string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.internalRoutes = append(g.internalRoutes, routePattern{method: method, path: path})
+}
+
+// isInternal reports whether a route was marked internal by MarkInternal, an override or its handler comment
+func (g *Generator) isInternal(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) bool {
+	if handlerSchema.HasDirective(analyzer.DirectiveInternal) {
+		return true
+	}
+	for _, pattern := range g.internalRoutes {
+		if pattern.matches(route) {
+			return true
+		}
+	}
+	parsed := g.pathParser.ParseRoute(route.Method, route.Path)
+	return g.overrideManager.GetMetadata(route.Method, route.Path, parsed).Internal
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// ignoredAnalyzer finds an //openapi:ignore comment on every handler
+type ignoredAnalyzer struct {
+	placeholderAnalyzer
+}
+
+func (ignoredAnalyzer) AnalyzeHandler(handler interface{}) analyzer.HandlerSchema {
+	return analyzer.HandlerSchema{
+		ResponseSchema: spec.Schema{Type: "object", Properties: map[string]spec.Schema{"heap": {Type: "integer"}}},
+		Directives:     []string{analyzer.DirectiveIgnore},
+	}
+}
+
+func TestHide(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/debug/pprof/heap"},
+		{Method: "GET", Path: "/debug/pprof/profile"},
+		{Method: "GET", Path: "/users"},
+		{Method: "POST", Path: "/users"},
+		{Method: "DELETE", Path: "/users"},
+	})
+	generator.Hide("GET", "/debug/pprof/*")
+	generator.Hide("delete", "/users")
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotContains(t, openAPISpec.Paths, "/debug/pprof/heap")
+	assert.NotContains(t, openAPISpec.Paths, "/debug/pprof/profile")
+	if assert.Contains(t, openAPISpec.Paths, "/users") {
+		assert.NotNil(t, openAPISpec.Paths["/users"].Get)
+		assert.NotNil(t, openAPISpec.Paths["/users"].Post)
+		assert.Nil(t, openAPISpec.Paths["/users"].Delete)
+	}
+
+	// Hidden routes are still known, refreshing does not bring them back
+	assert.NoError(t, generator.RefreshRoutes())
+	assert.NotContains(t, generator.spec.Paths, "/debug/pprof/heap")
+}
+
+func TestIgnoreDirective(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/debug/vars", HandlerName: "Vars", Handler: func() {}},
+		{Method: "GET", Path: "/health"},
+	})
+	generator.handlerAnalyzer = ignoredAnalyzer{}
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotContains(t, openAPISpec.Paths, "/debug/vars")
+	assert.Contains(t, openAPISpec.Paths, "/health")
+
+	// The schemas of an ignored handler are not registered either
+	for name, schema := range openAPISpec.Components.Schemas {
+		assert.NotContains(t, schema.Properties, "heap", name)
+	}
+}
+
+// internalAnalyzer finds an //openapi:internal comment on every handler
+type internalAnalyzer struct {
+	placeholderAnalyzer
+}
+
+func (internalAnalyzer) AnalyzeHandler(handler interface{}) analyzer.HandlerSchema {
+	return analyzer.HandlerSchema{Directives: []string{analyzer.DirectiveInternal}}
+}
+
+func TestInternalRoutes(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/admin/stats"},
+		{Method: "POST", Path: "/jobs/reindex"},
+		{Method: "GET", Path: "/cache/keys", HandlerName: "CacheKeys", Handler: func() {}},
+		{Method: "GET", Path: "/users"},
+	}
+	markInternal := func(g *Generator) {
+		g.handlerAnalyzer = internalAnalyzer{}
+		g.MarkInternal("*", "/admin/*")
+		g.GetOverrideManager().Override("POST", "/jobs/reindex", RouteMetadata{Internal: true})
+	}
+
+	generator := newTestGenerator(t, routes)
+	markInternal(generator)
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.True(t, openAPISpec.Paths["/admin/stats"].Get.XInternal)
+	assert.True(t, openAPISpec.Paths["/jobs/reindex"].Post.XInternal)
+	assert.True(t, openAPISpec.Paths["/cache/keys"].Get.XInternal)
+	assert.False(t, openAPISpec.Paths["/users"].Get.XInternal)
+
+	cfg := NewConfig(WithExcludeInternal(true))
+	cfg.SchemaDir = ""
+	public := newTestGenerator(t, routes, WithConfig(cfg))
+	markInternal(public)
+	publicSpec, err := public.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Len(t, publicSpec.Paths, 1)
+	assert.Contains(t, publicSpec.Paths, "/users")
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// rewriteComponentRefs points every schema reference in the spec paths at the deduplicated components
+func (g *Generator) rewriteComponentRefs(renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+
+	for path, pathItem := range g.spec.Paths {
+		for _, operation := range []*spec.Operation{
+			pathItem.Get, pathItem.Put, pathItem.Post, pathItem.Delete,
+			pathItem.Options, pathItem.Head, pathItem.Patch, pathItem.Trace,
+		} {
+			if operation != nil {
+				rewriteOperationRefs(operation, renames)
+			}
+		}
+		rewriteParameterRefs(pathItem.Parameters, renames)
+		g.spec.Paths[path] = pathItem
+	}
+}
+
+// rewriteOperationRefs rewrites references in parameters, request bodies and responses of an operation
+func rewriteOperationRefs(operation *spec.Operation, renames map[string]string) {
+	rewriteParameterRefs(operation.Parameters, renames)
+	if operation.RequestBody != nil {
+		rewriteContentRefs(operation.RequestBody.Content, renames)
+	}
+	for code, response := range operation.Responses {
+		rewriteContentRefs(response.Content, renames)
+		for name, header := range response.Headers {
+			analyzer.RewriteSchemaRefs(&header.Schema, renames)
+			response.Headers[name] = header
+		}
+		operation.Responses[code] = response
+	}
+}
+
+// rewriteParameterRefs rewrites references in parameter schemas
+func rewriteParameterRefs(parameters []spec.Parameter, renames map[string]string) {
+	for i := range parameters {
+		analyzer.RewriteSchemaRefs(&parameters[i].Schema, renames)
+	}
+}
+
+// rewriteContentRefs rewrites references in media type schemas
+func rewriteContentRefs(content map[string]spec.MediaType, renames map[string]string) {
+	for contentType, mediaType := range content {
+		analyzer.RewriteSchemaRefs(&mediaType.Schema, renames)
+		content[contentType] = mediaType
+	}
+}
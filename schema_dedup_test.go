@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dedupUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestGenerateSpecDeduplicatesSharedDTOs(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+		{Method: "PUT", Path: "/users/:id", HandlerName: "UpdateUser"},
+	})
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/users", reflect.TypeOf(dedupUserRequest{}), nil)
+	generator.schemaRegistry.RegisterHandlerTypes("PUT", "/users/:id", reflect.TypeOf(dedupUserRequest{}), nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	assert.Contains(t, openAPISpec.Components.Schemas, "dedupUserRequest")
+	assert.NotContains(t, openAPISpec.Components.Schemas, "POST_usersrequest")
+	assert.NotContains(t, openAPISpec.Components.Schemas, "PUT_users_idrequest")
+
+	create := openAPISpec.Paths["/users"].Post.RequestBody.Content["application/json"].Schema
+	update := openAPISpec.Paths["/users/{id}"].Put.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/dedupUserRequest", create.Ref)
+	assert.Equal(t, create.Ref, update.Ref)
+}
+
+func TestWithSchemaNamer(t *testing.T) {
+	routes := []spec.RouteInfo{{Method: "POST", Path: "/login", HandlerName: "Login"}}
+
+	generator := newTestGenerator(t, routes)
+	generator.OverrideRequest("POST", "/login", overrideLoginRequest{})
+	generator.OverrideResponse("POST", "/login", 401, overrideErrorResponse{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, openAPISpec.Components.Schemas, "overrideLoginRequest", "Schemas default to Go type names")
+	assert.Contains(t, openAPISpec.Components.Schemas, "overrideErrorResponse")
+	assert.Equal(t, "#/components/schemas/overrideErrorResponse",
+		openAPISpec.Paths["/login"].Post.Responses["401"].Content["application/json"].Schema.Ref)
+
+	var calls []string
+	generator = newTestGenerator(t, routes, WithSchemaNamer(func(routeKey, kind string, goType reflect.Type) string {
+		calls = append(calls, routeKey+" "+kind)
+		if goType == nil {
+			return ""
+		}
+		return "Api" + goType.Name()
+	}))
+	generator.OverrideRequest("POST", "/login", overrideLoginRequest{})
+
+	openAPISpec, err = generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Contains(t, calls, "POST /login request")
+	assert.Contains(t, openAPISpec.Components.Schemas, "ApioverrideLoginRequest")
+	assert.Equal(t, "#/components/schemas/ApioverrideLoginRequest",
+		openAPISpec.Paths["/login"].Post.RequestBody.Content["application/json"].Schema.Ref)
+}
+
+type accessUser struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Password  string `json:"password"`
+	CreatedAt string `json:"created_at" openapi:"readOnly"`
+}
+
+func TestWithReadOnlyAndWriteOnlyProperties(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+	}, WithReadOnlyProperties("id"), WithWriteOnlyProperties("password"))
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/users", reflect.TypeOf(accessUser{}), reflect.TypeOf(accessUser{}))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/users"].Post
+	assert.Equal(t, "#/components/schemas/accessUserRequest", operation.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/accessUserResponse", operation.Responses["200"].Content["application/json"].Schema.Ref)
+
+	request := openAPISpec.Components.Schemas["accessUserRequest"]
+	assert.NotContains(t, request.Properties, "id")
+	assert.NotContains(t, request.Properties, "created_at")
+	assert.Contains(t, request.Properties, "password")
+
+	response := openAPISpec.Components.Schemas["accessUserResponse"]
+	assert.True(t, response.Properties["id"].ReadOnly)
+	assert.True(t, response.Properties["created_at"].ReadOnly)
+	assert.NotContains(t, response.Properties, "password")
+}
+
+type dedupTeamRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestGenerateSpecKeepsIdenticalGoTypesApart(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+		{Method: "POST", Path: "/teams", HandlerName: "CreateTeam"},
+	})
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/users", reflect.TypeOf(dedupUserRequest{}), nil)
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/teams", reflect.TypeOf(dedupTeamRequest{}), nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	assert.Contains(t, openAPISpec.Components.Schemas, "dedupUserRequest")
+	assert.Contains(t, openAPISpec.Components.Schemas, "dedupTeamRequest")
+	assert.Equal(t, "#/components/schemas/dedupTeamRequest",
+		openAPISpec.Paths["/teams"].Post.RequestBody.Content["application/json"].Schema.Ref)
+}
+
+func TestGenerateSpecTwiceKeepsStoredReferences(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+		{Method: "PUT", Path: "/users/:id", HandlerName: "UpdateUser"},
+		{Method: "GET", Path: "/teams", HandlerName: "GetTeam"},
+	})
+	user := spec.Schema{Type: "object", Properties: map[string]spec.Schema{"name": {Type: "string"}}}
+	generator.schemaRegistry.RegisterRequestSchema("POST", "/users", user)
+	generator.schemaRegistry.RegisterRequestSchema("PUT", "/users/:id", user)
+	generator.schemaRegistry.RegisterResponseSchema("GET", "/teams", spec.Schema{
+		Type:       "object",
+		Properties: map[string]spec.Schema{"owner": {Ref: "#/components/schemas/PUT_users_idrequest"}},
+	})
+
+	first, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	owner := first.Components.Schemas["GET_teamsresponse"].Properties["owner"]
+	assert.Equal(t, "#/components/schemas/POST_usersrequest", owner.Ref, "Merged components are referenced by their new name")
+
+	// Deduplication rewrites copies, the registry keeps the references it was given
+	stored := generator.schemaRegistry.GetAllSchemas()["GET_teamsresponse"]
+	assert.Equal(t, "#/components/schemas/PUT_users_idrequest", stored.Properties["owner"].Ref)
+
+	second, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, first.Components.Schemas, second.Components.Schemas)
+}
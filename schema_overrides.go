@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// OverrideRequest documents the request body of a route with a Go type
+//
+// The schema is generated from the value's type and takes precedence over
+// pre-registered schemas and handler analysis. A nil value documents the route
+// without a request body, e.g. POST /logout.
+//
+// Example:
+//
+//	openapi.WithCustomizer(func(g *openapi.Generator) error {
+//		g.OverrideRequest("POST", "/login", LoginRequest{})
+//		g.OverrideResponse("POST", "/login", 200, LoginResponse{})
+//		g.OverrideResponse("POST", "/login", 401, ErrorResponse{})
+//		return nil
+//	})
+func (g *Generator) OverrideRequest(method, path string, request any) {
+	if t := valueType(request); t != nil {
+		g.schemaRegistry.RegisterRequestOverrideType(method, path, t)
+		return
+	}
+	g.schemaRegistry.RegisterRequestOverride(method, path, spec.Schema{})
+}
+
+// OverrideRequestRequired sets whether the request body of a route is required
+//
+// Request bodies are required by default. Optional bodies suit endpoints that accept
+// an empty request, e.g. a PATCH whose fields all have defaults.
+func (g *Generator) OverrideRequestRequired(method, path string, required bool) {
+	g.schemaRegistry.RegisterRequestRequired(method, path, required)
+}
+
+// OverrideResponse documents the response of a route for a status code with a Go type
+//
+// A nil value documents the status without a response body, e.g. 204 No Content.
+func (g *Generator) OverrideResponse(method, path string, status int, response any) {
+	if t := valueType(response); t != nil {
+		g.schemaRegistry.RegisterResponseOverrideType(method, path, status, t)
+		return
+	}
+	g.schemaRegistry.RegisterResponseOverride(method, path, status, spec.Schema{})
+}
+
+// valueType returns the dereferenced type of a value, nil for a nil value
+func valueType(value any) reflect.Type {
+	if value == nil {
+		return nil
+	}
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// applyResponseOverrides replaces the documented responses with the ones registered through OverrideResponse
+func (g *Generator) applyResponseOverrides(route spec.RouteInfo, operation *spec.Operation) {
+	for status, schema := range g.schemaRegistry.GetResponseOverrides(route.Method, route.Path) {
+		code := strconv.Itoa(status)
+		response := spec.Response{Description: http.StatusText(status)}
+		if response.Description == "" {
+			response.Description = "Response " + code
+		}
+
+		if !reflect.DeepEqual(schema, spec.Schema{}) {
+			response.Content = map[string]spec.MediaType{
+				"application/json": {Schema: g.generateSchemaReference(route.Method, route.Path, analyzer.ResponseSchemaKind(status))},
+			}
+		}
+		operation.Responses[code] = response
+	}
+}
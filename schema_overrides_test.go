@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type overrideLoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type overrideLoginResponse struct {
+	Token string `json:"token"`
+}
+
+type overrideErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+// resolveRef returns the component schema a $ref points to
+func resolveRef(t *testing.T, openAPISpec *spec.OpenAPISpec, schema spec.Schema) spec.Schema {
+	t.Helper()
+	assert.NotEmpty(t, schema.Ref)
+	return openAPISpec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+}
+
+func TestOverrideRequestAndResponse(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/login", HandlerName: "Login"},
+	})
+	// A pre-registered schema must lose against the overrides
+	generator.schemaRegistry.RegisterHandlerSchema("Login", analyzer.HandlerSchema{
+		RequestSchema:  spec.Schema{Type: "object", Properties: map[string]spec.Schema{"legacy": {Type: "string"}}},
+		ResponseSchema: spec.Schema{Type: "object", Properties: map[string]spec.Schema{"legacy": {Type: "string"}}},
+	})
+
+	generator.OverrideRequest("POST", "/login", overrideLoginRequest{})
+	generator.OverrideResponse("POST", "/login", 200, &overrideLoginResponse{})
+	generator.OverrideResponse("POST", "/login", 401, overrideErrorResponse{})
+	generator.OverrideResponse("POST", "/login", 204, nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/login"].Post
+	if !assert.NotNil(t, operation) || !assert.NotNil(t, operation.RequestBody) {
+		return
+	}
+
+	request := resolveRef(t, openAPISpec, operation.RequestBody.Content["application/json"].Schema)
+	assert.Contains(t, request.Properties, "email")
+	assert.NotContains(t, request.Properties, "legacy")
+
+	success := resolveRef(t, openAPISpec, operation.Responses["200"].Content["application/json"].Schema)
+	assert.Contains(t, success.Properties, "token")
+
+	unauthorized := operation.Responses["401"]
+	assert.Equal(t, "Unauthorized", unauthorized.Description)
+	assert.Contains(t, resolveRef(t, openAPISpec, unauthorized.Content["application/json"].Schema).Properties, "reason")
+
+	noContent := operation.Responses["204"]
+	assert.Equal(t, "No Content", noContent.Description)
+	assert.Empty(t, noContent.Content)
+}
+
+func TestOverrideRequestAddsBodyToGet(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/search", HandlerName: "Search"},
+	})
+	generator.OverrideRequest("GET", "/search", overrideLoginRequest{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotNil(t, openAPISpec.Paths["/search"].Get.RequestBody)
+}
+
+func TestRequestBodyPresenceAndRequired(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/logout", HandlerName: "Logout"},
+		{Method: "PATCH", Path: "/settings", HandlerName: "UpdateSettings"},
+		{Method: "POST", Path: "/login", HandlerName: "Login"},
+	})
+	generator.OverrideRequest("POST", "/logout", nil)
+	generator.OverrideRequest("PATCH", "/settings", overrideLoginRequest{})
+	generator.OverrideRequestRequired("PATCH", "/settings", false)
+	generator.schemaRegistry.RegisterHandlerTypes("POST", "/login", reflect.TypeOf(overrideLoginRequest{}), nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	assert.Nil(t, openAPISpec.Paths["/logout"].Post.RequestBody, "A nil override documents no body")
+	if settings := openAPISpec.Paths["/settings"].Patch.RequestBody; assert.NotNil(t, settings) {
+		assert.False(t, settings.Required)
+	}
+	if login := openAPISpec.Paths["/login"].Post.RequestBody; assert.NotNil(t, login) {
+		assert.True(t, login.Required, "Bodies are required by default")
+	}
+
+	// Without a known request schema no body is documented, unless generic bodies are enabled
+	route := spec.RouteInfo{Method: "POST", Path: "/refresh"}
+	assert.Nil(t, generator.createOperation(route, RouteMetadata{}, analyzer.HandlerSchema{}).RequestBody)
+
+	generator.config.GenericRequestBodies = true
+	if generic := generator.createOperation(route, RouteMetadata{}, analyzer.HandlerSchema{}).RequestBody; assert.NotNil(t, generic) {
+		assert.Contains(t, generic.Content["application/json"].Schema.Properties, "data")
+	}
+	// Handlers analyzed to never read their body get none either way
+	assert.Nil(t, generator.createOperation(route, RouteMetadata{}, analyzer.HandlerSchema{NoRequestBody: true}).RequestBody)
+}
@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// Variant selects one of several request or response shapes of a route that dispatches on
+// a content type or on the value of a request header
+//
+// Variants with a ContentType are documented as separate media types of the body. Variants
+// with a Header and Value share their media type, application/json unless ContentType is
+// set, as a oneOf, and the header is documented as a parameter listing the values.
+type Variant struct {
+	ContentType string // e.g. "application/vnd.acme.v2+json"
+	Header      string // e.g. "Accept-Version"
+	Value       string // e.g. "2"
+}
+
+// RegisterRequestVariant documents one of the request bodies a route accepts
+//
+// Example:
+//
+//	openapi.WithCustomizer(func(g *openapi.Generator) error {
+//		g.RegisterRequestVariant("POST", "/users", openapi.Variant{Header: "Accept-Version", Value: "1"}, CreateUserV1{})
+//		g.RegisterRequestVariant("POST", "/users", openapi.Variant{Header: "Accept-Version", Value: "2"}, CreateUserV2{})
+//		return nil
+//	})
+func (g *Generator) RegisterRequestVariant(method, path string, variant Variant, request any) {
+	g.registerVariant(method, path, 0, variant, request)
+}
+
+// RegisterResponseVariant documents one of the responses a route returns for a status code
+//
+// The variants of a status replace the response discovered for the media types they use, so
+// every variant, the default one included, should be registered.
+//
+// Example:
+//
+//	g.RegisterResponseVariant("GET", "/users/:id", 200, openapi.Variant{ContentType: "application/json"}, UserV1{})
+//	g.RegisterResponseVariant("GET", "/users/:id", 200, openapi.Variant{ContentType: "application/vnd.acme.v2+json"}, UserV2{})
+func (g *Generator) RegisterResponseVariant(method, path string, status int, variant Variant, response any) {
+	g.registerVariant(method, path, status, variant, response)
+}
+
+// registerVariant stores the schema of a variant in the schema registry, nil values are ignored
+func (g *Generator) registerVariant(method, path string, status int, variant Variant, value any) {
+	t := valueType(value)
+	if t == nil {
+		return
+	}
+	g.schemaRegistry.RegisterVariantType(method, path, analyzer.SchemaVariant{
+		Status:      status,
+		ContentType: variant.ContentType,
+		Header:      variant.Header,
+		Value:       variant.Value,
+	}, t)
+}
+
+// applySchemaVariants documents the registered variants of a route in its request body and responses
+func (g *Generator) applySchemaVariants(route spec.RouteInfo, operation *spec.Operation) {
+	variants := g.schemaRegistry.GetVariants(route.Method, route.Path)
+	if len(variants) == 0 {
+		return
+	}
+
+	// Content of the request body (status 0) and of each response, copied before changes,
+	// and the header variants of each media type in registration order
+	contents := make(map[int]map[string]spec.MediaType)
+	dispatched := make(map[int]map[string][]analyzer.SchemaVariant)
+	var statuses []int
+	headerValues := make(map[string][]string)
+	var headers []string
+
+	for _, variant := range variants {
+		content, exists := contents[variant.Status]
+		if !exists {
+			content = documentedContent(operation, variant.Status)
+			contents[variant.Status] = content
+			dispatched[variant.Status] = make(map[string][]analyzer.SchemaVariant)
+			statuses = append(statuses, variant.Status)
+		}
+
+		contentType := variant.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		if variant.Header == "" {
+			content[contentType] = spec.MediaType{Schema: g.generateSchemaReference(route.Method, route.Path, variant.Kind)}
+			continue
+		}
+		dispatched[variant.Status][contentType] = append(dispatched[variant.Status][contentType], variant)
+		if _, seen := headerValues[variant.Header]; !seen {
+			headers = append(headers, variant.Header)
+		}
+		if !slices.Contains(headerValues[variant.Header], variant.Value) {
+			headerValues[variant.Header] = append(headerValues[variant.Header], variant.Value)
+		}
+	}
+
+	for _, status := range statuses {
+		content := contents[status]
+		for contentType, group := range dispatched[status] {
+			schema := spec.Schema{}
+			choices := make([]string, 0, len(group))
+			for _, variant := range group {
+				schema.OneOf = append(schema.OneOf, g.generateSchemaReference(route.Method, route.Path, variant.Kind))
+				choices = append(choices, variant.Header+": "+variant.Value)
+			}
+			schema.Description = "Selected by the request header: " + strings.Join(choices, ", ")
+			content[contentType] = spec.MediaType{Schema: schema}
+		}
+
+		if status == 0 {
+			requestBody := spec.RequestBody{Required: true}
+			if operation.RequestBody != nil {
+				requestBody = *operation.RequestBody
+			} else if required, exists := g.schemaRegistry.GetRequestRequired(route.Method, route.Path); exists {
+				requestBody.Required = required
+			}
+			requestBody.Content = content
+			operation.RequestBody = &requestBody
+			continue
+		}
+		code := strconv.Itoa(status)
+		response := operation.Responses[code]
+		if response.Description == "" {
+			response.Description = http.StatusText(status)
+		}
+		if response.Description == "" {
+			response.Description = "Response " + code
+		}
+		response.Content = content
+		operation.Responses[code] = response
+	}
+
+	// The headers the route dispatches on are request parameters, except the ones OpenAPI
+	// documents in other ways such as Accept through the media types
+	for _, header := range headers {
+		if hasParameter(operation.Parameters, header, "header") || ignoredHeaderParameter(header) {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, spec.Parameter{
+			Name:        header,
+			In:          "header",
+			Description: "Selects the request or response variant",
+			Schema:      spec.Schema{Type: "string", Enum: headerValues[header]},
+		})
+	}
+}
+
+// documentedContent returns a copy of the content documented for the request body (status 0)
+// or a response of an operation, empty when there is none
+func documentedContent(operation *spec.Operation, status int) map[string]spec.MediaType {
+	var documented map[string]spec.MediaType
+	if status == 0 {
+		if operation.RequestBody != nil {
+			documented = operation.RequestBody.Content
+		}
+	} else {
+		documented = operation.Responses[strconv.Itoa(status)].Content
+	}
+	if documented == nil {
+		return make(map[string]spec.MediaType)
+	}
+	return maps.Clone(documented)
+}
+
+// ignoredHeaderParameter reports whether OpenAPI ignores header parameters with a name
+func ignoredHeaderParameter(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Accept", "Content-Type", "Authorization":
+		return true
+	}
+	return false
+}
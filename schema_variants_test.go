@@ -0,0 +1,88 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type variantUserV1 struct {
+	Name string `json:"name"`
+}
+
+type variantUserV2 struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type variantCreateUserV2 struct {
+	FirstName string `json:"first_name"`
+}
+
+func TestRegisterResponseVariantByContentType(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id", HandlerName: "GetUser"},
+	})
+	generator.RegisterResponseVariant("GET", "/users/:id", 200, Variant{ContentType: "application/json"}, variantUserV1{})
+	generator.RegisterResponseVariant("GET", "/users/:id", 200, Variant{ContentType: "application/vnd.acme.v2+json"}, &variantUserV2{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	operation := openAPISpec.Paths["/users/{id}"].Get
+	if !assert.NotNil(t, operation) {
+		return
+	}
+
+	content := operation.Responses["200"].Content
+	assert.Len(t, content, 2)
+	assert.Equal(t, "#/components/schemas/variantUserV1", content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/variantUserV2", content["application/vnd.acme.v2+json"].Schema.Ref)
+	assert.Contains(t, resolveRef(t, openAPISpec, content["application/vnd.acme.v2+json"].Schema).Properties, "first_name")
+	assert.False(t, hasParameter(operation.Parameters, "Accept", "header"))
+}
+
+func TestRegisterVariantByHeader(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/users", HandlerName: "CreateUser"},
+	})
+	generator.RegisterRequestVariant("POST", "/users", Variant{Header: "Accept-Version", Value: "1"}, variantUserV1{})
+	generator.RegisterRequestVariant("POST", "/users", Variant{Header: "Accept-Version", Value: "2"}, variantCreateUserV2{})
+	generator.RegisterResponseVariant("POST", "/users", 201, Variant{Header: "Accept-Version", Value: "1"}, variantUserV1{})
+	generator.RegisterResponseVariant("POST", "/users", 201, Variant{Header: "Accept-Version", Value: "2"}, variantUserV2{})
+	// Registering the same variant again replaces it
+	generator.RegisterResponseVariant("POST", "/users", 201, Variant{Header: "Accept-Version", Value: "2"}, variantUserV2{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	operation := openAPISpec.Paths["/users"].Post
+	if !assert.NotNil(t, operation) || !assert.NotNil(t, operation.RequestBody) {
+		return
+	}
+
+	request := operation.RequestBody.Content["application/json"].Schema
+	if assert.Len(t, request.OneOf, 2) {
+		assert.Contains(t, resolveRef(t, openAPISpec, request.OneOf[0]).Properties, "name")
+		assert.Contains(t, resolveRef(t, openAPISpec, request.OneOf[1]).Properties, "first_name")
+	}
+	assert.Equal(t, "Selected by the request header: Accept-Version: 1, Accept-Version: 2", request.Description)
+	assert.True(t, operation.RequestBody.Required)
+
+	created, exists := operation.Responses["201"]
+	if assert.True(t, exists) {
+		assert.Equal(t, "Created", created.Description)
+		assert.Len(t, created.Content["application/json"].Schema.OneOf, 2)
+	}
+
+	var versions []spec.Parameter
+	for _, parameter := range operation.Parameters {
+		if parameter.Name == "Accept-Version" {
+			versions = append(versions, parameter)
+		}
+	}
+	if assert.Len(t, versions, 1) {
+		assert.Equal(t, "header", versions[0].In)
+		assert.Equal(t, []string{"1", "2"}, versions[0].Schema.Enum)
+	}
+}
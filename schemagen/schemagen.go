@@ -0,0 +1,520 @@
+// Package schemagen holds the rules that turn Go types and struct tags into OpenAPI schemas
+//
+// The runtime SchemaGenerator, both its reflection and AST paths, and the openapi-gen
+// CLI build schemas with these helpers, so a type is documented the same way whichever
+// path generated it.
+package schemagen
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// BasicType returns the schema of a predeclared Go type such as "string" or "uint8"
+func BasicType(name string) (spec.Schema, bool) {
+	switch name {
+	case "string":
+		return spec.Schema{Type: "string"}, true
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return spec.Schema{Type: "integer"}, true
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+		return spec.Schema{Type: "integer", Minimum: float64Ptr(0)}, true
+	case "float32", "float64":
+		return spec.Schema{Type: "number"}, true
+	case "bool":
+		return spec.Schema{Type: "boolean"}, true
+	}
+	return spec.Schema{}, false
+}
+
+// knownTypes are standard library types documented by how they serialize rather than by their fields
+var knownTypes = map[string]map[string]spec.Schema{
+	"time": {
+		"Time":     {Type: "string", Format: "date-time"},
+		"Duration": {Type: "integer", Description: "Duration in nanoseconds"},
+	},
+	"mime/multipart": {"FileHeader": {Type: "string", Format: "binary"}},
+	"net/url":        {"URL": {Type: "string", Format: "uri"}},
+	"net/mail":       {"Address": {Type: "string", Format: "email"}},
+	"net/http":       {"Cookie": {Type: "object", Description: "HTTP cookie"}},
+	"encoding/json": {
+		"RawMessage": {Type: "object", Description: "Raw JSON message"},
+		"Number":     {Type: "number"},
+	},
+	"io": {
+		"Reader":     {Type: "string", Format: "binary"},
+		"Writer":     {Type: "string", Format: "binary"},
+		"ReadWriter": {Type: "string", Format: "binary"},
+	},
+	"math/big": {
+		"Int":   {Type: "string", Description: "Big integer"},
+		"Float": {Type: "string", Description: "Big float"},
+	},
+}
+
+// KnownType returns the schema of a well-known standard library type, e.g. time.Time
+//
+// pkg is the import path of the type's package, or its package name when only source
+// code is available ("multipart" for mime/multipart).
+func KnownType(pkg, name string) (spec.Schema, bool) {
+	for importPath, types := range knownTypes {
+		if pkg == importPath || pkg == path.Base(importPath) {
+			schema, exists := types[name]
+			return schema, exists
+		}
+	}
+	return spec.Schema{}, false
+}
+
+// TimeFormatTag is the struct tag choosing how a time.Time field is documented, e.g.
+// `time_format:"unixmilli"`; Gin binds form fields by the same tag
+const TimeFormatTag = "time_format"
+
+// unixTimeUnits are the epoch time formats, with the unit of their integers
+var unixTimeUnits = map[string]string{
+	"unix":      "seconds",
+	"unixmilli": "milliseconds",
+	"unixmicro": "microseconds",
+	"unixnano":  "nanoseconds",
+}
+
+// exampleTime is formatted in custom layouts as the example of their values
+var exampleTime = time.Date(2024, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+// TimeSchema returns the schema of time.Time values serialized in format
+//
+// An empty format or "date-time" is an RFC 3339 string, as encoding/json writes times.
+// "unix", "unixmilli", "unixmicro" and "unixnano" are epoch integers in seconds,
+// milliseconds, microseconds and nanoseconds. Anything else is a Go time layout such as
+// "2006-01-02", documented as a string with an example in that layout.
+func TimeSchema(format string) spec.Schema {
+	if unit, epoch := unixTimeUnits[format]; epoch {
+		var example int64
+		switch unit {
+		case "seconds":
+			example = exampleTime.Unix()
+		case "milliseconds":
+			example = exampleTime.UnixMilli()
+		case "microseconds":
+			example = exampleTime.UnixMicro()
+		default:
+			example = exampleTime.UnixNano()
+		}
+		return spec.Schema{Type: "integer", Format: "int64", Description: "Unix time in " + unit, Example: example}
+	}
+	switch format {
+	case "", "date-time", time.RFC3339, time.RFC3339Nano:
+		return spec.Schema{Type: "string", Format: "date-time"}
+	case time.DateOnly:
+		return spec.Schema{Type: "string", Format: "date"}
+	case time.TimeOnly:
+		return spec.Schema{Type: "string", Format: "time"}
+	}
+	return spec.Schema{Type: "string", Description: "Time in the Go layout " + format, Example: exampleTime.Format(format)}
+}
+
+// ValidTimeFormat reports whether format is a format of TimeSchema, a layout must contain at
+// least one element such as 2006 or 15
+func ValidTimeFormat(format string) bool {
+	if _, epoch := unixTimeUnits[format]; epoch || format == "" || format == "date-time" {
+		return true
+	}
+	return exampleTime.Format(format) != format
+}
+
+// ArrayOf returns the schema of a slice or array with the given items
+func ArrayOf(items spec.Schema) spec.Schema {
+	return spec.Schema{Type: "array", Items: &items}
+}
+
+// ArrayOfLength returns the schema of a fixed-size array, its length bounds the number of items
+func ArrayOfLength(items spec.Schema, length int) spec.Schema {
+	schema := ArrayOf(items)
+	schema.MinItems = &length
+	schema.MaxItems = &length
+	return schema
+}
+
+// Bytes returns the schema of a byte slice, which encoding/json sends as a base64 string
+func Bytes() spec.Schema {
+	return spec.Schema{Type: "string", Format: "byte"}
+}
+
+// IsByteType reports whether a Go type name is byte or uint8
+func IsByteType(name string) bool {
+	return name == "byte" || name == "uint8"
+}
+
+// ArrayLength returns the declared length of an array type, false for slices and non-literal lengths
+func ArrayLength(arrayType *ast.ArrayType) (int, bool) {
+	lit, ok := arrayType.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	length, err := strconv.ParseInt(lit.Value, 0, 0)
+	if err != nil || length < 0 {
+		return 0, false
+	}
+	return int(length), true
+}
+
+// MapOf returns the schema of a map with the given values
+func MapOf(values spec.Schema) spec.Schema {
+	return spec.Schema{Type: "object", AdditionalProperties: &values}
+}
+
+// MapWithKeys returns the schema of a map whose keys are of the named Go type
+//
+// JSON object keys are always strings, so the key type is documented in the x-key-type
+// extension: "integer" for integer keys, or the type name for named key types such as
+// `type Currency string`. Plain string keys need no extension.
+func MapWithKeys(keyType string, values spec.Schema) spec.Schema {
+	schema := MapOf(values)
+	if keyType == "string" {
+		return schema
+	}
+	if basic, ok := BasicType(keyType); ok {
+		schema.XKeyType = basic.Type
+		return schema
+	}
+	schema.XKeyType = keyType[strings.LastIndex(keyType, ".")+1:]
+	return schema
+}
+
+// FieldTag returns the struct tag of an AST field, empty when the field has none
+func FieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// FieldDoc returns the doc comment of an AST field, or its trailing line comment, as one line
+//
+// The field must come from a file parsed with parser.ParseComments.
+func FieldDoc(field *ast.Field) string {
+	group := field.Doc
+	if group == nil {
+		group = field.Comment
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// TypeDoc returns the doc comment of a type declaration
+//
+// A lone declaration carries the comment on its GenDecl, a grouped one on the TypeSpec.
+// decl may be nil when only the TypeSpec is known.
+func TypeDoc(decl *ast.GenDecl, typeSpec *ast.TypeSpec) string {
+	group := typeSpec.Doc
+	if group == nil && decl != nil && len(decl.Specs) == 1 {
+		group = decl.Doc
+	}
+	return strings.TrimSpace(group.Text())
+}
+
+// ApplyTypeDoc describes the schema of a named Go type with its name and doc comment
+//
+// The type name becomes the title, which Swagger UI heads the model with instead of an
+// anonymous object, and the doc comment the description.
+func ApplyTypeDoc(schema *spec.Schema, typeName, typeDoc string) {
+	schema.Title = typeName
+	if typeDoc != "" {
+		schema.Description = typeDoc
+	}
+}
+
+// FieldName returns the property name of a struct field, empty when the field is not serialized
+//
+// The json tag names the property, then the form tag for form-bound fields, otherwise the
+// Go field name in snake_case. form:"-" only keeps a field out of form binding, encoding/json
+// still serializes it.
+func FieldName(goName string, tag reflect.StructTag) string {
+	jsonTag := tag.Get("json")
+	if jsonTag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(jsonTag, ","); name != "" {
+		return name
+	}
+	if name, _, _ := strings.Cut(tag.Get("form"), ","); name != "" && name != "-" {
+		return name
+	}
+	return ToSnakeCase(goName)
+}
+
+// ParameterName returns the query or form parameter a struct field is bound from, empty when it is not bound
+//
+// Hertz binds query strings by the query tag and gin's ShouldBindQuery by the form tag, a
+// field with neither is bound by its property name.
+func ParameterName(goName string, tag reflect.StructTag) string {
+	for _, key := range []string{"query", "form"} {
+		value := tag.Get(key)
+		if value == "-" {
+			return ""
+		}
+		if name, _, _ := strings.Cut(value, ","); name != "" {
+			return name
+		}
+	}
+	return FieldName(goName, tag)
+}
+
+// BindName returns the spec.Schema BindName of a struct field with the given property name
+func BindName(propertyName, goName string, tag reflect.StructTag) string {
+	switch name := ParameterName(goName, tag); name {
+	case propertyName:
+		return ""
+	case "":
+		return "-"
+	default:
+		return name
+	}
+}
+
+// DefaultRequiredTags are the tags read for the required rule when RequiredRules.Tags is empty:
+// validate for go-playground/validator and binding for Gin
+var DefaultRequiredTags = []string{"validate", "binding"}
+
+// RequiredRules decide which struct fields are required properties
+//
+// A field is required when one of Tags validates it with the "required" rule, e.g.
+// `validate:"required"`; omitempty only drops zero values when encoding and has no say.
+// An openapi tag settles a field either way: `openapi:"required"` or `openapi:"optional"`.
+// The zero value reads DefaultRequiredTags.
+type RequiredRules struct {
+	// Tags holding validation rules, e.g. {"validate"} to ignore Gin's binding tag
+	Tags []string
+	// PointersOptional keeps pointer fields optional unless the openapi tag requires them,
+	// for APIs where nil means absent whatever the validation says
+	PointersOptional bool
+}
+
+// IsRequired reports whether a struct field with a tag is required, pointer tells whether its type is a pointer
+func (r RequiredRules) IsRequired(tag reflect.StructTag, pointer bool) bool {
+	for option := range strings.SplitSeq(tag.Get("openapi"), ",") {
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case "required":
+			return true
+		case "optional":
+			return false
+		}
+	}
+	if pointer && r.PointersOptional {
+		return false
+	}
+
+	tags := r.Tags
+	if len(tags) == 0 {
+		tags = DefaultRequiredTags
+	}
+	for _, key := range tags {
+		if hasRequiredRule(tag.Get(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRequiredRule reports whether validation rules have the required rule, rules after dive
+// apply to the elements of a slice or map rather than to the field
+func hasRequiredRule(rules string) bool {
+	for rule := range strings.SplitSeq(rules, ",") {
+		switch strings.TrimSpace(rule) {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+// ApplyFieldTags applies the validate, example, description, openapi and xml tags of a field to
+// its schema, and the string option of its json tag
+func ApplyFieldTags(schema *spec.Schema, tag reflect.StructTag) {
+	if validateTag := tag.Get("validate"); validateTag != "" {
+		ApplyValidation(schema, validateTag)
+	}
+
+	if example := tag.Get("example"); example != "" {
+		schema.Example = example
+	}
+
+	if description := tag.Get("description"); description != "" {
+		schema.Description = description
+	}
+
+	// openapi:"readOnly" fields only appear in responses, openapi:"writeOnly" ones only in requests
+	for option := range strings.SplitSeq(tag.Get("openapi"), ",") {
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case "readonly":
+			schema.ReadOnly = true
+		case "writeonly":
+			schema.WriteOnly = true
+		}
+	}
+
+	if hasJSONOption(tag, "string") {
+		quoteScalar(schema)
+	}
+	if xmlTag, tagged := tag.Lookup("xml"); tagged {
+		applyXMLTag(schema, xmlTag)
+	}
+}
+
+// XMLName returns the xml metadata of a struct from the tag of its XMLName field, nil when
+// the tag names no element, e.g. `xml:"urn:acme user"` is the element user in urn:acme
+func XMLName(tag reflect.StructTag) *spec.XML {
+	name, _, _ := strings.Cut(tag.Get("xml"), ",")
+	if name == "" || name == "-" {
+		return nil
+	}
+	xml := xmlElement(name)
+	return &xml
+}
+
+// xmlElement splits the name of an xml tag into its namespace and element name
+func xmlElement(name string) spec.XML {
+	if namespace, local, spaced := strings.Cut(name, " "); spaced {
+		return spec.XML{Namespace: namespace, Name: local}
+	}
+	return spec.XML{Name: name}
+}
+
+// applyXMLTag documents how encoding/xml renders a field from its xml tag
+//
+// A name sets the element, or the attribute for the attr option, and may be preceded by a
+// namespace. Slices render one element per item, named after the field, unless the name
+// nests them in a parent element, e.g. `xml:"tags>tag"` is a wrapped array of tag elements.
+// Items that are references keep their own name, OpenAPI 3.0 ignores keywords next to $ref.
+func applyXMLTag(schema *spec.Schema, xmlTag string) {
+	name, options, _ := strings.Cut(xmlTag, ",")
+	if name == "-" {
+		return
+	}
+	xml := xmlElement(name)
+	xml.Attribute = slices.Contains(strings.Split(options, ","), "attr")
+
+	if schema.Type == "array" && schema.Items != nil && !xml.Attribute {
+		elements := strings.Split(xml.Name, ">")
+		itemName := elements[len(elements)-1]
+		if len(elements) > 1 {
+			xml.Name, xml.Wrapped = elements[len(elements)-2], true
+		} else {
+			xml.Name = "" // Unwrapped arrays take their element name from the items
+		}
+		if items := *schema.Items; items.Ref == "" && itemName != "" {
+			items.XML = &spec.XML{Name: itemName}
+			schema.Items = &items
+		}
+	} else if elements := strings.Split(xml.Name, ">"); len(elements) > 1 {
+		xml.Name = elements[len(elements)-1] // Parent elements cannot be documented
+	}
+
+	if xml != (spec.XML{}) {
+		schema.XML = &xml
+	}
+}
+
+// hasJSONOption reports whether the json tag of a field has an option, e.g. "omitempty"
+func hasJSONOption(tag reflect.StructTag, option string) bool {
+	_, options, _ := strings.Cut(tag.Get("json"), ",")
+	return slices.Contains(strings.Split(options, ","), option)
+}
+
+// quoteScalar documents an integer, number or boolean as the JSON string encoding/json
+// writes for fields tagged json:",string", e.g. "42"
+//
+// The string keeps to the value's syntax through a pattern, or an enum for booleans.
+// Numeric bounds do not apply to strings and are dropped. Other schemas are left as they
+// are, encoding/json ignores the option for them.
+func quoteScalar(schema *spec.Schema) {
+	switch schema.Type {
+	case "integer":
+		schema.Pattern = `^-?[0-9]+$`
+		if schema.Minimum != nil && *schema.Minimum >= 0 {
+			schema.Pattern = `^[0-9]+$`
+		}
+	case "number":
+		schema.Pattern = `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`
+	case "boolean":
+		if len(schema.Enum) == 0 {
+			schema.Enum = []string{"true", "false"}
+		}
+	default:
+		return
+	}
+	schema.Type, schema.Format = "string", ""
+	schema.Minimum, schema.ExclusiveMinimum = nil, false
+	schema.Maximum, schema.ExclusiveMaximum = nil, false
+	schema.MultipleOf = nil
+}
+
+// ApplyValidation applies validate tag rules to a schema
+//
+// min and max bound the length of strings and the value of numbers, email sets the
+// string format. Other rules have no OpenAPI equivalent and are ignored.
+func ApplyValidation(schema *spec.Schema, validateTag string) {
+	for rule := range strings.SplitSeq(validateTag, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "min", "max":
+			switch schema.Type {
+			case "string":
+				length, err := strconv.Atoi(value)
+				if err != nil || length < 0 {
+					continue
+				}
+				if name == "min" {
+					schema.MinLength = &length
+				} else {
+					schema.MaxLength = &length
+				}
+			case "integer", "number":
+				bound, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					continue
+				}
+				if name == "min" {
+					schema.Minimum = &bound
+				} else {
+					schema.Maximum = &bound
+				}
+			}
+		case "email":
+			if schema.Type == "string" {
+				schema.Format = "email"
+			}
+		}
+	}
+}
+
+// ToSnakeCase converts PascalCase to snake_case
+func ToSnakeCase(s string) string {
+	var result strings.Builder
+	result.Grow(len(s) + 4)
+	for i, r := range s {
+		if i > 0 && ('A' <= r && r <= 'Z') {
+			result.WriteRune('_')
+		}
+		result.WriteRune(r)
+	}
+	return strings.ToLower(result.String())
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,298 @@
+package schemagen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+func TestBasicType(t *testing.T) {
+	schema, ok := BasicType("int64")
+	assert.True(t, ok)
+	assert.Equal(t, "integer", schema.Type)
+	assert.Nil(t, schema.Minimum)
+
+	schema, ok = BasicType("byte")
+	assert.True(t, ok)
+	if assert.NotNil(t, schema.Minimum) {
+		assert.Equal(t, 0.0, *schema.Minimum)
+	}
+
+	_, ok = BasicType("LoginRequest")
+	assert.False(t, ok)
+}
+
+func TestKnownType(t *testing.T) {
+	// Import paths at runtime and package names in source resolve to the same schema
+	byPath, ok := KnownType("mime/multipart", "FileHeader")
+	assert.True(t, ok)
+	byName, ok := KnownType("multipart", "FileHeader")
+	assert.True(t, ok)
+	assert.Equal(t, byPath, byName)
+	assert.Equal(t, "binary", byName.Format)
+
+	schema, ok := KnownType("time", "Time")
+	assert.True(t, ok)
+	assert.Equal(t, spec.Schema{Type: "string", Format: "date-time"}, schema)
+
+	// A user package that happens to be called "time" is not the standard library
+	_, ok = KnownType("example.com/app/time", "Time")
+	assert.False(t, ok)
+	_, ok = KnownType("time", "Ticker")
+	assert.False(t, ok)
+}
+
+func TestTimeSchema(t *testing.T) {
+	assert.Equal(t, spec.Schema{Type: "string", Format: "date-time"}, TimeSchema(""))
+	assert.Equal(t, spec.Schema{Type: "string", Format: "date-time"}, TimeSchema("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, spec.Schema{Type: "string", Format: "date"}, TimeSchema("2006-01-02"))
+	assert.Equal(t, spec.Schema{Type: "integer", Format: "int64", Description: "Unix time in seconds", Example: int64(1704207845)}, TimeSchema("unix"))
+	assert.Equal(t, spec.Schema{Type: "integer", Format: "int64", Description: "Unix time in milliseconds", Example: int64(1704207845000)}, TimeSchema("unixmilli"))
+
+	custom := TimeSchema("02/01/2006 15:04")
+	assert.Equal(t, "string", custom.Type)
+	assert.Equal(t, "02/01/2024 15:04", custom.Example)
+
+	assert.True(t, ValidTimeFormat("unixnano"))
+	assert.True(t, ValidTimeFormat("Jan 2, 2006"))
+	assert.False(t, ValidTimeFormat("epoch"))
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		tag      reflect.StructTag
+		expected string
+	}{
+		{`json:"email,omitempty"`, "email"},
+		{`form:"page"`, "page"},
+		{`json:",omitempty" form:"page"`, "page"},
+		{`json:"-" form:"ignored"`, ""},
+		{`form:"-"`, "user_name"},
+		{`validate:"required"`, "user_name"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FieldName("UserName", tt.tag), string(tt.tag))
+	}
+}
+
+func TestParameterName(t *testing.T) {
+	assert.Equal(t, "p", ParameterName("Page", `json:"page" form:"p"`))
+	assert.Equal(t, "state", ParameterName("Status", `json:"status" query:"state" form:"s"`))
+	assert.Equal(t, "page", ParameterName("Page", `json:"page"`))
+	assert.Empty(t, ParameterName("Page", `json:"page" form:"-"`))
+
+	assert.Empty(t, BindName("page", "Page", `json:"page" form:"page"`))
+	assert.Equal(t, "p", BindName("page", "Page", `json:"page" form:"p"`))
+	assert.Equal(t, "-", BindName("page", "Page", `json:"page" form:"-"`))
+}
+
+func TestIsRequired(t *testing.T) {
+	var rules RequiredRules
+	assert.True(t, rules.IsRequired(`validate:"required,email"`, false))
+	assert.True(t, rules.IsRequired(`validate:"min=1, required"`, false))
+	assert.False(t, rules.IsRequired(`validate:"required_if=Kind card"`, false))
+	assert.False(t, rules.IsRequired(`json:"name"`, false), "omitempty is not what makes a field optional")
+	assert.True(t, rules.IsRequired(`json:"name,omitempty" binding:"required"`, false))
+	assert.False(t, rules.IsRequired(`validate:"dive,required"`, false), "dive rules apply to the elements")
+	assert.True(t, rules.IsRequired(`json:"name" openapi:"readOnly,required"`, false))
+	assert.False(t, rules.IsRequired(`validate:"required" openapi:"optional"`, false))
+	assert.True(t, rules.IsRequired(`validate:"required"`, true))
+
+	rules = RequiredRules{Tags: []string{"validate"}, PointersOptional: true}
+	assert.False(t, rules.IsRequired(`binding:"required"`, false))
+	assert.False(t, rules.IsRequired(`validate:"required"`, true))
+	assert.True(t, rules.IsRequired(`validate:"required"`, false))
+	assert.True(t, rules.IsRequired(`openapi:"required"`, true))
+}
+
+func TestApplyFieldTags(t *testing.T) {
+	name := spec.Schema{Type: "string"}
+	ApplyFieldTags(&name, `validate:"required,min=2,max=50" example:"Jane" description:"Display name"`)
+	assert.Equal(t, 2, *name.MinLength)
+	assert.Equal(t, 50, *name.MaxLength)
+	assert.Equal(t, "Jane", name.Example)
+	assert.Equal(t, "Display name", name.Description)
+
+	age := spec.Schema{Type: "integer"}
+	ApplyFieldTags(&age, `validate:"min=18,max=120,email"`)
+	assert.Equal(t, 18.0, *age.Minimum)
+	assert.Equal(t, 120.0, *age.Maximum)
+	assert.Empty(t, age.Format)
+
+	email := spec.Schema{Type: "string"}
+	ApplyFieldTags(&email, `validate:"email,min=abc"`)
+	assert.Equal(t, "email", email.Format)
+	assert.Nil(t, email.MinLength)
+
+	id := spec.Schema{Type: "integer"}
+	ApplyFieldTags(&id, `openapi:"readOnly"`)
+	assert.True(t, id.ReadOnly)
+	assert.False(t, id.WriteOnly)
+
+	quoted := spec.Schema{Type: "integer"}
+	ApplyFieldTags(&quoted, `json:"id,string,omitempty" validate:"min=1,max=99"`)
+	assert.Equal(t, "string", quoted.Type)
+	assert.Equal(t, `^[0-9]+$`, quoted.Pattern)
+	assert.Nil(t, quoted.Minimum)
+	assert.Nil(t, quoted.Maximum)
+
+	price := spec.Schema{Type: "number"}
+	ApplyFieldTags(&price, `json:"price,string"`)
+	assert.Equal(t, "string", price.Type)
+	assert.Regexp(t, price.Pattern, "-1.5e3")
+
+	flag := spec.Schema{Type: "boolean"}
+	ApplyFieldTags(&flag, `json:",string"`)
+	assert.Equal(t, "string", flag.Type)
+	assert.Equal(t, []string{"true", "false"}, flag.Enum)
+
+	tags := spec.Schema{Type: "array", Items: &spec.Schema{Type: "integer"}}
+	ApplyFieldTags(&tags, `json:"tags,string"`)
+	assert.Equal(t, "array", tags.Type)
+	assert.Equal(t, "integer", tags.Items.Type)
+
+	named := spec.Schema{Type: "integer"}
+	ApplyFieldTags(&named, `json:"string"`)
+	assert.Equal(t, "integer", named.Type)
+}
+
+func TestFieldTag(t *testing.T) {
+	expr, err := parser.ParseExpr("struct{ Email string `json:\"email\" validate:\"required,email\"`; Name string }")
+	if !assert.NoError(t, err) {
+		return
+	}
+	fields := expr.(*ast.StructType).Fields.List
+
+	assert.Equal(t, "required,email", FieldTag(fields[0]).Get("validate"))
+	assert.Equal(t, reflect.StructTag(""), FieldTag(fields[1]))
+}
+
+func TestFieldDoc(t *testing.T) {
+	source := "package dto\n\ntype LoginRequest struct {\n" +
+		"\t// Email is the user's\n\t// login email\n\tEmail string `json:\"email\"`\n" +
+		"\tPassword string `json:\"password\"` // Plain text, sent over TLS only\n" +
+		"\tRemember bool `json:\"remember\"`\n}\n"
+	file, err := parser.ParseFile(token.NewFileSet(), "dto.go", source, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fields := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+
+	assert.Equal(t, "Email is the user's login email", FieldDoc(fields[0]))
+	assert.Equal(t, "Plain text, sent over TLS only", FieldDoc(fields[1]))
+	assert.Empty(t, FieldDoc(fields[2]))
+}
+
+func TestTypeDoc(t *testing.T) {
+	source := "package dto\n\n" +
+		"// LoginRequest is the payload for POST /login. It carries the user's credentials.\n" +
+		"type LoginRequest struct{}\n\n" +
+		"// Grouped declarations\ntype (\n\t// User is an account\n\tUser struct{}\n\tAdmin struct{}\n)\n"
+	file, err := parser.ParseFile(token.NewFileSet(), "dto.go", source, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return
+	}
+	lone := file.Decls[0].(*ast.GenDecl)
+	grouped := file.Decls[1].(*ast.GenDecl)
+
+	doc := TypeDoc(lone, lone.Specs[0].(*ast.TypeSpec))
+	assert.Equal(t, "LoginRequest is the payload for POST /login. It carries the user's credentials.", doc)
+	assert.Equal(t, "User is an account", TypeDoc(grouped, grouped.Specs[0].(*ast.TypeSpec)))
+	// The group comment does not describe each of its types
+	assert.Empty(t, TypeDoc(grouped, grouped.Specs[1].(*ast.TypeSpec)))
+
+	schema := spec.Schema{Type: "object"}
+	ApplyTypeDoc(&schema, "LoginRequest", doc)
+	assert.Equal(t, "LoginRequest", schema.Title)
+	assert.Equal(t, doc, schema.Description)
+
+	undocumented := spec.Schema{Type: "object"}
+	ApplyTypeDoc(&undocumented, "Admin", "")
+	assert.Equal(t, spec.Schema{Type: "object", Title: "Admin"}, undocumented)
+}
+
+func TestMapWithKeys(t *testing.T) {
+	values := spec.Schema{Type: "number"}
+
+	plain := MapWithKeys("string", values)
+	assert.Empty(t, plain.XKeyType)
+	assert.Equal(t, &values, plain.AdditionalProperties)
+
+	assert.Equal(t, "integer", MapWithKeys("uint64", values).XKeyType)
+	assert.Equal(t, "Currency", MapWithKeys("Currency", values).XKeyType)
+	assert.Equal(t, "Currency", MapWithKeys("money.Currency", values).XKeyType)
+	assert.Empty(t, MapWithKeys("", values).XKeyType)
+}
+
+func TestArrayLength(t *testing.T) {
+	tests := []struct {
+		source   string
+		length   int
+		expected bool
+	}{
+		{"[4]byte", 4, true},
+		{"[0x10]int", 16, true},
+		{"[]string", 0, false},
+		{"[Size]int", 0, false},
+	}
+
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.source)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		length, fixed := ArrayLength(expr.(*ast.ArrayType))
+		assert.Equal(t, tt.expected, fixed, tt.source)
+		assert.Equal(t, tt.length, length, tt.source)
+	}
+
+	schema := ArrayOfLength(spec.Schema{Type: "number"}, 2)
+	assert.Equal(t, 2, *schema.MinItems)
+	assert.Equal(t, 2, *schema.MaxItems)
+	assert.Equal(t, "number", schema.Items.Type)
+}
+
+func TestApplyFieldTagsXML(t *testing.T) {
+	id := spec.Schema{Type: "string"}
+	ApplyFieldTags(&id, `json:"id" xml:"id,attr"`)
+	assert.Equal(t, &spec.XML{Name: "id", Attribute: true}, id.XML)
+
+	name := spec.Schema{Type: "string"}
+	ApplyFieldTags(&name, `xml:"urn:acme full-name"`)
+	assert.Equal(t, &spec.XML{Namespace: "urn:acme", Name: "full-name"}, name.XML)
+
+	wrapped := spec.Schema{Type: "array", Items: &spec.Schema{Type: "string"}}
+	ApplyFieldTags(&wrapped, `xml:"tags>tag"`)
+	assert.Equal(t, &spec.XML{Name: "tags", Wrapped: true}, wrapped.XML)
+	assert.Equal(t, &spec.XML{Name: "tag"}, wrapped.Items.XML)
+
+	repeated := spec.Schema{Type: "array", Items: &spec.Schema{Ref: "#/components/schemas/Line"}}
+	ApplyFieldTags(&repeated, `xml:"line"`)
+	assert.Nil(t, repeated.XML, "unwrapped arrays are named by their items")
+	assert.Nil(t, repeated.Items.XML, "keywords next to $ref are ignored")
+
+	for _, tag := range []reflect.StructTag{`xml:"-"`, `xml:",chardata"`, `json:"body"`} {
+		body := spec.Schema{Type: "string"}
+		ApplyFieldTags(&body, tag)
+		assert.Nil(t, body.XML, tag)
+	}
+
+	assert.Equal(t, &spec.XML{Namespace: "urn:acme", Name: "user"}, XMLName(`xml:"urn:acme user"`))
+	assert.Nil(t, XMLName(`json:"-"`))
+}
+
+func BenchmarkApplyFieldTags(b *testing.B) {
+	tag := reflect.StructTag(`json:"email" validate:"required,email,min=3,max=254" example:"jane@example.com" openapi:"writeOnly"`)
+	b.ReportAllocs()
+	for b.Loop() {
+		schema := spec.Schema{Type: "string"}
+		ApplyFieldTags(&schema, tag)
+		RequiredRules{}.IsRequired(tag, false)
+	}
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// SortOrder is the order tags, paths or operations are listed in the spec, and so in the Swagger UI sidebar
+type SortOrder string
+
+const (
+	// SortAlphabetical lists tags and paths by name, and operations by method
+	SortAlphabetical SortOrder = "alphabetical"
+	// SortDeclaration lists them in the order their routes were discovered: source order with
+	// WithStaticRoutes and the order of AddRoute calls. Gin and Hertz list their routes by
+	// method, then by path prefix.
+	SortDeclaration SortOrder = "declaration"
+)
+
+// WithTagOrder chooses the order of the tags of the spec, SortAlphabetical by default
+//
+// Swagger UI lists the tags of the sidebar in this order. In declaration order a tag comes
+// with the first route that uses it, tags no route uses, such as those of merged fragments,
+// follow.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithTagOrder(openapi.SortDeclaration),
+//	)
+func WithTagOrder(order SortOrder) Option {
+	return func(opts *Options) {
+		opts.tagOrder = validSortOrder(opts, "tag", order)
+	}
+}
+
+// WithPathOrder chooses the order of the paths of the spec, SortAlphabetical by default
+//
+// In declaration order a path comes with its first route, paths no route declares, such as
+// those of merged fragments, follow alphabetically.
+func WithPathOrder(order SortOrder) Option {
+	return func(opts *Options) {
+		opts.pathOrder = validSortOrder(opts, "path", order)
+	}
+}
+
+// WithOperationOrder chooses the order of the operations of each path
+//
+// By default operations are listed in the order of the OpenAPI path item fields: get, put,
+// post, delete, options, head, patch and trace.
+func WithOperationOrder(order SortOrder) Option {
+	return func(opts *Options) {
+		opts.operationOrder = validSortOrder(opts, "operation", order)
+	}
+}
+
+// validSortOrder returns a known sort order, an unknown one is a conflict of the options
+func validSortOrder(opts *Options, what string, order SortOrder) SortOrder {
+	switch order {
+	case SortAlphabetical, SortDeclaration:
+		return order
+	default:
+		opts.conflicts = append(opts.conflicts, fmt.Errorf("unknown %s order %q", what, order))
+		return ""
+	}
+}
+
+// applySortOrders orders the tags, paths and operations of the spec as configured
+func (g *Generator) applySortOrders(openAPISpec *spec.OpenAPISpec) {
+	if g.tagOrder == SortAlphabetical {
+		// Merged fragments may have added tags after the generated ones
+		slices.SortStableFunc(openAPISpec.Tags, func(a, b spec.Tag) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	if g.tagOrder != SortDeclaration && g.pathOrder != SortDeclaration && g.operationOrder == "" {
+		return
+	}
+
+	// The documented operations in declaration order
+	type declaredOperation struct {
+		path, method string
+		operation    *spec.Operation
+	}
+	var declared []declaredOperation
+	routes := slices.SortedFunc(maps.Values(g.routes), func(a, b analyzedRoute) int {
+		return cmp.Compare(g.routeOrder[routeKey(a.route.Method, a.route.Path)], g.routeOrder[routeKey(b.route.Method, b.route.Path)])
+	})
+	for _, analyzed := range routes {
+		path := g.specPath(analyzed.route.Path)
+		pathItem, exists := openAPISpec.Paths[path]
+		if !exists {
+			continue
+		}
+		if operation := operationForMethod(&pathItem, analyzed.route.Method); operation != nil {
+			declared = append(declared, declaredOperation{path: path, method: strings.ToLower(analyzed.route.Method), operation: operation})
+		}
+	}
+
+	if g.tagOrder == SortDeclaration {
+		position := make(map[string]int)
+		for _, named := range declared {
+			for _, tag := range named.operation.Tags {
+				if _, seen := position[tag]; !seen {
+					position[tag] = len(position)
+				}
+			}
+		}
+		// Tags no route declares keep their order, after the declared ones
+		slices.SortStableFunc(openAPISpec.Tags, func(a, b spec.Tag) int {
+			positionA, declaredA := position[a.Name]
+			positionB, declaredB := position[b.Name]
+			switch {
+			case declaredA && declaredB:
+				return cmp.Compare(positionA, positionB)
+			case declaredA:
+				return -1
+			case declaredB:
+				return 1
+			}
+			return 0
+		})
+	}
+
+	if g.pathOrder == SortDeclaration {
+		openAPISpec.PathOrder = make([]string, 0, len(openAPISpec.Paths))
+		seen := make(map[string]bool, len(openAPISpec.Paths))
+		for _, named := range declared {
+			if !seen[named.path] {
+				seen[named.path] = true
+				openAPISpec.PathOrder = append(openAPISpec.PathOrder, named.path)
+			}
+		}
+	}
+
+	switch g.operationOrder {
+	case SortAlphabetical:
+		for path, pathItem := range openAPISpec.Paths {
+			pathItem.OperationOrder = []string{"delete", "get", "head", "options", "patch", "post", "put", "trace"}
+			openAPISpec.Paths[path] = pathItem
+		}
+	case SortDeclaration:
+		for _, named := range declared {
+			pathItem := openAPISpec.Paths[named.path]
+			pathItem.OperationOrder = append(pathItem.OperationOrder, named.method)
+			openAPISpec.Paths[named.path] = pathItem
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortOrderRoutes are declared out of alphabetical order
+var sortOrderRoutes = []spec.RouteInfo{
+	{Method: "POST", Path: "/users"},
+	{Method: "GET", Path: "/users"},
+	{Method: "GET", Path: "/orders"},
+	{Method: "DELETE", Path: "/accounts/:id"},
+}
+
+// keyOffsets returns the offsets of keys in a JSON document
+func keyOffsets(t *testing.T, document []byte, keys ...string) []int {
+	t.Helper()
+	offsets := make([]int, len(keys))
+	for i, key := range keys {
+		offsets[i] = strings.Index(string(document), key)
+		require.GreaterOrEqual(t, offsets[i], 0, "missing %s", key)
+	}
+	return offsets
+}
+
+func TestSortOrderDefault(t *testing.T) {
+	generator := newTestGenerator(t, sortOrderRoutes)
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+	document, err := json.Marshal(openAPISpec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts", "orders", "users"}, tagNames(openAPISpec))
+	assert.IsIncreasing(t, keyOffsets(t, document, `"/accounts/{id}":`, `"/orders":`, `"/users":`))
+	assert.IsIncreasing(t, keyOffsets(t, document, `"operationId":"GetUsers"`, `"operationId":"PostUsers"`))
+}
+
+func TestSortOrderDeclaration(t *testing.T) {
+	generator := newTestGenerator(t, sortOrderRoutes,
+		WithTagOrder(SortDeclaration), WithPathOrder(SortDeclaration), WithOperationOrder(SortDeclaration))
+	require.NoError(t, generator.MergeSpec(&spec.OpenAPISpec{Tags: []spec.Tag{{Name: "admin"}}}))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+	document, err := json.Marshal(openAPISpec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"users", "orders", "accounts", "admin"}, tagNames(openAPISpec))
+	assert.IsIncreasing(t, keyOffsets(t, document, `"/users":`, `"/orders":`, `"/accounts/{id}":`))
+	assert.IsIncreasing(t, keyOffsets(t, document, `"operationId":"PostUsers"`, `"operationId":"GetUsers"`))
+
+	// The spec reads back the same
+	var decoded spec.OpenAPISpec
+	require.NoError(t, json.Unmarshal(document, &decoded))
+	assert.Len(t, decoded.Paths, 3)
+	assert.NotNil(t, decoded.Paths["/users"].Post)
+}
+
+func TestSortOrderAlphabeticalOperations(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "DELETE", Path: "/users/:id"},
+	}, WithOperationOrder(SortAlphabetical))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+	document, err := json.Marshal(openAPISpec)
+	require.NoError(t, err)
+
+	assert.IsIncreasing(t, keyOffsets(t, document, `"delete":`, `"get":`))
+}
+
+func TestWithTagOrderUnknown(t *testing.T) {
+	options := processOptions(WithConfig(NewConfig()), WithTagOrder("random"))
+	assert.ErrorContains(t, options.validate(), `unknown tag order "random"`)
+}
+
+// tagNames returns the names of the tags of a spec, in order
+func tagNames(openAPISpec *spec.OpenAPISpec) []string {
+	names := make([]string, 0, len(openAPISpec.Tags))
+	for _, tag := range openAPISpec.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+package openapi
+
+// WithVendorSources also looks up handler packages in the vendor/ directory of the module
+//
+// Projects built with go mod vendor keep their dependencies under vendor/, handlers from
+// those modules are then analyzed from the vendored copy instead of the module cache.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithVendorSources(true),
+//	)
+func WithVendorSources(enabled bool) Option {
+	return func(opts *Options) {
+		opts.vendorSources = enabled
+	}
+}
+
+// WithBuildTags sets the build tags handler source files are matched against
+//
+// Files the build would not compile, guarded by //go:build lines or _GOOS/_GOARCH file
+// suffixes, are skipped when looking for handler sources. GOOS and GOARCH are those of the
+// running binary. Pass the tags the service was built with, e.g. go build -tags enterprise.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithBuildTags("enterprise"),
+//	)
+func WithBuildTags(tags ...string) Option {
+	return func(opts *Options) {
+		opts.buildTags = append(opts.buildTags, tags...)
+	}
+}
+
+// WithSourceDirs sets the directories handler sources are searched from
+//
+// By default sources are looked up from the working directory, its internal/, pkg/ and cmd/
+// directories and the modules around it. Services with other layouts, e.g. apps/ or
+// services/ in a monorepo, or started from outside their repository, list their source
+// directories instead. They are searched at any depth, relative directories are resolved
+// against the working directory.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithSourceDirs("apps/billing", "libs/contracts"),
+//	)
+func WithSourceDirs(dirs ...string) Option {
+	return func(opts *Options) {
+		opts.sourceDirs = append(opts.sourceDirs, dirs...)
+	}
+}
+
+// WithSourceExclude sets glob patterns of source files and directories that are never analyzed
+//
+// Patterns use path.Match syntax and match any trailing part of a path: "*_gen.go" skips
+// generated files anywhere, "internal/mocks" skips that directory and everything in it.
+// Large generated trees are then not walked when looking for sources.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithSourceExclude("*.pb.go", "gen", "internal/mocks"),
+//	)
+func WithSourceExclude(patterns ...string) Option {
+	return func(opts *Options) {
+		opts.sourceExclude = append(opts.sourceExclude, patterns...)
+	}
+}
+
+// WithStaticRoutes reads the routes from the Go source files under dir
+//
+// Route registrations such as r.GET("/users", h.List) and the prefixes of the groups they
+// are registered on are read from source instead of the running router, for frameworks and
+// custom routers that do not expose their route table. Handlers are known by name only, so
+// their schemas come from WithSchemaDir or WithSchemaBundle. WithRouteDiscoverer takes
+// precedence.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithStaticRoutes("./internal/router"),
+//		openapi.WithSchemaDir("./schemas"),
+//	)
+func WithStaticRoutes(dir string) Option {
+	return func(opts *Options) {
+		opts.staticRoutesDir = dir
+	}
+}
@@ -0,0 +1,254 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Reference objects replace the object they point to, OpenAPI 3.0 ignores any field next to
+// $ref, so objects with a Ref are written as {"$ref": ...} alone. Reading needs no special
+// handling, $ref is an ordinary field.
+
+// reference is a reference object
+type reference struct {
+	Ref string `json:"$ref"`
+}
+
+// MarshalJSON writes the paths in PathOrder first
+func (s OpenAPISpec) MarshalJSON() ([]byte, error) {
+	type plain OpenAPISpec // Without the methods, so it does not recurse
+	if s.PathOrder == nil {
+		return json.Marshal(plain(s))
+	}
+	paths := make(map[string]any, len(s.Paths))
+	for path, pathItem := range s.Paths {
+		paths[path] = pathItem
+	}
+	ordered, err := appendMembers([]byte("{}"), append(slices.Clone(s.PathOrder), slices.Sorted(maps.Keys(s.Paths))...), paths)
+	if err != nil {
+		return nil, err
+	}
+	// Strings escape their quotes, so the first "paths":null is the member of the document
+	s.Paths = nil
+	data, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Replace(data, []byte(`"paths":null`), append([]byte(`"paths":`), ordered...), 1), nil
+}
+
+// MarshalJSON writes a path item with a Ref as a reference object, and its operations in OperationOrder
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type plain PathItem // Without the methods, so it does not recurse
+	if p.Ref != "" {
+		return json.Marshal(reference{p.Ref})
+	}
+	if len(p.OperationOrder) == 0 {
+		return json.Marshal(plain(p))
+	}
+	operations := make(map[string]any)
+	for method, operation := range map[string]*Operation{
+		"get": p.Get, "put": p.Put, "post": p.Post, "delete": p.Delete,
+		"options": p.Options, "head": p.Head, "patch": p.Patch, "trace": p.Trace,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	p.Get, p.Put, p.Post, p.Delete, p.Options, p.Head, p.Patch, p.Trace = nil, nil, nil, nil, nil, nil, nil, nil
+	data, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	return appendMembers(data, append(slices.Clone(p.OperationOrder), "get", "put", "post", "delete", "options", "head", "patch", "trace"), operations)
+}
+
+// appendMembers appends members to a JSON object in the order of keys, each member once
+func appendMembers(object []byte, keys []string, values map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(object[:len(object)-1])
+	empty := len(object) == 2
+	written := make(map[string]bool, len(values))
+	for _, key := range keys {
+		value, exists := values[key]
+		if !exists || written[key] {
+			continue
+		}
+		written[key] = true
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		member, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(member)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON writes an empty, non-nil Security as "security": [], the operation opts out of
+// the document-level security requirement
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation // Without the methods, so it does not recurse
+	if o.Security == nil || len(o.Security) > 0 {
+		return json.Marshal(plain(o))
+	}
+	return json.Marshal(struct {
+		plain
+		Security []SecurityRequirement `json:"security"`
+	}{plain(o), []SecurityRequirement{}})
+}
+
+// MarshalJSON writes a parameter with a Ref as a reference object
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type plain Parameter // Without the methods, so it does not recurse
+	if p.Ref != "" {
+		return json.Marshal(reference{p.Ref})
+	}
+	return json.Marshal(plain(p))
+}
+
+// MarshalJSON writes a request body with a Ref as a reference object
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	type plain RequestBody // Without the methods, so it does not recurse
+	if r.Ref != "" {
+		return json.Marshal(reference{r.Ref})
+	}
+	return json.Marshal(plain(r))
+}
+
+// MarshalJSON writes a response with a Ref as a reference object
+func (r Response) MarshalJSON() ([]byte, error) {
+	type plain Response // Without the methods, so it does not recurse
+	if r.Ref != "" {
+		return json.Marshal(reference{r.Ref})
+	}
+	return json.Marshal(plain(r))
+}
+
+// MarshalJSON writes a header with a Ref as a reference object
+func (h Header) MarshalJSON() ([]byte, error) {
+	type plain Header // Without the methods, so it does not recurse
+	if h.Ref != "" {
+		return json.Marshal(reference{h.Ref})
+	}
+	return json.Marshal(plain(h))
+}
+
+// MarshalJSON writes a link with a Ref as a reference object
+func (l Link) MarshalJSON() ([]byte, error) {
+	type plain Link // Without the methods, so it does not recurse
+	if l.Ref != "" {
+		return json.Marshal(reference{l.Ref})
+	}
+	return json.Marshal(plain(l))
+}
+
+// MarshalJSON writes an example with a Ref as a reference object
+func (e Example) MarshalJSON() ([]byte, error) {
+	type plain Example // Without the methods, so it does not recurse
+	if e.Ref != "" {
+		return json.Marshal(reference{e.Ref})
+	}
+	return json.Marshal(plain(e))
+}
+
+// MarshalJSON writes a security scheme with a Ref as a reference object
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type plain SecurityScheme // Without the methods, so it does not recurse
+	if s.Ref != "" {
+		return json.Marshal(reference{s.Ref})
+	}
+	return json.Marshal(plain(s))
+}
+
+// MarshalJSON writes AdditionalPropertiesAllowed as a boolean additionalProperties
+//
+// Schemas keep the fields next to their Ref, e.g. nullable, as the generator writes them.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema // Without the methods, so it does not recurse
+	if s.AdditionalProperties != nil || s.AdditionalPropertiesAllowed == nil {
+		return json.Marshal(plain(s))
+	}
+	return json.Marshal(struct {
+		plain
+		AdditionalProperties bool `json:"additionalProperties"`
+	}{plain(s), *s.AdditionalPropertiesAllowed})
+}
+
+// UnmarshalJSON reads additionalProperties as a schema or, when it is a boolean, into AdditionalPropertiesAllowed
+//
+// Documents written by other tools are accepted where Schema is narrower than JSON Schema:
+// enum values that are not strings keep their JSON text, e.g. 1 or true, a null enum value
+// or a "null" entry of a type list makes the schema nullable.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema // Without the methods, so it does not recurse
+	var document struct {
+		plain
+		AdditionalProperties json.RawMessage   `json:"additionalProperties"`
+		Type                 json.RawMessage   `json:"type"`
+		Enum                 []json.RawMessage `json:"enum"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	*s = Schema(document.plain)
+
+	switch additional := bytes.TrimSpace(document.AdditionalProperties); string(additional) {
+	case "", "null":
+	case "true", "false":
+		allowed := string(additional) == "true"
+		s.AdditionalPropertiesAllowed = &allowed
+	default:
+		var values Schema
+		if err := json.Unmarshal(additional, &values); err != nil {
+			return err
+		}
+		s.AdditionalProperties = &values
+	}
+
+	if len(document.Type) > 0 {
+		var types []string
+		if bytes.HasPrefix(bytes.TrimSpace(document.Type), []byte("[")) {
+			if err := json.Unmarshal(document.Type, &types); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(document.Type, &s.Type); err != nil {
+			return err
+		}
+		for _, typ := range types {
+			if typ == "null" {
+				s.Nullable = true
+			} else if s.Type == "" {
+				s.Type = typ
+			}
+		}
+	}
+
+	if document.Enum != nil {
+		s.Enum = make([]string, 0, len(document.Enum))
+		for _, raw := range document.Enum {
+			var value string
+			switch trimmed := bytes.TrimSpace(raw); {
+			case string(trimmed) == "null":
+				s.Nullable = true
+				continue
+			case json.Unmarshal(trimmed, &value) != nil:
+				value = string(trimmed)
+			}
+			s.Enum = append(s.Enum, value)
+		}
+	}
+	return nil
+}
@@ -2,20 +2,27 @@ package spec
 
 // OpenAPISpec represents the OpenAPI 3.0 specification
 type OpenAPISpec struct {
-	OpenAPI    string                `json:"openapi"`
-	Info       Info                  `json:"info"`
-	Servers    []Server              `json:"servers,omitempty"`
-	Paths      map[string]PathItem   `json:"paths"`
-	Components Components            `json:"components,omitempty"`
-	Security   []SecurityRequirement `json:"security,omitempty"`
-	Tags       []Tag                 `json:"tags,omitempty"`
+	OpenAPI      string                `json:"openapi"`
+	Info         Info                  `json:"info"`
+	Servers      []Server              `json:"servers,omitempty"`
+	Paths        map[string]PathItem   `json:"paths"`
+	Components   Components            `json:"components,omitzero"`
+	Security     []SecurityRequirement `json:"security,omitempty"`
+	Tags         []Tag                 `json:"tags,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+
+	// PathOrder is the order paths are written in, the paths it does not list follow
+	// alphabetically. nil writes every path alphabetically.
+	PathOrder []string `json:"-"`
 }
 
 type Info struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description,omitempty"`
-	Version     string  `json:"version"`
-	Contact     Contact `json:"contact,omitempty"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty"`
+	Version        string   `json:"version"`
+	Contact        Contact  `json:"contact,omitzero"`
+	License        *License `json:"license,omitempty"`
 }
 
 type Contact struct {
@@ -24,6 +31,11 @@ type Contact struct {
 	Email string `json:"email,omitempty"`
 }
 
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
 type Server struct {
 	URL         string                    `json:"url"`
 	Description string                    `json:"description,omitempty"`
@@ -37,6 +49,7 @@ type ServerVariable struct {
 }
 
 type PathItem struct {
+	Ref         string      `json:"$ref,omitempty"` // Reference to a path item defined elsewhere, other fields are not written
 	Summary     string      `json:"summary,omitempty"`
 	Description string      `json:"description,omitempty"`
 	Get         *Operation  `json:"get,omitempty"`
@@ -48,43 +61,72 @@ type PathItem struct {
 	Patch       *Operation  `json:"patch,omitempty"`
 	Trace       *Operation  `json:"trace,omitempty"`
 	Parameters  []Parameter `json:"parameters,omitempty"`
+	Servers     []Server    `json:"servers,omitempty"`
+
+	// OperationOrder is the order operations are written in by lower case method, e.g. "post",
+	// the operations it does not list follow in the order of the fields. Operations are
+	// written after the other fields when it is set.
+	OperationOrder []string `json:"-"`
 }
 
 type Operation struct {
-	Tags        []string              `json:"tags,omitempty"`
-	Summary     string                `json:"summary,omitempty"`
-	Description string                `json:"description,omitempty"`
-	OperationID string                `json:"operationId,omitempty"`
-	Parameters  []Parameter           `json:"parameters,omitempty"`
-	RequestBody *RequestBody          `json:"requestBody,omitempty"`
-	Responses   map[string]Response   `json:"responses,omitempty"`
-	Deprecated  bool                  `json:"deprecated,omitempty"`
-	Security    []SecurityRequirement `json:"security,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	OperationID  string                `json:"operationId,omitempty"`
+	Parameters   []Parameter           `json:"parameters,omitempty"`
+	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
+	Responses    map[string]Response   `json:"responses,omitempty"`
+	Deprecated   bool                  `json:"deprecated,omitempty"`
+	Security     []SecurityRequirement `json:"security,omitempty"`
+	Callbacks    map[string]Callback   `json:"callbacks,omitempty"`
+	Servers      []Server              `json:"servers,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+	XWebSocket   *WebSocketExtension   `json:"x-websocket,omitempty"`
+	XStreaming   bool                  `json:"x-streaming,omitempty"` // Success response is streamed, e.g. SSE or chunked
+	XCompression *CompressionExtension `json:"x-compression,omitempty"`
+	XInternal    bool                  `json:"x-internal,omitempty"` // Internal-only, hidden by Redocly and API gateways
+}
+
+// CompressionExtension documents the content codings an operation compresses its bodies with
+type CompressionExtension struct {
+	Request  []string `json:"request,omitempty"`  // Codings request bodies may be sent in
+	Response []string `json:"response,omitempty"` // Codings response bodies may be compressed with
+}
+
+// WebSocketExtension documents the messages exchanged after a WebSocket upgrade
+type WebSocketExtension struct {
+	Inbound  map[string]Schema `json:"inbound,omitempty"`  // Messages sent by the client
+	Outbound map[string]Schema `json:"outbound,omitempty"` // Messages sent by the server
 }
 
 type Parameter struct {
-	Name            string             `json:"name"`
-	In              string             `json:"in"` // query, header, path, cookie
-	Description     string             `json:"description,omitempty"`
-	Required        bool               `json:"required,omitempty"`
-	Deprecated      bool               `json:"deprecated,omitempty"`
-	AllowEmptyValue bool               `json:"allowEmptyValue,omitempty"`
-	Style           string             `json:"style,omitempty"`
-	Explode         bool               `json:"explode,omitempty"`
-	AllowReserved   bool               `json:"allowReserved,omitempty"`
-	Schema          Schema             `json:"schema,omitempty"`
-	Example         interface{}        `json:"example,omitempty"`
-	Examples        map[string]Example `json:"examples,omitempty"`
+	Ref             string               `json:"$ref,omitempty"` // Reference to a component parameter, other fields are not written
+	Name            string               `json:"name"`
+	In              string               `json:"in"` // query, header, path, cookie
+	Description     string               `json:"description,omitempty"`
+	Required        bool                 `json:"required,omitempty"`
+	Deprecated      bool                 `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                 `json:"allowEmptyValue,omitempty"`
+	Style           string               `json:"style,omitempty"`
+	Explode         *bool                `json:"explode,omitempty"` // Pointer to distinguish false from unset
+	AllowReserved   bool                 `json:"allowReserved,omitempty"`
+	Schema          Schema               `json:"schema,omitzero"`
+	Example         interface{}          `json:"example,omitempty"`
+	Examples        map[string]Example   `json:"examples,omitempty"`
+	Content         map[string]MediaType `json:"content,omitempty"`
+	XWildcard       bool                 `json:"x-wildcard,omitempty"` // Catch-all parameter, its value may contain slashes
 }
 
 type RequestBody struct {
+	Ref         string               `json:"$ref,omitempty"` // Reference to a component request body, other fields are not written
 	Description string               `json:"description,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty"`
 	Required    bool                 `json:"required,omitempty"`
 }
 
 type MediaType struct {
-	Schema   Schema              `json:"schema,omitempty"`
+	Schema   Schema              `json:"schema,omitzero"`
 	Example  interface{}         `json:"example,omitempty"`
 	Examples map[string]Example  `json:"examples,omitempty"`
 	Encoding map[string]Encoding `json:"encoding,omitempty"`
@@ -94,11 +136,12 @@ type Encoding struct {
 	ContentType   string            `json:"contentType,omitempty"`
 	Headers       map[string]Header `json:"headers,omitempty"`
 	Style         string            `json:"style,omitempty"`
-	Explode       bool              `json:"explode,omitempty"`
+	Explode       *bool             `json:"explode,omitempty"` // Pointer to distinguish false from unset
 	AllowReserved bool              `json:"allowReserved,omitempty"`
 }
 
 type Response struct {
+	Ref         string               `json:"$ref,omitempty"` // Reference to a component response, other fields are not written
 	Description string               `json:"description"`
 	Headers     map[string]Header    `json:"headers,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty"`
@@ -106,28 +149,31 @@ type Response struct {
 }
 
 type Header struct {
+	Ref             string             `json:"$ref,omitempty"` // Reference to a component header, other fields are not written
 	Description     string             `json:"description,omitempty"`
 	Required        bool               `json:"required,omitempty"`
 	Deprecated      bool               `json:"deprecated,omitempty"`
 	AllowEmptyValue bool               `json:"allowEmptyValue,omitempty"`
 	Style           string             `json:"style,omitempty"`
-	Explode         bool               `json:"explode,omitempty"`
+	Explode         *bool              `json:"explode,omitempty"` // Pointer to distinguish false from unset
 	AllowReserved   bool               `json:"allowReserved,omitempty"`
-	Schema          Schema             `json:"schema,omitempty"`
+	Schema          Schema             `json:"schema,omitzero"`
 	Example         interface{}        `json:"example,omitempty"`
 	Examples        map[string]Example `json:"examples,omitempty"`
 }
 
 type Link struct {
+	Ref          string                 `json:"$ref,omitempty"` // Reference to a component link, other fields are not written
 	OperationRef string                 `json:"operationRef,omitempty"`
 	OperationID  string                 `json:"operationId,omitempty"`
 	Parameters   map[string]interface{} `json:"parameters,omitempty"`
 	RequestBody  interface{}            `json:"requestBody,omitempty"`
 	Description  string                 `json:"description,omitempty"`
-	Server       Server                 `json:"server,omitempty"`
+	Server       Server                 `json:"server,omitzero"`
 }
 
 type Example struct {
+	Ref           string      `json:"$ref,omitempty"` // Reference to a component example, other fields are not written
 	Summary       string      `json:"summary,omitempty"`
 	Description   string      `json:"description,omitempty"`
 	Value         interface{} `json:"value,omitempty"`
@@ -160,6 +206,10 @@ type Schema struct {
 	Default              interface{}       `json:"default,omitempty"`
 	Example              interface{}       `json:"example,omitempty"`
 
+	// AdditionalPropertiesAllowed is written as additionalProperties: true or false when
+	// AdditionalProperties is nil, true documents a free-form object
+	AdditionalPropertiesAllowed *bool `json:"-"`
+
 	// String validation
 	MaxLength *int     `json:"maxLength,omitempty"` // Pointer to distinguish 0 from nil
 	MinLength *int     `json:"minLength,omitempty"` // Pointer to distinguish 0 from nil
@@ -190,26 +240,61 @@ type Schema struct {
 	Deprecated bool   `json:"deprecated,omitempty"`
 	Nullable   bool   `json:"nullable,omitempty"`
 
+	// Polymorphism
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	// XML representation, the element name of the schema when it is rendered as XML
+	XML *XML `json:"xml,omitempty"`
+
+	// Map keys that are not plain strings, e.g. "integer" or a Go type name such as "Currency"
+	XKeyType string `json:"x-key-type,omitempty"`
+
+	// Names of the enum members for client generators, in the order of Enum
+	XEnumVarNames []string `json:"x-enum-varnames,omitempty"`
+
+	// Nullable as the x-nullable extension, for generators that do not read nullable
+	XNullable bool `json:"x-nullable,omitempty"`
+
+	// BindName is the query or form parameter a property is bound from when it differs from
+	// the property name, "-" when it is not bound at all. It is not part of the document.
+	BindName string `json:"-"`
+
 	// Reference
 	Ref string `json:"$ref,omitempty"`
 }
 
+// XML describes how a schema is rendered as XML
+type XML struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	Attribute bool   `json:"attribute,omitempty"`
+	Wrapped   bool   `json:"wrapped,omitempty"`
+}
+
+// Discriminator tells clients which oneOf/anyOf variant a payload holds
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"` // value -> schema reference
+}
+
 type SecurityScheme struct {
+	Ref              string     `json:"$ref,omitempty"` // Reference to a component security scheme, other fields are not written
 	Type             string     `json:"type"`
 	Description      string     `json:"description,omitempty"`
 	Name             string     `json:"name,omitempty"`
 	In               string     `json:"in,omitempty"`
 	Scheme           string     `json:"scheme,omitempty"`
 	BearerFormat     string     `json:"bearerFormat,omitempty"`
-	Flows            OAuthFlows `json:"flows,omitempty"`
+	Flows            OAuthFlows `json:"flows,omitzero"`
 	OpenIDConnectURL string     `json:"openIdConnectUrl,omitempty"`
 }
 
 type OAuthFlows struct {
-	Implicit          OAuthFlow `json:"implicit,omitempty"`
-	Password          OAuthFlow `json:"password,omitempty"`
-	ClientCredentials OAuthFlow `json:"clientCredentials,omitempty"`
-	AuthorizationCode OAuthFlow `json:"authorizationCode,omitempty"`
+	Implicit          OAuthFlow `json:"implicit,omitzero"`
+	Password          OAuthFlow `json:"password,omitzero"`
+	ClientCredentials OAuthFlow `json:"clientCredentials,omitzero"`
+	AuthorizationCode OAuthFlow `json:"authorizationCode,omitzero"`
 }
 
 type OAuthFlow struct {
@@ -224,7 +309,7 @@ type SecurityRequirement map[string][]string
 type Tag struct {
 	Name         string       `json:"name"`
 	Description  string       `json:"description,omitempty"`
-	ExternalDocs ExternalDocs `json:"externalDocs,omitempty"`
+	ExternalDocs ExternalDocs `json:"externalDocs,omitzero"`
 }
 
 type ExternalDocs struct {
@@ -246,4 +331,5 @@ type RouteInfo struct {
 	Summary      string
 	Description  string
 	Deprecated   bool
+	CORS         bool // The handler chain runs a CORS middleware
 }
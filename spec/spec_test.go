@@ -0,0 +1,235 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaAdditionalPropertiesJSON(t *testing.T) {
+	allowed, denied := true, false
+	tests := []struct {
+		name   string
+		schema Schema
+		json   string
+	}{
+		{"free-form", Schema{Type: "object", AdditionalPropertiesAllowed: &allowed}, `{"type":"object","additionalProperties":true}`},
+		{"closed", Schema{Type: "object", AdditionalPropertiesAllowed: &denied}, `{"type":"object","additionalProperties":false}`},
+		{"map", Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, `{"type":"object","additionalProperties":{"type":"string"}}`},
+		{"unset", Schema{Type: "object"}, `{"type":"object"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.schema)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(data))
+
+			var decoded Schema
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.schema, decoded)
+		})
+	}
+
+	// Nested schemas are read the same way
+	var nested Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"properties":{"meta":{"type":"object","additionalProperties":true}}}`), &nested))
+	assert.Equal(t, &allowed, nested.Properties["meta"].AdditionalPropertiesAllowed)
+}
+
+// petstoreJSON uses references for every kind of component and the fields a generated spec leaves out
+const petstoreJSON = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Petstore", "version": "1.0.0", "license": {"name": "MIT"}},
+  "servers": [{"url": "https://{region}.petstore.example.com", "variables": {"region": {"default": "eu", "enum": ["eu", "us"]}}}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "tags": ["pets"],
+        "parameters": [
+          {"$ref": "#/components/parameters/Limit"},
+          {"name": "status", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "string", "enum": ["available", "sold"]}}}
+        ],
+        "responses": {
+          "200": {
+            "description": "A page of pets",
+            "headers": {"X-Next": {"$ref": "#/components/headers/Next"}},
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}},
+            "links": {"first": {"$ref": "#/components/links/First"}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {"$ref": "#/components/requestBodies/Pet"},
+        "responses": {"201": {"description": "Created"}},
+        "callbacks": {
+          "adopted": {"{$request.body#/callbackUrl}": {"post": {"responses": {"200": {"description": "Received"}}}}}
+        },
+        "security": [{"petstoreAuth": ["write:pets"]}]
+      }
+    },
+    "/pets/{id}": {"$ref": "#/components/pathItems/Pet"}
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["id", "name"],
+        "properties": {
+          "id": {"type": "integer", "format": "int64", "minimum": 0},
+          "name": {"type": "string", "maxLength": 64},
+          "owner": {"$ref": "#/components/schemas/Owner"},
+          "tags": {"type": "object", "additionalProperties": {"type": "string"}},
+          "metadata": {"type": "object", "additionalProperties": true}
+        },
+        "additionalProperties": false
+      },
+      "Owner": {"type": "object", "nullable": true, "properties": {"name": {"type": "string"}}},
+      "Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+    },
+    "parameters": {
+      "Limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}}
+    },
+    "requestBodies": {
+      "Pet": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}
+    },
+    "responses": {
+      "Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}, "examples": {"missing": {"$ref": "#/components/examples/Missing"}}}}}
+    },
+    "headers": {
+      "Next": {"description": "Cursor of the next page", "schema": {"type": "string"}}
+    },
+    "examples": {
+      "Missing": {"summary": "Pet not found", "value": {"message": "not found"}}
+    },
+    "links": {
+      "First": {"operationId": "listPets", "parameters": {"limit": 10}}
+    },
+    "securitySchemes": {
+      "petstoreAuth": {
+        "type": "oauth2",
+        "flows": {"authorizationCode": {"authorizationUrl": "https://auth.example.com/authorize", "tokenUrl": "https://auth.example.com/token", "scopes": {"write:pets": "Modify pets"}}}
+      },
+      "apiKey": {"type": "apiKey", "name": "X-API-Key", "in": "header"}
+    }
+  },
+  "security": [{"apiKey": []}],
+  "tags": [{"name": "pets", "externalDocs": {"url": "https://docs.example.com/pets"}}]
+}`
+
+func TestOpenAPISpecJSONRoundTrip(t *testing.T) {
+	var decoded OpenAPISpec
+	require.NoError(t, json.Unmarshal([]byte(petstoreJSON), &decoded))
+
+	pets := decoded.Paths["/pets"]
+	if assert.NotNil(t, pets.Get) {
+		assert.Equal(t, "#/components/parameters/Limit", pets.Get.Parameters[0].Ref)
+		assert.Equal(t, "#/components/responses/Error", pets.Get.Responses["default"].Ref)
+		if assert.NotNil(t, pets.Get.Parameters[1].Explode) {
+			assert.False(t, *pets.Get.Parameters[1].Explode)
+		}
+	}
+	if assert.NotNil(t, pets.Post) && assert.NotNil(t, pets.Post.RequestBody) {
+		assert.Equal(t, "#/components/requestBodies/Pet", pets.Post.RequestBody.Ref)
+		assert.Contains(t, pets.Post.Callbacks["adopted"], "{$request.body#/callbackUrl}")
+	}
+	assert.Equal(t, "#/components/pathItems/Pet", decoded.Paths["/pets/{id}"].Ref)
+
+	pet := decoded.Components.Schemas["Pet"]
+	denied, allowed := false, true
+	assert.Equal(t, &denied, pet.AdditionalPropertiesAllowed)
+	assert.Equal(t, &allowed, pet.Properties["metadata"].AdditionalPropertiesAllowed)
+	assert.Equal(t, "string", pet.Properties["tags"].AdditionalProperties.Type)
+	assert.Equal(t, "https://auth.example.com/token", decoded.Components.SecuritySchemes["petstoreAuth"].Flows.AuthorizationCode.TokenURL)
+
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, petstoreJSON, string(data))
+}
+
+func TestReferenceObjectsJSON(t *testing.T) {
+	// Fields next to $ref are dropped, OpenAPI 3.0 ignores them
+	data, err := json.Marshal(Parameter{Ref: "#/components/parameters/Limit", Name: "limit", In: "query"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$ref":"#/components/parameters/Limit"}`, string(data))
+
+	data, err = json.Marshal(Response{Ref: "#/components/responses/Error"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$ref":"#/components/responses/Error"}`, string(data))
+
+	// Schemas keep them, the generator writes nullable references
+	data, err = json.Marshal(Schema{Ref: "#/components/schemas/Owner", Nullable: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$ref":"#/components/schemas/Owner","nullable":true}`, string(data))
+}
+
+func TestOperationSecurityJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation Operation
+		json      string
+	}{
+		{"inherited", Operation{Summary: "list"}, `{"summary":"list"}`},
+		{"public", Operation{Summary: "login", Security: []SecurityRequirement{}}, `{"summary":"login","security":[]}`},
+		{"secured", Operation{Summary: "me", Security: []SecurityRequirement{{"bearerAuth": {}}}}, `{"summary":"me","security":[{"bearerAuth":[]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.operation)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(data))
+
+			var decoded Operation
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.operation, decoded)
+		})
+	}
+}
+
+func TestSchemaUnmarshalJSONForeignValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		schema Schema
+	}{
+		{"integer enum", `{"type":"integer","enum":[1,2]}`, Schema{Type: "integer", Enum: []string{"1", "2"}}},
+		{"boolean enum", `{"type":"boolean","enum":[true]}`, Schema{Type: "boolean", Enum: []string{"true"}}},
+		{"null in enum", `{"type":"string","enum":["a",null]}`, Schema{Type: "string", Enum: []string{"a"}, Nullable: true}},
+		{"type list", `{"type":["string","null"]}`, Schema{Type: "string", Nullable: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded Schema
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &decoded))
+			assert.Equal(t, tt.schema, decoded)
+		})
+	}
+
+	var decoded Schema
+	assert.Error(t, json.Unmarshal([]byte(`{"type":1}`), &decoded))
+}
+
+func TestPathAndOperationOrderJSON(t *testing.T) {
+	document := OpenAPISpec{
+		OpenAPI: "3.0.3",
+		Paths: map[string]PathItem{
+			"/a": {Get: &Operation{OperationID: "getA"}},
+			"/b": {Summary: "B", Get: &Operation{OperationID: "getB"}, Post: &Operation{OperationID: "postB"}, OperationOrder: []string{"post"}},
+			"/c": {Get: &Operation{OperationID: "getC"}},
+		},
+		PathOrder: []string{"/c", "/missing"},
+	}
+
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"openapi":"3.0.3","info":{"title":"","version":""},"paths":{
+		"/a":{"get":{"operationId":"getA"}},
+		"/b":{"summary":"B","get":{"operationId":"getB"},"post":{"operationId":"postB"}},
+		"/c":{"get":{"operationId":"getC"}}}}`, string(data))
+	assert.Contains(t, string(data), `"paths":{"/c":{"get":{"operationId":"getC"}},"/a":`)
+	assert.Contains(t, string(data), `"/b":{"summary":"B","post":{"operationId":"postB"},"get":{"operationId":"getB"}}`)
+}
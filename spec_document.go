@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// specDocument holds a serialized OpenAPI spec ready to be served over HTTP
+//
+// The JSON body, its gzip-compressed form and the validators (ETag and
+// Last-Modified) are computed once when the spec is generated so that
+// conditional requests from Swagger UI can be answered with 304 Not Modified.
+type specDocument struct {
+	body         []byte
+	gzipBody     []byte
+	checksum     string
+	lastModified time.Time
+}
+
+// newSpecDocument serializes the spec and precomputes its checksum and gzip body
+func newSpecDocument(openAPISpec *spec.OpenAPISpec) (*specDocument, error) {
+	body, err := json.Marshal(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI spec: %w", err)
+	}
+
+	// Compressed once per spec, so spend the time to make large specs small on slow links
+	var compressed bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress OpenAPI spec: %w", err)
+	}
+	if _, err := gz.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress OpenAPI spec: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress OpenAPI spec: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+
+	return &specDocument{
+		body:     body,
+		gzipBody: compressed.Bytes(),
+		checksum: hex.EncodeToString(sum[:]),
+		// HTTP dates have second precision, truncate so If-Modified-Since compares cleanly
+		lastModified: time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Checksum returns the hex-encoded SHA-256 of the serialized spec
+func (d *specDocument) Checksum() string {
+	return d.checksum
+}
+
+// etag returns the entity tag for the given content encoding
+func (d *specDocument) etag(encoding string) string {
+	if encoding == "" {
+		return `"` + d.checksum + `"`
+	}
+	return `"` + d.checksum + "-" + encoding + `"`
+}
+
+// ServeHTTP writes the spec honoring If-None-Match, If-Modified-Since and Accept-Encoding
+func (d *specDocument) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	encoding := ""
+	if acceptsEncoding(r.Header.Get("Accept-Encoding"), "gzip") {
+		encoding = "gzip"
+	}
+	etag := d.etag(encoding)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Vary", "Accept-Encoding")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", d.lastModified.Format(http.TimeFormat))
+
+	if d.isNotModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body := d.body
+	if encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+		body = d.gzipBody
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// isNotModified evaluates conditional request headers (If-None-Match takes precedence)
+func (d *specDocument) isNotModified(r *http.Request, etag string) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		if since, err := http.ParseTime(ifModifiedSince); err == nil {
+			return !d.lastModified.After(since)
+		}
+	}
+
+	return false
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows the given coding
+//
+// A coding that is not listed is acceptable through a "*" entry.
+func acceptsEncoding(header, coding string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		if name == "*" {
+			wildcard = acceptableQuality(params)
+			continue
+		}
+		if strings.EqualFold(name, coding) {
+			return acceptableQuality(params)
+		}
+	}
+	return wildcard
+}
+
+// acceptableQuality reports whether the parameters of an Accept-Encoding entry allow it,
+// an explicit q=0 means the coding is not acceptable
+func acceptableQuality(params string) bool {
+	params = strings.ReplaceAll(params, " ", "")
+	return params != "q=0" && params != "q=0.0" && params != "q=0.00" && params != "q=0.000"
+}
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zainokta/openapi-gen/integration"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// recordingServer captures handlers registered through the HTTPServer interface
+type recordingServer struct {
+	handlers map[string]integration.HTTPHandler
+}
+
+func newRecordingServer() *recordingServer {
+	return &recordingServer{handlers: make(map[string]integration.HTTPHandler)}
+}
+
+func (s *recordingServer) GET(path string, handler integration.HTTPHandler) {
+	s.Handle(http.MethodGet, path, handler)
+}
+
+func (s *recordingServer) POST(path string, handler integration.HTTPHandler) {
+	s.Handle(http.MethodPost, path, handler)
+}
+
+func (s *recordingServer) HEAD(path string, handler integration.HTTPHandler) {
+	s.Handle(http.MethodHead, path, handler)
+}
+
+// Handle records GET handlers, the ones the tests serve
+func (s *recordingServer) Handle(method, path string, handler integration.HTTPHandler) {
+	if method == http.MethodGet {
+		s.handlers[path] = handler
+	}
+}
+
+func (s *recordingServer) serve(path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	s.handlers[path](rec, req)
+	return rec
+}
+
+func TestSpecDocumentETag(t *testing.T) {
+	server := newRecordingServer()
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health"}})
+	assert.NoError(t, generator.ServeSwaggerUI(server))
+
+	first := server.serve("/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, `"`+generator.SpecChecksum()+`"`, etag)
+	assert.NotEmpty(t, first.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, first.Body.Bytes())
+
+	revalidated := server.serve("/openapi.json", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, revalidated.Code)
+	assert.Empty(t, revalidated.Body.Bytes())
+
+	stale := server.serve("/openapi.json", http.Header{"If-None-Match": {`"outdated"`}})
+	assert.Equal(t, http.StatusOK, stale.Code)
+}
+
+func TestSpecDocumentLastModified(t *testing.T) {
+	document, err := newSpecDocument(&spec.OpenAPISpec{OpenAPI: "3.0.3"})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("If-Modified-Since", document.lastModified.Format(http.TimeFormat))
+	rec := httptest.NewRecorder()
+	document.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("If-Modified-Since", document.lastModified.Add(-time.Hour).Format(http.TimeFormat))
+	rec = httptest.NewRecorder()
+	document.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestSpecDocumentGzip(t *testing.T) {
+	document, err := newSpecDocument(&spec.OpenAPISpec{OpenAPI: "3.0.3", Info: spec.Info{Title: "Test"}})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+	document.ServeHTTP(rec, req)
+
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, document.etag("gzip"), rec.Header().Get("ETag"))
+
+	reader, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	assert.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, document.body, decoded)
+
+	req = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	rec = httptest.NewRecorder()
+	document.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, document.body, rec.Body.Bytes())
+
+	// A wildcard accepts gzip unless gzip itself is refused
+	assert.True(t, acceptsEncoding("br, *", "gzip"))
+	assert.False(t, acceptsEncoding("*, gzip;q=0", "gzip"))
+	assert.False(t, acceptsEncoding("*;q=0", "gzip"))
+}
@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// MergeConflict reports an entry of a merged OpenAPI fragment that differs from the spec
+//
+// The generated spec, or the fragment merged first, keeps the entry and the conflicting
+// one is left out.
+type MergeConflict struct {
+	Source   string // File the fragment was read from, "MergeSpec" for fragments merged in code
+	Location string // Conflicting entry, e.g. "paths./users.get" or "components.schemas.User"
+}
+
+// specFragment is a hand-written OpenAPI document merged into every generated spec
+type specFragment struct {
+	source string
+	spec   *spec.OpenAPISpec
+}
+
+// WithAdditionalSpecFiles merges hand-written OpenAPI documents into the generated spec
+//
+// Paths, components, security requirements and tags of the files are added to the spec,
+// e.g. to document endpoints served by a proxy or a legacy component alongside the
+// generated ones. Files are read as YAML when named .yaml or .yml, as JSON otherwise; their
+// info and servers are ignored. Swagger 2.0 documents are converted, see ConvertSwagger2. Entries the generated spec already has win, see
+// Generator.MergeConflicts.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithAdditionalSpecFiles("./docs/legacy.yaml"),
+//	)
+func WithAdditionalSpecFiles(paths ...string) Option {
+	return func(opts *Options) {
+		opts.specFiles = append(opts.specFiles, paths...)
+	}
+}
+
+// loadSpecFragment reads an OpenAPI document from a JSON or YAML file, Swagger 2.0 documents are converted
+func loadSpecFragment(path string) (*spec.OpenAPISpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The spec types only carry JSON tags, YAML is decoded generically and converted to JSON
+	extension := strings.ToLower(filepath.Ext(path))
+	document, err := decodeSpecDocument(data, extension == ".yaml" || extension == ".yml")
+	if err != nil {
+		return nil, err
+	}
+	if _, swagger2 := document["swagger"]; swagger2 {
+		return convertSwagger2(document)
+	}
+	if data, err = json.Marshal(document); err != nil {
+		return nil, err
+	}
+
+	var fragment spec.OpenAPISpec
+	if err := json.Unmarshal(data, &fragment); err != nil {
+		return nil, err
+	}
+	return &fragment, nil
+}
+
+// MergeSpec merges a hand-written OpenAPI document into the generated spec
+//
+// The fragment is merged into every spec built from now on, like the files of
+// WithAdditionalSpecFiles. When a spec was already generated it is rebuilt and the
+// OnSpecChange listeners are notified.
+//
+// Example:
+//
+//	err := g.MergeSpec(&spec.OpenAPISpec{
+//		Paths: map[string]spec.PathItem{
+//			"/legacy/report": {Get: &spec.Operation{Summary: "Download report", Responses: responses}},
+//		},
+//	})
+func (g *Generator) MergeSpec(other *spec.OpenAPISpec) error {
+	if other == nil {
+		return fmt.Errorf("cannot merge a nil OpenAPI spec")
+	}
+
+	g.mu.Lock()
+	g.fragments = append(g.fragments, specFragment{source: "MergeSpec", spec: other})
+	if g.spec == nil {
+		g.mu.Unlock()
+		return nil
+	}
+	return g.updateSpec()
+}
+
+// MergeConflicts returns the conflicts found merging fragments the last time the spec was built
+//
+// They are also logged as warnings.
+func (g *Generator) MergeConflicts() []MergeConflict {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.mergeConflicts)
+}
+
+// mergeFragments merges the fragments into the spec being built, in the order they were added
+func (g *Generator) mergeFragments(openAPISpec *spec.OpenAPISpec) {
+	g.mergeConflicts = nil
+	for _, fragment := range g.fragments {
+		report := func(location string) {
+			conflict := MergeConflict{Source: fragment.source, Location: location}
+			g.mergeConflicts = append(g.mergeConflicts, conflict)
+			g.logger.Warn("Conflicting entry in merged OpenAPI spec", "source", conflict.Source, "location", conflict.Location)
+		}
+		mergeSpec(openAPISpec, fragment.spec, report)
+	}
+}
+
+// mergeSpec deep-merges src into dst, entries dst already has are kept and reported when they differ
+func mergeSpec(dst, src *spec.OpenAPISpec, report func(location string)) {
+	if dst.Paths == nil && len(src.Paths) > 0 {
+		dst.Paths = make(map[string]spec.PathItem)
+	}
+	for _, path := range slices.Sorted(maps.Keys(src.Paths)) {
+		item, exists := dst.Paths[path]
+		if !exists {
+			dst.Paths[path] = src.Paths[path]
+			continue
+		}
+		dst.Paths[path] = mergePathItem(item, src.Paths[path], "paths."+path, report)
+	}
+
+	components := &dst.Components
+	mergeEntries(&components.Schemas, src.Components.Schemas, "components.schemas", report)
+	mergeEntries(&components.Responses, src.Components.Responses, "components.responses", report)
+	mergeEntries(&components.Parameters, src.Components.Parameters, "components.parameters", report)
+	mergeEntries(&components.Examples, src.Components.Examples, "components.examples", report)
+	mergeEntries(&components.RequestBodies, src.Components.RequestBodies, "components.requestBodies", report)
+	mergeEntries(&components.Headers, src.Components.Headers, "components.headers", report)
+	mergeEntries(&components.SecuritySchemes, src.Components.SecuritySchemes, "components.securitySchemes", report)
+	mergeEntries(&components.Links, src.Components.Links, "components.links", report)
+	mergeEntries(&components.Callbacks, src.Components.Callbacks, "components.callbacks", report)
+
+	for _, requirement := range src.Security {
+		if !slices.ContainsFunc(dst.Security, func(existing spec.SecurityRequirement) bool {
+			return reflect.DeepEqual(existing, requirement)
+		}) {
+			dst.Security = append(dst.Security, requirement)
+		}
+	}
+
+	for _, tag := range src.Tags {
+		index := slices.IndexFunc(dst.Tags, func(existing spec.Tag) bool { return existing.Name == tag.Name })
+		switch {
+		case index < 0:
+			dst.Tags = append(dst.Tags, tag)
+		case dst.Tags[index].Description == "":
+			dst.Tags[index].Description = tag.Description
+		case tag.Description != "" && tag.Description != dst.Tags[index].Description:
+			report("tags." + tag.Name)
+		}
+	}
+}
+
+// mergePathItem merges the operations of src into dst, operations dst already has are kept
+func mergePathItem(dst, src spec.PathItem, location string, report func(location string)) spec.PathItem {
+	operations := []struct {
+		method   string
+		dst, src **spec.Operation
+	}{
+		{"get", &dst.Get, &src.Get},
+		{"put", &dst.Put, &src.Put},
+		{"post", &dst.Post, &src.Post},
+		{"delete", &dst.Delete, &src.Delete},
+		{"options", &dst.Options, &src.Options},
+		{"head", &dst.Head, &src.Head},
+		{"patch", &dst.Patch, &src.Patch},
+		{"trace", &dst.Trace, &src.Trace},
+	}
+	for _, operation := range operations {
+		switch {
+		case *operation.src == nil:
+		case *operation.dst == nil:
+			*operation.dst = *operation.src
+		case !reflect.DeepEqual(*operation.dst, *operation.src):
+			report(location + "." + operation.method)
+		}
+	}
+
+	if dst.Summary == "" {
+		dst.Summary = src.Summary
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if len(dst.Parameters) == 0 {
+		dst.Parameters = src.Parameters
+	} else if len(src.Parameters) > 0 && !reflect.DeepEqual(dst.Parameters, src.Parameters) {
+		report(location + ".parameters")
+	}
+	return dst
+}
+
+// mergeEntries adds the entries of src missing from dst, entries that differ are reported
+func mergeEntries[T any](dst *map[string]T, src map[string]T, location string, report func(location string)) {
+	if len(src) == 0 {
+		return
+	}
+	if *dst == nil {
+		*dst = make(map[string]T, len(src))
+	}
+	for _, name := range slices.Sorted(maps.Keys(src)) {
+		existing, exists := (*dst)[name]
+		if !exists {
+			(*dst)[name] = src[name]
+			continue
+		}
+		if !reflect.DeepEqual(existing, src[name]) {
+			report(location + "." + name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+const legacySpecYAML = `openapi: 3.0.3
+info:
+  title: Legacy
+  version: "1"
+paths:
+  /legacy/report:
+    get:
+      summary: Download report
+      tags: [legacy]
+      responses:
+        "200":
+          description: The report
+  /health:
+    get:
+      summary: Proxy health
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Report:
+      type: object
+      properties:
+        id:
+          type: string
+  securitySchemes:
+    basicAuth:
+      type: http
+      scheme: basic
+security:
+  - basicAuth: []
+tags:
+  - name: legacy
+    description: Endpoints served by the legacy backend
+`
+
+func TestWithAdditionalSpecFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "legacy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(legacySpecYAML), 0o644))
+
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health"}}, WithAdditionalSpecFiles(file))
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	report := openAPISpec.Paths["/legacy/report"].Get
+	if assert.NotNil(t, report) {
+		assert.Equal(t, "Download report", report.Summary)
+		assert.Equal(t, "The report", report.Responses["200"].Description)
+	}
+	assert.Equal(t, "string", openAPISpec.Components.Schemas["Report"].Properties["id"].Type)
+	assert.Equal(t, "basic", openAPISpec.Components.SecuritySchemes["basicAuth"].Scheme)
+	assert.Contains(t, openAPISpec.Components.SecuritySchemes, "bearerAuth", "Generated schemes are kept")
+	assert.Contains(t, openAPISpec.Security, spec.SecurityRequirement{"basicAuth": []string{}})
+	assert.Contains(t, openAPISpec.Tags, spec.Tag{Name: "legacy", Description: "Endpoints served by the legacy backend"})
+	assert.NotEqual(t, "Legacy", openAPISpec.Info.Title, "Info of fragments is ignored")
+
+	// The generated operation wins over the fragment and the conflict is reported
+	assert.NotEqual(t, "Proxy health", openAPISpec.Paths["/health"].Get.Summary)
+	assert.Equal(t, []MergeConflict{{Source: file, Location: "paths./health.get"}}, generator.MergeConflicts())
+
+	_, err = NewGenerator(nil, nil, processOptions(
+		WithRouteDiscoverer(&staticDiscoverer{}),
+		WithAdditionalSpecFiles(filepath.Join(t.TempDir(), "missing.json")),
+	))
+	assert.ErrorContains(t, err, "missing.json")
+}
+
+func TestMergeSpec(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/users"}})
+	assert.Error(t, generator.MergeSpec(nil))
+
+	_, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	var notified *spec.OpenAPISpec
+	generator.OnSpecChange(func(updated *spec.OpenAPISpec) { notified = updated })
+
+	fragment := &spec.OpenAPISpec{
+		Paths: map[string]spec.PathItem{
+			"/users": {Post: &spec.Operation{Summary: "Create user via proxy"}},
+		},
+		Components: spec.Components{
+			Schemas: map[string]spec.Schema{"ProxyError": {Type: "object"}},
+		},
+	}
+	require.NoError(t, generator.MergeSpec(fragment))
+
+	if assert.NotNil(t, notified) {
+		users := notified.Paths["/users"]
+		assert.NotNil(t, users.Get, "Generated operations of the path are kept")
+		if assert.NotNil(t, users.Post) {
+			assert.Equal(t, "Create user via proxy", users.Post.Summary)
+		}
+		assert.Contains(t, notified.Components.Schemas, "ProxyError")
+	}
+	assert.Empty(t, generator.MergeConflicts())
+
+	// Fragments are merged into every regeneration
+	regenerated, err := generator.GenerateSpec()
+	require.NoError(t, err)
+	assert.NotNil(t, regenerated.Paths["/users"].Post)
+
+	// A later fragment never replaces an entry of an earlier one
+	require.NoError(t, generator.MergeSpec(&spec.OpenAPISpec{
+		Components: spec.Components{Schemas: map[string]spec.Schema{"ProxyError": {Type: "string"}}},
+	}))
+	assert.Equal(t, []MergeConflict{{Source: "MergeSpec", Location: "components.schemas.ProxyError"}}, generator.MergeConflicts())
+}
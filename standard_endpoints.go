@@ -0,0 +1,206 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// StandardEndpoint is a conventional operational endpoint documented with a canned schema
+type StandardEndpoint string
+
+const (
+	HealthEndpoint  StandardEndpoint = "health"  // Liveness, a HealthStatus with 200 or 503
+	ReadyEndpoint   StandardEndpoint = "ready"   // Readiness, a HealthStatus with 200 or 503
+	MetricsEndpoint StandardEndpoint = "metrics" // Metrics in the Prometheus text exposition format
+	VersionEndpoint StandardEndpoint = "version" // Build information, a VersionInfo
+)
+
+// Component names of the canned schemas of standard endpoints
+const (
+	HealthStatusSchemaName = "HealthStatus"
+	VersionInfoSchemaName  = "VersionInfo"
+)
+
+// PrometheusContentType is the media type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4"
+
+// conventionalEndpoints are the last path segments of the routes documented as standard endpoints
+var conventionalEndpoints = map[string]StandardEndpoint{
+	"health": HealthEndpoint, "healthz": HealthEndpoint, "livez": HealthEndpoint,
+	"ready": ReadyEndpoint, "readyz": ReadyEndpoint, "readiness": ReadyEndpoint,
+	"metrics": MetricsEndpoint,
+	"version": VersionEndpoint,
+}
+
+// WithStandardEndpoints documents health, readiness, metrics and version endpoints with canned schemas
+//
+// GET routes whose path ends in /health, /healthz, /livez, /ready, /readyz, /readiness,
+// /metrics or /version get the canned documentation when their handler analysis found no
+// response schema. paths maps further route paths, as registered with the framework, to
+// a standard endpoint; mapped routes always get the canned documentation. Responses
+// overridden with OverrideResponse win over the canned ones.
+//
+// Prometheus handlers are documented as MetricsEndpoint without the option: handlers using
+// promhttp, and net/http handlers wrapped with gin.WrapH or Hertz's adaptor.HertzHandler on
+// a path ending in /metrics.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithStandardEndpoints(map[string]openapi.StandardEndpoint{
+//			"/internal/alive": openapi.HealthEndpoint,
+//			"/internal/build": openapi.VersionEndpoint,
+//		}),
+//	)
+func WithStandardEndpoints(paths map[string]StandardEndpoint) Option {
+	return func(opts *Options) {
+		if opts.standardPaths == nil {
+			opts.standardPaths = make(map[string]StandardEndpoint, len(paths))
+		}
+		for routePath, endpoint := range paths {
+			switch endpoint {
+			case HealthEndpoint, ReadyEndpoint, MetricsEndpoint, VersionEndpoint:
+				opts.standardPaths[routePath] = endpoint
+			default:
+				opts.conflicts = append(opts.conflicts, fmt.Errorf("unknown standard endpoint %q for %s", endpoint, routePath))
+			}
+		}
+	}
+}
+
+// standardEndpoint returns the standard endpoint a route is documented as, if any
+func (g *Generator) standardEndpoint(route spec.RouteInfo, handlerSchema analyzer.HandlerSchema) (StandardEndpoint, bool) {
+	if !strings.EqualFold(route.Method, http.MethodGet) {
+		return "", false
+	}
+	// Prometheus handlers are recognized without WithStandardEndpoints, JSON would mislead client generators
+	if handlerSchema.Metrics || (wrapsHTTPHandler(route.Handler) && strings.EqualFold(path.Base(route.Path), "metrics")) {
+		return MetricsEndpoint, true
+	}
+	if g.standardPaths == nil {
+		return "", false
+	}
+	if endpoint, mapped := g.standardPaths[route.Path]; mapped {
+		return endpoint, true
+	}
+	// Conventional paths only stand in for a response the analysis could not find
+	if handlerSchema.ResponseSchema.Type != "" && handlerSchema.ResponseSchema.Description != analyzer.FallbackResponseDescription {
+		return "", false
+	}
+	endpoint, conventional := conventionalEndpoints[strings.ToLower(path.Base(route.Path))]
+	return endpoint, conventional
+}
+
+// httpHandlerWrappers name the closures of the functions adapting a net/http handler such as
+// promhttp.Handler() to a framework: gin.WrapH, gin.WrapF and Hertz's adaptor.HertzHandler.
+// Inlined wrappers are named after their caller, e.g. main.setupRoutes.WrapH.func1.
+var httpHandlerWrappers = []string{".WrapH.func", ".WrapF.func", ".HertzHandler.func"}
+
+// wrapsHTTPHandler reports whether a route handler is a net/http handler adapted to the framework
+func wrapsHTTPHandler(handler any) bool {
+	value := reflect.ValueOf(handler)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return false
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	return fn != nil && slices.ContainsFunc(httpHandlerWrappers, func(wrapper string) bool {
+		return strings.Contains(fn.Name(), wrapper)
+	})
+}
+
+// documentsStandardEndpoint reports whether a documented route is the standard endpoint, its schema is then a component
+func (g *Generator) documentsStandardEndpoint(endpoints ...StandardEndpoint) bool {
+	for _, analyzed := range g.routes {
+		if analyzed.hidden {
+			continue
+		}
+		if endpoint, standard := g.standardEndpoint(analyzed.route, analyzed.handlerSchema); standard && slices.Contains(endpoints, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyStandardEndpoint replaces the request and responses of an operation with the canned ones of an endpoint
+func (g *Generator) applyStandardEndpoint(endpoint StandardEndpoint, operation *spec.Operation) {
+	operation.RequestBody = nil
+
+	healthStatus := map[string]spec.MediaType{
+		"application/json": {Schema: spec.Schema{Ref: "#/components/schemas/" + HealthStatusSchemaName}},
+	}
+	switch endpoint {
+	case HealthEndpoint:
+		operation.Responses = map[string]spec.Response{
+			"200": {Description: "The service is alive", Content: healthStatus},
+			"503": {Description: "The service is unhealthy", Content: healthStatus},
+		}
+	case ReadyEndpoint:
+		operation.Responses = map[string]spec.Response{
+			"200": {Description: "The service is ready to accept traffic", Content: healthStatus},
+			"503": {Description: "The service or one of its dependencies is not ready", Content: healthStatus},
+		}
+	case MetricsEndpoint:
+		operation.Responses = map[string]spec.Response{
+			"200": {
+				Description: "Metrics in the Prometheus text exposition format",
+				Content: map[string]spec.MediaType{
+					PrometheusContentType: {Schema: spec.Schema{Type: "string"}},
+				},
+			},
+		}
+	case VersionEndpoint:
+		operation.Responses = map[string]spec.Response{
+			"200": {
+				Description: "Build information of the service",
+				Content: map[string]spec.MediaType{
+					"application/json": {Schema: spec.Schema{Ref: "#/components/schemas/" + VersionInfoSchemaName}},
+				},
+			},
+		}
+	}
+}
+
+// healthStatusSchema returns the canned schema of health and readiness responses
+func healthStatusSchema() spec.Schema {
+	status := spec.Schema{Type: "string", Enum: []string{"ok", "degraded", "down"}}
+	check := spec.Schema{
+		Type: "object",
+		Properties: map[string]spec.Schema{
+			"status": status,
+			"error":  {Type: "string", Description: "Why the check failed"},
+		},
+		Required: []string{"status"},
+	}
+	return spec.Schema{
+		Type:        "object",
+		Description: "Health of the service",
+		Properties: map[string]spec.Schema{
+			"status": status,
+			"checks": {Type: "object", Description: "Health of each dependency, by name", AdditionalProperties: &check},
+		},
+		Required: []string{"status"},
+	}
+}
+
+// versionInfoSchema returns the canned schema of version responses
+func versionInfoSchema() spec.Schema {
+	return spec.Schema{
+		Type:        "object",
+		Description: "Build information of the service",
+		Properties: map[string]spec.Schema{
+			"version":    {Type: "string", Description: "Release version", Example: "1.4.2"},
+			"commit":     {Type: "string", Description: "VCS revision the service was built from"},
+			"build_time": {Type: "string", Format: "date-time"},
+			"go_version": {Type: "string", Example: "go1.25.1"},
+		},
+		Required: []string{"version"},
+	}
+}
@@ -0,0 +1,82 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type healthResponse struct {
+	Uptime int `json:"uptime"`
+}
+
+func TestWithStandardEndpoints(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/healthz", HandlerName: "Health"},
+		{Method: "GET", Path: "/api/v1/ready", HandlerName: "Ready"},
+		{Method: "GET", Path: "/metrics", HandlerName: "Metrics"},
+		{Method: "GET", Path: "/internal/build", HandlerName: "Build"},
+		{Method: "GET", Path: "/status/health", HandlerName: "DetailedStatus"},
+		{Method: "POST", Path: "/version", HandlerName: "CreateVersion"},
+	}
+
+	generator := newTestGenerator(t, routes, WithStandardEndpoints(map[string]StandardEndpoint{
+		"/internal/build": VersionEndpoint,
+	}))
+	// A response found by the analysis wins over the conventional path
+	generator.schemaRegistry.RegisterHandlerSchema("DetailedStatus", analyzer.HandlerSchema{
+		ResponseSchema: generator.schemaRegistry.GenerateSchemaFromType(reflect.TypeOf(healthResponse{})),
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, healthStatusSchema(), openAPISpec.Components.Schemas[HealthStatusSchemaName])
+	assert.Equal(t, versionInfoSchema(), openAPISpec.Components.Schemas[VersionInfoSchemaName])
+
+	health := openAPISpec.Paths["/healthz"].Get.Responses
+	assert.Len(t, health, 2)
+	assert.Equal(t, "#/components/schemas/HealthStatus", health["503"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/HealthStatus", openAPISpec.Paths["/api/v1/ready"].Get.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, spec.Schema{Type: "string"}, openAPISpec.Paths["/metrics"].Get.Responses["200"].Content[PrometheusContentType].Schema)
+	assert.Empty(t, openAPISpec.Paths["/metrics"].Get.Security, "Scrapers call it without credentials")
+	assert.Equal(t, "#/components/schemas/VersionInfo", openAPISpec.Paths["/internal/build"].Get.Responses["200"].Content["application/json"].Schema.Ref)
+
+	statusHealth := resolveRef(t, openAPISpec, openAPISpec.Paths["/status/health"].Get.Responses["200"].Content["application/json"].Schema)
+	assert.Contains(t, statusHealth.Properties, "uptime")
+	assert.NotContains(t, openAPISpec.Paths["/version"].Post.Responses, "503")
+
+	// Standard endpoints are opt-in
+	openAPISpec, err = newTestGenerator(t, routes).GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotContains(t, openAPISpec.Components.Schemas, HealthStatusSchemaName)
+	assert.Contains(t, openAPISpec.Paths["/metrics"].Get.Responses["200"].Content, "application/json")
+}
+
+func TestWithStandardEndpointsUnknown(t *testing.T) {
+	options := processOptions(WithConfig(NewConfig()), WithStandardEndpoints(map[string]StandardEndpoint{"/alive": "liveness"}))
+	assert.ErrorContains(t, options.validate(), `unknown standard endpoint "liveness" for /alive`)
+}
+
+func TestPrometheusMetricsDocumentation(t *testing.T) {
+	wrapped := gin.WrapH(http.NotFoundHandler())
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/metrics", HandlerName: "WrapH", Handler: wrapped},
+		{Method: "GET", Path: "/internal/prometheus", HandlerName: "PrometheusHandler"},
+		{Method: "POST", Path: "/webhook", HandlerName: "Webhook", Handler: wrapped},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("PrometheusHandler", analyzer.HandlerSchema{Metrics: true})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+	for _, path := range []string{"/metrics", "/internal/prometheus"} {
+		content := openAPISpec.Paths[path].Get.Responses["200"].Content
+		assert.Equal(t, map[string]spec.MediaType{PrometheusContentType: {Schema: spec.Schema{Type: "string"}}}, content, path)
+	}
+	assert.Contains(t, openAPISpec.Paths["/webhook"].Post.Responses["200"].Content, "application/json")
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// RegisterWebSocketInbound registers a message the client sends over a WebSocket endpoint
+//
+// Registering a message also marks the route as a WebSocket upgrade, which is useful
+// when the upgrade cannot be detected from the handler source.
+//
+// Example:
+//
+//	openapi.WithCustomizer(func(g *openapi.Generator) error {
+//		g.RegisterWebSocketInbound("/ws/chat", reflect.TypeOf(ChatMessage{}))
+//		g.RegisterWebSocketOutbound("/ws/chat", reflect.TypeOf(ChatBroadcast{}))
+//		return nil
+//	})
+func (g *Generator) RegisterWebSocketInbound(path string, messageType reflect.Type) {
+	g.registerStreamMessage(path, analyzer.StreamWebSocket, true, streamMessageName(messageType), messageType)
+}
+
+// RegisterWebSocketOutbound registers a message the server sends over a WebSocket endpoint
+func (g *Generator) RegisterWebSocketOutbound(path string, messageType reflect.Type) {
+	g.registerStreamMessage(path, analyzer.StreamWebSocket, false, streamMessageName(messageType), messageType)
+}
+
+// RegisterSSEEvent registers a server-sent event emitted by an SSE endpoint
+//
+// Example:
+//
+//	g.RegisterSSEEvent("/events", "progress", reflect.TypeOf(ProgressEvent{}))
+func (g *Generator) RegisterSSEEvent(path, event string, payloadType reflect.Type) {
+	g.registerStreamMessage(path, analyzer.StreamSSE, false, event, payloadType)
+}
+
+// registerStreamMessage generates the message schema and stores it in the schema registry
+func (g *Generator) registerStreamMessage(path string, kind analyzer.StreamKind, inbound bool, name string, messageType reflect.Type) {
+	var schema spec.Schema
+	if messageType != nil {
+		schema = g.schemaRegistry.GenerateSchemaFromType(messageType)
+	}
+	// WebSocket upgrades and SSE streams are always initiated with GET
+	g.schemaRegistry.RegisterStreamMessage(http.MethodGet, path, kind, inbound, name, schema)
+}
+
+// streamMessageName returns the name used for a message type
+func streamMessageName(messageType reflect.Type) string {
+	if messageType == nil {
+		return "message"
+	}
+	for messageType.Kind() == reflect.Ptr {
+		messageType = messageType.Elem()
+	}
+	if messageType.Name() == "" {
+		return "message"
+	}
+	return messageType.Name()
+}
+
+// applyWebSocketDocumentation documents a route as a WebSocket upgrade
+func (g *Generator) applyWebSocketDocumentation(route spec.RouteInfo, operation *spec.Operation) {
+	operation.RequestBody = nil
+	operation.Parameters = append(operation.Parameters,
+		spec.Parameter{
+			Name:        "Upgrade",
+			In:          "header",
+			Required:    true,
+			Description: "Must be websocket",
+			Schema:      spec.Schema{Type: "string", Enum: []string{"websocket"}},
+		},
+		spec.Parameter{
+			Name:        "Connection",
+			In:          "header",
+			Required:    true,
+			Description: "Must contain Upgrade",
+			Schema:      spec.Schema{Type: "string", Enum: []string{"Upgrade"}},
+		},
+	)
+
+	responses := make(map[string]spec.Response)
+	responses["101"] = spec.Response{
+		Description: "Switching Protocols",
+		Headers: map[string]spec.Header{
+			"Upgrade":              {Schema: spec.Schema{Type: "string"}},
+			"Connection":           {Schema: spec.Schema{Type: "string"}},
+			"Sec-WebSocket-Accept": {Schema: spec.Schema{Type: "string"}},
+		},
+	}
+	for code, response := range operation.Responses {
+		if code != "200" {
+			responses[code] = response
+		}
+	}
+	operation.Responses = responses
+
+	extension := &spec.WebSocketExtension{}
+	if stream, exists := g.schemaRegistry.GetStreamSchema(route.Method, route.Path); exists {
+		if len(stream.Inbound) > 0 {
+			extension.Inbound = stream.Inbound
+		}
+		if len(stream.Outbound) > 0 {
+			extension.Outbound = stream.Outbound
+		}
+	}
+	operation.XWebSocket = extension
+}
+
+// applySSEDocumentation documents a route as a text/event-stream response
+func (g *Generator) applySSEDocumentation(route spec.RouteInfo, operation *spec.Operation) {
+	operation.RequestBody = nil
+
+	schema := spec.Schema{Type: "string", Description: "Server-sent event stream"}
+	if stream, exists := g.schemaRegistry.GetStreamSchema(route.Method, route.Path); exists && len(stream.Outbound) > 0 {
+		events := make([]string, 0, len(stream.Outbound))
+		for event := range stream.Outbound {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+
+		// Each event payload becomes a titled oneOf variant, named after the event
+		schema = spec.Schema{
+			Description: "Server-sent events: " + strings.Join(events, ", "),
+		}
+		for _, event := range events {
+			variant := stream.Outbound[event]
+			variant.Title = event
+			schema.OneOf = append(schema.OneOf, variant)
+		}
+	}
+
+	success := operation.Responses["200"]
+	success.Description = "Event stream"
+	success.Content = map[string]spec.MediaType{
+		"text/event-stream": {Schema: schema},
+	}
+	operation.Responses["200"] = success
+	operation.XStreaming = true
+}
+
+// applyChunkedDocumentation documents a route as a streamed application/octet-stream response
+//
+// The request body is kept, handlers streaming e.g. an export may be POST routes.
+func (g *Generator) applyChunkedDocumentation(operation *spec.Operation) {
+	success := operation.Responses["200"]
+	success.Description = "Streamed response"
+	success.Content = map[string]spec.MediaType{
+		"application/octet-stream": {Schema: spec.Schema{Type: "string", Format: "binary"}},
+	}
+	operation.Responses["200"] = success
+	operation.XStreaming = true
+}
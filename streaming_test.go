@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+type ChatBroadcast struct {
+	From string `json:"from"`
+	Text string `json:"text"`
+}
+
+type progressEvent struct {
+	Percent int `json:"percent"`
+}
+
+func TestWebSocketDocumentation(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/ws/chat", HandlerName: "Chat"},
+	})
+	generator.RegisterWebSocketInbound("/ws/chat", reflect.TypeOf(chatMessage{}))
+	generator.RegisterWebSocketOutbound("/ws/chat", reflect.TypeOf(&ChatBroadcast{}))
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/ws/chat"].Get
+	if assert.NotNil(t, operation) {
+		assert.Contains(t, operation.Responses, "101")
+		assert.NotContains(t, operation.Responses, "200")
+		assert.Nil(t, operation.RequestBody)
+		if assert.NotNil(t, operation.XWebSocket) {
+			assert.Contains(t, operation.XWebSocket.Inbound, "chatMessage")
+			assert.Contains(t, operation.XWebSocket.Outbound, "ChatBroadcast")
+			assert.Contains(t, operation.XWebSocket.Outbound["ChatBroadcast"].Properties, "from")
+		}
+	}
+}
+
+func TestSSEDocumentation(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/events", HandlerName: "Events"},
+		{Method: "GET", Path: "/users", HandlerName: "ListUsers"},
+	})
+	generator.RegisterSSEEvent("/events", "progress", reflect.TypeOf(progressEvent{}))
+	generator.RegisterSSEEvent("/events", "done", nil)
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/events"].Get
+	if assert.NotNil(t, operation) {
+		content := operation.Responses["200"].Content
+		assert.NotContains(t, content, "application/json")
+		if assert.Contains(t, content, "text/event-stream") {
+			oneOf := content["text/event-stream"].Schema.OneOf
+			if assert.Len(t, oneOf, 2) {
+				assert.Equal(t, "done", oneOf[0].Title)
+				assert.Equal(t, "progress", oneOf[1].Title)
+			}
+		}
+		assert.Nil(t, operation.XWebSocket)
+		assert.True(t, operation.XStreaming)
+	}
+
+	// Ordinary routes are unaffected
+	users := openAPISpec.Paths["/users"].Get
+	if assert.NotNil(t, users) {
+		assert.Contains(t, users.Responses["200"].Content, "application/json")
+	}
+}
+
+func TestChunkedDocumentation(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "POST", Path: "/exports", HandlerName: "Export"},
+	})
+	generator.schemaRegistry.RegisterHandlerSchema("Export", analyzer.HandlerSchema{
+		RequestSchema: spec.Schema{Type: "object"},
+		Stream:        analyzer.StreamChunked,
+	})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	operation := openAPISpec.Paths["/exports"].Post
+	if assert.NotNil(t, operation) {
+		assert.True(t, operation.XStreaming)
+		assert.Equal(t, map[string]spec.MediaType{
+			"application/octet-stream": {Schema: spec.Schema{Type: "string", Format: "binary"}},
+		}, operation.Responses["200"].Content)
+		assert.NotNil(t, operation.RequestBody)
+	}
+}
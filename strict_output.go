@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+// errorResponseSchemaName names the default error response schema in strict output
+const errorResponseSchemaName = "ErrorResponse"
+
+// WithStrictOutput shapes the spec for client generators such as openapi-generator
+//
+// Inline object schemas of request bodies, responses and properties move to named
+// components, e.g. GetUsers200Response or UserAddress, so generators emit named models
+// instead of InlineObject classes; identical inline schemas share one component. Every
+// schema gets a type, maps of "any" values become additionalProperties: true, and
+// operation IDs are turned into identifiers and made unique.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithStrictOutput(),
+//	)
+func WithStrictOutput() Option {
+	return func(opts *Options) {
+		opts.strictOutput = true
+	}
+}
+
+// applyStrictOutput rewrites the built spec for client generators, see WithStrictOutput
+func (g *Generator) applyStrictOutput(openAPISpec *spec.OpenAPISpec) {
+	if openAPISpec.Components.Schemas == nil {
+		openAPISpec.Components.Schemas = make(map[string]spec.Schema)
+	}
+	namer := newInlineSchemaNamer(openAPISpec.Components.Schemas)
+	namer.preferred[analyzer.SchemaHash(errorSchema())] = errorResponseSchemaName
+
+	// Components first, so inline copies of a component are replaced by references to it
+	for _, name := range slices.Sorted(maps.Keys(openAPISpec.Components.Schemas)) {
+		openAPISpec.Components.Schemas[name] = namer.visit(openAPISpec.Components.Schemas[name], name)
+	}
+
+	operationIDs := make(map[string]bool)
+	for _, path := range slices.Sorted(maps.Keys(openAPISpec.Paths)) {
+		pathItem := openAPISpec.Paths[path]
+		for i := range pathItem.Parameters {
+			pathItem.Parameters[i].Schema = namer.visit(pathItem.Parameters[i].Schema, "")
+		}
+		for _, operation := range pathOperations(&pathItem) {
+			operation.OperationID = uniqueOperationID(operation.OperationID, operationIDs)
+			namer.nameOperationSchemas(operation)
+		}
+		openAPISpec.Paths[path] = pathItem
+	}
+}
+
+// pathOperations returns the operations of a path item in a fixed order
+func pathOperations(pathItem *spec.PathItem) []*spec.Operation {
+	var operations []*spec.Operation
+	for _, operation := range []*spec.Operation{
+		pathItem.Get, pathItem.Put, pathItem.Post, pathItem.Delete,
+		pathItem.Options, pathItem.Head, pathItem.Patch, pathItem.Trace,
+	} {
+		if operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// uniqueOperationID turns an operation ID into an identifier, numbered when it is taken
+//
+// IDs that already are identifiers keep their casing, e.g. the snake case of WithOperationIDCasing.
+func uniqueOperationID(operationID string, taken map[string]bool) string {
+	base := operationID
+	if !isIdentifier(base) {
+		base = identifier(operationID)
+	}
+	unique := base
+	for n := 2; taken[unique]; n++ {
+		unique = base + strconv.Itoa(n)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// identifier joins the letters and digits of s, capitalizing each run, e.g. "GetUsers{id}X" is "GetUsersIdX"
+func identifier(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// inlineSchemaNamer moves inline object schemas to named components
+type inlineSchemaNamer struct {
+	components map[string]spec.Schema
+	byHash     map[string]string // Component holding each schema, by the hash of its inline form
+	preferred  map[string]string // Component names for well-known schemas, by hash
+}
+
+// newInlineSchemaNamer creates a namer adding to components, which already hold their own schemas
+func newInlineSchemaNamer(components map[string]spec.Schema) *inlineSchemaNamer {
+	namer := &inlineSchemaNamer{
+		components: components,
+		byHash:     make(map[string]string, len(components)),
+		preferred:  make(map[string]string),
+	}
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		hash := analyzer.SchemaHash(components[name])
+		if _, exists := namer.byHash[hash]; !exists && hash != "" {
+			namer.byHash[hash] = name
+		}
+	}
+	return namer
+}
+
+// nameOperationSchemas names the inline schemas of an operation after its operation ID
+func (n *inlineSchemaNamer) nameOperationSchemas(operation *spec.Operation) {
+	for i := range operation.Parameters {
+		operation.Parameters[i].Schema = n.visit(operation.Parameters[i].Schema, "")
+	}
+	if operation.RequestBody != nil {
+		n.nameContent(operation.RequestBody.Content, operation.OperationID+"Request")
+	}
+	for _, code := range slices.Sorted(maps.Keys(operation.Responses)) {
+		response := operation.Responses[code]
+		n.nameContent(response.Content, operation.OperationID+identifier(code)+"Response")
+		for name, header := range response.Headers {
+			header.Schema = n.visit(header.Schema, "")
+			response.Headers[name] = header
+		}
+		operation.Responses[code] = response
+	}
+}
+
+// nameContent names the inline schemas of media types, media types sharing a schema share its component
+func (n *inlineSchemaNamer) nameContent(content map[string]spec.MediaType, name string) {
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		mediaType := content[contentType]
+		mediaType.Schema = n.inline(mediaType.Schema, name)
+		content[contentType] = mediaType
+	}
+}
+
+// inline returns a reference to a component for an inline object schema, other schemas are
+// returned with their nested schemas named
+func (n *inlineSchemaNamer) inline(schema spec.Schema, name string) spec.Schema {
+	if schema.Ref != "" || len(schema.Properties) == 0 || name == "" {
+		return n.visit(schema, name)
+	}
+
+	hash := analyzer.SchemaHash(schema)
+	if existing, exists := n.byHash[hash]; exists {
+		return spec.Schema{Ref: "#/components/schemas/" + existing}
+	}
+	if preferred, exists := n.preferred[hash]; exists {
+		name = preferred
+	}
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := n.components[unique]; !taken {
+			break
+		}
+		unique = name + strconv.Itoa(i)
+	}
+
+	// Claim the name before visiting, a schema nested in itself refers back to it
+	n.byHash[hash] = unique
+	n.components[unique] = schema
+	n.components[unique] = n.visit(schema, unique)
+	return spec.Schema{Ref: "#/components/schemas/" + unique}
+}
+
+// visit gives a schema a type and names its nested inline schemas, after name when it is set
+func (n *inlineSchemaNamer) visit(schema spec.Schema, name string) spec.Schema {
+	if schema.Type == "any" {
+		schema.Type = ""
+	}
+	composed := len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || schema.Not != nil
+	if schema.Type == "" && schema.Ref == "" && !composed {
+		switch {
+		case schema.Items != nil:
+			schema.Type = "array"
+		case len(schema.Enum) > 0:
+			schema.Type = "string"
+		default:
+			schema.Type = "object"
+		}
+	}
+
+	nested := func(suffix string) string {
+		if name == "" {
+			return ""
+		}
+		return name + suffix
+	}
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]spec.Schema, len(schema.Properties))
+		for _, property := range slices.Sorted(maps.Keys(schema.Properties)) {
+			properties[property] = n.inline(schema.Properties[property], nested(identifier(property)))
+		}
+		schema.Properties = properties
+	}
+	if schema.Items != nil {
+		items := n.inline(*schema.Items, nested("Item"))
+		schema.Items = &items
+	}
+	if schema.AdditionalProperties != nil {
+		if schema.AdditionalProperties.Type == "any" {
+			// Values of any type make a free-form object, "any" is not an OpenAPI type
+			freeForm := true
+			schema.AdditionalProperties, schema.AdditionalPropertiesAllowed = nil, &freeForm
+		} else {
+			values := n.inline(*schema.AdditionalProperties, nested("Value"))
+			schema.AdditionalProperties = &values
+		}
+	}
+	for _, list := range []*[]spec.Schema{&schema.AllOf, &schema.OneOf, &schema.AnyOf} {
+		if *list == nil {
+			continue
+		}
+		named := make([]spec.Schema, len(*list))
+		for i, member := range *list {
+			named[i] = n.inline(member, nested(strconv.Itoa(i+1)))
+		}
+		*list = named
+	}
+	if schema.Not != nil {
+		not := n.visit(*schema.Not, "")
+		schema.Not = &not
+	}
+	return schema
+}
+
+// isIdentifier reports whether s is made of letters, digits and underscores, starting with a letter
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r) && r != '_') {
+			return false
+		}
+	}
+	return s != ""
+}
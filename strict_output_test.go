@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+type strictAddress struct {
+	City string `json:"city"`
+}
+
+type strictUser struct {
+	ID      int            `json:"id"`
+	Address strictAddress  `json:"home_address"`
+	Labels  map[string]any `json:"labels"`
+	Nested  struct {
+		Count int `json:"count"`
+	} `json:"nested"`
+}
+
+func TestWithStrictOutput(t *testing.T) {
+	routes := []spec.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "GET", Path: "/files/{name}"},
+		{Method: "POST", Path: "/users"},
+	}
+	generator := newTestGenerator(t, routes, WithStrictOutput())
+	generator.OverrideRequest("POST", "/users", strictUser{})
+	require.NoError(t, generator.MergeSpec(&spec.OpenAPISpec{
+		Components: spec.Components{Schemas: map[string]spec.Schema{
+			"Settings": {Properties: map[string]spec.Schema{
+				"extra": {Type: "object", AdditionalProperties: &spec.Schema{Type: "any"}},
+				"mode":  {Enum: []string{"a", "b"}},
+			}},
+		}},
+	}))
+
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	// Operation IDs are identifiers and unique
+	operationIDs := make(map[string]bool)
+	for _, pathItem := range openAPISpec.Paths {
+		for _, operation := range pathOperations(&pathItem) {
+			assert.Equal(t, identifier(operation.OperationID), operation.OperationID)
+			assert.False(t, operationIDs[operation.OperationID], "duplicate operation ID %s", operation.OperationID)
+			operationIDs[operation.OperationID] = true
+		}
+	}
+	assert.Contains(t, operationIDs, "GetFiles")
+
+	// Inline objects are named, identical ones share a component
+	errorRef := openAPISpec.Paths["/users"].Get.Responses["400"].Content["application/json"].Schema.Ref
+	assert.Equal(t, "#/components/schemas/ErrorResponse", errorRef)
+	assert.Equal(t, errorRef, openAPISpec.Paths["/users/{id}"].Get.Responses["500"].Content["application/json"].Schema.Ref)
+
+	user := resolveRef(t, openAPISpec, openAPISpec.Paths["/users"].Post.RequestBody.Content["application/json"].Schema)
+	assert.Equal(t, "#/components/schemas/strictUserHomeAddress", user.Properties["home_address"].Ref)
+	assert.Equal(t, "#/components/schemas/strictUserNested", user.Properties["nested"].Ref)
+	assert.Equal(t, "integer", openAPISpec.Components.Schemas["strictUserNested"].Properties["count"].Type)
+
+	// Every schema has a type, "any" values are free-form
+	settings := openAPISpec.Components.Schemas["Settings"]
+	assert.Equal(t, "object", settings.Type)
+	assert.Equal(t, "string", settings.Properties["mode"].Type)
+	assert.Nil(t, settings.Properties["extra"].AdditionalProperties)
+	assert.Equal(t, true, *settings.Properties["extra"].AdditionalPropertiesAllowed)
+
+	// Without the option the spec is left as generated
+	openAPISpec, err = newTestGenerator(t, routes).GenerateSpec()
+	require.NoError(t, err)
+	assert.Empty(t, openAPISpec.Paths["/users"].Get.Responses["400"].Content["application/json"].Schema.Ref)
+}
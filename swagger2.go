@@ -0,0 +1,532 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zainokta/openapi-gen/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// swagger2Methods are the operations a Swagger 2.0 path item can hold
+var swagger2Methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// ConvertSwagger2 converts a Swagger 2.0 (OpenAPI 2) document, JSON or YAML, to OpenAPI 3
+//
+// Definitions become component schemas and their references are rewritten, body and
+// formData parameters become request bodies with the consumes media types, response
+// schemas get a content entry per produces media type and security definitions become
+// security schemes. Paths are prefixed with the basePath, so they line up with the paths
+// of the generated spec. Merge the result with Generator.MergeSpec, or list the file in
+// WithAdditionalSpecFiles which converts Swagger 2.0 documents itself.
+//
+// Example:
+//
+//	data, err := os.ReadFile("docs/swagger.json") // generated by swaggo
+//	legacy, err := openapi.ConvertSwagger2(data)
+//	err = g.MergeSpec(legacy)
+func ConvertSwagger2(data []byte) (*spec.OpenAPISpec, error) {
+	document, err := decodeSpecDocument(data, !json.Valid(data))
+	if err != nil {
+		return nil, err
+	}
+	if version, _ := document["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("not a Swagger 2.0 document, swagger is %q", version)
+	}
+	return convertSwagger2(document)
+}
+
+// decodeSpecDocument decodes a JSON or YAML document into generic maps with string keys
+func decodeSpecDocument(data []byte, isYAML bool) (map[string]any, error) {
+	var document any
+	if isYAML {
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	// YAML keys may be numbers, e.g. unquoted response codes, JSON objects only have string keys
+	object, ok := stringKeys(document).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("document is not an object")
+	}
+	return object, nil
+}
+
+// stringKeys converts the map keys of a decoded YAML value to strings
+func stringKeys(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringKeys(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+		return v
+	}
+	return value
+}
+
+// convertSwagger2 converts a decoded Swagger 2.0 document to OpenAPI 3
+func convertSwagger2(document map[string]any) (*spec.OpenAPISpec, error) {
+	converter := &swagger2Converter{
+		document: document,
+		consumes: stringList(document["consumes"], []string{"application/json"}),
+		produces: stringList(document["produces"], []string{"application/json"}),
+	}
+
+	converted := map[string]any{
+		"openapi": "3.0.3",
+		"info":    document["info"],
+		"paths":   converter.paths(),
+	}
+	if servers := converter.servers(); len(servers) > 0 {
+		converted["servers"] = servers
+	}
+	for _, key := range []string{"security", "tags", "externalDocs"} {
+		if value, exists := document[key]; exists {
+			converted[key] = value
+		}
+	}
+	converted["components"] = converter.components()
+
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Swagger 2.0 document: %w", err)
+	}
+	var openAPISpec spec.OpenAPISpec
+	if err := json.Unmarshal(data, &openAPISpec); err != nil {
+		return nil, fmt.Errorf("failed to convert Swagger 2.0 document: %w", err)
+	}
+	return &openAPISpec, nil
+}
+
+// swagger2Converter holds the document being converted and its default media types
+type swagger2Converter struct {
+	document map[string]any
+	consumes []string
+	produces []string
+}
+
+// basePath returns the basePath of the document, empty for the root
+func (c *swagger2Converter) basePath() string {
+	basePath, _ := c.document["basePath"].(string)
+	return strings.TrimSuffix(basePath, "/")
+}
+
+// servers builds a server per scheme from the host, the basePath is part of the paths
+func (c *swagger2Converter) servers() []any {
+	host, _ := c.document["host"].(string)
+	if host == "" {
+		return nil
+	}
+	var servers []any
+	for _, scheme := range stringList(c.document["schemes"], []string{"https"}) {
+		servers = append(servers, map[string]any{"url": scheme + "://" + host})
+	}
+	return servers
+}
+
+// paths converts the path items, prefixed with the basePath
+func (c *swagger2Converter) paths() map[string]any {
+	paths := make(map[string]any)
+	items, _ := c.document["paths"].(map[string]any)
+	for path, value := range items {
+		item, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		shared := c.resolveParameters(item["parameters"])
+
+		converted := make(map[string]any)
+		for _, key := range []string{"summary", "description"} {
+			if value, exists := item[key]; exists {
+				converted[key] = value
+			}
+		}
+		var pathParameters []any
+		for _, parameter := range shared {
+			if in, _ := parameter["in"].(string); in != "body" && in != "formData" {
+				pathParameters = append(pathParameters, c.parameter(parameter))
+			}
+		}
+		if len(pathParameters) > 0 {
+			converted["parameters"] = pathParameters
+		}
+		for _, method := range swagger2Methods {
+			if operation, ok := item[method].(map[string]any); ok {
+				converted[method] = c.operation(operation, shared)
+			}
+		}
+		paths[c.basePath()+path] = converted
+	}
+	return paths
+}
+
+// operation converts an operation, shared holds the parameters of its path item
+func (c *swagger2Converter) operation(operation map[string]any, shared []map[string]any) map[string]any {
+	converted := make(map[string]any)
+	for _, key := range []string{"tags", "summary", "description", "operationId", "deprecated", "security"} {
+		if value, exists := operation[key]; exists {
+			converted[key] = value
+		}
+	}
+	consumes := stringList(operation["consumes"], c.consumes)
+	produces := stringList(operation["produces"], c.produces)
+
+	// Other path item parameters stay on the path item, its body and form fields become part
+	// of the request body of every operation that does not declare its own
+	own := c.resolveParameters(operation["parameters"])
+	parameters := slices.Clone(own)
+	for _, parameter := range shared {
+		if in := parameter["in"]; in != "body" && in != "formData" {
+			continue
+		}
+		if !slices.ContainsFunc(own, func(p map[string]any) bool {
+			return p["name"] == parameter["name"] && p["in"] == parameter["in"]
+		}) {
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	var operationParameters []any
+	var form []map[string]any
+	for _, parameter := range parameters {
+		switch parameter["in"] {
+		case "body":
+			converted["requestBody"] = c.bodyRequest(parameter, consumes)
+		case "formData":
+			form = append(form, parameter)
+		default:
+			operationParameters = append(operationParameters, c.parameter(parameter))
+		}
+	}
+	if len(operationParameters) > 0 {
+		converted["parameters"] = operationParameters
+	}
+	if len(form) > 0 {
+		converted["requestBody"] = c.formRequest(form, consumes)
+	}
+
+	responses := make(map[string]any)
+	declared, _ := operation["responses"].(map[string]any)
+	for code, response := range declared {
+		if response, ok := c.resolve(response, "#/responses/", "responses"); ok {
+			responses[code] = c.response(response, produces)
+		}
+	}
+	converted["responses"] = responses
+	return converted
+}
+
+// resolveParameters resolves the parameter references of a parameter list
+func (c *swagger2Converter) resolveParameters(value any) []map[string]any {
+	list, _ := value.([]any)
+	parameters := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if parameter, ok := c.resolve(item, "#/parameters/", "parameters"); ok {
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// resolve follows a reference to a top-level parameter or response, OpenAPI 3 parameters
+// and responses of this package are always inlined
+func (c *swagger2Converter) resolve(value any, prefix, section string) (map[string]any, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	ref, _ := object["$ref"].(string)
+	name, found := strings.CutPrefix(ref, prefix)
+	if !found {
+		return object, true
+	}
+	declared, _ := c.document[section].(map[string]any)
+	resolved, ok := declared[name].(map[string]any)
+	return resolved, ok
+}
+
+// parameter converts a query, header, path or cookie parameter, its type moves to a schema
+func (c *swagger2Converter) parameter(parameter map[string]any) map[string]any {
+	converted := make(map[string]any)
+	for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if value, exists := parameter[key]; exists {
+			converted[key] = value
+		}
+	}
+	converted["schema"] = parameterSchema(parameter)
+
+	switch parameter["collectionFormat"] {
+	case "multi":
+		converted["style"], converted["explode"] = "form", true
+	case "ssv":
+		converted["style"] = "spaceDelimited"
+	case "pipes":
+		converted["style"] = "pipeDelimited"
+	case "csv", nil:
+		if parameter["type"] == "array" && parameter["in"] == "query" {
+			converted["style"], converted["explode"] = "form", false
+		}
+	}
+	return converted
+}
+
+// bodyRequest converts a body parameter to a request body with the consumes media types
+func (c *swagger2Converter) bodyRequest(parameter map[string]any, consumes []string) map[string]any {
+	content := make(map[string]any)
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]any{"schema": convertSwagger2Schema(parameter["schema"])}
+	}
+	request := map[string]any{"content": content}
+	for _, key := range []string{"description", "required"} {
+		if value, exists := parameter[key]; exists {
+			request[key] = value
+		}
+	}
+	return request
+}
+
+// formRequest converts formData parameters to an object request body
+func (c *swagger2Converter) formRequest(parameters []map[string]any, consumes []string) map[string]any {
+	properties := make(map[string]any)
+	var required []any
+	multipart := slices.Contains(consumes, "multipart/form-data")
+	for _, parameter := range parameters {
+		name, _ := parameter["name"].(string)
+		property := parameterSchema(parameter)
+		if description, exists := parameter["description"]; exists {
+			property["description"] = description
+		}
+		properties[name] = property
+		if parameter["required"] == true {
+			required = append(required, name)
+		}
+		multipart = multipart || parameter["type"] == "file"
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart {
+		mediaType = "multipart/form-data"
+	}
+	return map[string]any{"content": map[string]any{mediaType: map[string]any{"schema": schema}}}
+}
+
+// response converts a response, its schema gets a content entry per produces media type
+func (c *swagger2Converter) response(response map[string]any, produces []string) map[string]any {
+	description, _ := response["description"].(string)
+	converted := map[string]any{"description": description}
+
+	if schema, exists := response["schema"]; exists {
+		examples, _ := response["examples"].(map[string]any)
+		content := make(map[string]any)
+		for _, mediaType := range produces {
+			media := map[string]any{"schema": convertSwagger2Schema(schema)}
+			if example, exists := examples[mediaType]; exists {
+				media["example"] = example
+			}
+			content[mediaType] = media
+		}
+		converted["content"] = content
+	}
+
+	if headers, ok := response["headers"].(map[string]any); ok {
+		convertedHeaders := make(map[string]any, len(headers))
+		for name, value := range headers {
+			header, _ := value.(map[string]any)
+			convertedHeader := map[string]any{"schema": parameterSchema(header)}
+			if description, exists := header["description"]; exists {
+				convertedHeader["description"] = description
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+	return converted
+}
+
+// components converts definitions, parameters, responses and security definitions
+func (c *swagger2Converter) components() map[string]any {
+	components := make(map[string]any)
+
+	if definitions, ok := c.document["definitions"].(map[string]any); ok {
+		schemas := make(map[string]any, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSwagger2Schema(schema)
+		}
+		components["schemas"] = schemas
+	}
+
+	// Body and formData parameters have no OpenAPI 3 counterpart, they are inlined where used
+	if declared, ok := c.document["parameters"].(map[string]any); ok {
+		parameters := make(map[string]any)
+		for name, value := range declared {
+			parameter, _ := value.(map[string]any)
+			if in, _ := parameter["in"].(string); in != "body" && in != "formData" {
+				parameters[name] = c.parameter(parameter)
+			}
+		}
+		if len(parameters) > 0 {
+			components["parameters"] = parameters
+		}
+	}
+
+	if declared, ok := c.document["responses"].(map[string]any); ok {
+		responses := make(map[string]any, len(declared))
+		for name, value := range declared {
+			if response, ok := value.(map[string]any); ok {
+				responses[name] = c.response(response, c.produces)
+			}
+		}
+		components["responses"] = responses
+	}
+
+	if declared, ok := c.document["securityDefinitions"].(map[string]any); ok {
+		schemes := make(map[string]any, len(declared))
+		for name, value := range declared {
+			if scheme, ok := value.(map[string]any); ok {
+				schemes[name] = convertSwagger2SecurityScheme(scheme)
+			}
+		}
+		components["securitySchemes"] = schemes
+	}
+	return components
+}
+
+// parameterSchema builds the schema of a non-body parameter or header from its type keywords
+func parameterSchema(parameter map[string]any) map[string]any {
+	schema := make(map[string]any)
+	for key, value := range parameter {
+		switch key {
+		case "type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum",
+			"exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems",
+			"uniqueItems", "enum", "multipleOf":
+			schema[key] = value
+		}
+	}
+	return convertSwagger2Schema(schema).(map[string]any)
+}
+
+// convertSwagger2Schema rewrites a Swagger 2.0 schema to OpenAPI 3
+//
+// Definition references point to component schemas, a discriminator names its property
+// in an object, x-nullable becomes nullable and file types become binary strings.
+func convertSwagger2Schema(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			switch key {
+			case "$ref":
+				ref, _ := item.(string)
+				if name, found := strings.CutPrefix(ref, "#/definitions/"); found {
+					ref = "#/components/schemas/" + name
+				}
+				converted[key] = ref
+			case "discriminator":
+				if property, ok := item.(string); ok {
+					converted[key] = map[string]any{"propertyName": property}
+				} else {
+					converted[key] = item
+				}
+			case "x-nullable":
+				converted["nullable"] = item
+			case "enum":
+				// Enums are kept as strings, like the generated schemas
+				values, _ := item.([]any)
+				enum := make([]any, 0, len(values))
+				for _, value := range values {
+					enum = append(enum, fmt.Sprint(value))
+				}
+				converted[key] = enum
+			case "example", "default":
+				converted[key] = item
+			default:
+				converted[key] = convertSwagger2Schema(item)
+			}
+		}
+		if converted["type"] == "file" {
+			converted["type"], converted["format"] = "string", "binary"
+		}
+		return converted
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = convertSwagger2Schema(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// convertSwagger2SecurityScheme converts a security definition to a security scheme
+func convertSwagger2SecurityScheme(scheme map[string]any) map[string]any {
+	converted := make(map[string]any)
+	if description, exists := scheme["description"]; exists {
+		converted["description"] = description
+	}
+
+	switch scheme["type"] {
+	case "basic":
+		converted["type"], converted["scheme"] = "http", "basic"
+	case "apiKey":
+		converted["type"], converted["name"], converted["in"] = "apiKey", scheme["name"], scheme["in"]
+	case "oauth2":
+		flow := map[string]any{"scopes": scheme["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]any{}
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if value, exists := scheme[key]; exists {
+				flow[key] = value
+			}
+		}
+		flows := map[string]any{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		name, _ := flows[fmt.Sprint(scheme["flow"])].(string)
+		converted["type"] = "oauth2"
+		if name != "" {
+			converted["flows"] = map[string]any{name: flow}
+		}
+	default:
+		converted["type"] = scheme["type"]
+	}
+	return converted
+}
+
+// stringList returns the strings of a decoded list, fallback when it is missing or empty
+func stringList(value any, fallback []string) []string {
+	items, _ := value.([]any)
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	if len(list) == 0 {
+		return fallback
+	}
+	return list
+}
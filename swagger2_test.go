@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zainokta/openapi-gen/spec"
+)
+
+const swagger2JSON = `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy", "version": "1.0"},
+  "host": "legacy.example.com",
+  "basePath": "/api/v1",
+  "schemes": ["https"],
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"$ref": "#/parameters/UserID"}],
+      "get": {
+        "tags": ["users"],
+        "summary": "Get user",
+        "parameters": [
+          {"name": "fields", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The user",
+            "schema": {"$ref": "#/definitions/User"},
+            "headers": {"X-Rate-Limit": {"type": "integer", "description": "Requests left"}}
+          },
+          "404": {"$ref": "#/responses/NotFound"}
+        },
+        "security": [{"apiKey": []}]
+      },
+      "put": {
+        "parameters": [{"name": "user", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}],
+        "responses": {"204": {"description": "Updated"}}
+      }
+    },
+    "/avatars": {
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "file", "in": "formData", "type": "file", "required": true},
+          {"name": "caption", "in": "formData", "type": "string"}
+        ],
+        "responses": {"201": {"description": "Uploaded"}}
+      }
+    }
+  },
+  "definitions": {
+    "User": {
+      "type": "object",
+      "required": ["id"],
+      "properties": {
+        "id": {"type": "integer", "format": "int64"},
+        "role": {"type": "integer", "enum": [1, 2]},
+        "manager": {"$ref": "#/definitions/User", "x-nullable": true},
+        "labels": {"type": "object", "additionalProperties": true}
+      }
+    },
+    "Pet": {"type": "object", "discriminator": "kind", "properties": {"kind": {"type": "string"}}}
+  },
+  "parameters": {
+    "UserID": {"name": "id", "in": "path", "required": true, "type": "integer"}
+  },
+  "responses": {
+    "NotFound": {"description": "Not found", "schema": {"type": "string"}}
+  },
+  "securityDefinitions": {
+    "apiKey": {"type": "apiKey", "name": "X-API-Key", "in": "header"},
+    "basic": {"type": "basic"},
+    "oauth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://auth/authorize", "tokenUrl": "https://auth/token", "scopes": {"read": "Read access"}}
+  }
+}`
+
+func TestConvertSwagger2(t *testing.T) {
+	converted, err := ConvertSwagger2([]byte(swagger2JSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", converted.OpenAPI)
+	assert.Equal(t, []spec.Server{{URL: "https://legacy.example.com"}}, converted.Servers)
+
+	user := converted.Paths["/api/v1/users/{id}"]
+	if assert.Len(t, user.Parameters, 1) {
+		assert.Equal(t, "id", user.Parameters[0].Name)
+		assert.Equal(t, "integer", user.Parameters[0].Schema.Type)
+	}
+	if assert.NotNil(t, user.Get) {
+		fields := user.Get.Parameters[0]
+		assert.Equal(t, "array", fields.Schema.Type)
+		assert.Equal(t, "form", fields.Style)
+		if assert.NotNil(t, fields.Explode) {
+			assert.True(t, *fields.Explode)
+		}
+
+		ok := user.Get.Responses["200"]
+		assert.Equal(t, "#/components/schemas/User", ok.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "integer", ok.Headers["X-Rate-Limit"].Schema.Type)
+		assert.Equal(t, "Not found", user.Get.Responses["404"].Description, "Response references are inlined")
+		assert.Equal(t, []spec.SecurityRequirement{{"apiKey": []string{}}}, user.Get.Security)
+	}
+	if assert.NotNil(t, user.Put) && assert.NotNil(t, user.Put.RequestBody) {
+		assert.True(t, user.Put.RequestBody.Required)
+		assert.Equal(t, "#/components/schemas/User", user.Put.RequestBody.Content["application/json"].Schema.Ref)
+	}
+
+	avatars := converted.Paths["/api/v1/avatars"].Post
+	if assert.NotNil(t, avatars) && assert.NotNil(t, avatars.RequestBody) {
+		form := avatars.RequestBody.Content["multipart/form-data"].Schema
+		assert.Equal(t, spec.Schema{Type: "string", Format: "binary"}, form.Properties["file"])
+		assert.Equal(t, []string{"file"}, form.Required)
+	}
+
+	schemas := converted.Components.Schemas
+	assert.Equal(t, []string{"1", "2"}, schemas["User"].Properties["role"].Enum)
+	assert.True(t, schemas["User"].Properties["manager"].Nullable)
+	assert.Equal(t, "#/components/schemas/User", schemas["User"].Properties["manager"].Ref)
+	assert.Equal(t, true, *schemas["User"].Properties["labels"].AdditionalPropertiesAllowed)
+	assert.Equal(t, "kind", schemas["Pet"].Discriminator.PropertyName)
+
+	schemes := converted.Components.SecuritySchemes
+	assert.Equal(t, spec.SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"}, schemes["apiKey"])
+	assert.Equal(t, spec.SecurityScheme{Type: "http", Scheme: "basic"}, schemes["basic"])
+	assert.Equal(t, "https://auth/token", schemes["oauth"].Flows.AuthorizationCode.TokenURL)
+	assert.Equal(t, map[string]string{"read": "Read access"}, schemes["oauth"].Flows.AuthorizationCode.Scopes)
+
+	_, err = ConvertSwagger2([]byte(`{"openapi": "3.0.3"}`))
+	assert.Error(t, err)
+}
+
+func TestWithAdditionalSpecFilesSwagger2(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "swagger.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`swagger: "2.0"
+info:
+  title: Legacy
+  version: "1.0"
+paths:
+  /reports:
+    get:
+      produces: [text/csv]
+      responses:
+        200:
+          description: The report
+          schema:
+            type: string
+`), 0o644))
+
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health"}}, WithAdditionalSpecFiles(file))
+	openAPISpec, err := generator.GenerateSpec()
+	require.NoError(t, err)
+
+	reports := openAPISpec.Paths["/reports"].Get
+	if assert.NotNil(t, reports) {
+		assert.Equal(t, "string", reports.Responses["200"].Content["text/csv"].Schema.Type)
+	}
+	assert.Contains(t, openAPISpec.Paths, "/health")
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName names the tracer and meter of the generator
+const instrumentationName = "github.com/zainokta/openapi-gen"
+
+// WithTracerProvider traces spec generation with OpenTelemetry
+//
+// GenerateSpec, route discovery and the analysis of each handler are recorded as spans.
+// Without a provider no spans are created.
+//
+// Example:
+//
+//	err := openapi.EnableDocs(framework, httpServer,
+//		openapi.WithTracerProvider(otel.GetTracerProvider()),
+//	)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(opts *Options) {
+		opts.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records spec generation metrics with OpenTelemetry
+//
+// The metrics are openapi.routes.processed, openapi.analysis.failures,
+// openapi.analysis.fallbacks (handlers documented with placeholder schemas) and
+// openapi.generation.duration. Without a provider nothing is recorded.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(opts *Options) {
+		opts.meterProvider = provider
+	}
+}
+
+// telemetry holds the tracer and metric instruments of a generator, no-ops unless providers are configured
+type telemetry struct {
+	tracer           trace.Tracer
+	routesProcessed  metric.Int64Counter
+	analysisFailures metric.Int64Counter
+	fallbacks        metric.Int64Counter
+	drift            metric.Int64Counter
+	duration         metric.Float64Histogram
+}
+
+// newTelemetry creates the tracer and metric instruments, nil providers fall back to no-op ones
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*telemetry, error) {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(instrumentationName)
+
+	t := &telemetry{tracer: tracerProvider.Tracer(instrumentationName)}
+	var err error
+	if t.routesProcessed, err = meter.Int64Counter("openapi.routes.processed",
+		metric.WithDescription("Routes documented by spec generation"), metric.WithUnit("{route}")); err != nil {
+		return nil, fmt.Errorf("failed to create routes counter: %w", err)
+	}
+	if t.analysisFailures, err = meter.Int64Counter("openapi.analysis.failures",
+		metric.WithDescription("Routes that could not be documented"), metric.WithUnit("{route}")); err != nil {
+		return nil, fmt.Errorf("failed to create failures counter: %w", err)
+	}
+	if t.fallbacks, err = meter.Int64Counter("openapi.analysis.fallbacks",
+		metric.WithDescription("Handlers documented with placeholder schemas"), metric.WithUnit("{handler}")); err != nil {
+		return nil, fmt.Errorf("failed to create fallbacks counter: %w", err)
+	}
+	if t.drift, err = meter.Int64Counter("openapi.schema.drift",
+		metric.WithDescription("Served responses that do not match their documented schema"), metric.WithUnit("{mismatch}")); err != nil {
+		return nil, fmt.Errorf("failed to create drift counter: %w", err)
+	}
+	if t.duration, err = meter.Float64Histogram("openapi.generation.duration",
+		metric.WithDescription("Duration of spec generation"), metric.WithUnit("s")); err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+	return t, nil
+}
+
+// routeAttributes identify a route on spans
+func routeAttributes(method, path, handlerName string) trace.SpanStartEventOption {
+	return trace.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("http.route", path),
+		attribute.String("openapi.handler", handlerName),
+	)
+}
+
+// recordError marks a span as failed with err, a nil err leaves it untouched
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// placeholderAnalyzer documents every handler with fallback schemas
+type placeholderAnalyzer struct{}
+
+func (placeholderAnalyzer) ExtractTypes(handler interface{}) (reflect.Type, reflect.Type, error) {
+	return nil, nil, errors.New("no source")
+}
+
+func (placeholderAnalyzer) AnalyzeHandler(handler interface{}) analyzer.HandlerSchema {
+	return analyzer.HandlerSchema{ResponseSchema: spec.Schema{Type: "object", Description: analyzer.FallbackResponseDescription}}
+}
+
+func (placeholderAnalyzer) GetFrameworkName() string {
+	return "Placeholder"
+}
+
+func (placeholderAnalyzer) SetConfig(config analyzer.Config) {}
+
+// failingDiscoverer cannot list routes
+type failingDiscoverer struct{}
+
+func (failingDiscoverer) DiscoverRoutes() ([]spec.RouteInfo, error) {
+	return nil, errors.New("router not started")
+}
+
+func (failingDiscoverer) GetFrameworkName() string {
+	return "Failing"
+}
+
+func TestGenerateSpecTelemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	generator := newTestGenerator(t, []spec.RouteInfo{
+		{Method: "GET", Path: "/users", HandlerName: "ListUsers", Handler: func() {}},
+		{Method: "GET", Path: "/health", HandlerName: "Health"},
+	},
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	generator.handlerAnalyzer = placeholderAnalyzer{}
+
+	_, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, span := range spans.Ended() {
+		names = append(names, span.Name())
+	}
+	assert.ElementsMatch(t, []string{"openapi.DiscoverRoutes", "openapi.AnalyzeHandler", "openapi.GenerateSpec"}, names)
+
+	var metrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &metrics))
+	sums := make(map[string]int64)
+	var durations uint64
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					sums[m.Name] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					durations += point.Count
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(2), sums["openapi.routes.processed"])
+	assert.Equal(t, int64(1), sums["openapi.analysis.fallbacks"])
+	assert.Equal(t, uint64(1), durations)
+}
+
+func TestGenerateSpecTelemetryDiscoveryError(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	generator := newTestGenerator(t, nil, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))
+	generator.discoverer = failingDiscoverer{}
+
+	_, err := generator.GenerateSpec()
+	assert.Error(t, err)
+
+	for _, span := range spans.Ended() {
+		assert.Equal(t, codes.Error, span.Status().Code, span.Name())
+	}
+	assert.Len(t, spans.Ended(), 2)
+}
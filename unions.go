@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/zainokta/openapi-gen/analyzer"
+)
+
+// RegisterUnion declares the concrete types an interface can hold
+//
+// Fields of type T are documented as a oneOf of the variants, discriminated by a
+// "type" property whose value is the variant's Go type name. Unions belong to the
+// generator g and are usually registered right after it is created, before the spec
+// is generated.
+//
+// Example:
+//
+//	type Animal interface{ Sound() string }
+//
+//	openapi.RegisterUnion[Animal](g, Dog{}, Cat{})
+func RegisterUnion[T any](g *Generator, variants ...any) error {
+	unionVariants := make([]analyzer.UnionVariant, 0, len(variants))
+	for _, variant := range variants {
+		unionVariants = append(unionVariants, analyzer.UnionVariant{Type: reflect.TypeOf(variant)})
+	}
+	return g.schemaRegistry.GetSchemaGenerator().Unions().Register(reflect.TypeFor[T](), "", unionVariants)
+}
+
+// RegisterUnionWithDiscriminator declares a union with a custom discriminator property and values
+//
+// Example:
+//
+//	openapi.RegisterUnionWithDiscriminator[Animal](g, "kind", map[string]any{
+//		"dog": Dog{},
+//		"cat": Cat{},
+//	})
+func RegisterUnionWithDiscriminator[T any](g *Generator, property string, variants map[string]any) error {
+	values := make([]string, 0, len(variants))
+	for value := range variants {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	unionVariants := make([]analyzer.UnionVariant, 0, len(variants))
+	for _, value := range values {
+		unionVariants = append(unionVariants, analyzer.UnionVariant{Value: value, Type: reflect.TypeOf(variants[value])})
+	}
+	return g.schemaRegistry.GetSchemaGenerator().Unions().Register(reflect.TypeFor[T](), property, unionVariants)
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zainokta/openapi-gen/spec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unionAnimal interface{ Sound() string }
+
+type unionDog struct {
+	Breed string `json:"breed"`
+}
+
+func (unionDog) Sound() string { return "woof" }
+
+type unionCat struct {
+	Indoor bool `json:"indoor"`
+}
+
+func (unionCat) Sound() string { return "meow" }
+
+type unionPetResponse struct {
+	Pet unionAnimal `json:"pet"`
+}
+
+func TestRegisterUnion(t *testing.T) {
+	generator := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/pet", HandlerName: "GetPet"}})
+	assert.NoError(t, RegisterUnion[unionAnimal](generator, unionDog{}, unionCat{}))
+	generator.OverrideResponse("GET", "/pet", 200, unionPetResponse{})
+
+	openAPISpec, err := generator.GenerateSpec()
+	assert.NoError(t, err)
+
+	response := openAPISpec.Components.Schemas["unionPetResponse"]
+	pet := response.Properties["pet"]
+	if assert.NotNil(t, pet.Discriminator) {
+		assert.Equal(t, "type", pet.Discriminator.PropertyName)
+		assert.Equal(t, "#/components/schemas/unionDog", pet.Discriminator.Mapping["unionDog"])
+	}
+	assert.Len(t, pet.OneOf, 2)
+	assert.Contains(t, openAPISpec.Components.Schemas, "unionDog")
+	assert.Contains(t, openAPISpec.Components.Schemas, "unionCat")
+
+	// Unions are scoped to their generator
+	other := newTestGenerator(t, []spec.RouteInfo{{Method: "GET", Path: "/health", HandlerName: "Health"}})
+	otherSpec, err := other.GenerateSpec()
+	assert.NoError(t, err)
+	assert.NotContains(t, otherSpec.Components.Schemas, "unionDog")
+}
+
+func TestRegisterUnionWithDiscriminator(t *testing.T) {
+	generator := newTestGenerator(t, nil)
+	assert.NoError(t, RegisterUnionWithDiscriminator[unionAnimal](generator, "kind", map[string]any{
+		"dog": unionDog{},
+		"cat": &unionCat{},
+	}))
+
+	union, exists := generator.schemaRegistry.GetSchemaGenerator().Unions().Lookup(reflect.TypeFor[unionAnimal]())
+	if assert.True(t, exists) {
+		assert.Equal(t, "kind", union.PropertyName)
+		assert.Equal(t, "cat", union.Variants[0].Value)
+		assert.Equal(t, reflect.TypeOf(unionCat{}), union.Variants[0].Type)
+	}
+
+	assert.Error(t, RegisterUnion[unionDog](generator, unionDog{}), "Only interfaces can be unions")
+}